@@ -25,6 +25,11 @@ type Option interface {
 type Options struct {
 	FgColor Color
 	BgColor Color
+
+	// Text attributes, support for these depends on the terminal.
+	Bold      bool
+	Underline bool
+	Inverse   bool
 }
 
 // Set allows existing options to be passed as an option.
@@ -62,3 +67,27 @@ func BgColor(color Color) Option {
 		co.BgColor = color
 	})
 }
+
+// Bold makes the text in the cell bold.
+// Not all terminals support this attribute.
+func Bold() Option {
+	return option(func(co *Options) {
+		co.Bold = true
+	})
+}
+
+// Underline underlines the text in the cell.
+// Not all terminals support this attribute.
+func Underline() Option {
+	return option(func(co *Options) {
+		co.Underline = true
+	})
+}
+
+// Inverse swaps the foreground and the background colors of the cell.
+// Not all terminals support this attribute.
+func Inverse() Option {
+	return option(func(co *Options) {
+		co.Inverse = true
+	})
+}
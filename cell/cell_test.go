@@ -59,6 +59,19 @@ func TestNewOptions(t *testing.T) {
 				BgColor: ColorMagenta,
 			},
 		},
+		{
+			desc: "setting text attributes",
+			opts: []Option{
+				Bold(),
+				Underline(),
+				Inverse(),
+			},
+			want: &Options{
+				Bold:      true,
+				Underline: true,
+				Inverse:   true,
+			},
+		},
 		{
 			desc: "setting options by passing the options struct",
 			opts: []Option{
@@ -45,6 +45,19 @@ type Container struct {
 	first  *Container
 	second *Container
 
+	// tabs are the pages of the container if it was created with the Tabs
+	// option. The active page is also the first sub container, the inactive
+	// pages aren't part of the tree.
+	tabs *tabs
+
+	// popupArea is the area on the terminal where the popup of the widget was
+	// drawn on the last redraw. Empty if no popup was drawn.
+	popupArea image.Rectangle
+
+	// overlays are the container trees displayed above this container in the
+	// order they were opened. Only set on the root container.
+	overlays []*overlay
+
 	// term is the terminal this container is placed on.
 	// All containers in the tree share the same terminal.
 	term terminalapi.Terminal
@@ -171,6 +184,9 @@ func (c *Container) split() (image.Rectangle, image.Rectangle, error) {
 	if err != nil {
 		return image.ZR, image.ZR, err
 	}
+	if c.tabs != nil {
+		return tabPageArea(ar), image.ZR, nil
+	}
 	if c.opts.split == splitTypeVertical {
 		return area.VSplit(ar, c.opts.splitPercent)
 	}
@@ -202,7 +218,7 @@ func (c *Container) Draw() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if c.clearNeeded {
+	if c.overlayClearNeeded() || c.clearNeeded {
 		if err := c.term.Clear(); err != nil {
 			return fmt.Errorf("term.Clear => error: %v", err)
 		}
@@ -216,7 +232,10 @@ func (c *Container) Draw() error {
 		return err
 	}
 	c.focusTracker.updateArea(ar)
-	return drawTree(c)
+	if err := clearPopups(c); err != nil {
+		return err
+	}
+	return drawLayers(c)
 }
 
 // Update updates container with the specified id by setting the provided
@@ -244,7 +263,7 @@ func (c *Container) Update(id string, opts ...Option) error {
 
 	// The currently focused container might not be reachable anymore, because
 	// it was under the target. If that is so, move the focus up to the target.
-	if !c.focusTracker.reachableFrom(c) {
+	if !c.focusReachable() {
 		c.focusTracker.setActive(target)
 	}
 	return nil
@@ -282,13 +301,26 @@ func (c *Container) processEvent(ev terminalapi.Event) error {
 // prepareEvTargets returns a closure, that when called delivers the event to
 // widgets that registered for it.
 // Also processes the event on behalf of the container (tracks keyboard focus).
+// While any overlays are open, only the overlay on the top receives events.
 // Caller must hold c.mu.
 func (c *Container) prepareEvTargets(ev terminalapi.Event) (func() error, error) {
+	top := c.topLayer()
 	switch e := ev.(type) {
 	case *terminalapi.Mouse:
-		c.updateFocus(ev.(*terminalapi.Mouse))
+		// Events on popups are delivered only to the widget that owns it and
+		// don't change the focus.
+		if deliver := popupForMouse(top, e); deliver != nil {
+			return deliver, nil
+		}
+
+		top.updateFocus(ev.(*terminalapi.Mouse))
+		if activate := tabForMouse(top, e); activate != nil {
+			if err := activate(); err != nil {
+				return nil, err
+			}
+		}
 
-		targets, err := c.mouseEvTargets(e)
+		targets, err := top.mouseEvTargets(e)
 		if err != nil {
 			return nil, err
 		}
@@ -302,7 +334,25 @@ func (c *Container) prepareEvTargets(ev terminalapi.Event) (func() error, error)
 		}, nil
 
 	case *terminalapi.Keyboard:
-		targets := c.keyEvTargets()
+		if o := c.overlayForKey(e); o != nil {
+			if _, err := c.closeOverlay(o.id); err != nil {
+				return nil, err
+			}
+			// The key that closes the overlay isn't forwarded to the widgets.
+			return func() error {
+				if o.opts.onClose != nil {
+					o.opts.onClose()
+				}
+				return nil
+			}, nil
+		}
+
+		if activate := tabForKey(top, e); activate != nil {
+			// Keys that switch tabs aren't forwarded to the widgets.
+			return func() error { return nil }, activate()
+		}
+
+		targets := top.keyEvTargets()
 		return func() error {
 			for _, w := range targets {
 				if err := w.Keyboard(e); err != nil {
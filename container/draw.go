@@ -25,16 +25,23 @@ import (
 	"github.com/mum4k/termdash/internal/area"
 	"github.com/mum4k/termdash/internal/canvas"
 	"github.com/mum4k/termdash/internal/draw"
+	"github.com/mum4k/termdash/terminal/terminalapi"
 	"github.com/mum4k/termdash/widgetapi"
 )
 
 // drawTree draws this container and all of its sub containers.
 func drawTree(c *Container) error {
-	var errStr string
-
 	root := rootCont(c)
 	size := root.term.Size()
-	ar, err := root.opts.margin.apply(image.Rect(0, 0, size.X, size.Y))
+	return drawSubtree(root, image.Rect(0, 0, size.X, size.Y), root.term)
+}
+
+// drawSubtree draws the tree starting at the provided root container into
+// the area onto the terminal.
+func drawSubtree(root *Container, ar image.Rectangle, term terminalapi.Terminal) error {
+	var errStr string
+
+	ar, err := root.opts.margin.apply(ar)
 	if err != nil {
 		return err
 	}
@@ -60,16 +67,16 @@ func drawTree(c *Container) error {
 			}
 			c.second.area = ar
 		}
-		return drawCont(c)
+		return drawCont(c, term)
 	}))
 	if errStr != "" {
 		return errors.New(errStr)
 	}
-	return nil
+	return drawPopups(root, term)
 }
 
 // drawBorder draws the border around the container if requested.
-func drawBorder(c *Container) error {
+func drawBorder(c *Container, term terminalapi.Terminal) error {
 	if !c.hasBorder() {
 		return nil
 	}
@@ -99,11 +106,11 @@ func drawBorder(c *Container) error {
 	); err != nil {
 		return err
 	}
-	return cvs.Apply(c.term)
+	return cvs.Apply(term)
 }
 
 // drawWidget requests the widget to draw on the canvas.
-func drawWidget(c *Container) error {
+func drawWidget(c *Container, term terminalapi.Terminal) error {
 	widgetArea, err := c.widgetArea()
 	if err != nil {
 		return err
@@ -123,7 +130,7 @@ func drawWidget(c *Container) error {
 	}
 
 	if widgetArea.Dx() < needSize.X || widgetArea.Dy() < needSize.Y {
-		return drawResize(c, c.usable())
+		return drawResize(term, c.usable())
 	}
 
 	cvs, err := canvas.New(widgetArea)
@@ -138,12 +145,12 @@ func drawWidget(c *Container) error {
 	if err := c.opts.widget.Draw(cvs, meta); err != nil {
 		return err
 	}
-	return cvs.Apply(c.term)
+	return cvs.Apply(term)
 }
 
 // drawResize draws an unicode character indicating that the size is too small to draw this container.
 // Does nothing if the size is smaller than one cell, leaving no space for the character.
-func drawResize(c *Container, area image.Rectangle) error {
+func drawResize(term terminalapi.Terminal, area image.Rectangle) error {
 	if area.Dx() < 1 || area.Dy() < 1 {
 		return nil
 	}
@@ -155,20 +162,24 @@ func drawResize(c *Container, area image.Rectangle) error {
 	if err := draw.ResizeNeeded(cvs); err != nil {
 		return err
 	}
-	return cvs.Apply(c.term)
+	return cvs.Apply(term)
 }
 
 // drawCont draws the container and its widget.
-func drawCont(c *Container) error {
+func drawCont(c *Container, term terminalapi.Terminal) error {
 	if us := c.usable(); us.Dx() <= 0 || us.Dy() <= 0 {
-		return drawResize(c, c.area)
+		return drawResize(term, c.area)
 	}
 
-	if err := drawBorder(c); err != nil {
+	if err := drawBorder(c, term); err != nil {
 		return fmt.Errorf("unable to draw container border: %v", err)
 	}
 
-	if err := drawWidget(c); err != nil {
+	if err := drawTabBar(c, term); err != nil {
+		return fmt.Errorf("unable to draw the tab bar: %v", err)
+	}
+
+	if err := drawWidget(c, term); err != nil {
 		return fmt.Errorf("unable to draw widget %T: %v", c.opts.widget, err)
 	}
 	return nil
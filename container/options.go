@@ -43,7 +43,7 @@ func validateOptions(c *Container) error {
 	// ensure all the container identifiers are either empty or unique.
 	var errStr string
 	seenID := map[string]bool{}
-	preOrder(c, &errStr, func(c *Container) error {
+	preOrderAll(c, &errStr, func(c *Container) error {
 		if c.opts.id == "" {
 			return nil
 		}
@@ -223,6 +223,7 @@ func SplitVertical(l LeftOption, r RightOption, opts ...SplitOption) Option {
 	return option(func(c *Container) error {
 		c.opts.split = splitTypeVertical
 		c.opts.widget = nil
+		c.tabs = nil
 		for _, opt := range opts {
 			if err := opt.setSplit(c.opts); err != nil {
 				return err
@@ -243,6 +244,7 @@ func SplitHorizontal(t TopOption, b BottomOption, opts ...SplitOption) Option {
 	return option(func(c *Container) error {
 		c.opts.split = splitTypeHorizontal
 		c.opts.widget = nil
+		c.tabs = nil
 		for _, opt := range opts {
 			if err := opt.setSplit(c.opts); err != nil {
 				return err
@@ -279,6 +281,7 @@ func Clear() Option {
 		c.opts.widget = nil
 		c.first = nil
 		c.second = nil
+		c.tabs = nil
 		return nil
 	})
 }
@@ -291,6 +294,7 @@ func PlaceWidget(w widgetapi.Widget) Option {
 		c.opts.widget = w
 		c.first = nil
 		c.second = nil
+		c.tabs = nil
 		return nil
 	})
 }
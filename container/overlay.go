@@ -0,0 +1,457 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+// overlay.go contains code for containers displayed above the layout.
+
+import (
+	"errors"
+	"fmt"
+	"image"
+
+	"github.com/mum4k/termdash/align"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/alignfor"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+// Backdrop determines what happens to the content below an overlay.
+type Backdrop int
+
+// String implements fmt.Stringer()
+func (b Backdrop) String() string {
+	if n, ok := backdropNames[b]; ok {
+		return n
+	}
+	return "BackdropUnknown"
+}
+
+// backdropNames maps Backdrop values to human readable names.
+var backdropNames = map[Backdrop]string{
+	BackdropDim:   "BackdropDim",
+	BackdropClear: "BackdropClear",
+	BackdropNone:  "BackdropNone",
+}
+
+const (
+	// BackdropDim draws the content below the overlay in a dim color.
+	BackdropDim Backdrop = iota
+
+	// BackdropClear hides the content below the overlay.
+	BackdropClear
+
+	// BackdropNone keeps the content below the overlay unchanged.
+	BackdropNone
+)
+
+// overlay is a container tree displayed above the layout.
+type overlay struct {
+	// id identifies the overlay.
+	id string
+	// cont is the root container of the overlay.
+	cont *Container
+	// opts are the provided overlay options.
+	opts *overlayOptions
+	// prevFocus is the container that was focused when the overlay opened.
+	prevFocus *Container
+}
+
+// overlayOptions stores the options provided when opening an overlay.
+type overlayOptions struct {
+	// Only one of the cells or percent is set for each dimension.
+	widthCells  int
+	heightCells int
+	widthPerc   int
+	heightPerc  int
+
+	hAlign align.Horizontal
+	vAlign align.Vertical
+
+	backdrop  Backdrop
+	dimColor  cell.Color
+	keepOnEsc bool
+	onClose   func()
+}
+
+// newOverlayOptions returns overlay options with the default values set.
+func newOverlayOptions() *overlayOptions {
+	return &overlayOptions{
+		widthPerc:  DefaultOverlaySizePercent,
+		heightPerc: DefaultOverlaySizePercent,
+		hAlign:     align.HorizontalCenter,
+		vAlign:     align.VerticalMiddle,
+		backdrop:   BackdropDim,
+		dimColor:   cell.ColorNumber(240),
+	}
+}
+
+// area returns the area of the overlay on a terminal of the provided size.
+func (oo *overlayOptions) area(termAr image.Rectangle) (image.Rectangle, error) {
+	width := termAr.Dx() * oo.widthPerc / 100
+	if oo.widthCells > 0 {
+		width = oo.widthCells
+	}
+	if width < 1 {
+		// The percentage rounded down to zero on a tiny terminal.
+		width = 1
+	}
+	if width > termAr.Dx() {
+		width = termAr.Dx()
+	}
+
+	height := termAr.Dy() * oo.heightPerc / 100
+	if oo.heightCells > 0 {
+		height = oo.heightCells
+	}
+	if height < 1 {
+		height = 1
+	}
+	if height > termAr.Dy() {
+		height = termAr.Dy()
+	}
+	return alignfor.Rectangle(termAr, image.Rect(0, 0, width, height), oo.hAlign, oo.vAlign)
+}
+
+// OverlayOption is used to provide options when opening an overlay.
+type OverlayOption interface {
+	// setOverlay sets the provided option.
+	setOverlay(*overlayOptions) error
+}
+
+// overlayOption implements OverlayOption.
+type overlayOption func(*overlayOptions) error
+
+// setOverlay implements OverlayOption.setOverlay.
+func (oo overlayOption) setOverlay(opts *overlayOptions) error {
+	return oo(opts)
+}
+
+// DefaultOverlaySizePercent is the default width and height of an overlay as
+// a percentage of the terminal size.
+const DefaultOverlaySizePercent = 50
+
+// OverlaySize sets the size of the overlay in cells. The overlay is shrunk if
+// the terminal is smaller. Both values must be positive integers.
+func OverlaySize(width, height int) OverlayOption {
+	return overlayOption(func(opts *overlayOptions) error {
+		if width <= 0 || height <= 0 {
+			return fmt.Errorf("invalid overlay size %dx%d, both values must be positive", width, height)
+		}
+		opts.widthCells, opts.heightCells = width, height
+		opts.widthPerc, opts.heightPerc = 0, 0
+		return nil
+	})
+}
+
+// OverlaySizePercent sets the size of the overlay as a percentage of the
+// terminal size. Both values must be in range 0 < p <= 100.
+// Defaults to DefaultOverlaySizePercent.
+func OverlaySizePercent(width, height int) OverlayOption {
+	return overlayOption(func(opts *overlayOptions) error {
+		if min, max := 0, 100; width <= min || width > max || height <= min || height > max {
+			return fmt.Errorf("invalid overlay size percentage %dx%d, both values must be in range %d < p <= %d", width, height, min, max)
+		}
+		opts.widthPerc, opts.heightPerc = width, height
+		opts.widthCells, opts.heightCells = 0, 0
+		return nil
+	})
+}
+
+// OverlayAlign anchors the overlay on the terminal.
+// Defaults to the center of the terminal.
+func OverlayAlign(h align.Horizontal, v align.Vertical) OverlayOption {
+	return overlayOption(func(opts *overlayOptions) error {
+		opts.hAlign, opts.vAlign = h, v
+		return nil
+	})
+}
+
+// OverlayBackdrop sets what happens to the content below the overlay.
+// Defaults to BackdropDim.
+func OverlayBackdrop(b Backdrop) OverlayOption {
+	return overlayOption(func(opts *overlayOptions) error {
+		if _, ok := backdropNames[b]; !ok {
+			return fmt.Errorf("unsupported backdrop %v(%d)", b, b)
+		}
+		opts.backdrop = b
+		return nil
+	})
+}
+
+// OverlayDimColor sets the color used to draw the content below the overlay
+// when using BackdropDim. Defaults to color number 240.
+func OverlayDimColor(color cell.Color) OverlayOption {
+	return overlayOption(func(opts *overlayOptions) error {
+		opts.dimColor = color
+		return nil
+	})
+}
+
+// OverlayKeepOnEsc prevents the Esc key from closing the overlay. Such overlay
+// must be closed by calling CloseOverlay.
+// By default the Esc key closes the overlay and isn't forwarded to widgets.
+func OverlayKeepOnEsc() OverlayOption {
+	return overlayOption(func(opts *overlayOptions) error {
+		opts.keepOnEsc = true
+		return nil
+	})
+}
+
+// OverlayOnClose sets a function that is called after the overlay closes,
+// either on the Esc key or by a call to CloseOverlay.
+// The function is called without holding the container's lock, so it can
+// call methods of the container.
+func OverlayOnClose(fn func()) OverlayOption {
+	return overlayOption(func(opts *overlayOptions) error {
+		opts.onClose = fn
+		return nil
+	})
+}
+
+// OpenOverlay opens an overlay above the layout and any overlays opened
+// previously. The overlay is a container tree created by applying the
+// provided options to its root container, i.e. it can contain a widget or
+// further splits.
+//
+// While open, the overlay on the top receives all the keyboard and mouse
+// events, the containers below it receive none. The overlay is initially
+// focused.
+//
+// The id identifies the overlay for CloseOverlay, it must not be empty and
+// must be unique among the open overlays. The id isn't a container ID, use
+// the ID option to be able to call Update on containers in the overlay.
+func (c *Container) OpenOverlay(id string, opts []Option, oOpts ...OverlayOption) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	root := rootCont(c)
+	if id == "" {
+		return errors.New("the overlay ID must not be empty")
+	}
+	for _, o := range root.overlays {
+		if o.id == id {
+			return fmt.Errorf("overlay with ID %q is already open", id)
+		}
+	}
+
+	oo := newOverlayOptions()
+	for _, opt := range oOpts {
+		if err := opt.setOverlay(oo); err != nil {
+			return err
+		}
+	}
+
+	cont := &Container{
+		term:         root.term,
+		focusTracker: root.focusTracker,
+		opts:         newOptions(root.opts),
+		mu:           root.mu,
+	}
+	if err := applyOptions(cont, opts...); err != nil {
+		return err
+	}
+
+	o := &overlay{
+		id:        id,
+		cont:      cont,
+		opts:      oo,
+		prevFocus: root.focusTracker.container,
+	}
+	root.overlays = append(root.overlays, o)
+	if err := validateOptions(root); err != nil {
+		root.overlays = root.overlays[:len(root.overlays)-1]
+		return err
+	}
+
+	root.focusTracker.setActive(cont)
+	root.clearNeeded = true
+	return nil
+}
+
+// CloseOverlay closes the overlay with the provided id.
+func (c *Container) CloseOverlay(id string) error {
+	c.mu.Lock()
+	o, err := rootCont(c).closeOverlay(id)
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if o.opts.onClose != nil {
+		o.opts.onClose()
+	}
+	return nil
+}
+
+// closeOverlay removes the overlay with the provided id and returns it.
+// Caller must hold c.mu and c must be the root container.
+func (c *Container) closeOverlay(id string) (*overlay, error) {
+	idx := -1
+	for i, o := range c.overlays {
+		if o.id == id {
+			idx = i
+		}
+	}
+	if idx == -1 {
+		return nil, fmt.Errorf("cannot find an open overlay with ID %q", id)
+	}
+
+	o := c.overlays[idx]
+	c.overlays = append(c.overlays[:idx], c.overlays[idx+1:]...)
+	c.clearNeeded = true
+
+	// Return the focus where it was before the overlay opened, unless that
+	// container isn't displayed anymore.
+	if c.focusTracker.isActive(o.cont) || !c.focusReachable() {
+		c.focusTracker.setActive(o.prevFocus)
+	}
+	if !c.focusReachable() {
+		c.focusTracker.setActive(c.topLayer())
+	}
+	return o, nil
+}
+
+// topLayer returns the root container of the overlay on the top or the
+// container itself if no overlays are open.
+// Caller must hold c.mu and c must be the root container.
+func (c *Container) topLayer() *Container {
+	if len(c.overlays) == 0 {
+		return c
+	}
+	return c.overlays[len(c.overlays)-1].cont
+}
+
+// focusReachable asserts whether the focused container is displayed, either
+// in the layout or in one of the overlays.
+// Caller must hold c.mu and c must be the root container.
+func (c *Container) focusReachable() bool {
+	if c.focusTracker.reachableFrom(c) {
+		return true
+	}
+	for _, o := range c.overlays {
+		if c.focusTracker.reachableFrom(o.cont) {
+			return true
+		}
+	}
+	return false
+}
+
+// overlayClearNeeded asserts whether any of the overlays requested the
+// terminal to be cleared, resets the requests.
+// Caller must hold c.mu and c must be the root container.
+func (c *Container) overlayClearNeeded() bool {
+	var needed bool
+	for _, o := range c.overlays {
+		if o.cont.clearNeeded {
+			needed = true
+			o.cont.clearNeeded = false
+		}
+	}
+	return needed
+}
+
+// overlayForKey returns the overlay on the top if the keyboard event is the
+// Esc key and the overlay can be closed by it. Returns nil otherwise.
+// Caller must hold c.mu and c must be the root container.
+func (c *Container) overlayForKey(k *terminalapi.Keyboard) *overlay {
+	if len(c.overlays) == 0 || k.Key != keyboard.KeyEsc {
+		return nil
+	}
+	top := c.overlays[len(c.overlays)-1]
+	if top.opts.keepOnEsc {
+		return nil
+	}
+	return top
+}
+
+// dimTerminal is a terminal that draws all the cells in a dim color. Used to
+// draw the content below overlays.
+type dimTerminal struct {
+	terminalapi.Terminal
+
+	// color is the color of the cells.
+	color cell.Color
+}
+
+// SetCell implements terminalapi.Terminal.SetCell.
+func (dt *dimTerminal) SetCell(p image.Point, r rune, opts ...cell.Option) error {
+	opts = append(opts, cell.FgColor(dt.color), cell.BgColor(cell.ColorDefault))
+	return dt.Terminal.SetCell(p, r, opts...)
+}
+
+// drawLayers draws the layout and any open overlays above it.
+// Caller must hold c.mu and c must be the root container.
+func drawLayers(c *Container) error {
+	if len(c.overlays) == 0 {
+		return drawTree(c)
+	}
+
+	size := c.term.Size()
+	termAr := image.Rect(0, 0, size.X, size.Y)
+
+	// Layers below an overlay that clears its backdrop aren't drawn at all.
+	// Layer zero is the layout, layer i is the overlay at index i-1.
+	first := 0
+	for i, o := range c.overlays {
+		if o.opts.backdrop == BackdropClear {
+			first = i + 1
+		}
+	}
+
+	for layer := first; layer <= len(c.overlays); layer++ {
+		var term terminalapi.Terminal = c.term
+		for _, above := range c.overlays[layer:] {
+			if above.opts.backdrop == BackdropDim {
+				term = &dimTerminal{
+					Terminal: c.term,
+					color:    above.opts.dimColor,
+				}
+				break
+			}
+		}
+
+		if layer == 0 {
+			if err := drawSubtree(c, termAr, term); err != nil {
+				return err
+			}
+			continue
+		}
+
+		o := c.overlays[layer-1]
+		ar, err := o.opts.area(termAr)
+		if err != nil {
+			return err
+		}
+		if ar.Empty() {
+			// No room for the overlay on an empty terminal.
+			continue
+		}
+		// Clear the area of the overlay, so that the content below doesn't
+		// show through cells the overlay doesn't draw.
+		cvs, err := canvas.New(ar)
+		if err != nil {
+			return err
+		}
+		if err := cvs.Apply(term); err != nil {
+			return err
+		}
+		if err := drawSubtree(o.cont, ar, term); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,577 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"fmt"
+	"image"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mum4k/termdash/align"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/canvas/testcanvas"
+	"github.com/mum4k/termdash/internal/draw/testdraw"
+	"github.com/mum4k/termdash/internal/event"
+	"github.com/mum4k/termdash/internal/event/testevent"
+	"github.com/mum4k/termdash/internal/faketerm"
+	"github.com/mum4k/termdash/internal/fakewidget"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+func TestOverlay(t *testing.T) {
+	keyOpts := widgetapi.Options{WantKeyboard: widgetapi.KeyScopeGlobal}
+	mouseOpts := widgetapi.Options{WantMouse: widgetapi.MouseScopeGlobal}
+
+	tests := []struct {
+		desc      string
+		termSize  image.Point
+		container func(ft *faketerm.Terminal) (*Container, error)
+		events    []terminalapi.Event
+		want      func(size image.Point) *faketerm.Terminal
+		wantErr   bool
+	}{
+		{
+			desc:     "fails on empty overlay ID",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				c, err := New(ft)
+				if err != nil {
+					return nil, err
+				}
+				return c, c.OpenOverlay("", nil)
+			},
+			wantErr: true,
+		},
+		{
+			desc:     "fails on duplicate overlay ID",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				c, err := New(ft)
+				if err != nil {
+					return nil, err
+				}
+				if err := c.OpenOverlay("dialog", nil); err != nil {
+					return nil, err
+				}
+				return c, c.OpenOverlay("dialog", nil)
+			},
+			wantErr: true,
+		},
+		{
+			desc:     "fails on invalid size",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				c, err := New(ft)
+				if err != nil {
+					return nil, err
+				}
+				return c, c.OpenOverlay("dialog", nil, OverlaySize(0, 1))
+			},
+			wantErr: true,
+		},
+		{
+			desc:     "fails on invalid size percentage",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				c, err := New(ft)
+				if err != nil {
+					return nil, err
+				}
+				return c, c.OpenOverlay("dialog", nil, OverlaySizePercent(50, 101))
+			},
+			wantErr: true,
+		},
+		{
+			desc:     "fails on unsupported backdrop",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				c, err := New(ft)
+				if err != nil {
+					return nil, err
+				}
+				return c, c.OpenOverlay("dialog", nil, OverlayBackdrop(Backdrop(-1)))
+			},
+			wantErr: true,
+		},
+		{
+			desc:     "fails on container ID that duplicates one in the layout",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				c, err := New(ft, ID("cont"))
+				if err != nil {
+					return nil, err
+				}
+				return c, c.OpenOverlay("dialog", []Option{ID("cont")})
+			},
+			wantErr: true,
+		},
+		{
+			desc:     "draws overlay of the default size above the layout",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				c, err := New(ft, PlaceWidget(fakewidget.New(widgetapi.Options{})))
+				if err != nil {
+					return nil, err
+				}
+				return c, c.OpenOverlay("dialog",
+					[]Option{PlaceWidget(fakewidget.New(widgetapi.Options{}))},
+					OverlayBackdrop(BackdropNone),
+				)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(ft.Area()), &widgetapi.Meta{}, widgetapi.Options{})
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(5, 2, 15, 7)), &widgetapi.Meta{Focused: true}, widgetapi.Options{})
+				return ft
+			},
+		},
+		{
+			desc:     "draws overlay of a fixed size anchored to the corner",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				c, err := New(ft, PlaceWidget(fakewidget.New(widgetapi.Options{})))
+				if err != nil {
+					return nil, err
+				}
+				return c, c.OpenOverlay("dialog",
+					[]Option{PlaceWidget(fakewidget.New(widgetapi.Options{}))},
+					OverlaySize(8, 4),
+					OverlayAlign(align.HorizontalRight, align.VerticalBottom),
+					OverlayBackdrop(BackdropNone),
+				)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(ft.Area()), &widgetapi.Meta{}, widgetapi.Options{})
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(12, 6, 20, 10)), &widgetapi.Meta{Focused: true}, widgetapi.Options{})
+				return ft
+			},
+		},
+		{
+			desc:     "draws overlay at least one cell tall on a tiny terminal",
+			termSize: image.Point{20, 1},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				minOpts := widgetapi.Options{MinimumSize: image.Point{3, 3}}
+				c, err := New(ft, PlaceWidget(fakewidget.New(minOpts)))
+				if err != nil {
+					return nil, err
+				}
+				return c, c.OpenOverlay("dialog",
+					[]Option{PlaceWidget(fakewidget.New(minOpts))},
+					OverlayBackdrop(BackdropNone),
+				)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				base := testcanvas.MustNew(ft.Area())
+				testdraw.MustResizeNeeded(base)
+				testcanvas.MustApply(base, ft)
+				overlay := testcanvas.MustNew(image.Rect(5, 0, 15, 1))
+				testdraw.MustResizeNeeded(overlay)
+				testcanvas.MustApply(overlay, ft)
+				return ft
+			},
+		},
+		{
+			desc:     "shrinks overlay larger than the terminal",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				c, err := New(ft)
+				if err != nil {
+					return nil, err
+				}
+				return c, c.OpenOverlay("dialog",
+					[]Option{PlaceWidget(fakewidget.New(widgetapi.Options{}))},
+					OverlaySize(30, 30),
+				)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(ft.Area()), &widgetapi.Meta{Focused: true}, widgetapi.Options{})
+				return ft
+			},
+		},
+		{
+			desc:     "clear backdrop hides the layout",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				c, err := New(ft, PlaceWidget(fakewidget.New(widgetapi.Options{})))
+				if err != nil {
+					return nil, err
+				}
+				return c, c.OpenOverlay("dialog",
+					[]Option{PlaceWidget(fakewidget.New(widgetapi.Options{}))},
+					OverlaySize(10, 4),
+					OverlayBackdrop(BackdropClear),
+				)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(5, 3, 15, 7)), &widgetapi.Meta{Focused: true}, widgetapi.Options{})
+				return ft
+			},
+		},
+		{
+			desc:     "dim backdrop draws the layout in the dim color",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				c, err := New(ft, PlaceWidget(fakewidget.New(widgetapi.Options{})))
+				if err != nil {
+					return nil, err
+				}
+				return c, c.OpenOverlay("dialog",
+					[]Option{PlaceWidget(fakewidget.New(widgetapi.Options{}))},
+					OverlaySize(10, 4),
+					OverlayDimColor(cell.ColorRed),
+				)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				dt := &dimTerminal{Terminal: ft, color: cell.ColorRed}
+				fakewidget.MustDraw(dt, testcanvas.MustNew(ft.Area()), &widgetapi.Meta{}, widgetapi.Options{})
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(5, 3, 15, 7)), &widgetapi.Meta{Focused: true}, widgetapi.Options{})
+				return ft
+			},
+		},
+		{
+			desc:     "overlays stack in the order they were opened",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				c, err := New(ft)
+				if err != nil {
+					return nil, err
+				}
+				if err := c.OpenOverlay("first",
+					[]Option{PlaceWidget(fakewidget.New(widgetapi.Options{}))},
+					OverlaySize(10, 6),
+					OverlayAlign(align.HorizontalLeft, align.VerticalTop),
+					OverlayBackdrop(BackdropNone),
+				); err != nil {
+					return nil, err
+				}
+				return c, c.OpenOverlay("second",
+					[]Option{PlaceWidget(fakewidget.New(widgetapi.Options{}))},
+					OverlaySize(10, 6),
+					OverlayAlign(align.HorizontalRight, align.VerticalBottom),
+					OverlayDimColor(cell.ColorRed),
+				)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				dt := &dimTerminal{Terminal: ft, color: cell.ColorRed}
+				fakewidget.MustDraw(dt, testcanvas.MustNew(image.Rect(0, 0, 10, 6)), &widgetapi.Meta{}, widgetapi.Options{})
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(10, 4, 20, 10)), &widgetapi.Meta{Focused: true}, widgetapi.Options{})
+				return ft
+			},
+		},
+		{
+			desc:     "keyboard events only reach the overlay",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				c, err := New(ft, PlaceWidget(fakewidget.New(keyOpts)))
+				if err != nil {
+					return nil, err
+				}
+				return c, c.OpenOverlay("dialog",
+					[]Option{PlaceWidget(fakewidget.New(keyOpts))},
+					OverlaySize(10, 4),
+					OverlayBackdrop(BackdropNone),
+				)
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(ft.Area()), &widgetapi.Meta{}, keyOpts)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(5, 3, 15, 7)), &widgetapi.Meta{Focused: true}, keyOpts,
+					&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+				)
+				return ft
+			},
+		},
+		{
+			desc:     "mouse events only reach the overlay",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				c, err := New(ft, PlaceWidget(fakewidget.New(mouseOpts)))
+				if err != nil {
+					return nil, err
+				}
+				return c, c.OpenOverlay("dialog",
+					[]Option{PlaceWidget(fakewidget.New(mouseOpts))},
+					OverlaySize(10, 4),
+					OverlayBackdrop(BackdropNone),
+				)
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{6, 4}, Button: mouse.ButtonLeft},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(ft.Area()), &widgetapi.Meta{}, mouseOpts)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(5, 3, 15, 7)), &widgetapi.Meta{Focused: true}, mouseOpts,
+					&terminalapi.Mouse{Position: image.Point{0, 0}, Button: mouse.ButtonLeft},
+				)
+				return ft
+			},
+		},
+		{
+			desc:     "Esc closes the overlay and isn't forwarded to widgets",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				c, err := New(ft, PlaceWidget(fakewidget.New(keyOpts)))
+				if err != nil {
+					return nil, err
+				}
+				return c, c.OpenOverlay("dialog",
+					[]Option{PlaceWidget(fakewidget.New(keyOpts))},
+					OverlaySize(10, 4),
+				)
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyEsc},
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(ft.Area()), &widgetapi.Meta{Focused: true}, keyOpts,
+					&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+				)
+				return ft
+			},
+		},
+		{
+			desc:     "Esc closes only the overlay on the top",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				c, err := New(ft)
+				if err != nil {
+					return nil, err
+				}
+				if err := c.OpenOverlay("first",
+					[]Option{PlaceWidget(fakewidget.New(widgetapi.Options{}))},
+					OverlaySize(10, 4),
+				); err != nil {
+					return nil, err
+				}
+				return c, c.OpenOverlay("second", nil, OverlaySize(4, 2))
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyEsc},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(5, 3, 15, 7)), &widgetapi.Meta{Focused: true}, widgetapi.Options{})
+				return ft
+			},
+		},
+		{
+			desc:     "Esc doesn't close overlay with OverlayKeepOnEsc",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				c, err := New(ft, PlaceWidget(fakewidget.New(keyOpts)))
+				if err != nil {
+					return nil, err
+				}
+				return c, c.OpenOverlay("dialog", nil,
+					OverlaySize(10, 4),
+					OverlayBackdrop(BackdropClear),
+					OverlayKeepOnEsc(),
+				)
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyEsc},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+		},
+		{
+			desc:     "CloseOverlay returns the focus",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				c, err := New(ft,
+					SplitVertical(
+						Left(ID("left")),
+						Right(PlaceWidget(fakewidget.New(widgetapi.Options{}))),
+					),
+				)
+				if err != nil {
+					return nil, err
+				}
+				if err := c.Draw(); err != nil {
+					return nil, err
+				}
+				// Focus the right container.
+				if err := c.processEvent(&terminalapi.Mouse{Position: image.Point{15, 5}, Button: mouse.ButtonLeft}); err != nil {
+					return nil, err
+				}
+				if err := c.processEvent(&terminalapi.Mouse{Position: image.Point{15, 5}, Button: mouse.ButtonRelease}); err != nil {
+					return nil, err
+				}
+				if err := c.OpenOverlay("dialog", nil); err != nil {
+					return nil, err
+				}
+				return c, c.CloseOverlay("dialog")
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(10, 0, 20, 10)), &widgetapi.Meta{Focused: true}, widgetapi.Options{})
+				return ft
+			},
+		},
+		{
+			desc:     "Update finds containers in overlays",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				c, err := New(ft)
+				if err != nil {
+					return nil, err
+				}
+				if err := c.OpenOverlay("dialog", []Option{ID("content")},
+					OverlaySize(10, 4),
+					OverlayBackdrop(BackdropNone),
+				); err != nil {
+					return nil, err
+				}
+				return c, c.Update("content", PlaceWidget(fakewidget.New(widgetapi.Options{})))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(5, 3, 15, 7)), &widgetapi.Meta{Focused: true}, widgetapi.Options{})
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := faketerm.New(tc.termSize)
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+
+			c, err := tc.container(got)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("tc.container => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			eds := event.NewDistributionSystem()
+			eh := &errorHandler{}
+			// Subscribe to receive errors.
+			eds.Subscribe([]terminalapi.Event{terminalapi.NewError("")}, func(ev terminalapi.Event) {
+				eh.handle(ev.(*terminalapi.Error).Error())
+			})
+
+			c.Subscribe(eds)
+			// Initial draw to determine sizes of containers.
+			if err := c.Draw(); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+			for _, ev := range tc.events {
+				eds.Event(ev)
+			}
+
+			if err := testevent.WaitFor(5*time.Second, func() error {
+				if got, want := eds.Processed(), len(tc.events); got != want {
+					return fmt.Errorf("the event distribution system processed %d events, want %d", got, want)
+				}
+				return nil
+			}); err != nil {
+				t.Fatalf("testevent.WaitFor => %v", err)
+			}
+
+			if err := c.Draw(); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+
+			if diff := faketerm.Diff(tc.want(tc.termSize), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+
+			if err := eh.get(); err != nil {
+				t.Errorf("errorHandler => unexpected error %v", err)
+			}
+		})
+	}
+}
+
+// closeCounter counts calls to the OverlayOnClose function.
+type closeCounter struct {
+	mu    sync.Mutex
+	count int
+}
+
+// onClose implements the function provided to OverlayOnClose.
+func (cc *closeCounter) onClose() {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	cc.count++
+}
+
+// get returns the number of calls.
+func (cc *closeCounter) get() int {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	return cc.count
+}
+
+func TestCloseOverlay(t *testing.T) {
+	ft, err := faketerm.New(image.Point{20, 10})
+	if err != nil {
+		t.Fatalf("faketerm.New => unexpected error: %v", err)
+	}
+	c, err := New(ft)
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+
+	if err := c.CloseOverlay("dialog"); err == nil {
+		t.Errorf("CloseOverlay => got nil error, want an error for overlay that isn't open")
+	}
+
+	cc := &closeCounter{}
+	if err := c.OpenOverlay("dialog", nil, OverlayOnClose(cc.onClose)); err != nil {
+		t.Fatalf("OpenOverlay => unexpected error: %v", err)
+	}
+	if err := c.CloseOverlay("dialog"); err != nil {
+		t.Fatalf("CloseOverlay => unexpected error: %v", err)
+	}
+	if got, want := cc.get(), 1; got != want {
+		t.Errorf("after CloseOverlay the OnClose function was called %d times, want %d", got, want)
+	}
+
+	if err := c.OpenOverlay("dialog", nil, OverlayOnClose(cc.onClose)); err != nil {
+		t.Fatalf("OpenOverlay => unexpected error: %v", err)
+	}
+	if err := c.processEvent(&terminalapi.Keyboard{Key: keyboard.KeyEsc}); err != nil {
+		t.Fatalf("processEvent => unexpected error: %v", err)
+	}
+	if got, want := cc.get(), 2; got != want {
+		t.Errorf("after the Esc key the OnClose function was called %d times, want %d", got, want)
+	}
+	if err := c.CloseOverlay("dialog"); err == nil {
+		t.Errorf("CloseOverlay => got nil error, want an error for overlay closed by the Esc key")
+	}
+}
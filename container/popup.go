@@ -0,0 +1,162 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+// popup.go contains code that draws popups of widgets and routes events to
+// them.
+
+import (
+	"errors"
+	"image"
+
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// popupPlacement returns the area of a popup of the provided size for a
+// widget whose canvas is at the widget area. The popup is placed below the
+// widget or above it if there is more space there and it doesn't fit below.
+// The popup is shrunk to fit the terminal area.
+func popupPlacement(termAr, widgetAr image.Rectangle, size image.Point) image.Rectangle {
+	width := size.X
+	if width > termAr.Dx() {
+		width = termAr.Dx()
+	}
+	x := widgetAr.Min.X
+	if x+width > termAr.Max.X {
+		x = termAr.Max.X - width
+	}
+
+	below := termAr.Max.Y - widgetAr.Max.Y
+	above := widgetAr.Min.Y - termAr.Min.Y
+	height := size.Y
+	if height <= below || below >= above {
+		if height > below {
+			height = below
+		}
+		return image.Rect(x, widgetAr.Max.Y, x+width, widgetAr.Max.Y+height)
+	}
+
+	if height > above {
+		height = above
+	}
+	return image.Rect(x, widgetAr.Min.Y-height, x+width, widgetAr.Min.Y)
+}
+
+// drawPopups draws the popups of widgets in the tree starting at the provided
+// root container onto the terminal.
+func drawPopups(root *Container, term terminalapi.Terminal) error {
+	size := term.Size()
+	termAr := image.Rect(0, 0, size.X, size.Y)
+
+	var errStr string
+	preOrder(root, &errStr, visitFunc(func(c *Container) error {
+		c.popupArea = image.ZR
+		if !c.hasWidget() {
+			return nil
+		}
+		p, ok := c.opts.widget.(widgetapi.Popup)
+		if !ok {
+			return nil
+		}
+		popupSize := p.PopupSize()
+		if popupSize.X <= 0 || popupSize.Y <= 0 {
+			return nil
+		}
+		wa, err := c.widgetArea()
+		if err != nil {
+			return err
+		}
+		if wa.Empty() {
+			return nil
+		}
+
+		ar := popupPlacement(termAr, wa, popupSize)
+		if ar.Empty() {
+			return nil
+		}
+		cvs, err := canvas.New(ar)
+		if err != nil {
+			return err
+		}
+		meta := &widgetapi.Meta{
+			Focused: c.focusTracker.isActive(c),
+		}
+		if err := p.DrawPopup(cvs, meta); err != nil {
+			return err
+		}
+		if err := cvs.Apply(term); err != nil {
+			return err
+		}
+		c.popupArea = ar
+		return nil
+	}))
+	if errStr != "" {
+		return errors.New(errStr)
+	}
+	return nil
+}
+
+// clearPopups blanks the areas where popups were drawn on the last redraw, so
+// that closed popups don't remain visible above the containers that don't
+// redraw all of their cells.
+func clearPopups(root *Container) error {
+	var errStr string
+	preOrderAll(root, &errStr, visitFunc(func(c *Container) error {
+		if c.popupArea.Empty() {
+			return nil
+		}
+		cvs, err := canvas.New(c.popupArea)
+		if err != nil {
+			return err
+		}
+		return cvs.Apply(c.term)
+	}))
+	if errStr != "" {
+		return errors.New(errStr)
+	}
+	return nil
+}
+
+// popupForMouse returns a function that delivers the mouse event to the widget
+// whose popup the event falls onto. Returns nil if the event doesn't fall
+// onto any popups.
+// Caller must hold c.mu.
+func popupForMouse(c *Container, m *terminalapi.Mouse) func() error {
+	var (
+		errStr string
+		target *Container
+	)
+	preOrder(c, &errStr, visitFunc(func(cur *Container) error {
+		// Popups drawn later are above those drawn earlier.
+		if m.Position.In(cur.popupArea) {
+			target = cur
+		}
+		return nil
+	}))
+	if target == nil {
+		return nil
+	}
+
+	p, ok := target.opts.widget.(widgetapi.Popup)
+	if !ok {
+		return nil
+	}
+	ev := adjustMouseEv(m, target.popupArea)
+	return func() error {
+		return p.PopupMouse(ev)
+	}
+}
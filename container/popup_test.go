@@ -0,0 +1,368 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"fmt"
+	"image"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/canvas/testcanvas"
+	"github.com/mum4k/termdash/internal/event"
+	"github.com/mum4k/termdash/internal/event/testevent"
+	"github.com/mum4k/termdash/internal/faketerm"
+	"github.com/mum4k/termdash/internal/fakewidget"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+func TestPopupPlacement(t *testing.T) {
+	tests := []struct {
+		desc     string
+		termAr   image.Rectangle
+		widgetAr image.Rectangle
+		size     image.Point
+		want     image.Rectangle
+	}{
+		{
+			desc:     "places the popup below the widget",
+			termAr:   image.Rect(0, 0, 20, 10),
+			widgetAr: image.Rect(2, 1, 8, 2),
+			size:     image.Point{10, 4},
+			want:     image.Rect(2, 2, 12, 6),
+		},
+		{
+			desc:     "places the popup above the widget when it doesn't fit below",
+			termAr:   image.Rect(0, 0, 20, 10),
+			widgetAr: image.Rect(2, 7, 8, 8),
+			size:     image.Point{10, 4},
+			want:     image.Rect(2, 3, 12, 7),
+		},
+		{
+			desc:     "shrinks the popup below the widget when there is more space there",
+			termAr:   image.Rect(0, 0, 20, 10),
+			widgetAr: image.Rect(2, 4, 8, 5),
+			size:     image.Point{10, 8},
+			want:     image.Rect(2, 5, 12, 10),
+		},
+		{
+			desc:     "shrinks the popup above the widget when there is more space there",
+			termAr:   image.Rect(0, 0, 20, 10),
+			widgetAr: image.Rect(2, 6, 8, 7),
+			size:     image.Point{10, 8},
+			want:     image.Rect(2, 0, 12, 6),
+		},
+		{
+			desc:     "shifts the popup left to fit the terminal",
+			termAr:   image.Rect(0, 0, 20, 10),
+			widgetAr: image.Rect(15, 1, 20, 2),
+			size:     image.Point{10, 4},
+			want:     image.Rect(10, 2, 20, 6),
+		},
+		{
+			desc:     "shrinks the popup wider than the terminal",
+			termAr:   image.Rect(0, 0, 20, 10),
+			widgetAr: image.Rect(15, 1, 20, 2),
+			size:     image.Point{30, 4},
+			want:     image.Rect(0, 2, 20, 6),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := popupPlacement(tc.termAr, tc.widgetAr, tc.size)
+			if got != tc.want {
+				t.Errorf("popupPlacement => %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// popupWidget is a fake widget with a popup.
+// Both the widget and its popup are drawn by fake widgets.
+type popupWidget struct {
+	*fakewidget.Mirror
+
+	// popup draws the popup.
+	popup *fakewidget.Mirror
+
+	// size is the size of the popup.
+	size image.Point
+	// mu protects size.
+	mu sync.Mutex
+}
+
+// newPopupWidget returns a new popupWidget with a popup of the provided size.
+func newPopupWidget(opts widgetapi.Options, size image.Point) *popupWidget {
+	return &popupWidget{
+		Mirror: fakewidget.New(opts),
+		popup:  fakewidget.New(widgetapi.Options{WantMouse: widgetapi.MouseScopeWidget}),
+		size:   size,
+	}
+}
+
+// setSize sets the size of the popup.
+func (pw *popupWidget) setSize(size image.Point) {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+	pw.size = size
+}
+
+// PopupSize implements widgetapi.Popup.PopupSize.
+func (pw *popupWidget) PopupSize() image.Point {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+	return pw.size
+}
+
+// DrawPopup implements widgetapi.Popup.DrawPopup.
+func (pw *popupWidget) DrawPopup(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	return pw.popup.Draw(cvs, meta)
+}
+
+// PopupMouse implements widgetapi.Popup.PopupMouse.
+func (pw *popupWidget) PopupMouse(m *terminalapi.Mouse) error {
+	return pw.popup.Mouse(m)
+}
+
+func TestPopup(t *testing.T) {
+	mouseOpts := widgetapi.Options{WantMouse: widgetapi.MouseScopeWidget}
+	popupOpts := widgetapi.Options{WantMouse: widgetapi.MouseScopeWidget}
+
+	tests := []struct {
+		desc      string
+		termSize  image.Point
+		popupSize image.Point
+		container func(ft *faketerm.Terminal, pw *popupWidget) (*Container, error)
+		events    []terminalapi.Event
+		// closePopup when true, closes the popup before the last draw.
+		closePopup bool
+		want       func(size image.Point) *faketerm.Terminal
+	}{
+		{
+			desc:      "draws the popup below the widget above other containers",
+			termSize:  image.Point{20, 10},
+			popupSize: image.Point{10, 4},
+			container: func(ft *faketerm.Terminal, pw *popupWidget) (*Container, error) {
+				return New(ft,
+					SplitHorizontal(
+						Top(PlaceWidget(pw)),
+						Bottom(PlaceWidget(fakewidget.New(mouseOpts))),
+						SplitPercent(30),
+					),
+				)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(0, 0, 20, 3)), &widgetapi.Meta{}, mouseOpts)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(0, 3, 20, 10)), &widgetapi.Meta{}, mouseOpts)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(0, 3, 10, 7)), &widgetapi.Meta{}, popupOpts)
+				return ft
+			},
+		},
+		{
+			desc:      "draws the popup above the widget when it doesn't fit below",
+			termSize:  image.Point{20, 10},
+			popupSize: image.Point{10, 4},
+			container: func(ft *faketerm.Terminal, pw *popupWidget) (*Container, error) {
+				return New(ft,
+					SplitHorizontal(
+						Top(),
+						Bottom(PlaceWidget(pw)),
+						SplitPercent(70),
+					),
+				)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(0, 7, 20, 10)), &widgetapi.Meta{}, mouseOpts)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(0, 3, 10, 7)), &widgetapi.Meta{}, popupOpts)
+				return ft
+			},
+		},
+		{
+			desc:      "shrinks the popup to fit the terminal",
+			termSize:  image.Point{20, 10},
+			popupSize: image.Point{30, 12},
+			container: func(ft *faketerm.Terminal, pw *popupWidget) (*Container, error) {
+				return New(ft,
+					SplitHorizontal(
+						Top(PlaceWidget(pw)),
+						Bottom(),
+						SplitPercent(30),
+					),
+				)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(0, 0, 20, 3)), &widgetapi.Meta{}, mouseOpts)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(0, 3, 20, 10)), &widgetapi.Meta{}, popupOpts)
+				return ft
+			},
+		},
+		{
+			desc:      "doesn't draw a popup of zero size",
+			termSize:  image.Point{20, 10},
+			popupSize: image.ZP,
+			container: func(ft *faketerm.Terminal, pw *popupWidget) (*Container, error) {
+				return New(ft,
+					SplitHorizontal(
+						Top(PlaceWidget(pw)),
+						Bottom(),
+						SplitPercent(30),
+					),
+				)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(0, 0, 20, 3)), &widgetapi.Meta{}, mouseOpts)
+				return ft
+			},
+		},
+		{
+			desc:      "mouse events on the popup only reach the popup and don't move focus",
+			termSize:  image.Point{20, 10},
+			popupSize: image.Point{10, 4},
+			container: func(ft *faketerm.Terminal, pw *popupWidget) (*Container, error) {
+				return New(ft,
+					SplitHorizontal(
+						Top(PlaceWidget(pw)),
+						Bottom(PlaceWidget(fakewidget.New(mouseOpts))),
+						SplitPercent(30),
+					),
+				)
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{2, 4}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{2, 4}, Button: mouse.ButtonRelease},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(0, 0, 20, 3)), &widgetapi.Meta{}, mouseOpts)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(0, 3, 20, 10)), &widgetapi.Meta{}, mouseOpts)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(0, 3, 10, 7)), &widgetapi.Meta{}, popupOpts,
+					&terminalapi.Mouse{Position: image.Point{2, 1}, Button: mouse.ButtonRelease},
+				)
+				return ft
+			},
+		},
+		{
+			desc:      "mouse events outside of the popup reach the widgets",
+			termSize:  image.Point{20, 10},
+			popupSize: image.Point{10, 4},
+			container: func(ft *faketerm.Terminal, pw *popupWidget) (*Container, error) {
+				return New(ft,
+					SplitHorizontal(
+						Top(PlaceWidget(pw)),
+						Bottom(PlaceWidget(fakewidget.New(mouseOpts))),
+						SplitPercent(30),
+					),
+				)
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{5, 8}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{5, 8}, Button: mouse.ButtonRelease},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(0, 0, 20, 3)), &widgetapi.Meta{}, mouseOpts)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(0, 3, 20, 10)), &widgetapi.Meta{Focused: true}, mouseOpts,
+					&terminalapi.Mouse{Position: image.Point{5, 5}, Button: mouse.ButtonRelease},
+				)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(0, 3, 10, 7)), &widgetapi.Meta{}, popupOpts)
+				return ft
+			},
+		},
+		{
+			desc:      "blanks the area of a closed popup",
+			termSize:  image.Point{20, 10},
+			popupSize: image.Point{10, 4},
+			container: func(ft *faketerm.Terminal, pw *popupWidget) (*Container, error) {
+				return New(ft,
+					SplitHorizontal(
+						Top(PlaceWidget(pw)),
+						Bottom(),
+						SplitPercent(30),
+					),
+				)
+			},
+			closePopup: true,
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(0, 0, 20, 3)), &widgetapi.Meta{}, mouseOpts)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := faketerm.New(tc.termSize)
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+
+			pw := newPopupWidget(mouseOpts, tc.popupSize)
+			c, err := tc.container(got, pw)
+			if err != nil {
+				t.Fatalf("tc.container => unexpected error: %v", err)
+			}
+
+			eds := event.NewDistributionSystem()
+			eh := &errorHandler{}
+			// Subscribe to receive errors.
+			eds.Subscribe([]terminalapi.Event{terminalapi.NewError("")}, func(ev terminalapi.Event) {
+				eh.handle(ev.(*terminalapi.Error).Error())
+			})
+
+			c.Subscribe(eds)
+			// Initial draw to determine sizes of containers and popups.
+			if err := c.Draw(); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+			for _, ev := range tc.events {
+				eds.Event(ev)
+			}
+
+			if err := testevent.WaitFor(5*time.Second, func() error {
+				if got, want := eds.Processed(), len(tc.events); got != want {
+					return fmt.Errorf("the event distribution system processed %d events, want %d", got, want)
+				}
+				return nil
+			}); err != nil {
+				t.Fatalf("testevent.WaitFor => %v", err)
+			}
+
+			if tc.closePopup {
+				pw.setSize(image.ZP)
+			}
+			if err := c.Draw(); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+
+			if diff := faketerm.Diff(tc.want(tc.termSize), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+
+			if err := eh.get(); err != nil {
+				t.Errorf("errorHandler => unexpected error %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,344 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+// tabs.go contains code for containers that display several pages (layouts)
+// switched by a tab bar.
+
+import (
+	"errors"
+	"fmt"
+	"image"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/draw"
+	"github.com/mum4k/termdash/internal/runewidth"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+// tabs stores the pages of a container created with the Tabs option.
+type tabs struct {
+	// labels are the labels of the pages displayed in the tab bar.
+	labels []string
+	// pages are the root containers of the individual pages.
+	pages []*Container
+	// active is the index of the currently displayed page.
+	active int
+
+	// keys are the keyboard keys that activate the pages, keys[i] activates
+	// pages[i].
+	keys []keyboard.Key
+
+	// Colors of the labels in the tab bar.
+	activeColor   cell.Color
+	inactiveColor cell.Color
+
+	// labelAreas are the areas of the labels on the terminal, updated each
+	// time the tab bar is drawn.
+	labelAreas []image.Rectangle
+}
+
+// newTabs returns a new tabs instance with the default values.
+func newTabs() *tabs {
+	return &tabs{
+		activeColor:   DefaultTabActiveColor,
+		inactiveColor: DefaultTabInactiveColor,
+	}
+}
+
+// tabBarHeight is the height of the tab bar in cells.
+const tabBarHeight = 1
+
+// tabBarArea returns the area of the tab bar within the provided usable area
+// of the container.
+func tabBarArea(ar image.Rectangle) image.Rectangle {
+	if ar.Dy() < tabBarHeight {
+		return ar
+	}
+	return image.Rect(ar.Min.X, ar.Min.Y, ar.Max.X, ar.Min.Y+tabBarHeight)
+}
+
+// tabPageArea returns the area available to the active page within the
+// provided usable area of the container.
+func tabPageArea(ar image.Rectangle) image.Rectangle {
+	if ar.Dy() < tabBarHeight {
+		return image.Rect(ar.Min.X, ar.Max.Y, ar.Max.X, ar.Max.Y)
+	}
+	return image.Rect(ar.Min.X, ar.Min.Y+tabBarHeight, ar.Max.X, ar.Max.Y)
+}
+
+// activateTab displays the page with the provided index.
+// Caller must hold c.mu.
+func (c *Container) activateTab(i int) error {
+	if min, max := 0, len(c.tabs.pages); i < min || i >= max {
+		return fmt.Errorf("invalid tab index %d, must be in range %d <= i < %d", i, min, max)
+	}
+	c.tabs.active = i
+	c.first = c.tabs.pages[i]
+	c.second = nil
+
+	root := rootCont(c)
+	root.clearNeeded = true
+	// The focused container might have been on the page that is now hidden.
+	if !c.focusTracker.reachableFrom(root) {
+		c.focusTracker.setActive(c)
+	}
+	return nil
+}
+
+// tabForKey returns a function that activates a tab if the keyboard event
+// matches one of the keys configured for tabs of containers that are
+// currently displayed. Returns nil if the event doesn't match any key.
+// Caller must hold c.mu.
+func tabForKey(root *Container, k *terminalapi.Keyboard) func() error {
+	var (
+		errStr string
+		fn     func() error
+	)
+	preOrder(root, &errStr, visitFunc(func(c *Container) error {
+		if c.tabs == nil || fn != nil {
+			return nil
+		}
+		for i, key := range c.tabs.keys {
+			if key == k.Key {
+				cont, idx := c, i
+				fn = func() error {
+					return cont.activateTab(idx)
+				}
+				return nil
+			}
+		}
+		return nil
+	}))
+	return fn
+}
+
+// tabForMouse returns a function that activates a tab if the mouse event is
+// a click on one of the labels in a tab bar of containers that are currently
+// displayed. Returns nil if the event didn't click on any label.
+// Caller must hold c.mu.
+func tabForMouse(root *Container, m *terminalapi.Mouse) func() error {
+	if m.Button != mouse.ButtonLeft {
+		return nil
+	}
+
+	var (
+		errStr string
+		fn     func() error
+	)
+	preOrder(root, &errStr, visitFunc(func(c *Container) error {
+		if c.tabs == nil || fn != nil {
+			return nil
+		}
+		for i, ar := range c.tabs.labelAreas {
+			if m.Position.In(ar) {
+				cont, idx := c, i
+				fn = func() error {
+					return cont.activateTab(idx)
+				}
+				return nil
+			}
+		}
+		return nil
+	}))
+	return fn
+}
+
+// drawTabBar draws the tab bar of the container if it has tabs.
+func drawTabBar(c *Container, term terminalapi.Terminal) error {
+	if c.tabs == nil {
+		return nil
+	}
+	c.tabs.labelAreas = nil
+
+	padded, err := c.opts.padding.apply(c.usable())
+	if err != nil {
+		return err
+	}
+	barAr := tabBarArea(padded)
+	if barAr.Dx() <= 0 || barAr.Dy() <= 0 {
+		return nil
+	}
+
+	cvs, err := canvas.New(barAr)
+	if err != nil {
+		return err
+	}
+
+	x := 0
+	for i, label := range c.tabs.labels {
+		if x >= cvs.Area().Dx() {
+			break
+		}
+
+		color := c.tabs.inactiveColor
+		if i == c.tabs.active {
+			color = c.tabs.activeColor
+		}
+		text := fmt.Sprintf(" %s ", label)
+		width := runewidth.StringWidth(text)
+		maxX := x + width
+		if maxX > cvs.Area().Dx() {
+			maxX = cvs.Area().Dx()
+		}
+		if err := draw.Text(cvs, text, image.Point{x, 0},
+			draw.TextCellOpts(cell.BgColor(color)),
+			draw.TextMaxX(maxX),
+			draw.TextOverrunMode(draw.OverrunModeThreeDot),
+		); err != nil {
+			return err
+		}
+
+		c.tabs.labelAreas = append(c.tabs.labelAreas, image.Rect(
+			barAr.Min.X+x, barAr.Min.Y,
+			barAr.Min.X+maxX, barAr.Max.Y,
+		))
+		// One empty cell between the labels.
+		x = maxX + 1
+	}
+	return cvs.Apply(term)
+}
+
+// TabOption is used to provide a page to the Tabs option.
+type TabOption interface {
+	// tabOpts returns the label and the options of the page.
+	tabOpts() (string, []Option)
+}
+
+// tabOption implements TabOption.
+type tabOption func() (string, []Option)
+
+// tabOpts implements TabOption.tabOpts.
+func (to tabOption) tabOpts() (string, []Option) {
+	return to()
+}
+
+// Tab creates a page with the provided label for the Tabs option. The options
+// are applied to the root container of the page, which can contain a widget or
+// be further split.
+func Tab(label string, opts ...Option) TabOption {
+	return tabOption(func() (string, []Option) {
+		return label, opts
+	})
+}
+
+// TabsOption is used to configure the tab bar when using the Tabs option.
+type TabsOption interface {
+	// setTabs sets the provided option.
+	setTabs(*tabs) error
+}
+
+// tabsOption implements TabsOption.
+type tabsOption func(*tabs) error
+
+// setTabs implements TabsOption.setTabs.
+func (to tabsOption) setTabs(t *tabs) error {
+	return to(t)
+}
+
+// TabKeys sets keyboard keys that activate the pages, the key at index i
+// activates the page at index i. Fewer keys than pages can be provided.
+// The keys are processed regardless of the focused container and aren't
+// forwarded to widgets. Prefer keys widgets don't use, e.g.
+// keyboard.KeyF1 through keyboard.KeyF9.
+func TabKeys(keys ...keyboard.Key) TabsOption {
+	return tabsOption(func(t *tabs) error {
+		t.keys = keys
+		return nil
+	})
+}
+
+// DefaultTabActiveColor is the default value for the TabActiveColor option.
+const DefaultTabActiveColor = cell.ColorBlue
+
+// TabActiveColor sets the background color of the label of the active page.
+func TabActiveColor(color cell.Color) TabsOption {
+	return tabsOption(func(t *tabs) error {
+		t.activeColor = color
+		return nil
+	})
+}
+
+// DefaultTabInactiveColor is the default value for the TabInactiveColor
+// option.
+const DefaultTabInactiveColor = cell.ColorDefault
+
+// TabInactiveColor sets the background color of the labels of the inactive
+// pages.
+func TabInactiveColor(color cell.Color) TabsOption {
+	return tabsOption(func(t *tabs) error {
+		t.inactiveColor = color
+		return nil
+	})
+}
+
+// Tabs turns the container into a container with several pages, only the
+// active page is displayed below a tab bar that contains the labels of all
+// the pages. At least one page must be provided and the first page is
+// initially active.
+//
+// The pages are switched by clicking on their labels with the left mouse
+// button, by the keys configured with the TabKeys option or by calling Update
+// with the ActiveTab option. Widgets on the inactive pages keep their state,
+// but aren't drawn and don't receive any events.
+//
+// The use of this option removes any widget placed at this container and any
+// sub containers.
+func Tabs(pages []TabOption, opts ...TabsOption) Option {
+	return option(func(c *Container) error {
+		if len(pages) == 0 {
+			return errors.New("the Tabs option requires at least one page")
+		}
+
+		t := newTabs()
+		for _, opt := range opts {
+			if err := opt.setTabs(t); err != nil {
+				return err
+			}
+		}
+		if len(t.keys) > len(pages) {
+			return fmt.Errorf("got %d TabKeys, but only %d pages", len(t.keys), len(pages))
+		}
+
+		for _, p := range pages {
+			label, pOpts := p.tabOpts()
+			page, err := newChild(c, pOpts)
+			if err != nil {
+				return err
+			}
+			t.labels = append(t.labels, label)
+			t.pages = append(t.pages, page)
+		}
+
+		c.opts.widget = nil
+		c.tabs = t
+		return c.activateTab(0)
+	})
+}
+
+// ActiveTab activates the page with the provided index in a container created
+// with the Tabs option. This is meant to be used with Container.Update to
+// switch pages programmatically.
+func ActiveTab(i int) Option {
+	return option(func(c *Container) error {
+		if c.tabs == nil {
+			return errors.New("the ActiveTab option requires a container created with the Tabs option")
+		}
+		return c.activateTab(i)
+	})
+}
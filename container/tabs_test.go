@@ -0,0 +1,342 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"fmt"
+	"image"
+	"testing"
+	"time"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/canvas/testcanvas"
+	"github.com/mum4k/termdash/internal/draw"
+	"github.com/mum4k/termdash/internal/draw/testdraw"
+	"github.com/mum4k/termdash/internal/event"
+	"github.com/mum4k/termdash/internal/event/testevent"
+	"github.com/mum4k/termdash/internal/faketerm"
+	"github.com/mum4k/termdash/internal/fakewidget"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// mustDrawTabBar draws a tab bar with the provided labels into the first row
+// of the terminal or panics.
+func mustDrawTabBar(ft *faketerm.Terminal, active int, labels ...string) {
+	cvs := testcanvas.MustNew(image.Rect(0, 0, ft.Area().Dx(), 1))
+	x := 0
+	for i, l := range labels {
+		color := DefaultTabInactiveColor
+		if i == active {
+			color = DefaultTabActiveColor
+		}
+		text := fmt.Sprintf(" %s ", l)
+		testdraw.MustText(cvs, text, image.Point{x, 0},
+			draw.TextCellOpts(cell.BgColor(color)),
+			draw.TextMaxX(x+len(text)),
+			draw.TextOverrunMode(draw.OverrunModeThreeDot),
+		)
+		x += len(text) + 1
+	}
+	testcanvas.MustApply(cvs, ft)
+}
+
+func TestTabs(t *testing.T) {
+	keyOpts := widgetapi.Options{WantKeyboard: widgetapi.KeyScopeGlobal}
+
+	tests := []struct {
+		desc      string
+		termSize  image.Point
+		container func(ft *faketerm.Terminal) (*Container, error)
+		events    []terminalapi.Event
+		want      func(size image.Point) *faketerm.Terminal
+		wantErr   bool
+	}{
+		{
+			desc:     "fails without pages",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Tabs(nil))
+			},
+			wantErr: true,
+		},
+		{
+			desc:     "fails with more keys than pages",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Tabs(
+					[]TabOption{Tab("A")},
+					TabKeys(keyboard.KeyF1, keyboard.KeyF2),
+				))
+			},
+			wantErr: true,
+		},
+		{
+			desc:     "fails on ActiveTab without tabs",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, ActiveTab(0))
+			},
+			wantErr: true,
+		},
+		{
+			desc:     "fails on ActiveTab out of range",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Tabs([]TabOption{Tab("A")}), ActiveTab(1))
+			},
+			wantErr: true,
+		},
+		{
+			desc:     "fails on duplicate IDs on inactive pages",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Tabs([]TabOption{
+					Tab("A"),
+					Tab("B", ID("page")),
+					Tab("C", ID("page")),
+				}))
+			},
+			wantErr: true,
+		},
+		{
+			desc:     "draws the tab bar and the first page only",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Tabs([]TabOption{
+					Tab("A", PlaceWidget(fakewidget.New(widgetapi.Options{}))),
+					Tab("B", PlaceWidget(fakewidget.New(widgetapi.Options{}))),
+				}))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				mustDrawTabBar(ft, 0, "A", "B")
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(0, 1, 20, 10)), &widgetapi.Meta{}, widgetapi.Options{})
+				return ft
+			},
+		},
+		{
+			desc:     "clicking a label switches the page",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Tabs([]TabOption{
+					Tab("A", PlaceWidget(fakewidget.New(keyOpts))),
+					Tab("B", PlaceWidget(fakewidget.New(keyOpts))),
+				}))
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+				&terminalapi.Mouse{Position: image.Point{5, 0}, Button: mouse.ButtonLeft},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				mustDrawTabBar(ft, 1, "A", "B")
+				// The widget on the first page got the key, this one didn't.
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(0, 1, 20, 10)), &widgetapi.Meta{}, keyOpts)
+				return ft
+			},
+		},
+		{
+			desc:     "clicks outside of the labels don't switch the page",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Tabs([]TabOption{
+					Tab("A", PlaceWidget(fakewidget.New(widgetapi.Options{}))),
+					Tab("B", PlaceWidget(fakewidget.New(widgetapi.Options{}))),
+				}))
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{15, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{5, 0}, Button: mouse.ButtonRight},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				mustDrawTabBar(ft, 0, "A", "B")
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(0, 1, 20, 10)), &widgetapi.Meta{}, widgetapi.Options{})
+				return ft
+			},
+		},
+		{
+			desc:     "keys switch pages and aren't forwarded to widgets, pages keep widget state",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Tabs(
+					[]TabOption{
+						Tab("A", PlaceWidget(fakewidget.New(keyOpts))),
+						Tab("B", PlaceWidget(fakewidget.New(keyOpts))),
+					},
+					TabKeys(keyboard.KeyF1, keyboard.KeyF2),
+				))
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+				&terminalapi.Keyboard{Key: keyboard.KeyF2},
+				&terminalapi.Keyboard{Key: keyboard.KeyTab},
+				&terminalapi.Keyboard{Key: keyboard.KeyF1},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				mustDrawTabBar(ft, 0, "A", "B")
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(0, 1, 20, 10)), &widgetapi.Meta{}, keyOpts,
+					&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+				)
+				return ft
+			},
+		},
+		{
+			desc:     "Update with ActiveTab switches the page",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				c, err := New(ft, ID("tabs"), Tabs([]TabOption{
+					Tab("A", PlaceWidget(fakewidget.New(widgetapi.Options{}))),
+					Tab("B"),
+				}))
+				if err != nil {
+					return nil, err
+				}
+				return c, c.Update("tabs", ActiveTab(1))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				mustDrawTabBar(ft, 1, "A", "B")
+				return ft
+			},
+		},
+		{
+			desc:     "Update finds containers on inactive pages",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				c, err := New(ft, ID("tabs"), Tabs([]TabOption{
+					Tab("A"),
+					Tab("B", ID("second")),
+				}))
+				if err != nil {
+					return nil, err
+				}
+				if err := c.Update("second", PlaceWidget(fakewidget.New(widgetapi.Options{}))); err != nil {
+					return nil, err
+				}
+				return c, c.Update("tabs", ActiveTab(1))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				mustDrawTabBar(ft, 1, "A", "B")
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(0, 1, 20, 10)), &widgetapi.Meta{}, widgetapi.Options{})
+				return ft
+			},
+		},
+		{
+			desc:     "focus moves to the tabs container when the focused page is hidden",
+			termSize: image.Point{20, 10},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Tabs([]TabOption{
+					Tab("A", PlaceWidget(fakewidget.New(keyOpts))),
+					Tab("B", PlaceWidget(fakewidget.New(widgetapi.Options{}))),
+				}))
+			},
+			events: []terminalapi.Event{
+				// Focus the first page.
+				&terminalapi.Mouse{Position: image.Point{5, 5}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{5, 5}, Button: mouse.ButtonRelease},
+				// Switch to the second page.
+				&terminalapi.Mouse{Position: image.Point{5, 0}, Button: mouse.ButtonLeft},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				mustDrawTabBar(ft, 1, "A", "B")
+				fakewidget.MustDraw(ft, testcanvas.MustNew(image.Rect(0, 1, 20, 10)), &widgetapi.Meta{}, widgetapi.Options{})
+				return ft
+			},
+		},
+		{
+			desc:     "trims labels that don't fit",
+			termSize: image.Point{8, 5},
+			container: func(ft *faketerm.Terminal) (*Container, error) {
+				return New(ft, Tabs([]TabOption{
+					Tab("A"),
+					Tab("Long"),
+				}))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(image.Rect(0, 0, 8, 1))
+				testdraw.MustText(cvs, " A ", image.Point{0, 0}, draw.TextCellOpts(cell.BgColor(DefaultTabActiveColor)))
+				testdraw.MustText(cvs, " Long ", image.Point{4, 0},
+					draw.TextCellOpts(cell.BgColor(DefaultTabInactiveColor)),
+					draw.TextMaxX(8),
+					draw.TextOverrunMode(draw.OverrunModeThreeDot),
+				)
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := faketerm.New(tc.termSize)
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+
+			c, err := tc.container(got)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("tc.container => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			eds := event.NewDistributionSystem()
+			eh := &errorHandler{}
+			// Subscribe to receive errors.
+			eds.Subscribe([]terminalapi.Event{terminalapi.NewError("")}, func(ev terminalapi.Event) {
+				eh.handle(ev.(*terminalapi.Error).Error())
+			})
+
+			c.Subscribe(eds)
+			// Initial draw to determine sizes of containers.
+			if err := c.Draw(); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+			for _, ev := range tc.events {
+				eds.Event(ev)
+			}
+
+			if err := testevent.WaitFor(5*time.Second, func() error {
+				if got, want := eds.Processed(), len(tc.events); got != want {
+					return fmt.Errorf("the event distribution system processed %d events, want %d", got, want)
+				}
+				return nil
+			}); err != nil {
+				t.Fatalf("testevent.WaitFor => %v", err)
+			}
+
+			if err := c.Draw(); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+
+			if diff := faketerm.Diff(tc.want(tc.termSize), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+
+			if err := eh.get(); err != nil {
+				t.Errorf("errorHandler => unexpected error %v", err)
+			}
+		})
+	}
+}
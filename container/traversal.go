@@ -48,6 +48,31 @@ func preOrder(c *Container, errStr *string, visit visitFunc) {
 	preOrder(c.second, errStr, visit)
 }
 
+// preOrderAll performs pre-order DFS traversal on the container tree
+// including the pages of containers with tabs that aren't currently active
+// and the trees of any open overlays.
+func preOrderAll(c *Container, errStr *string, visit visitFunc) {
+	if c == nil || *errStr != "" {
+		return
+	}
+
+	if err := visit(c); err != nil {
+		*errStr = err.Error()
+		return
+	}
+	for _, o := range c.overlays {
+		preOrderAll(o.cont, errStr, visit)
+	}
+	if c.tabs != nil {
+		for _, p := range c.tabs.pages {
+			preOrderAll(p, errStr, visit)
+		}
+		return
+	}
+	preOrderAll(c.first, errStr, visit)
+	preOrderAll(c.second, errStr, visit)
+}
+
 // postOrder performs post-order DFS traversal on the container tree.
 func postOrder(c *Container, errStr *string, visit visitFunc) {
 	if c == nil || *errStr != "" {
@@ -73,7 +98,7 @@ func findID(root *Container, id string) (*Container, error) {
 		errStr string
 		cont   *Container
 	)
-	preOrder(root, &errStr, visitFunc(func(c *Container) error {
+	preOrderAll(root, &errStr, visitFunc(func(c *Container) error {
 		if c.opts.id == id {
 			cont = c
 		}
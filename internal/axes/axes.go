@@ -12,7 +12,8 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package axes calculates the required layout and draws the X and Y axes of a line chart.
+// Package axes calculates the required layout and draws the X and Y axes of a
+// line chart or a scatter plot.
 package axes
 
 import (
@@ -23,6 +23,7 @@ import (
 
 	"github.com/mum4k/termdash/cell"
 	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/markup"
 	"github.com/mum4k/termdash/internal/runewidth"
 )
 
@@ -150,6 +151,13 @@ func TrimText(text string, maxCells int, om OverrunMode) (string, error) {
 
 // Text prints the provided text on the canvas starting at the provided point.
 func Text(c *canvas.Canvas, text string, start image.Point, opts ...TextOption) error {
+	return TextChunks(c, markup.Plain(text), start, opts...)
+}
+
+// TextChunks is like Text, but prints multiple chunks of text, each with its
+// own cell options. The cell options of each chunk are applied on top of any
+// options provided via TextCellOpts.
+func TextChunks(c *canvas.Canvas, chunks []*markup.Chunk, start image.Point, opts ...TextOption) error {
 	ar := c.Area()
 	if !start.In(ar) {
 		return fmt.Errorf("the requested start point %v falls outside of the provided canvas %v", start, ar)
@@ -171,15 +179,26 @@ func Text(c *canvas.Canvas, text string, start image.Point, opts ...TextOption)
 		wantMaxX = opt.maxX
 	}
 
+	// The cell options for each rune of the text.
+	var runeOpts [][]cell.Option
+	for _, ch := range chunks {
+		chOpts := append(append([]cell.Option(nil), opt.cellOpts...), ch.Opts...)
+		for range ch.Text {
+			runeOpts = append(runeOpts, chOpts)
+		}
+	}
+
 	maxCells := wantMaxX - start.X
-	trimmed, err := TrimText(text, maxCells, opt.overrunMode)
+	trimmed, err := TrimText(markup.Text(chunks), maxCells, opt.overrunMode)
 	if err != nil {
 		return err
 	}
 
 	cur := start
-	for _, r := range trimmed {
-		cells, err := c.SetCell(cur, r, opt.cellOpts...)
+	// The trimmed text is a prefix of the original text, except for the last
+	// rune that can be replaced by the overrun character.
+	for i, r := range []rune(trimmed) {
+		cells, err := c.SetCell(cur, r, runeOpts[i]...)
 		if err != nil {
 			return err
 		}
@@ -22,6 +22,7 @@ import (
 	"github.com/mum4k/termdash/internal/canvas"
 	"github.com/mum4k/termdash/internal/canvas/testcanvas"
 	"github.com/mum4k/termdash/internal/faketerm"
+	"github.com/mum4k/termdash/internal/markup"
 )
 
 func TestTrimText(t *testing.T) {
@@ -602,6 +603,106 @@ func TestText(t *testing.T) {
 	}
 }
 
+func TestTextChunks(t *testing.T) {
+	tests := []struct {
+		desc    string
+		canvas  image.Rectangle
+		chunks  []*markup.Chunk
+		start   image.Point
+		opts    []TextOption
+		want    func(size image.Point) *faketerm.Terminal
+		wantErr bool
+	}{
+		{
+			desc:   "start falls outside of the canvas",
+			canvas: image.Rect(0, 0, 2, 2),
+			start:  image.Point{2, 2},
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantErr: true,
+		},
+		{
+			desc:   "draws chunks with their options applied on top of the provided ones",
+			canvas: image.Rect(0, 0, 10, 1),
+			chunks: []*markup.Chunk{
+				{Text: "ab", Opts: []cell.Option{cell.FgColor(cell.ColorRed)}},
+				{Text: "cd"},
+				{Text: "ef", Opts: []cell.Option{cell.Bold()}},
+			},
+			start: image.Point{1, 0},
+			opts: []TextOption{
+				TextCellOpts(cell.FgColor(cell.ColorBlue)),
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testcanvas.MustSetCell(c, image.Point{1, 0}, 'a', cell.FgColor(cell.ColorRed))
+				testcanvas.MustSetCell(c, image.Point{2, 0}, 'b', cell.FgColor(cell.ColorRed))
+				testcanvas.MustSetCell(c, image.Point{3, 0}, 'c', cell.FgColor(cell.ColorBlue))
+				testcanvas.MustSetCell(c, image.Point{4, 0}, 'd', cell.FgColor(cell.ColorBlue))
+				testcanvas.MustSetCell(c, image.Point{5, 0}, 'e', cell.FgColor(cell.ColorBlue), cell.Bold())
+				testcanvas.MustSetCell(c, image.Point{6, 0}, 'f', cell.FgColor(cell.ColorBlue), cell.Bold())
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "trims chunks with full-width runes",
+			canvas: image.Rect(0, 0, 4, 1),
+			chunks: []*markup.Chunk{
+				{Text: "a", Opts: []cell.Option{cell.FgColor(cell.ColorRed)}},
+				{Text: "你好", Opts: []cell.Option{cell.FgColor(cell.ColorGreen)}},
+			},
+			start: image.Point{0, 0},
+			opts: []TextOption{
+				TextOverrunMode(OverrunModeThreeDot),
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testcanvas.MustSetCell(c, image.Point{0, 0}, 'a', cell.FgColor(cell.ColorRed))
+				testcanvas.MustSetCell(c, image.Point{1, 0}, '你', cell.FgColor(cell.ColorGreen))
+				testcanvas.MustSetCell(c, image.Point{3, 0}, '…', cell.FgColor(cell.ColorGreen))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+
+			err = TextChunks(c, tc.chunks, tc.start, tc.opts...)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("TextChunks => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("TextChunks => %v", diff)
+			}
+		})
+	}
+}
+
 func TestResizeNeeded(t *testing.T) {
 	tests := []struct {
 		desc   string
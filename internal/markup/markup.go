@@ -0,0 +1,249 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package markup parses text with inline style markup into chunks of text that
+share the same cell options.
+
+The markup consists of tags enclosed in square brackets. An opening tag
+contains a comma separated list of attributes that apply to the text that
+follows it. The closing tag "[/]" ends the most recently opened tag. Tags can
+be nested, the attributes of a nested tag apply on top of the attributes of
+the enclosing tags. Tags that are still open at the end of the text apply
+until its end.
+
+  [fg=red,bold]ERROR[/] disk full
+
+The supported attributes are:
+  fg=<color>   sets the foreground color.
+  bg=<color>   sets the background color.
+  bold         makes the text bold.
+  underline    underlines the text.
+  inverse      swaps the foreground and background colors.
+
+A color is either one of the names default, black, red, green, yellow, blue,
+magenta, cyan, white, a color number in the range 0-255 (see cell.ColorNumber)
+or a web color in the format #rrggbb (see cell.ColorRGB24).
+
+A literal opening square bracket is written as "[[". A closing square bracket
+outside of a tag doesn't need to be escaped.
+*/
+package markup
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mum4k/termdash/cell"
+)
+
+// Chunk is a part of the text that shares the same cell options.
+type Chunk struct {
+	// Text is the text of the chunk with all the markup removed.
+	Text string
+
+	// Opts are the cell options set by the markup for this chunk.
+	// These are meant to be applied on top of any options the caller uses
+	// when drawing the text.
+	Opts []cell.Option
+}
+
+// String implements fmt.Stringer.
+func (c *Chunk) String() string {
+	return fmt.Sprintf("%q%+v", c.Text, *cell.NewOptions(c.Opts...))
+}
+
+// Text returns the text of all the chunks with the markup removed.
+func Text(chunks []*Chunk) string {
+	var b strings.Builder
+	for _, c := range chunks {
+		b.WriteString(c.Text)
+	}
+	return b.String()
+}
+
+// Plain returns the text as a single chunk without interpreting any markup.
+// Returns no chunks for an empty text.
+func Plain(text string) []*Chunk {
+	if text == "" {
+		return nil
+	}
+	return []*Chunk{{Text: text}}
+}
+
+// Parse parses the provided markup into chunks of text.
+// Returns an error if the markup is invalid.
+func Parse(markup string) ([]*Chunk, error) {
+	p := &parser{}
+	runes := []rune(markup)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r != '[' {
+			p.text.WriteRune(r)
+			continue
+		}
+
+		if i+1 < len(runes) && runes[i+1] == '[' {
+			p.text.WriteRune('[')
+			i++
+			continue
+		}
+
+		end := i + 1
+		for end < len(runes) && runes[end] != ']' {
+			end++
+		}
+		if end == len(runes) {
+			return nil, fmt.Errorf("invalid markup %q, the tag starting at position %d isn't closed by ']'", markup, i)
+		}
+
+		if err := p.tag(string(runes[i+1 : end])); err != nil {
+			return nil, fmt.Errorf("invalid markup %q, tag at position %d: %v", markup, i, err)
+		}
+		i = end
+	}
+	p.flush()
+	return p.chunks, nil
+}
+
+// parser holds the state of the markup parsing.
+type parser struct {
+	// chunks are the chunks parsed so far.
+	chunks []*Chunk
+	// text is the text of the current chunk.
+	text strings.Builder
+	// stack contains the options of the currently open tags.
+	// Each entry contains all the options that apply to the text, i.e.
+	// including the options of the enclosing tags.
+	stack [][]cell.Option
+}
+
+// current returns the options that apply to the current text.
+func (p *parser) current() []cell.Option {
+	if len(p.stack) == 0 {
+		return nil
+	}
+	return p.stack[len(p.stack)-1]
+}
+
+// flush finishes the current chunk.
+func (p *parser) flush() {
+	if p.text.Len() == 0 {
+		return
+	}
+	p.chunks = append(p.chunks, &Chunk{
+		Text: p.text.String(),
+		Opts: p.current(),
+	})
+	p.text.Reset()
+}
+
+// tag processes the content of a tag, i.e. the text between the brackets.
+func (p *parser) tag(content string) error {
+	p.flush()
+	if content == "/" {
+		if len(p.stack) == 0 {
+			return errors.New("the closing tag doesn't have a matching opening tag")
+		}
+		p.stack = p.stack[:len(p.stack)-1]
+		return nil
+	}
+
+	opts := append([]cell.Option(nil), p.current()...)
+	for _, attr := range strings.Split(content, ",") {
+		o, err := parseAttr(strings.TrimSpace(attr))
+		if err != nil {
+			return err
+		}
+		opts = append(opts, o)
+	}
+	p.stack = append(p.stack, opts)
+	return nil
+}
+
+// parseAttr parses a single attribute of a tag.
+func parseAttr(attr string) (cell.Option, error) {
+	kv := strings.SplitN(attr, "=", 2)
+	if len(kv) == 2 {
+		key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		c, err := parseColor(value)
+		if err != nil {
+			return nil, err
+		}
+		switch key {
+		case "fg":
+			return cell.FgColor(c), nil
+		case "bg":
+			return cell.BgColor(c), nil
+		default:
+			return nil, fmt.Errorf("unknown attribute %q, the supported attributes with a value are fg and bg", key)
+		}
+	}
+
+	switch attr {
+	case "":
+		return nil, errors.New("the attributes cannot be empty")
+	case "bold":
+		return cell.Bold(), nil
+	case "underline":
+		return cell.Underline(), nil
+	case "inverse":
+		return cell.Inverse(), nil
+	default:
+		return nil, fmt.Errorf("unknown attribute %q, the supported attributes without a value are bold, underline and inverse", attr)
+	}
+}
+
+// colorNames maps the names of the colors to their values.
+var colorNames = map[string]cell.Color{
+	"default": cell.ColorDefault,
+	"black":   cell.ColorBlack,
+	"red":     cell.ColorRed,
+	"green":   cell.ColorGreen,
+	"yellow":  cell.ColorYellow,
+	"blue":    cell.ColorBlue,
+	"magenta": cell.ColorMagenta,
+	"cyan":    cell.ColorCyan,
+	"white":   cell.ColorWhite,
+}
+
+// parseColor parses the value of a color attribute.
+func parseColor(value string) (cell.Color, error) {
+	if c, ok := colorNames[value]; ok {
+		return c, nil
+	}
+
+	if strings.HasPrefix(value, "#") {
+		hex := value[1:]
+		if len(hex) != 6 {
+			return 0, fmt.Errorf("invalid web color %q, must be in the format #rrggbb", value)
+		}
+		rgb, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil {
+			return 0, fmt.Errorf("invalid web color %q, must be in the format #rrggbb: %v", value, err)
+		}
+		return cell.ColorRGB24(int(rgb>>16), int(rgb>>8&0xff), int(rgb&0xff)), nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid color %q, must be a color name, a number or a web color in the format #rrggbb", value)
+	}
+	if min, max := 0, 255; n < min || n > max {
+		return 0, fmt.Errorf("invalid color number %d, must be in range %d <= n <= %d", n, min, max)
+	}
+	return cell.ColorNumber(n), nil
+}
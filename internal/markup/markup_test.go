@@ -0,0 +1,192 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package markup
+
+import (
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+)
+
+// resolvedChunk is a chunk with its options resolved for comparison.
+type resolvedChunk struct {
+	Text string
+	Opts *cell.Options
+}
+
+// resolve resolves the options of the chunks.
+func resolve(chunks []*Chunk) []*resolvedChunk {
+	var res []*resolvedChunk
+	for _, c := range chunks {
+		res = append(res, &resolvedChunk{
+			Text: c.Text,
+			Opts: cell.NewOptions(c.Opts...),
+		})
+	}
+	return res
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		desc    string
+		markup  string
+		want    []*resolvedChunk
+		wantErr bool
+	}{
+		{
+			desc:   "empty markup",
+			markup: "",
+		},
+		{
+			desc:   "text without tags",
+			markup: "hello world",
+			want: []*resolvedChunk{
+				{Text: "hello world", Opts: cell.NewOptions()},
+			},
+		},
+		{
+			desc:   "single tag",
+			markup: "[fg=red,bold]ERROR[/] disk full",
+			want: []*resolvedChunk{
+				{Text: "ERROR", Opts: cell.NewOptions(cell.FgColor(cell.ColorRed), cell.Bold())},
+				{Text: " disk full", Opts: cell.NewOptions()},
+			},
+		},
+		{
+			desc:   "all attributes",
+			markup: "[fg=blue, bg=white, bold, underline, inverse]x",
+			want: []*resolvedChunk{
+				{
+					Text: "x",
+					Opts: cell.NewOptions(
+						cell.FgColor(cell.ColorBlue),
+						cell.BgColor(cell.ColorWhite),
+						cell.Bold(),
+						cell.Underline(),
+						cell.Inverse(),
+					),
+				},
+			},
+		},
+		{
+			desc:   "color numbers and web colors",
+			markup: "[fg=123]a[/][bg=#ff0000]b",
+			want: []*resolvedChunk{
+				{Text: "a", Opts: cell.NewOptions(cell.FgColor(cell.ColorNumber(123)))},
+				{Text: "b", Opts: cell.NewOptions(cell.BgColor(cell.ColorRGB24(255, 0, 0)))},
+			},
+		},
+		{
+			desc:   "nested tags apply on top of the enclosing ones",
+			markup: "[fg=red]a[bold]b[fg=green]c[/]d[/]e[/]f",
+			want: []*resolvedChunk{
+				{Text: "a", Opts: cell.NewOptions(cell.FgColor(cell.ColorRed))},
+				{Text: "b", Opts: cell.NewOptions(cell.FgColor(cell.ColorRed), cell.Bold())},
+				{Text: "c", Opts: cell.NewOptions(cell.FgColor(cell.ColorGreen), cell.Bold())},
+				{Text: "d", Opts: cell.NewOptions(cell.FgColor(cell.ColorRed), cell.Bold())},
+				{Text: "e", Opts: cell.NewOptions(cell.FgColor(cell.ColorRed))},
+				{Text: "f", Opts: cell.NewOptions()},
+			},
+		},
+		{
+			desc:   "escaped opening bracket",
+			markup: "[[x] [fg=red][[y]",
+			want: []*resolvedChunk{
+				{Text: "[x] ", Opts: cell.NewOptions()},
+				{Text: "[y]", Opts: cell.NewOptions(cell.FgColor(cell.ColorRed))},
+			},
+		},
+		{
+			desc:   "full-width runes",
+			markup: "你[bold]好",
+			want: []*resolvedChunk{
+				{Text: "你", Opts: cell.NewOptions()},
+				{Text: "好", Opts: cell.NewOptions(cell.Bold())},
+			},
+		},
+		{
+			desc:    "fails on unterminated tag",
+			markup:  "[fg=red hello",
+			wantErr: true,
+		},
+		{
+			desc:    "fails on empty tag",
+			markup:  "[]hello",
+			wantErr: true,
+		},
+		{
+			desc:    "fails on empty attribute",
+			markup:  "[bold,]hello",
+			wantErr: true,
+		},
+		{
+			desc:    "fails on unknown attribute",
+			markup:  "[italic]hello",
+			wantErr: true,
+		},
+		{
+			desc:    "fails on unknown attribute with value",
+			markup:  "[color=red]hello",
+			wantErr: true,
+		},
+		{
+			desc:    "fails on unknown color name",
+			markup:  "[fg=purple]hello",
+			wantErr: true,
+		},
+		{
+			desc:    "fails on color number out of range",
+			markup:  "[fg=256]hello",
+			wantErr: true,
+		},
+		{
+			desc:    "fails on invalid web color",
+			markup:  "[fg=#ff00]hello",
+			wantErr: true,
+		},
+		{
+			desc:    "fails on closing tag without opening tag",
+			markup:  "hello[/]",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := Parse(tc.markup)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Parse => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if diff := pretty.Compare(tc.want, resolve(got)); diff != "" {
+				t.Errorf("Parse => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestText(t *testing.T) {
+	chunks, err := Parse("[fg=red]ERROR[/] disk [[full]")
+	if err != nil {
+		t.Fatalf("Parse => unexpected error: %v", err)
+	}
+	if got, want := Text(chunks), "ERROR disk [full]"; got != want {
+		t.Errorf("Text => %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,387 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package dotmatrix simulates a 5x7 dot-matrix display drawn on a canvas.
+
+Given a canvas, determines the placement and size of the individual dots and
+exposes API that can turn individual dots on and off or display ASCII
+characters.
+
+The dots are addressed by their column and row, the top left dot is at
+image.Point{0, 0} and the bottom right dot is at image.Point{Cols-1, Rows-1}.
+*/
+package dotmatrix
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strings"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/area"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/canvas/braille"
+)
+
+// Dimensions of the matrix of dots.
+const (
+	// Cols is the number of dot columns in the display.
+	Cols = 5
+	// Rows is the number of dot rows in the display.
+	Rows = 7
+)
+
+// characterDots maps characters that can be displayed to the columns of dots
+// that must be set in order to display the character.
+// Each byte is one column of dots, left to right, the least significant bit
+// is the top row.
+var characterDots = map[rune][Cols]byte{
+	' ':  {0x00, 0x00, 0x00, 0x00, 0x00},
+	'!':  {0x00, 0x00, 0x5f, 0x00, 0x00},
+	'"':  {0x00, 0x07, 0x00, 0x07, 0x00},
+	'#':  {0x14, 0x7f, 0x14, 0x7f, 0x14},
+	'$':  {0x24, 0x2a, 0x7f, 0x2a, 0x12},
+	'%':  {0x23, 0x13, 0x08, 0x64, 0x62},
+	'&':  {0x36, 0x49, 0x55, 0x22, 0x50},
+	'\'': {0x00, 0x05, 0x03, 0x00, 0x00},
+	'(':  {0x00, 0x1c, 0x22, 0x41, 0x00},
+	')':  {0x00, 0x41, 0x22, 0x1c, 0x00},
+	'*':  {0x14, 0x08, 0x3e, 0x08, 0x14},
+	'+':  {0x08, 0x08, 0x3e, 0x08, 0x08},
+	',':  {0x00, 0x50, 0x30, 0x00, 0x00},
+	'-':  {0x08, 0x08, 0x08, 0x08, 0x08},
+	'.':  {0x00, 0x60, 0x60, 0x00, 0x00},
+	'/':  {0x20, 0x10, 0x08, 0x04, 0x02},
+	'0':  {0x3e, 0x51, 0x49, 0x45, 0x3e},
+	'1':  {0x00, 0x42, 0x7f, 0x40, 0x00},
+	'2':  {0x42, 0x61, 0x51, 0x49, 0x46},
+	'3':  {0x21, 0x41, 0x45, 0x4b, 0x31},
+	'4':  {0x18, 0x14, 0x12, 0x7f, 0x10},
+	'5':  {0x27, 0x45, 0x45, 0x45, 0x39},
+	'6':  {0x3c, 0x4a, 0x49, 0x49, 0x30},
+	'7':  {0x01, 0x71, 0x09, 0x05, 0x03},
+	'8':  {0x36, 0x49, 0x49, 0x49, 0x36},
+	'9':  {0x06, 0x49, 0x49, 0x29, 0x1e},
+	':':  {0x00, 0x36, 0x36, 0x00, 0x00},
+	';':  {0x00, 0x56, 0x36, 0x00, 0x00},
+	'<':  {0x08, 0x14, 0x22, 0x41, 0x00},
+	'=':  {0x14, 0x14, 0x14, 0x14, 0x14},
+	'>':  {0x00, 0x41, 0x22, 0x14, 0x08},
+	'?':  {0x02, 0x01, 0x51, 0x09, 0x06},
+	'@':  {0x32, 0x49, 0x79, 0x41, 0x3e},
+	'A':  {0x7e, 0x11, 0x11, 0x11, 0x7e},
+	'B':  {0x7f, 0x49, 0x49, 0x49, 0x36},
+	'C':  {0x3e, 0x41, 0x41, 0x41, 0x22},
+	'D':  {0x7f, 0x41, 0x41, 0x22, 0x1c},
+	'E':  {0x7f, 0x49, 0x49, 0x49, 0x41},
+	'F':  {0x7f, 0x09, 0x09, 0x09, 0x01},
+	'G':  {0x3e, 0x41, 0x49, 0x49, 0x7a},
+	'H':  {0x7f, 0x08, 0x08, 0x08, 0x7f},
+	'I':  {0x00, 0x41, 0x7f, 0x41, 0x00},
+	'J':  {0x20, 0x40, 0x41, 0x3f, 0x01},
+	'K':  {0x7f, 0x08, 0x14, 0x22, 0x41},
+	'L':  {0x7f, 0x40, 0x40, 0x40, 0x40},
+	'M':  {0x7f, 0x02, 0x0c, 0x02, 0x7f},
+	'N':  {0x7f, 0x04, 0x08, 0x10, 0x7f},
+	'O':  {0x3e, 0x41, 0x41, 0x41, 0x3e},
+	'P':  {0x7f, 0x09, 0x09, 0x09, 0x06},
+	'Q':  {0x3e, 0x41, 0x51, 0x21, 0x5e},
+	'R':  {0x7f, 0x09, 0x19, 0x29, 0x46},
+	'S':  {0x46, 0x49, 0x49, 0x49, 0x31},
+	'T':  {0x01, 0x01, 0x7f, 0x01, 0x01},
+	'U':  {0x3f, 0x40, 0x40, 0x40, 0x3f},
+	'V':  {0x1f, 0x20, 0x40, 0x20, 0x1f},
+	'W':  {0x3f, 0x40, 0x38, 0x40, 0x3f},
+	'X':  {0x63, 0x14, 0x08, 0x14, 0x63},
+	'Y':  {0x07, 0x08, 0x70, 0x08, 0x07},
+	'Z':  {0x61, 0x51, 0x49, 0x45, 0x43},
+	'[':  {0x00, 0x7f, 0x41, 0x41, 0x00},
+	'\\': {0x02, 0x04, 0x08, 0x10, 0x20},
+	']':  {0x00, 0x41, 0x41, 0x7f, 0x00},
+	'^':  {0x04, 0x02, 0x01, 0x02, 0x04},
+	'_':  {0x40, 0x40, 0x40, 0x40, 0x40},
+	'`':  {0x00, 0x01, 0x02, 0x04, 0x00},
+	'a':  {0x20, 0x54, 0x54, 0x54, 0x78},
+	'b':  {0x7f, 0x48, 0x44, 0x44, 0x38},
+	'c':  {0x38, 0x44, 0x44, 0x44, 0x20},
+	'd':  {0x38, 0x44, 0x44, 0x48, 0x7f},
+	'e':  {0x38, 0x54, 0x54, 0x54, 0x18},
+	'f':  {0x08, 0x7e, 0x09, 0x01, 0x02},
+	'g':  {0x0c, 0x52, 0x52, 0x52, 0x3e},
+	'h':  {0x7f, 0x08, 0x04, 0x04, 0x78},
+	'i':  {0x00, 0x44, 0x7d, 0x40, 0x00},
+	'j':  {0x20, 0x40, 0x44, 0x3d, 0x00},
+	'k':  {0x7f, 0x10, 0x28, 0x44, 0x00},
+	'l':  {0x00, 0x41, 0x7f, 0x40, 0x00},
+	'm':  {0x7c, 0x04, 0x18, 0x04, 0x78},
+	'n':  {0x7c, 0x08, 0x04, 0x04, 0x78},
+	'o':  {0x38, 0x44, 0x44, 0x44, 0x38},
+	'p':  {0x7c, 0x14, 0x14, 0x14, 0x08},
+	'q':  {0x08, 0x14, 0x14, 0x18, 0x7c},
+	'r':  {0x7c, 0x08, 0x04, 0x04, 0x08},
+	's':  {0x48, 0x54, 0x54, 0x54, 0x20},
+	't':  {0x04, 0x3f, 0x44, 0x40, 0x20},
+	'u':  {0x3c, 0x40, 0x40, 0x20, 0x7c},
+	'v':  {0x1c, 0x20, 0x40, 0x20, 0x1c},
+	'w':  {0x3c, 0x40, 0x30, 0x40, 0x3c},
+	'x':  {0x44, 0x28, 0x10, 0x28, 0x44},
+	'y':  {0x0c, 0x50, 0x50, 0x50, 0x3c},
+	'z':  {0x44, 0x64, 0x54, 0x4c, 0x44},
+	'{':  {0x00, 0x08, 0x36, 0x41, 0x00},
+	'|':  {0x00, 0x00, 0x7f, 0x00, 0x00},
+	'}':  {0x00, 0x41, 0x36, 0x08, 0x00},
+	'~':  {0x02, 0x01, 0x02, 0x04, 0x02},
+}
+
+// SupportsChars asserts whether the display supports all runes in the
+// provided string.
+// The display only supports a subset of ASCII characters.
+// Returns any unsupported runes found in the string in an unspecified order.
+func SupportsChars(s string) (bool, []rune) {
+	unsupp := map[rune]bool{}
+	for _, r := range s {
+		if _, ok := characterDots[r]; !ok {
+			unsupp[r] = true
+		}
+	}
+
+	var res []rune
+	for r := range unsupp {
+		res = append(res, r)
+	}
+	return len(res) == 0, res
+}
+
+// Sanitize returns a copy of the string, replacing all unsupported characters
+// with a space character.
+func Sanitize(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if _, ok := characterDots[r]; !ok {
+			b.WriteRune(' ')
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*Display)
+}
+
+// option implements Option.
+type option func(*Display)
+
+// set implements Option.set.
+func (o option) set(d *Display) {
+	o(d)
+}
+
+// CellOpts sets the cell options on the cells that contain the dots.
+func CellOpts(cOpts ...cell.Option) Option {
+	return option(func(d *Display) {
+		d.cellOpts = cOpts
+	})
+}
+
+// Display represents the dot-matrix display.
+type Display struct {
+	// dots maps dots to their state, true if the dot is set.
+	dots map[image.Point]bool
+
+	cellOpts []cell.Option
+}
+
+// New creates a new dot-matrix display.
+func New(opts ...Option) *Display {
+	d := &Display{
+		dots: map[image.Point]bool{},
+	}
+
+	for _, opt := range opts {
+		opt.set(d)
+	}
+	return d
+}
+
+// Clear clears the entire display, turning all dots off.
+func (d *Display) Clear(opts ...Option) {
+	for _, opt := range opts {
+		opt.set(d)
+	}
+
+	d.dots = map[image.Point]bool{}
+}
+
+// validateDot returns an error if the point doesn't identify a dot.
+func validateDot(p image.Point) error {
+	if p.X < 0 || p.X >= Cols || p.Y < 0 || p.Y >= Rows {
+		return fmt.Errorf("invalid dot %v, must be in range 0 <= X < %d and 0 <= Y < %d", p, Cols, Rows)
+	}
+	return nil
+}
+
+// SetDot sets the specified dot on.
+// This method is idempotent.
+func (d *Display) SetDot(p image.Point) error {
+	if err := validateDot(p); err != nil {
+		return err
+	}
+	d.dots[p] = true
+	return nil
+}
+
+// ClearDot sets the specified dot off.
+// This method is idempotent.
+func (d *Display) ClearDot(p image.Point) error {
+	if err := validateDot(p); err != nil {
+		return err
+	}
+	d.dots[p] = false
+	return nil
+}
+
+// ToggleDot toggles the state of the specified dot, i.e. it either sets or
+// clears it depending on its current state.
+func (d *Display) ToggleDot(p image.Point) error {
+	if err := validateDot(p); err != nil {
+		return err
+	}
+	d.dots[p] = !d.dots[p]
+	return nil
+}
+
+// SetCharacter sets dots that are needed to display the provided character.
+// For supported characters see SupportsChars.
+// Doesn't clear the display of dots set previously.
+func (d *Display) SetCharacter(c rune) error {
+	cols, ok := characterDots[c]
+	if !ok {
+		return fmt.Errorf("display doesn't support character %q rune(%v)", c, c)
+	}
+
+	for x, col := range cols {
+		for y := 0; y < Rows; y++ {
+			if col&(1<<uint(y)) == 0 {
+				continue
+			}
+			if err := d.SetDot(image.Point{x, y}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Minimum valid size of a cell canvas in order to draw the dot-matrix display.
+const (
+	// MinCols is the smallest valid amount of columns in a cell area.
+	MinCols = 5
+	// MinRows is the smallest valid amount of rows in a cell area.
+	MinRows = 4
+)
+
+// aspectRatio is the desired aspect ratio of a single dot-matrix display.
+var aspectRatio = image.Point{Cols, Rows}
+
+// Draw draws the current state of the dot-matrix display onto the canvas.
+// The canvas must be at least MinCols x MinRows cells, or an error will be
+// returned.
+// Any options provided to draw overwrite the values provided to New.
+func (d *Display) Draw(cvs *canvas.Canvas, opts ...Option) error {
+	for _, o := range opts {
+		o.set(d)
+	}
+
+	bc, bcAr, err := toBraille(cvs)
+	if err != nil {
+		return err
+	}
+
+	// The distance between the starts of two neighbouring dots and the gap
+	// that separates them.
+	pitch := bcAr.Dx() / Cols
+	if p := bcAr.Dy() / Rows; p < pitch {
+		pitch = p
+	}
+	gap := pitch / 4
+	if gap < 1 {
+		gap = 1
+	}
+	dotSize := pitch - gap
+
+	// Center the matrix of dots in the available area.
+	start := image.Point{
+		bcAr.Min.X + (bcAr.Dx()-(Cols*pitch-gap))/2,
+		bcAr.Min.Y + (bcAr.Dy()-(Rows*pitch-gap))/2,
+	}
+	for y := 0; y < Rows; y++ {
+		for x := 0; x < Cols; x++ {
+			if !d.dots[image.Point{x, y}] {
+				continue
+			}
+
+			dotStart := start.Add(image.Point{x * pitch, y * pitch})
+			for py := dotStart.Y; py < dotStart.Y+dotSize; py++ {
+				for px := dotStart.X; px < dotStart.X+dotSize; px++ {
+					if err := bc.SetPixel(image.Point{px, py}, d.cellOpts...); err != nil {
+						return fmt.Errorf("failed to draw dot %v, SetPixel => %v", image.Point{x, y}, err)
+					}
+				}
+			}
+		}
+	}
+	return bc.CopyTo(cvs)
+}
+
+// Required when given an area of cells, returns either an area of the same
+// size or a smaller area that is required to draw one display.
+// Returns a smaller area when the provided area didn't have the required
+// aspect ratio.
+// Returns an error if the area is too small to draw a dot-matrix display, i.e.
+// smaller than MinCols x MinRows.
+func Required(cellArea image.Rectangle) (image.Rectangle, error) {
+	if cols, rows := cellArea.Dx(), cellArea.Dy(); cols < MinCols || rows < MinRows {
+		return image.ZR, fmt.Errorf("cell area %v is too small to draw the dot-matrix display, has %dx%d cells, need at least %dx%d cells",
+			cellArea, cols, rows, MinCols, MinRows)
+	}
+
+	bcAr := image.Rect(0, 0, cellArea.Dx()*braille.ColMult, cellArea.Dy()*braille.RowMult)
+	bcArAdj := area.WithRatio(bcAr, aspectRatio)
+
+	needCols := int(math.Ceil(float64(bcArAdj.Dx()) / braille.ColMult))
+	needRows := int(math.Ceil(float64(bcArAdj.Dy()) / braille.RowMult))
+	needAr := image.Rect(cellArea.Min.X, cellArea.Min.Y, cellArea.Min.X+needCols, cellArea.Min.Y+needRows)
+	return needAr, nil
+}
+
+// toBraille converts the canvas into a braille canvas and returns a pixel area
+// with aspect ratio adjusted for the dot-matrix display.
+func toBraille(cvs *canvas.Canvas) (*braille.Canvas, image.Rectangle, error) {
+	ar, err := Required(cvs.Area())
+	if err != nil {
+		return nil, image.ZR, fmt.Errorf("Required => %v", err)
+	}
+
+	bc, err := braille.New(ar)
+	if err != nil {
+		return nil, image.ZR, fmt.Errorf("braille.New => %v", err)
+	}
+	return bc, area.WithRatio(bc.Area(), aspectRatio), nil
+}
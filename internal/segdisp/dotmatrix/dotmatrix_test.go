@@ -0,0 +1,501 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dotmatrix
+
+import (
+	"image"
+	"sort"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/area"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/canvas/braille/testbraille"
+	"github.com/mum4k/termdash/internal/faketerm"
+)
+
+func TestDraw(t *testing.T) {
+	tests := []struct {
+		desc       string
+		opts       []Option
+		drawOpts   []Option
+		cellCanvas image.Rectangle
+		// If not nil, it is called before Draw is called and can set, clear or
+		// toggle dots or characters.
+		update        func(*Display) error
+		want          func(size image.Point) *faketerm.Terminal
+		wantErr       bool
+		wantUpdateErr bool
+	}{
+		{
+			desc:       "fails for area not wide enough",
+			cellCanvas: image.Rect(0, 0, MinCols-1, MinRows),
+			wantErr:    true,
+		},
+		{
+			desc:       "fails for area not tall enough",
+			cellCanvas: image.Rect(0, 0, MinCols, MinRows-1),
+			wantErr:    true,
+		},
+		{
+			desc:       "fails to set invalid dot (negative)",
+			cellCanvas: image.Rect(0, 0, MinCols, MinRows),
+			update: func(d *Display) error {
+				return d.SetDot(image.Point{-1, 0})
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc:       "fails to set invalid dot (too large)",
+			cellCanvas: image.Rect(0, 0, MinCols, MinRows),
+			update: func(d *Display) error {
+				return d.SetDot(image.Point{0, Rows})
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc:       "fails to clear invalid dot",
+			cellCanvas: image.Rect(0, 0, MinCols, MinRows),
+			update: func(d *Display) error {
+				return d.ClearDot(image.Point{Cols, 0})
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc:       "fails to toggle invalid dot",
+			cellCanvas: image.Rect(0, 0, MinCols, MinRows),
+			update: func(d *Display) error {
+				return d.ToggleDot(image.Point{0, -1})
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc:       "empty when no dots set",
+			cellCanvas: image.Rect(0, 0, MinCols, MinRows),
+		},
+		{
+			desc:       "smallest valid display 5x4, corner dots",
+			cellCanvas: image.Rect(0, 0, MinCols, MinRows),
+			update: func(d *Display) error {
+				for _, p := range []image.Point{
+					{0, 0},
+					{Cols - 1, 0},
+					{0, Rows - 1},
+					{Cols - 1, Rows - 1},
+				} {
+					if err := d.SetDot(p); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				bc := testbraille.MustNew(ft.Area())
+
+				testbraille.MustSetPixel(bc, image.Point{0, 0})
+				testbraille.MustSetPixel(bc, image.Point{8, 0})
+				testbraille.MustSetPixel(bc, image.Point{0, 12})
+				testbraille.MustSetPixel(bc, image.Point{8, 12})
+				testbraille.MustApply(bc, ft)
+				return ft
+			},
+		},
+		{
+			desc: "smallest valid display 5x4, New sets cell options",
+			opts: []Option{
+				CellOpts(
+					cell.FgColor(cell.ColorRed),
+					cell.BgColor(cell.ColorGreen),
+				),
+			},
+			cellCanvas: image.Rect(0, 0, MinCols, MinRows),
+			update: func(d *Display) error {
+				return d.SetDot(image.Point{1, 1})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				bc := testbraille.MustNew(ft.Area())
+
+				testbraille.MustSetPixel(bc, image.Point{2, 2}, cell.FgColor(cell.ColorRed), cell.BgColor(cell.ColorGreen))
+				testbraille.MustApply(bc, ft)
+				return ft
+			},
+		},
+		{
+			desc:       "smallest valid display 5x4, Draw sets cell options",
+			cellCanvas: image.Rect(0, 0, MinCols, MinRows),
+			drawOpts: []Option{
+				CellOpts(
+					cell.FgColor(cell.ColorRed),
+					cell.BgColor(cell.ColorGreen),
+				),
+			},
+			update: func(d *Display) error {
+				return d.SetDot(image.Point{1, 1})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				bc := testbraille.MustNew(ft.Area())
+
+				testbraille.MustSetPixel(bc, image.Point{2, 2}, cell.FgColor(cell.ColorRed), cell.BgColor(cell.ColorGreen))
+				testbraille.MustApply(bc, ft)
+				return ft
+			},
+		},
+		{
+			desc:       "clears and toggles dots",
+			cellCanvas: image.Rect(0, 0, MinCols, MinRows),
+			update: func(d *Display) error {
+				for _, p := range []image.Point{{0, 0}, {1, 1}, {2, 2}} {
+					if err := d.SetDot(p); err != nil {
+						return err
+					}
+				}
+				if err := d.ClearDot(image.Point{1, 1}); err != nil {
+					return err
+				}
+				if err := d.ToggleDot(image.Point{2, 2}); err != nil {
+					return err
+				}
+				return d.ToggleDot(image.Point{3, 3})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				bc := testbraille.MustNew(ft.Area())
+
+				testbraille.MustSetPixel(bc, image.Point{0, 0})
+				testbraille.MustSetPixel(bc, image.Point{6, 6})
+				testbraille.MustApply(bc, ft)
+				return ft
+			},
+		},
+		{
+			desc:       "larger display draws dots as squares with gaps",
+			cellCanvas: image.Rect(0, 0, MinCols*2, MinRows*2),
+			update: func(d *Display) error {
+				return d.SetDot(image.Point{1, 0})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				bc := testbraille.MustNew(ft.Area())
+
+				// Pixel area is 20x28, pitch 4, gap 1 and dot 3.
+				for y := 0; y < 3; y++ {
+					for x := 4; x < 7; x++ {
+						testbraille.MustSetPixel(bc, image.Point{x, y})
+					}
+				}
+				testbraille.MustApply(bc, ft)
+				return ft
+			},
+		},
+		{
+			desc:       "adjusts the area to the aspect ratio",
+			cellCanvas: image.Rect(0, 0, 6, 5),
+			update: func(d *Display) error {
+				return d.SetDot(image.Point{0, 0})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				bc := testbraille.MustNew(ft.Area())
+
+				// Pixel area is 10x14 (adjusted to the aspect ratio), the
+				// matrix is 9x13 pixels.
+				testbraille.MustSetPixel(bc, image.Point{0, 0})
+				testbraille.MustApply(bc, ft)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			d := New(tc.opts...)
+			if tc.update != nil {
+				err := tc.update(d)
+				if (err != nil) != tc.wantUpdateErr {
+					t.Errorf("tc.update => unexpected error: %v, wantUpdateErr: %v", err, tc.wantUpdateErr)
+				}
+				if err != nil {
+					return
+				}
+			}
+
+			cvs, err := canvas.New(tc.cellCanvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+
+			{
+				err := d.Draw(cvs, tc.drawOpts...)
+				if (err != nil) != tc.wantErr {
+					t.Errorf("Draw => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+				}
+				if err != nil {
+					return
+				}
+			}
+
+			size := area.Size(tc.cellCanvas)
+			want := faketerm.MustNew(size)
+			if tc.want != nil {
+				want = tc.want(size)
+			}
+
+			got, err := faketerm.New(size)
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := cvs.Apply(got); err != nil {
+				t.Fatalf("bc.Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(want, got); diff != "" {
+				t.Fatalf("Draw => %v", diff)
+			}
+		})
+	}
+}
+
+// dotsOf returns the dots that are set on the display, sorted by row and
+// column.
+func dotsOf(d *Display) []image.Point {
+	var res []image.Point
+	for p, set := range d.dots {
+		if set {
+			res = append(res, p)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Y != res[j].Y {
+			return res[i].Y < res[j].Y
+		}
+		return res[i].X < res[j].X
+	})
+	return res
+}
+
+func TestSetCharacter(t *testing.T) {
+	tests := []struct {
+		desc string
+		char rune
+		// If not nil, it is called before SetCharacter is called and can set,
+		// clear or toggle dots or characters.
+		update  func(*Display) error
+		want    []image.Point
+		wantErr bool
+	}{
+		{
+			desc:    "fails on unsupported character",
+			char:    '\u00e9',
+			wantErr: true,
+		},
+		{
+			desc: "displays ' '",
+			char: ' ',
+		},
+		{
+			desc: "doesn't clear dots set previously",
+			char: ' ',
+			update: func(d *Display) error {
+				return d.SetDot(image.Point{2, 3})
+			},
+			want: []image.Point{{2, 3}},
+		},
+		{
+			desc: "displays '-'",
+			char: '-',
+			want: []image.Point{{0, 3}, {1, 3}, {2, 3}, {3, 3}, {4, 3}},
+		},
+		{
+			desc: "displays '1'",
+			char: '1',
+			want: []image.Point{
+				{2, 0},
+				{1, 1}, {2, 1},
+				{2, 2},
+				{2, 3},
+				{2, 4},
+				{2, 5},
+				{1, 6}, {2, 6}, {3, 6},
+			},
+		},
+		{
+			desc: "displays 'T'",
+			char: 'T',
+			want: []image.Point{
+				{0, 0}, {1, 0}, {2, 0}, {3, 0}, {4, 0},
+				{2, 1},
+				{2, 2},
+				{2, 3},
+				{2, 4},
+				{2, 5},
+				{2, 6},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			d := New()
+			if tc.update != nil {
+				if err := tc.update(d); err != nil {
+					t.Fatalf("tc.update => unexpected error: %v", err)
+				}
+			}
+
+			err := d.SetCharacter(tc.char)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("SetCharacter => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if diff := pretty.Compare(tc.want, dotsOf(d)); diff != "" {
+				t.Errorf("SetCharacter => unexpected dots, diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRequired(t *testing.T) {
+	tests := []struct {
+		desc     string
+		cellArea image.Rectangle
+		want     image.Rectangle
+		wantErr  bool
+	}{
+		{
+			desc:     "fails when area isn't wide enough",
+			cellArea: image.Rect(0, 0, MinCols-1, MinRows),
+			wantErr:  true,
+		},
+		{
+			desc:     "fails when area isn't tall enough",
+			cellArea: image.Rect(0, 0, MinCols, MinRows-1),
+			wantErr:  true,
+		},
+		{
+			desc:     "returns same area when no adjustment needed",
+			cellArea: image.Rect(0, 0, MinCols, MinRows),
+			want:     image.Rect(0, 0, MinCols, MinRows),
+		},
+		{
+			desc:     "adjusts width to aspect ratio",
+			cellArea: image.Rect(0, 0, MinCols+100, MinRows),
+			want:     image.Rect(0, 0, MinCols, MinRows),
+		},
+		{
+			desc:     "adjusts height to aspect ratio",
+			cellArea: image.Rect(0, 0, MinCols, MinRows+100),
+			want:     image.Rect(0, 0, MinCols, MinRows),
+		},
+		{
+			desc:     "keeps the position of the area",
+			cellArea: image.Rect(2, 3, 2+MinCols, 3+MinRows),
+			want:     image.Rect(2, 3, 2+MinCols, 3+MinRows),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := Required(tc.cellArea)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Required => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if diff := pretty.Compare(tc.want, got); diff != "" {
+				t.Errorf("Required => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSupportsChars(t *testing.T) {
+	tests := []struct {
+		desc       string
+		str        string
+		wantRes    bool
+		wantUnsupp []rune
+	}{
+		{
+			desc:    "supports all chars in an empty string",
+			wantRes: true,
+		},
+		{
+			desc:    "supports all printable ASCII characters",
+			str:     " !09:AZaz~",
+			wantRes: true,
+		},
+		{
+			desc:       "doesn't support characters outside of printable ASCII",
+			str:        "a\tb\u00e9",
+			wantRes:    false,
+			wantUnsupp: []rune{'\t', '\u00e9'},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			gotRes, gotUnsupp := SupportsChars(tc.str)
+			if gotRes != tc.wantRes {
+				t.Errorf("SupportsChars(%q) => %v, %v, want %v, %v", tc.str, gotRes, gotUnsupp, tc.wantRes, tc.wantUnsupp)
+			}
+
+			sort.Slice(gotUnsupp, func(i, j int) bool {
+				return gotUnsupp[i] < gotUnsupp[j]
+			})
+			if diff := pretty.Compare(tc.wantUnsupp, gotUnsupp); diff != "" {
+				t.Errorf("SupportsChars => unexpected unsupported characters returned, diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		desc string
+		str  string
+		want string
+	}{
+		{
+			desc: "no alternation to empty string",
+		},
+		{
+			desc: "all characters are supported",
+			str:  "Hello, World!",
+			want: "Hello, World!",
+		},
+		{
+			desc: "some characters are supported",
+			str:  "caf\u00e9",
+			want: "caf ",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := Sanitize(tc.str)
+			if got != tc.want {
+				t.Errorf("Sanitize => %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package seven
+
+// attributes.go calculates attributes needed when determining placement of
+// segments.
+
+import (
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/mum4k/termdash/internal/numbers"
+	"github.com/mum4k/termdash/internal/segdisp/segment"
+)
+
+// segType maps segments to their type.
+var segType = map[Segment]segment.Type{
+	A: segment.Horizontal,
+	B: segment.Vertical,
+	C: segment.Vertical,
+	D: segment.Horizontal,
+	E: segment.Vertical,
+	F: segment.Vertical,
+	G: segment.Horizontal,
+}
+
+// segmentSize given an area for the display determines the size of individual
+// segments, i.e. the width of a vertical or the height of a horizontal
+// segment.
+func segmentSize(ar image.Rectangle) int {
+	// widthPerc is the relative width of a segment to the width of the canvas.
+	const widthPerc = 9
+	s := int(math.Round(float64(ar.Dx()) * widthPerc / 100))
+	if s > 3 && s%2 == 0 {
+		// Segments with odd number of pixels in their width/height look
+		// better, since the spike at the top of their slopes has only one
+		// pixel.
+		s++
+	}
+	return s
+}
+
+// attributes contains attributes needed to draw the segment display.
+// The placement of the segments matches the placement of the outer segments on
+// the 16-segment display, refer to sixteen/doc/segment_placement.svg for a
+// visual aid and explanation of the usage of the square roots.
+type attributes struct {
+	// segSize is the width of a vertical or height of a horizontal segment.
+	segSize int
+
+	// peakToPeak is a vertical distance between peaks of two vertical
+	// segments.
+	peakToPeak int
+
+	// horizLen is length of the horizontal segments, e.g. A.
+	horizLen int
+
+	// vertLen is length of the vertical segments, e.g. F.
+	vertLen int
+
+	// horizLeftX is the X coordinate where the area of the horizontal
+	// segments starts, i.e. X coordinate of A, G and D.
+	horizLeftX int
+	// horizRightX is the X coordinate where the area of the segment
+	// horizontally on the right starts, i.e. X coordinate of B and C.
+	horizRightX int
+
+	// vertCenY is the Y coordinate where the area of the segment vertically
+	// in the center starts, i.e. Y coordinate of G.
+	vertCenY int
+	// vertBotY is the Y coordinate where the area of the segment vertically
+	// at the bottom starts, i.e. Y coordinate of D.
+	vertBotY int
+}
+
+// newAttributes calculates attributes needed to place the segments for the
+// provided pixel area.
+func newAttributes(bcAr image.Rectangle) *attributes {
+	segSize := segmentSize(bcAr)
+
+	// diaPerc is the size of the gap between segments in percentage of the
+	// segment's size.
+	const diaPerc = 40
+	// Ensure there is at least one pixel diagonally between segments so they
+	// don't visually blend.
+	_, dg := numbers.MinMaxInts([]int{
+		int(float64(segSize) * diaPerc / 100),
+		1,
+	})
+	diaGap := float64(dg)
+
+	segLeg := float64(segSize) / math.Sqrt2
+	segPeakDist := segLeg / math.Sqrt2
+
+	diaLeg := diaGap / math.Sqrt2
+	peakToPeak := diaLeg * 2
+	if segSize == 2 {
+		// Display that has segment size of two looks more balanced with peak
+		// distance of two.
+		peakToPeak = 2
+	}
+	if peakToPeak > 3 && int(peakToPeak)%2 == 0 {
+		// Prefer odd distances to create centered look.
+		peakToPeak++
+	}
+
+	twoSegHypo := 2*segLeg + diaGap
+	twoSegLeg := twoSegHypo / math.Sqrt2
+	edgeSegGap := twoSegLeg - segPeakDist
+
+	spaces := int(math.Round(2*edgeSegGap + peakToPeak))
+	shortLen := (bcAr.Dx()-spaces)/2 - 1
+	vertLen := (bcAr.Dy()-spaces)/2 - 1
+
+	ptp := int(math.Round(peakToPeak))
+	horizLeftX := int(math.Round(edgeSegGap))
+
+	// Refer to sixteen/doc/segment_placement.svg.
+	// Diagram labeled "A mid point".
+	offset := int(math.Round(diaLeg - segPeakDist))
+	horizRightX := horizLeftX + shortLen + ptp + shortLen + offset
+
+	vertCenY := horizLeftX + vertLen + offset
+	vertBotY := horizLeftX + vertLen + ptp + vertLen + offset
+
+	return &attributes{
+		segSize:    segSize,
+		peakToPeak: ptp,
+		// The horizontal segments span the space of two horizontal segments
+		// of the 16-segment display and the gap between them.
+		horizLen: shortLen + ptp + shortLen,
+		vertLen:  vertLen,
+
+		horizLeftX:  horizLeftX,
+		horizRightX: horizRightX,
+		vertCenY:    vertCenY,
+		vertBotY:    vertBotY,
+	}
+}
+
+// segArea returns the area for the specified segment.
+func (a *attributes) segArea(s Segment) image.Rectangle {
+	var (
+		start  image.Point
+		length int
+	)
+
+	switch s {
+	case A:
+		start = image.Point{a.horizLeftX, 0}
+		length = a.horizLen
+
+	case F:
+		start = image.Point{0, a.horizLeftX}
+		length = a.vertLen
+
+	case B:
+		start = image.Point{a.horizRightX, a.horizLeftX}
+		length = a.vertLen
+
+	case G:
+		start = image.Point{a.horizLeftX, a.vertCenY}
+		length = a.horizLen
+
+	case E:
+		f := a.segArea(F)
+		start = image.Point{0, f.Max.Y + a.peakToPeak}
+		length = a.vertLen
+
+	case C:
+		b := a.segArea(B)
+		start = image.Point{a.horizRightX, b.Max.Y + a.peakToPeak}
+		length = a.vertLen
+
+	case D:
+		start = image.Point{a.horizLeftX, a.vertBotY}
+		length = a.horizLen
+
+	default:
+		panic(fmt.Sprintf("cannot determine area for unknown segment %v(%d)", s, s))
+	}
+
+	switch st := segType[s]; st {
+	case segment.Horizontal:
+		return image.Rect(start.X, start.Y, start.X+length, start.Y+a.segSize)
+	default:
+		return image.Rect(start.X, start.Y, start.X+a.segSize, start.Y+length)
+	}
+}
@@ -0,0 +1,383 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package seven simulates a 7-segment display drawn on a canvas.
+
+Given a canvas, determines the placement and size of the individual
+segments and exposes API that can turn individual segments on and off or
+display ASCII characters.
+
+The following outlines segments in the display and their names.
+
+	        A
+	   -------------
+	  |             |
+	  |             |
+	F |             | B
+	  |             |
+	  |      G      |
+	   -------------
+	  |             |
+	  |             |
+	E |             | C
+	  |             |
+	  |             |
+	   -------------
+	        D
+*/
+package seven
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strings"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/area"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/canvas/braille"
+	"github.com/mum4k/termdash/internal/segdisp/segment"
+)
+
+// Segment represents a single segment in the display.
+type Segment int
+
+// String implements fmt.Stringer()
+func (s Segment) String() string {
+	if n, ok := segmentNames[s]; ok {
+		return n
+	}
+	return "SegmentUnknown"
+}
+
+// segmentNames maps Segment values to human readable names.
+var segmentNames = map[Segment]string{
+	A: "A",
+	B: "B",
+	C: "C",
+	D: "D",
+	E: "E",
+	F: "F",
+	G: "G",
+}
+
+const (
+	segmentUnknown Segment = iota
+
+	// A is a segment, see the diagram above.
+	A
+	// B is a segment, see the diagram above.
+	B
+	// C is a segment, see the diagram above.
+	C
+	// D is a segment, see the diagram above.
+	D
+	// E is a segment, see the diagram above.
+	E
+	// F is a segment, see the diagram above.
+	F
+	// G is a segment, see the diagram above.
+	G
+
+	segmentMax // Used for validation.
+)
+
+// characterSegments maps characters that can be displayed on their segments.
+// Letters that cannot be recognized on a 7-segment display aren't supported.
+// Letters that are only recognizable in one case are only supported in that
+// case, e.g. 'b', but not 'B'.
+var characterSegments = map[rune][]Segment{
+	' ':  nil,
+	'"':  {F, B},
+	'\'': {F},
+	'-':  {G},
+	'=':  {G, D},
+	'[':  {A, F, E, D},
+	']':  {A, B, C, D},
+	'_':  {D},
+
+	'0': {A, B, C, D, E, F},
+	'1': {B, C},
+	'2': {A, B, G, E, D},
+	'3': {A, B, G, C, D},
+	'4': {F, G, B, C},
+	'5': {A, F, G, C, D},
+	'6': {A, F, G, E, C, D},
+	'7': {A, B, C},
+	'8': {A, B, C, D, E, F, G},
+	'9': {A, B, C, D, F, G},
+
+	'A': {A, B, C, E, F, G},
+	'C': {A, D, E, F},
+	'E': {A, D, E, F, G},
+	'F': {A, E, F, G},
+	'G': {A, C, D, E, F},
+	'H': {B, C, E, F, G},
+	'I': {E, F},
+	'J': {B, C, D, E},
+	'L': {D, E, F},
+	'O': {A, B, C, D, E, F},
+	'P': {A, B, E, F, G},
+	'S': {A, C, D, F, G},
+	'U': {B, C, D, E, F},
+
+	'a': {A, B, C, D, E, G},
+	'b': {C, D, E, F, G},
+	'c': {D, E, G},
+	'd': {B, C, D, E, G},
+	'h': {C, E, F, G},
+	'i': {E},
+	'n': {C, E, G},
+	'o': {C, D, E, G},
+	'q': {A, B, C, F, G},
+	'r': {E, G},
+	't': {D, E, F, G},
+	'u': {C, D, E},
+	'y': {B, C, D, F, G},
+}
+
+// SupportsChars asserts whether the display supports all runes in the
+// provided string.
+// The display only supports a subset of ASCII characters.
+// Returns any unsupported runes found in the string in an unspecified order.
+func SupportsChars(s string) (bool, []rune) {
+	unsupp := map[rune]bool{}
+	for _, r := range s {
+		if _, ok := characterSegments[r]; !ok {
+			unsupp[r] = true
+		}
+	}
+
+	var res []rune
+	for r := range unsupp {
+		res = append(res, r)
+	}
+	return len(res) == 0, res
+}
+
+// Sanitize returns a copy of the string, replacing all unsupported characters
+// with a space character.
+func Sanitize(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if _, ok := characterSegments[r]; !ok {
+			b.WriteRune(' ')
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// AllSegments returns all 7 segments in an undefined order.
+func AllSegments() []Segment {
+	var res []Segment
+	for s := range segmentNames {
+		res = append(res, s)
+	}
+	return res
+}
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*Display)
+}
+
+// option implements Option.
+type option func(*Display)
+
+// set implements Option.set.
+func (o option) set(d *Display) {
+	o(d)
+}
+
+// CellOpts sets the cell options on the cells that contain the segment display.
+func CellOpts(cOpts ...cell.Option) Option {
+	return option(func(d *Display) {
+		d.cellOpts = cOpts
+	})
+}
+
+// Display represents the segment display.
+// This object is not thread-safe.
+type Display struct {
+	// segments maps segments to their current status.
+	segments map[Segment]bool
+
+	cellOpts []cell.Option
+}
+
+// New creates a new segment display.
+// Initially all the segments are off.
+func New(opts ...Option) *Display {
+	d := &Display{
+		segments: map[Segment]bool{},
+	}
+
+	for _, opt := range opts {
+		opt.set(d)
+	}
+	return d
+}
+
+// Clear clears the entire display, turning all segments off.
+func (d *Display) Clear(opts ...Option) {
+	for _, opt := range opts {
+		opt.set(d)
+	}
+
+	d.segments = map[Segment]bool{}
+}
+
+// SetSegment sets the specified segment on.
+// This method is idempotent.
+func (d *Display) SetSegment(s Segment) error {
+	if s <= segmentUnknown || s >= segmentMax {
+		return fmt.Errorf("unknown segment %v(%d)", s, s)
+	}
+	d.segments[s] = true
+	return nil
+}
+
+// ClearSegment sets the specified segment off.
+// This method is idempotent.
+func (d *Display) ClearSegment(s Segment) error {
+	if s <= segmentUnknown || s >= segmentMax {
+		return fmt.Errorf("unknown segment %v(%d)", s, s)
+	}
+	d.segments[s] = false
+	return nil
+}
+
+// ToggleSegment toggles the state of the specified segment, i.e it either sets
+// or clears it depending on its current state.
+func (d *Display) ToggleSegment(s Segment) error {
+	if s <= segmentUnknown || s >= segmentMax {
+		return fmt.Errorf("unknown segment %v(%d)", s, s)
+	}
+	d.segments[s] = !d.segments[s]
+	return nil
+}
+
+// SetCharacter sets all the segments that are needed to display the provided
+// character.
+// The display only supports a subset of ASCII characters, use SupportsChars()
+// or Sanitize() to ensure the provided character is supported.
+// Doesn't clear the display of segments set previously.
+func (d *Display) SetCharacter(c rune) error {
+	seg, ok := characterSegments[c]
+	if !ok {
+		return fmt.Errorf("display doesn't support character %q rune(%v)", c, c)
+	}
+
+	for _, s := range seg {
+		if err := d.SetSegment(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Minimum valid size of a cell canvas in order to draw the segment display.
+const (
+	// MinCols is the smallest valid amount of columns in a cell area.
+	MinCols = 6
+	// MinRows is the smallest valid amount of rows in a cell area.
+	MinRows = 5
+)
+
+// aspectRatio is the desired aspect ratio of a single segment display.
+var aspectRatio = image.Point{3, 5}
+
+// Draw draws the current state of the segment display onto the canvas.
+// The canvas must be at least MinCols x MinRows cells, or an error will be
+// returned.
+// Any options provided to draw overwrite the values provided to New.
+func (d *Display) Draw(cvs *canvas.Canvas, opts ...Option) error {
+	for _, o := range opts {
+		o.set(d)
+	}
+
+	bc, bcAr, err := toBraille(cvs)
+	if err != nil {
+		return err
+	}
+
+	attr := newAttributes(bcAr)
+	var sOpts []segment.Option
+	if len(d.cellOpts) > 0 {
+		sOpts = append(sOpts, segment.CellOpts(d.cellOpts...))
+	}
+	for _, segArg := range []struct {
+		s    Segment
+		opts []segment.Option
+	}{
+		{A, nil},
+		{F, nil},
+		{B, []segment.Option{segment.ReverseSlopes()}},
+		{G, nil},
+		{E, nil},
+		{C, []segment.Option{segment.ReverseSlopes()}},
+		{D, []segment.Option{segment.ReverseSlopes()}},
+	} {
+		if !d.segments[segArg.s] {
+			continue
+		}
+		sOpts := append(sOpts, segArg.opts...)
+		ar := attr.segArea(segArg.s)
+		if err := segment.HV(bc, ar, segType[segArg.s], sOpts...); err != nil {
+			return fmt.Errorf("failed to draw segment %v, segment.HV => %v", segArg.s, err)
+		}
+	}
+	return bc.CopyTo(cvs)
+}
+
+// Required when given an area of cells, returns either an area of the same
+// size or a smaller area that is required to draw one display.
+// Returns a smaller area when the provided area didn't have the required
+// aspect ratio.
+// Returns an error if the area is too small to draw a segment display, i.e.
+// smaller than MinCols x MinRows.
+func Required(cellArea image.Rectangle) (image.Rectangle, error) {
+	if cols, rows := cellArea.Dx(), cellArea.Dy(); cols < MinCols || rows < MinRows {
+		return image.ZR, fmt.Errorf("cell area %v is too small to draw the segment display, has %dx%d cells, need at least %dx%d cells",
+			cellArea, cols, rows, MinCols, MinRows)
+	}
+
+	bcAr := image.Rect(0, 0, cellArea.Dx()*braille.ColMult, cellArea.Dy()*braille.RowMult)
+	bcArAdj := area.WithRatio(bcAr, aspectRatio)
+
+	needCols := int(math.Ceil(float64(bcArAdj.Dx()) / braille.ColMult))
+	needRows := int(math.Ceil(float64(bcArAdj.Dy()) / braille.RowMult))
+	needAr := image.Rect(cellArea.Min.X, cellArea.Min.Y, cellArea.Min.X+needCols, cellArea.Min.Y+needRows)
+	return needAr, nil
+}
+
+// toBraille converts the canvas into a braille canvas and returns a pixel area
+// with aspect ratio adjusted for the segment display.
+func toBraille(cvs *canvas.Canvas) (*braille.Canvas, image.Rectangle, error) {
+	ar, err := Required(cvs.Area())
+	if err != nil {
+		return nil, image.ZR, fmt.Errorf("Required => %v", err)
+	}
+
+	bc, err := braille.New(ar)
+	if err != nil {
+		return nil, image.ZR, fmt.Errorf("braille.New => %v", err)
+	}
+	return bc, area.WithRatio(bc.Area(), aspectRatio), nil
+}
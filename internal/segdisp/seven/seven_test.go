@@ -0,0 +1,731 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package seven
+
+import (
+	"image"
+	"sort"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/area"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/canvas/braille/testbraille"
+	"github.com/mum4k/termdash/internal/canvas/testcanvas"
+	"github.com/mum4k/termdash/internal/faketerm"
+	"github.com/mum4k/termdash/internal/segdisp/segment"
+	"github.com/mum4k/termdash/internal/segdisp/segment/testsegment"
+)
+
+func TestDraw(t *testing.T) {
+	tests := []struct {
+		desc       string
+		opts       []Option
+		drawOpts   []Option
+		cellCanvas image.Rectangle
+		// If not nil, it is called before Draw is called and can set, clear or
+		// toggle segments or characters.
+		update        func(*Display) error
+		want          func(size image.Point) *faketerm.Terminal
+		wantErr       bool
+		wantUpdateErr bool
+	}{
+		{
+			desc:       "fails for area not wide enough",
+			cellCanvas: image.Rect(0, 0, MinCols-1, MinRows),
+			wantErr:    true,
+		},
+		{
+			desc:       "fails for area not tall enough",
+			cellCanvas: image.Rect(0, 0, MinCols, MinRows-1),
+			wantErr:    true,
+		},
+		{
+			desc:       "fails to set invalid segment (too small)",
+			cellCanvas: image.Rect(0, 0, MinCols, MinRows),
+			update: func(d *Display) error {
+				return d.SetSegment(Segment(-1))
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc:       "fails to set invalid segment (too large)",
+			cellCanvas: image.Rect(0, 0, MinCols, MinRows),
+			update: func(d *Display) error {
+				return d.SetSegment(Segment(segmentMax))
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc:       "fails to clear invalid segment (too small)",
+			cellCanvas: image.Rect(0, 0, MinCols, MinRows),
+			update: func(d *Display) error {
+				return d.ClearSegment(Segment(-1))
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc:       "fails to clear invalid segment (too large)",
+			cellCanvas: image.Rect(0, 0, MinCols, MinRows),
+			update: func(d *Display) error {
+				return d.ClearSegment(Segment(segmentMax))
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc:       "fails to toggle invalid segment (too small)",
+			cellCanvas: image.Rect(0, 0, MinCols, MinRows),
+			update: func(d *Display) error {
+				return d.ToggleSegment(Segment(-1))
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc:       "fails to toggle invalid segment (too large)",
+			cellCanvas: image.Rect(0, 0, MinCols, MinRows),
+			update: func(d *Display) error {
+				return d.ToggleSegment(Segment(segmentMax))
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc:       "empty when no segments set",
+			cellCanvas: image.Rect(0, 0, MinCols, MinRows),
+		},
+		{
+			desc:       "smallest valid display 6x5, A",
+			cellCanvas: image.Rect(0, 0, MinCols, MinRows),
+			update: func(d *Display) error {
+				return d.SetSegment(A)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				bc := testbraille.MustNew(ft.Area())
+
+				testsegment.MustHV(bc, image.Rect(1, 0, 8, 1), segment.Horizontal) // A
+				testbraille.MustApply(bc, ft)
+				return ft
+			},
+		},
+		{
+			desc:       "smallest valid display 6x5, F",
+			cellCanvas: image.Rect(0, 0, MinCols, MinRows),
+			update: func(d *Display) error {
+				return d.SetSegment(F)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				bc := testbraille.MustNew(ft.Area())
+
+				testsegment.MustHV(bc, image.Rect(0, 1, 1, 8), segment.Vertical) // F
+				testbraille.MustApply(bc, ft)
+				return ft
+			},
+		},
+		{
+			desc:       "smallest valid display 6x5, B",
+			cellCanvas: image.Rect(0, 0, MinCols, MinRows),
+			update: func(d *Display) error {
+				return d.SetSegment(B)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				bc := testbraille.MustNew(ft.Area())
+
+				testsegment.MustHV(bc, image.Rect(8, 1, 9, 8), segment.Vertical) // B
+				testbraille.MustApply(bc, ft)
+				return ft
+			},
+		},
+		{
+			desc:       "smallest valid display 6x5, G",
+			cellCanvas: image.Rect(0, 0, MinCols, MinRows),
+			update: func(d *Display) error {
+				return d.SetSegment(G)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				bc := testbraille.MustNew(ft.Area())
+
+				testsegment.MustHV(bc, image.Rect(1, 8, 8, 9), segment.Horizontal) // G
+				testbraille.MustApply(bc, ft)
+				return ft
+			},
+		},
+		{
+			desc:       "smallest valid display 6x5, E",
+			cellCanvas: image.Rect(0, 0, MinCols, MinRows),
+			update: func(d *Display) error {
+				return d.SetSegment(E)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				bc := testbraille.MustNew(ft.Area())
+
+				testsegment.MustHV(bc, image.Rect(0, 9, 1, 16), segment.Vertical) // E
+				testbraille.MustApply(bc, ft)
+				return ft
+			},
+		},
+		{
+			desc:       "smallest valid display 6x5, C",
+			cellCanvas: image.Rect(0, 0, MinCols, MinRows),
+			update: func(d *Display) error {
+				return d.SetSegment(C)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				bc := testbraille.MustNew(ft.Area())
+
+				testsegment.MustHV(bc, image.Rect(8, 9, 9, 16), segment.Vertical) // C
+				testbraille.MustApply(bc, ft)
+				return ft
+			},
+		},
+		{
+			desc:       "smallest valid display 6x5, D",
+			cellCanvas: image.Rect(0, 0, MinCols, MinRows),
+			update: func(d *Display) error {
+				return d.SetSegment(D)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				bc := testbraille.MustNew(ft.Area())
+
+				testsegment.MustHV(bc, image.Rect(1, 16, 8, 17), segment.Horizontal) // D
+				testbraille.MustApply(bc, ft)
+				return ft
+			},
+		},
+		{
+			desc: "smallest valid display 6x5, all segments, New sets cell options",
+			opts: []Option{
+				CellOpts(
+					cell.FgColor(cell.ColorRed),
+					cell.BgColor(cell.ColorGreen),
+				),
+			},
+			cellCanvas: image.Rect(0, 0, MinCols, MinRows),
+			update: func(d *Display) error {
+				for _, s := range AllSegments() {
+					if err := d.SetSegment(s); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				bc := testbraille.MustNew(ft.Area())
+
+				cOpts := []cell.Option{
+					cell.FgColor(cell.ColorRed),
+					cell.BgColor(cell.ColorGreen),
+				}
+				testsegment.MustHV(bc, image.Rect(1, 0, 8, 1), segment.Horizontal, segment.CellOpts(cOpts...))   // A
+				testsegment.MustHV(bc, image.Rect(0, 1, 1, 8), segment.Vertical, segment.CellOpts(cOpts...))     // F
+				testsegment.MustHV(bc, image.Rect(8, 1, 9, 8), segment.Vertical, segment.CellOpts(cOpts...))     // B
+				testsegment.MustHV(bc, image.Rect(1, 8, 8, 9), segment.Horizontal, segment.CellOpts(cOpts...))   // G
+				testsegment.MustHV(bc, image.Rect(0, 9, 1, 16), segment.Vertical, segment.CellOpts(cOpts...))    // E
+				testsegment.MustHV(bc, image.Rect(8, 9, 9, 16), segment.Vertical, segment.CellOpts(cOpts...))    // C
+				testsegment.MustHV(bc, image.Rect(1, 16, 8, 17), segment.Horizontal, segment.CellOpts(cOpts...)) // D
+				testbraille.MustApply(bc, ft)
+				return ft
+			},
+		},
+		{
+			desc:       "smallest valid display 6x5, all segments, Draw sets cell options",
+			cellCanvas: image.Rect(0, 0, MinCols, MinRows),
+			drawOpts: []Option{
+				CellOpts(
+					cell.FgColor(cell.ColorRed),
+					cell.BgColor(cell.ColorGreen),
+				),
+			},
+			update: func(d *Display) error {
+				for _, s := range AllSegments() {
+					if err := d.SetSegment(s); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				bc := testbraille.MustNew(ft.Area())
+
+				cOpts := []cell.Option{
+					cell.FgColor(cell.ColorRed),
+					cell.BgColor(cell.ColorGreen),
+				}
+				testsegment.MustHV(bc, image.Rect(1, 0, 8, 1), segment.Horizontal, segment.CellOpts(cOpts...))   // A
+				testsegment.MustHV(bc, image.Rect(0, 1, 1, 8), segment.Vertical, segment.CellOpts(cOpts...))     // F
+				testsegment.MustHV(bc, image.Rect(8, 1, 9, 8), segment.Vertical, segment.CellOpts(cOpts...))     // B
+				testsegment.MustHV(bc, image.Rect(1, 8, 8, 9), segment.Horizontal, segment.CellOpts(cOpts...))   // G
+				testsegment.MustHV(bc, image.Rect(0, 9, 1, 16), segment.Vertical, segment.CellOpts(cOpts...))    // E
+				testsegment.MustHV(bc, image.Rect(8, 9, 9, 16), segment.Vertical, segment.CellOpts(cOpts...))    // C
+				testsegment.MustHV(bc, image.Rect(1, 16, 8, 17), segment.Horizontal, segment.CellOpts(cOpts...)) // D
+				testbraille.MustApply(bc, ft)
+				return ft
+			},
+		},
+		{
+			desc:       "clears and toggles segments",
+			cellCanvas: image.Rect(0, 0, MinCols, MinRows),
+			update: func(d *Display) error {
+				for _, s := range []Segment{A, G, D} {
+					if err := d.SetSegment(s); err != nil {
+						return err
+					}
+				}
+				if err := d.ClearSegment(G); err != nil {
+					return err
+				}
+				if err := d.ToggleSegment(D); err != nil {
+					return err
+				}
+				return d.ToggleSegment(F)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				bc := testbraille.MustNew(ft.Area())
+
+				testsegment.MustHV(bc, image.Rect(1, 0, 8, 1), segment.Horizontal) // A
+				testsegment.MustHV(bc, image.Rect(0, 1, 1, 8), segment.Vertical)   // F
+				testbraille.MustApply(bc, ft)
+				return ft
+			},
+		},
+		{
+			desc:       "larger display, all segments",
+			cellCanvas: image.Rect(0, 0, MinCols*4, MinRows*4),
+			update: func(d *Display) error {
+				for _, s := range AllSegments() {
+					if err := d.SetSegment(s); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				bc := testbraille.MustNew(ft.Area())
+
+				testsegment.MustHV(bc, image.Rect(4, 0, 41, 5), segment.Horizontal)   // A
+				testsegment.MustHV(bc, image.Rect(0, 4, 5, 37), segment.Vertical)     // F
+				testsegment.MustHV(bc, image.Rect(40, 4, 45, 37), segment.Vertical)   // B
+				testsegment.MustHV(bc, image.Rect(4, 36, 41, 41), segment.Horizontal) // G
+				testsegment.MustHV(bc, image.Rect(0, 40, 5, 73), segment.Vertical)    // E
+				testsegment.MustHV(bc, image.Rect(40, 40, 45, 73), segment.Vertical)  // C
+				testsegment.MustHV(bc, image.Rect(4, 72, 41, 77), segment.Horizontal) // D
+				testbraille.MustApply(bc, ft)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			d := New(tc.opts...)
+			if tc.update != nil {
+				err := tc.update(d)
+				if (err != nil) != tc.wantUpdateErr {
+					t.Errorf("tc.update => unexpected error: %v, wantUpdateErr: %v", err, tc.wantUpdateErr)
+				}
+				if err != nil {
+					return
+				}
+			}
+
+			cvs, err := canvas.New(tc.cellCanvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+
+			{
+				err := d.Draw(cvs, tc.drawOpts...)
+				if (err != nil) != tc.wantErr {
+					t.Errorf("Draw => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+				}
+				if err != nil {
+					return
+				}
+			}
+
+			size := area.Size(tc.cellCanvas)
+			want := faketerm.MustNew(size)
+			if tc.want != nil {
+				want = tc.want(size)
+			}
+
+			got, err := faketerm.New(size)
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := cvs.Apply(got); err != nil {
+				t.Fatalf("bc.Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(want, got); diff != "" {
+				t.Fatalf("Draw => %v", diff)
+			}
+
+		})
+	}
+}
+
+// mustDrawSegments returns a fake terminal of the specified size with the
+// segments drawn on it or panics.
+func mustDrawSegments(size image.Point, seg ...Segment) *faketerm.Terminal {
+	ft := faketerm.MustNew(size)
+	cvs := testcanvas.MustNew(ft.Area())
+
+	d := New()
+	for _, s := range seg {
+		if err := d.SetSegment(s); err != nil {
+			panic(err)
+		}
+	}
+
+	if err := d.Draw(cvs); err != nil {
+		panic(err)
+	}
+
+	testcanvas.MustApply(cvs, ft)
+	return ft
+}
+
+func TestSetCharacter(t *testing.T) {
+	tests := []struct {
+		desc string
+		char rune
+		// If not nil, it is called before Draw is called and can set, clear or
+		// toggle segments or characters.
+		update  func(*Display) error
+		want    func(size image.Point) *faketerm.Terminal
+		wantErr bool
+	}{
+		{
+			desc:    "fails on unsupported character",
+			char:    'M',
+			wantErr: true,
+		},
+		{
+			desc: "displays ' '",
+			char: ' ',
+		},
+		{
+			desc: "doesn't clear segments set previously",
+			char: ' ',
+			update: func(d *Display) error {
+				return d.SetSegment(G)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				return mustDrawSegments(size, G)
+			},
+		},
+		{
+			desc: "displays '-'",
+			char: '-',
+			want: func(size image.Point) *faketerm.Terminal {
+				return mustDrawSegments(size, G)
+			},
+		},
+		{
+			desc: "displays '0'",
+			char: '0',
+			want: func(size image.Point) *faketerm.Terminal {
+				return mustDrawSegments(size, A, B, C, D, E, F)
+			},
+		},
+		{
+			desc: "displays '1'",
+			char: '1',
+			want: func(size image.Point) *faketerm.Terminal {
+				return mustDrawSegments(size, B, C)
+			},
+		},
+		{
+			desc: "displays '2'",
+			char: '2',
+			want: func(size image.Point) *faketerm.Terminal {
+				return mustDrawSegments(size, A, B, G, E, D)
+			},
+		},
+		{
+			desc: "displays '3'",
+			char: '3',
+			want: func(size image.Point) *faketerm.Terminal {
+				return mustDrawSegments(size, A, B, G, C, D)
+			},
+		},
+		{
+			desc: "displays '4'",
+			char: '4',
+			want: func(size image.Point) *faketerm.Terminal {
+				return mustDrawSegments(size, F, G, B, C)
+			},
+		},
+		{
+			desc: "displays '5'",
+			char: '5',
+			want: func(size image.Point) *faketerm.Terminal {
+				return mustDrawSegments(size, A, F, G, C, D)
+			},
+		},
+		{
+			desc: "displays '6'",
+			char: '6',
+			want: func(size image.Point) *faketerm.Terminal {
+				return mustDrawSegments(size, A, F, G, E, C, D)
+			},
+		},
+		{
+			desc: "displays '7'",
+			char: '7',
+			want: func(size image.Point) *faketerm.Terminal {
+				return mustDrawSegments(size, A, B, C)
+			},
+		},
+		{
+			desc: "displays '8'",
+			char: '8',
+			want: func(size image.Point) *faketerm.Terminal {
+				return mustDrawSegments(size, A, B, C, D, E, F, G)
+			},
+		},
+		{
+			desc: "displays '9'",
+			char: '9',
+			want: func(size image.Point) *faketerm.Terminal {
+				return mustDrawSegments(size, A, B, C, D, F, G)
+			},
+		},
+		{
+			desc: "displays 'b'",
+			char: 'b',
+			want: func(size image.Point) *faketerm.Terminal {
+				return mustDrawSegments(size, C, D, E, F, G)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			d := New()
+			if tc.update != nil {
+				err := tc.update(d)
+				if err != nil {
+					t.Fatalf("tc.update => unexpected error: %v", err)
+				}
+			}
+
+			{
+				err := d.SetCharacter(tc.char)
+				if (err != nil) != tc.wantErr {
+					t.Errorf("SetCharacter => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+				}
+				if err != nil {
+					return
+				}
+			}
+
+			ar := image.Rect(0, 0, MinCols, MinRows)
+			cvs, err := canvas.New(ar)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+
+			if err := d.Draw(cvs); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+
+			size := area.Size(ar)
+			want := faketerm.MustNew(size)
+			if tc.want != nil {
+				want = tc.want(size)
+			}
+
+			got, err := faketerm.New(size)
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := cvs.Apply(got); err != nil {
+				t.Fatalf("bc.Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(want, got); diff != "" {
+				t.Fatalf("SetCharacter => %v", diff)
+			}
+		})
+	}
+}
+
+func TestRequired(t *testing.T) {
+	tests := []struct {
+		desc     string
+		cellArea image.Rectangle
+		want     image.Rectangle
+		wantErr  bool
+	}{
+		{
+			desc:     "fails when area isn't wide enough",
+			cellArea: image.Rect(0, 0, MinCols-1, MinRows),
+			wantErr:  true,
+		},
+		{
+			desc:     "fails when area isn't tall enough",
+			cellArea: image.Rect(0, 0, MinCols, MinRows-1),
+			wantErr:  true,
+		},
+		{
+			desc:     "returns same area when no adjustment needed",
+			cellArea: image.Rect(0, 0, MinCols, MinRows),
+			want:     image.Rect(0, 0, MinCols, MinRows),
+		},
+		{
+			desc:     "adjusts width to aspect ratio",
+			cellArea: image.Rect(0, 0, MinCols+100, MinRows),
+			want:     image.Rect(0, 0, MinCols, MinRows),
+		},
+		{
+			desc:     "adjusts height to aspect ratio",
+			cellArea: image.Rect(0, 0, MinCols, MinRows+100),
+			want:     image.Rect(0, 0, MinCols, MinRows),
+		},
+		{
+			desc:     "adjusts larger area to aspect ratio",
+			cellArea: image.Rect(0, 0, MinCols*2, MinRows*4),
+			want:     image.Rect(0, 0, 12, 10),
+		},
+		{
+			desc:     "keeps the position of the area",
+			cellArea: image.Rect(2, 3, 2+MinCols, 3+MinRows),
+			want:     image.Rect(2, 3, 2+MinCols, 3+MinRows),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := Required(tc.cellArea)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Required => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if diff := pretty.Compare(tc.want, got); diff != "" {
+				t.Errorf("Required => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAllSegments(t *testing.T) {
+	want := []Segment{A, B, C, D, E, F, G}
+	got := AllSegments()
+	sort.Slice(got, func(i, j int) bool {
+		return int(got[i]) < int(got[j])
+	})
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("AllSegments => unexpected diff (-want, +got):\n%s", diff)
+	}
+}
+
+func TestSupportsChars(t *testing.T) {
+	tests := []struct {
+		desc       string
+		str        string
+		wantRes    bool
+		wantUnsupp []rune
+	}{
+		{
+			desc:    "supports all chars in an empty string",
+			wantRes: true,
+		},
+		{
+			desc:       "supports some chars in the string",
+			str:        " 12:3",
+			wantRes:    false,
+			wantUnsupp: []rune{':'},
+		},
+		{
+			desc:    "supports digits and hexadecimal letters",
+			str:     "0123456789AbCdEF",
+			wantRes: true,
+		},
+		{
+			desc:       "supports no chars in the string",
+			str:        "MW",
+			wantRes:    false,
+			wantUnsupp: []rune{'M', 'W'},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			gotRes, gotUnsupp := SupportsChars(tc.str)
+			if gotRes != tc.wantRes {
+				t.Errorf("SupportsChars(%q) => %v, %v, want %v, %v", tc.str, gotRes, gotUnsupp, tc.wantRes, tc.wantUnsupp)
+			}
+
+			sort.Slice(gotUnsupp, func(i, j int) bool {
+				return gotUnsupp[i] < gotUnsupp[j]
+			})
+			sort.Slice(tc.wantUnsupp, func(i, j int) bool {
+				return tc.wantUnsupp[i] < tc.wantUnsupp[j]
+			})
+			if diff := pretty.Compare(tc.wantUnsupp, gotUnsupp); diff != "" {
+				t.Errorf("SupportsChars => unexpected unsupported characters returned, diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		desc string
+		str  string
+		want string
+	}{
+		{
+			desc: "no alternation to empty string",
+		},
+		{
+			desc: "all characters are supported",
+			str:  " 12-3",
+			want: " 12-3",
+		},
+		{
+			desc: "some characters are supported",
+			str:  "12:30",
+			want: "12 30",
+		},
+		{
+			desc: "no characters are supported",
+			str:  "M",
+			want: " ",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := Sanitize(tc.str)
+			if got != tc.want {
+				t.Errorf("Sanitize => %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
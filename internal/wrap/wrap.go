@@ -12,7 +12,8 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package wrap implements line wrapping at character or word boundaries.
+// Package wrap implements line wrapping at character or word boundaries and
+// expansion of tab characters.
 package wrap
 
 import (
@@ -58,16 +59,68 @@ const (
 	AtWords
 )
 
+// DefaultTabWidth is the default distance between tab stops in cells.
+const DefaultTabWidth = 8
+
+// Option is used to provide options to Cells.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// options stores the provided options.
+type options struct {
+	tabWidth int
+	tabCells map[*buffer.Cell]*buffer.Cell
+}
+
+// newOptions returns a new options instance.
+func newOptions(opts ...Option) *options {
+	opt := &options{
+		tabWidth: DefaultTabWidth,
+	}
+	for _, o := range opts {
+		o.set(opt)
+	}
+	return opt
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// TabWidth sets the distance between tab stops in cells. Tab characters are
+// expanded into space characters up to the next tab stop. Must be a positive
+// number. Defaults to DefaultTabWidth.
+func TabWidth(cells int) Option {
+	return option(func(opts *options) {
+		opts.tabWidth = cells
+	})
+}
+
+// TabCells records the space cells that replace tab characters in the
+// provided map. Each of the space cells maps to the tab cell it replaces,
+// which allows the caller to find the origin of the space cells in the input.
+func TabCells(m map[*buffer.Cell]*buffer.Cell) Option {
+	return option(func(opts *options) {
+		opts.tabCells = m
+	})
+}
+
 // ValidText validates the provided text for wrapping.
 // The text must not contain any control or space characters other
-// than '\n' and ' '.
+// than '\n', '\t' and ' '.
 func ValidText(text string) error {
 	if text == "" {
 		return errors.New("the text cannot be empty")
 	}
 
 	for _, c := range text {
-		if c == ' ' || c == '\n' { // Allowed space and control runes.
+		if c == ' ' || c == '\n' || c == '\t' { // Allowed space and control runes.
 			continue
 		}
 		if unicode.IsControl(c) {
@@ -90,18 +143,55 @@ func ValidCells(cells []*buffer.Cell) error {
 	return ValidText(b.String())
 }
 
+// ExpandTabs returns the text with any tab characters replaced by space
+// characters up to the next tab stop. The tab stops are placed every tabWidth
+// cells from the start of each line. Use this for text that is drawn on a
+// single line without wrapping, Cells expands tabs while wrapping.
+func ExpandTabs(text string, tabWidth int) string {
+	if tabWidth <= 0 || !strings.ContainsRune(text, '\t') {
+		return text
+	}
+
+	var b strings.Builder
+	col := 0
+	for _, r := range text {
+		switch r {
+		case '\t':
+			n := tabWidth - col%tabWidth
+			b.WriteString(strings.Repeat(" ", n))
+			col += n
+		case '\n':
+			b.WriteRune(r)
+			col = 0
+		default:
+			b.WriteRune(r)
+			col += runewidth.RuneWidth(r)
+		}
+	}
+	return b.String()
+}
+
 // Cells returns the cells wrapped into individual lines according to the
 // specified width and wrapping mode.
 //
 // This function consumes any cells that contain newline characters and uses
 // them to start new lines.
 //
+// Any cells that contain tab characters are replaced with cells containing
+// space characters up to the next tab stop, see the TabWidth option. The tab
+// stops are relative to the start of each wrapped line and don't extend past
+// the width.
+//
 // If the mode is AtWords, this function also drops cells with leading space
 // character before a word at which the wrap occurs.
-func Cells(cells []*buffer.Cell, width int, m Mode) ([][]*buffer.Cell, error) {
+func Cells(cells []*buffer.Cell, width int, m Mode, opts ...Option) ([][]*buffer.Cell, error) {
 	if err := ValidCells(cells); err != nil {
 		return nil, err
 	}
+	opt := newOptions(opts...)
+	if opt.tabWidth <= 0 {
+		return nil, fmt.Errorf("invalid TabWidth(%d), must be a positive number", opt.tabWidth)
+	}
 	switch m {
 	case Never:
 	case AtRunes:
@@ -113,7 +203,8 @@ func Cells(cells []*buffer.Cell, width int, m Mode) ([][]*buffer.Cell, error) {
 		return nil, nil
 	}
 
-	cs := newCellScanner(cells, width, m)
+	cs := newCellScanner(cells, width, m, opt.tabWidth)
+	cs.tabCells = opt.tabCells
 	for state := scanCellRunes; state != nil; state = state(cs) {
 	}
 	return cs.lines, nil
@@ -153,6 +244,13 @@ type cellScanner struct {
 	// mode is the wrapping mode.
 	mode Mode
 
+	// tabWidth is the distance between tab stops.
+	tabWidth int
+
+	// tabCells if not nil records the space cells that replace tab
+	// characters.
+	tabCells map[*buffer.Cell]*buffer.Cell
+
 	// atRunesInWord overrides the mode back to AtRunes.
 	atRunesInWord bool
 
@@ -164,11 +262,12 @@ type cellScanner struct {
 }
 
 // newCellScanner returns a scanner of the provided cells.
-func newCellScanner(cells []*buffer.Cell, width int, m Mode) *cellScanner {
+func newCellScanner(cells []*buffer.Cell, width int, m Mode, tabWidth int) *cellScanner {
 	return &cellScanner{
-		cells: cells,
-		width: width,
-		mode:  m,
+		cells:    cells,
+		width:    width,
+		mode:     m,
+		tabWidth: tabWidth,
 	}
 }
 
@@ -230,6 +329,7 @@ func (cs *cellScanner) isWordStart() bool {
 	switch nr := next.Rune; {
 	case nr == '\n':
 	case nr == ' ':
+	case nr == '\t':
 	default:
 		return true
 	}
@@ -249,6 +349,11 @@ func scanCellRunes(cs *cellScanner) cellScannerState {
 			return newLineForLineBreak
 		}
 
+		if r == '\t' {
+			cs.atRunesInWord = false
+			return tabToCurrentLine
+		}
+
 		if cs.mode == Never {
 			return runeToCurrentLine
 		}
@@ -280,6 +385,35 @@ func runeToCurrentLine(cs *cellScanner) cellScannerState {
 	return scanCellRunes
 }
 
+// tabToCurrentLine expands a tab character cell into space character cells up
+// to the next tab stop on the current line.
+func tabToCurrentLine(cs *cellScanner) cellScannerState {
+	tab := cs.peekPrev()
+	n := cs.tabWidth - cs.posX%cs.tabWidth
+	if cs.mode != Never {
+		if cs.posX >= cs.width {
+			// The current line is full, the tab starts the next line.
+			cs.lines = append(cs.lines, cs.line)
+			cs.posX = 0
+			cs.line = nil
+			n = cs.tabWidth
+		}
+		if max := cs.width - cs.posX; n > max {
+			n = max // The tab stops don't extend past the width.
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		space := buffer.NewCell(' ', tab.Opts)
+		if cs.tabCells != nil {
+			cs.tabCells[space] = tab
+		}
+		cs.line = append(cs.line, space)
+	}
+	cs.posX += n
+	return scanCellRunes
+}
+
 // newLineForLineBreak processes a newline character cell.
 func newLineForLineBreak(cs *cellScanner) cellScannerState {
 	cs.lines = append(cs.lines, cs.line)
@@ -359,7 +493,9 @@ func wrapWord(cs *cellScanner) cellScannerState {
 			continue
 		}
 
-		if !runeWrapNeeded(wc.Rune, cs.posX, cs.width) {
+		// A rune wider than the width doesn't fit onto any line, it is placed
+		// onto its own line.
+		if !runeWrapNeeded(wc.Rune, cs.posX, cs.width) || len(cs.line) == 0 {
 			cs.posX += runewidth.RuneWidth(wc.Rune)
 			cs.line = append(cs.line, wc)
 			continue
@@ -395,6 +531,7 @@ func isWordCell(c *buffer.Cell) bool {
 	switch r := c.Rune; {
 	case r == '\n':
 	case r == ' ':
+	case r == '\t':
 	default:
 		return true
 	}
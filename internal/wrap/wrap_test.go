@@ -77,9 +77,13 @@ func TestValidTextAndCells(t *testing.T) {
 			desc: "spaces are allowed",
 			text: "   ",
 		},
+		{
+			desc: "tabs are allowed",
+			text: "\t\t",
+		},
 		{
 			desc:    "no other space characters",
-			text:    fmt.Sprintf("\t\v\f\r%c%c", 0x85, 0xA0),
+			text:    fmt.Sprintf("\v\f\r%c%c", 0x85, 0xA0),
 			wantErr: true,
 		},
 		{
@@ -132,17 +136,111 @@ func TestCells(t *testing.T) {
 		// width is the width of the canvas.
 		width   int
 		mode    Mode
+		opts    []Option
 		want    [][]*buffer.Cell
 		wantErr bool
 	}{
+		{
+			desc:    "fails with zero tab width",
+			cells:   buffer.NewCells("hello"),
+			width:   1,
+			opts:    []Option{TabWidth(0)},
+			wantErr: true,
+		},
+		{
+			desc:  "wrapping disabled, expands tabs to the default tab stops",
+			cells: buffer.NewCells("a\tb\n\tc"),
+			width: 5,
+			mode:  Never,
+			want: [][]*buffer.Cell{
+				buffer.NewCells("a       b"),
+				buffer.NewCells("        c"),
+			},
+		},
+		{
+			desc:  "expands tabs to the configured tab stops",
+			cells: buffer.NewCells("a\tbcde\tf\t\tg"),
+			width: 20,
+			mode:  AtRunes,
+			opts:  []Option{TabWidth(4)},
+			want: [][]*buffer.Cell{
+				buffer.NewCells("a   bcde    f       g"[:20]),
+				buffer.NewCells("g"),
+			},
+		},
+		{
+			desc:  "tab stops account for full-width runes",
+			cells: buffer.NewCells("世\tx"),
+			width: 10,
+			mode:  AtRunes,
+			opts:  []Option{TabWidth(4)},
+			want: [][]*buffer.Cell{
+				buffer.NewCells("世  x"),
+			},
+		},
+		{
+			desc:  "expanded tab keeps the cell options",
+			cells: buffer.NewCells("\t", cell.FgColor(cell.ColorRed)),
+			width: 10,
+			mode:  AtRunes,
+			opts:  []Option{TabWidth(2)},
+			want: [][]*buffer.Cell{
+				buffer.NewCells("  ", cell.FgColor(cell.ColorRed)),
+			},
+		},
+		{
+			desc:  "wrapping at runes, tab stops are relative to the wrapped line",
+			cells: buffer.NewCells("abcdef\tg"),
+			width: 4,
+			mode:  AtRunes,
+			opts:  []Option{TabWidth(4)},
+			want: [][]*buffer.Cell{
+				buffer.NewCells("abcd"),
+				buffer.NewCells("ef  "),
+				buffer.NewCells("g"),
+			},
+		},
+		{
+			desc:  "wrapping at runes, tab doesn't extend past the width",
+			cells: buffer.NewCells("ab\tc"),
+			width: 6,
+			mode:  AtRunes,
+			opts:  []Option{TabWidth(8)},
+			want: [][]*buffer.Cell{
+				buffer.NewCells("ab    "),
+				buffer.NewCells("c"),
+			},
+		},
+		{
+			desc:  "wrapping at runes, tab on a full line starts the next line",
+			cells: buffer.NewCells("abcd\tx"),
+			width: 4,
+			mode:  AtRunes,
+			opts:  []Option{TabWidth(2)},
+			want: [][]*buffer.Cell{
+				buffer.NewCells("abcd"),
+				buffer.NewCells("  x"),
+			},
+		},
+		{
+			desc:  "wrapping at words, tabs separate words",
+			cells: buffer.NewCells("ab\tcd\tefgh"),
+			width: 8,
+			mode:  AtWords,
+			opts:  []Option{TabWidth(4)},
+			want: [][]*buffer.Cell{
+				buffer.NewCells("ab  cd  "),
+				buffer.NewCells("efgh"),
+			},
+		},
 		{
 			desc:    "fails with zero text",
 			width:   1,
 			wantErr: true,
 		},
 		{
-			desc:    "fails with invalid runes (tabs)",
-			cells:   buffer.NewCells("hello\t"),
+			desc:    "fails with invalid runes (carriage return)",
+			cells:   buffer.NewCells("hello\r"),
 			width:   1,
 			wantErr: true,
 		},
@@ -650,6 +748,17 @@ func TestCells(t *testing.T) {
 				buffer.NewCells("世", cell.FgColor(cell.ColorRed), cell.BgColor(cell.ColorBlue)),
 			},
 		},
+		{
+			desc:  "wraps full-width runes wider than the width at words",
+			cells: buffer.NewCells("世界 a"),
+			width: 1,
+			mode:  AtWords,
+			want: [][]*buffer.Cell{
+				buffer.NewCells("世"),
+				buffer.NewCells("界"),
+				buffer.NewCells("a"),
+			},
+		},
 		{
 			desc:  "inserted dash inherits cell options",
 			cells: buffer.NewCells("abc", cell.FgColor(cell.ColorRed), cell.BgColor(cell.ColorBlue)),
@@ -665,7 +774,7 @@ func TestCells(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.desc, func(t *testing.T) {
 			t.Logf(fmt.Sprintf("Mode: %v", tc.mode))
-			got, err := Cells(tc.cells, tc.width, tc.mode)
+			got, err := Cells(tc.cells, tc.width, tc.mode, tc.opts...)
 			if (err != nil) != tc.wantErr {
 				t.Errorf("Cells => unexpected error %v, wantErr %v", err, tc.wantErr)
 			}
@@ -680,6 +789,93 @@ func TestCells(t *testing.T) {
 
 }
 
+func TestCellsRecordsTabCells(t *testing.T) {
+	cells := buffer.NewCells("a\tb\t\tc")
+	tabCells := map[*buffer.Cell]*buffer.Cell{}
+	got, err := Cells(cells, 6, AtRunes, TabWidth(3), TabCells(tabCells))
+	if err != nil {
+		t.Fatalf("Cells => unexpected error: %v", err)
+	}
+
+	// Maps the cells on the wrapped lines to their index in the input, -1 for
+	// cells that aren't in the input or a replaced tab.
+	indexes := map[*buffer.Cell]int{}
+	for i, c := range cells {
+		indexes[c] = i
+	}
+	var gotIdx [][]int
+	for _, line := range got {
+		var l []int
+		for _, c := range line {
+			if tab, ok := tabCells[c]; ok {
+				c = tab
+			}
+			idx, ok := indexes[c]
+			if !ok {
+				idx = -1
+			}
+			l = append(l, idx)
+		}
+		gotIdx = append(gotIdx, l)
+	}
+
+	want := [][]int{
+		{0, 1, 1, 2, 3, 3},
+		{4, 4, 4, 5},
+	}
+	if diff := pretty.Compare(want, gotIdx); diff != "" {
+		t.Errorf("Cells => unexpected cell indexes, diff (-want, +got):\n%s", diff)
+	}
+}
+
+func TestExpandTabs(t *testing.T) {
+	tests := []struct {
+		desc     string
+		text     string
+		tabWidth int
+		want     string
+	}{
+		{
+			desc:     "no tabs",
+			text:     "hello world",
+			tabWidth: 4,
+			want:     "hello world",
+		},
+		{
+			desc:     "expands tabs to the tab stops",
+			text:     "a\tbcde\tf",
+			tabWidth: 4,
+			want:     "a   bcde    f",
+		},
+		{
+			desc:     "tab stops restart on each line",
+			text:     "abc\td\n\te",
+			tabWidth: 4,
+			want:     "abc d\n    e",
+		},
+		{
+			desc:     "tab stops account for full-width runes",
+			text:     "世\tx",
+			tabWidth: 4,
+			want:     "世  x",
+		},
+		{
+			desc:     "invalid tab width leaves the text unchanged",
+			text:     "a\tb",
+			tabWidth: 0,
+			want:     "a\tb",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := ExpandTabs(tc.text, tc.tabWidth); got != tc.want {
+				t.Errorf("ExpandTabs => %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
 func TestRuneWrapNeeded(t *testing.T) {
 	tests := []struct {
 		desc  string
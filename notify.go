@@ -0,0 +1,419 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package termdash
+
+// notify.go contains code that displays transient notifications (toasts).
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"time"
+
+	"github.com/mum4k/termdash/align"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/alignfor"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/draw"
+	"github.com/mum4k/termdash/internal/runewidth"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+// NotificationLevel indicates the severity of a notification.
+type NotificationLevel int
+
+// String implements fmt.Stringer()
+func (nl NotificationLevel) String() string {
+	if n, ok := notificationLevelNames[nl]; ok {
+		return n
+	}
+	return "NotificationLevelUnknown"
+}
+
+// notificationLevelNames maps NotificationLevel values to human readable names.
+var notificationLevelNames = map[NotificationLevel]string{
+	NotificationInfo:    "NotificationInfo",
+	NotificationSuccess: "NotificationSuccess",
+	NotificationWarning: "NotificationWarning",
+	NotificationError:   "NotificationError",
+}
+
+const (
+	// NotificationInfo is an informational notification.
+	NotificationInfo NotificationLevel = iota
+
+	// NotificationSuccess notifies about a successfully completed operation.
+	NotificationSuccess
+
+	// NotificationWarning notifies about a condition that might need
+	// attention.
+	NotificationWarning
+
+	// NotificationError notifies about a failure.
+	NotificationError
+)
+
+// DefaultNotificationColors are the default colors of notifications for each
+// of the levels.
+var DefaultNotificationColors = map[NotificationLevel]cell.Color{
+	NotificationInfo:    cell.ColorBlue,
+	NotificationSuccess: cell.ColorGreen,
+	NotificationWarning: cell.ColorYellow,
+	NotificationError:   cell.ColorRed,
+}
+
+// DefaultNotificationMaxWidth is the maximum width of a notification in
+// cells including its border. Longer messages are trimmed.
+const DefaultNotificationMaxWidth = 40
+
+// notificationHeight is the height of a notification in cells, one line for
+// the message and two for the border.
+const notificationHeight = 3
+
+// notificationMinWidth is the minimum width of a notification in cells, room
+// for the border, padding and one character of the message.
+const notificationMinWidth = 5
+
+// NotificationAlign sets the corner or edge of the terminal where
+// notifications are displayed. The newest notification is displayed closest
+// to the terminal edge.
+// Defaults to the top right corner.
+func NotificationAlign(h align.Horizontal, v align.Vertical) Option {
+	return option(func(td *termdash) {
+		td.notifier.hAlign = h
+		td.notifier.vAlign = v
+	})
+}
+
+// NotificationColor sets the color of the border and the message of
+// notifications of the specified level. The level must be one of the
+// supported notification levels.
+// Defaults to the color in DefaultNotificationColors.
+func NotificationColor(level NotificationLevel, color cell.Color) Option {
+	return option(func(td *termdash) {
+		td.notifier.colors[level] = color
+	})
+}
+
+// NotificationDismissKey sets a keyboard key that dismisses the newest
+// notification. The key is still forwarded to the container and any
+// subscribers.
+// By default notifications are only dismissed by a left mouse click or when
+// they expire.
+func NotificationDismissKey(k keyboard.Key) Option {
+	return option(func(td *termdash) {
+		td.notifier.dismissKey = &k
+	})
+}
+
+// Notify displays a notification with the provided message above the
+// container. Notifications are stacked in a corner of the terminal and
+// disappear after the specified duration, a duration of zero displays the
+// notification until it is dismissed. Clicking on a notification with the
+// left mouse button dismisses it. Mouse events on notifications aren't
+// forwarded to the container or the MouseSubscriber.
+//
+// Unlike the other methods of the controller, Notify is thread-safe so it can
+// be called from background goroutines. The terminal is redrawn immediately
+// and again when the notification expires.
+func (c *Controller) Notify(msg string, level NotificationLevel, d time.Duration) error {
+	if msg == "" {
+		return errors.New("the notification message must not be empty")
+	}
+	if _, ok := notificationLevelNames[level]; !ok {
+		return fmt.Errorf("unsupported notification level %v(%d)", level, level)
+	}
+	if d < 0 {
+		return fmt.Errorf("invalid notification duration %v, must be zero or positive", d)
+	}
+
+	td := c.notifyTD
+	td.mu.Lock()
+	defer td.mu.Unlock()
+	if td.notifier.stopped {
+		return errors.New("the termdash instance is no longer running, this controller is now invalid")
+	}
+	td.notifier.add(msg, level, d, td.notificationExpired)
+	// Notifications already displayed might move.
+	td.clearNeeded = true
+	return td.redraw()
+}
+
+// notificationExpired redraws the terminal once a notification expires.
+func (td *termdash) notificationExpired() {
+	td.mu.Lock()
+	defer td.mu.Unlock()
+	if td.notifier.stopped {
+		return
+	}
+	if err := td.redraw(); err != nil {
+		td.handleError(err)
+	}
+}
+
+// evNotification handles mouse events on the displayed notifications.
+// Returns true if the event was on a notification, these events are consumed
+// so that they don't reach the widgets below the notification.
+func (td *termdash) evNotification(ev terminalapi.Event) bool {
+	m, ok := ev.(*terminalapi.Mouse)
+	if !ok {
+		return false
+	}
+
+	td.mu.Lock()
+	defer td.mu.Unlock()
+	if !td.notifier.at(m.Position) {
+		return false
+	}
+	if td.notifier.dismiss(ev) {
+		// Dismissed notifications leave content behind that the widgets
+		// might not overwrite.
+		td.clearNeeded = true
+		if err := td.redraw(); err != nil {
+			td.handleError(err)
+		}
+	}
+	return true
+}
+
+// toast is a single displayed notification.
+type toast struct {
+	// msg is the notification message.
+	msg string
+	// level is the notification level.
+	level NotificationLevel
+	// expires is the time when the notification expires. Zero if the
+	// notification doesn't expire.
+	expires time.Time
+	// timer triggers a redraw when the notification expires.
+	timer *time.Timer
+	// area is where the notification was last drawn, empty if it didn't fit.
+	area image.Rectangle
+}
+
+// notifier tracks and draws the displayed notifications.
+// This is not thread-safe, the owner must perform locking.
+type notifier struct {
+	// toasts are the displayed notifications, the oldest first.
+	toasts []*toast
+
+	// stopped is set once termdash stops, no more redraws are triggered when
+	// notifications expire.
+	stopped bool
+
+	// timeNow returns the current time.
+	timeNow func() time.Time
+
+	// Options.
+	colors     map[NotificationLevel]cell.Color
+	hAlign     align.Horizontal
+	vAlign     align.Vertical
+	dismissKey *keyboard.Key
+}
+
+// newNotifier returns a new notifier with the default options.
+func newNotifier() *notifier {
+	colors := map[NotificationLevel]cell.Color{}
+	for l, c := range DefaultNotificationColors {
+		colors[l] = c
+	}
+	return &notifier{
+		timeNow: time.Now,
+		colors:  colors,
+		hAlign:  align.HorizontalRight,
+		vAlign:  align.VerticalTop,
+	}
+}
+
+// add adds a notification. The onExpire function is called when a
+// notification with a non-zero duration expires.
+func (n *notifier) add(msg string, level NotificationLevel, d time.Duration, onExpire func()) {
+	t := &toast{
+		msg:   msg,
+		level: level,
+	}
+	if d > 0 {
+		t.expires = n.timeNow().Add(d)
+		t.timer = time.AfterFunc(d, onExpire)
+	}
+	n.toasts = append(n.toasts, t)
+}
+
+// remove removes the notification at the specified index.
+func (n *notifier) remove(i int) {
+	if t := n.toasts[i]; t.timer != nil {
+		t.timer.Stop()
+	}
+	n.toasts = append(n.toasts[:i], n.toasts[i+1:]...)
+}
+
+// expire removes notifications that expired.
+// Returns true if any notifications were removed.
+func (n *notifier) expire() bool {
+	now := n.timeNow()
+	var removed bool
+	for i := len(n.toasts) - 1; i >= 0; i-- {
+		if exp := n.toasts[i].expires; !exp.IsZero() && !now.Before(exp) {
+			n.remove(i)
+			removed = true
+		}
+	}
+	return removed
+}
+
+// at determines if a notification is displayed at the point.
+func (n *notifier) at(p image.Point) bool {
+	for _, t := range n.toasts {
+		if p.In(t.area) {
+			return true
+		}
+	}
+	return false
+}
+
+// dismiss removes the notification that the event dismisses, if any.
+// Returns true if a notification was removed.
+func (n *notifier) dismiss(ev terminalapi.Event) bool {
+	switch e := ev.(type) {
+	case *terminalapi.Mouse:
+		if e.Button != mouse.ButtonLeft {
+			return false
+		}
+		for i, t := range n.toasts {
+			if e.Position.In(t.area) {
+				n.remove(i)
+				return true
+			}
+		}
+
+	case *terminalapi.Keyboard:
+		if n.dismissKey == nil || e.Key != *n.dismissKey || len(n.toasts) == 0 {
+			return false
+		}
+		n.remove(len(n.toasts) - 1)
+		return true
+	}
+	return false
+}
+
+// validate validates the provided options.
+func (n *notifier) validate() error {
+	for l := range n.colors {
+		if _, ok := notificationLevelNames[l]; !ok {
+			return fmt.Errorf("unsupported NotificationColor level %v(%d)", l, l)
+		}
+	}
+	return nil
+}
+
+// stop stops all the expiry timers.
+func (n *notifier) stop() {
+	n.stopped = true
+	for _, t := range n.toasts {
+		if t.timer != nil {
+			t.timer.Stop()
+		}
+	}
+}
+
+// width returns the width of the notification on a terminal of the provided
+// width.
+func (n *notifier) width(t *toast, termWidth int) int {
+	width := runewidth.StringWidth(t.msg) + 4 // Border and padding.
+	if width > DefaultNotificationMaxWidth {
+		width = DefaultNotificationMaxWidth
+	}
+	if width > termWidth {
+		width = termWidth
+	}
+	return width
+}
+
+// draw draws the notifications on the terminal. Notifications that don't fit
+// are skipped, starting with the oldest.
+func (n *notifier) draw(term terminalapi.Terminal) error {
+	if len(n.toasts) == 0 {
+		return nil
+	}
+
+	size := term.Size()
+	termAr := image.Rect(0, 0, size.X, size.Y)
+	for _, t := range n.toasts {
+		t.area = image.ZR
+	}
+	if size.X < notificationMinWidth {
+		return nil
+	}
+
+	// The newest notifications that fit on the terminal, newest first.
+	var visible []*toast
+	for i := len(n.toasts) - 1; i >= 0; i-- {
+		if (len(visible)+1)*notificationHeight > size.Y {
+			break
+		}
+		visible = append(visible, n.toasts[i])
+	}
+	if len(visible) == 0 {
+		return nil
+	}
+	// The newest notification is closest to the terminal edge.
+	if n.vAlign == align.VerticalBottom {
+		for i, j := 0, len(visible)-1; i < j; i, j = i+1, j-1 {
+			visible[i], visible[j] = visible[j], visible[i]
+		}
+	}
+
+	stackHeight := len(visible) * notificationHeight
+	stack, err := alignfor.Rectangle(termAr, image.Rect(0, 0, size.X, stackHeight), n.hAlign, n.vAlign)
+	if err != nil {
+		return err
+	}
+
+	for i, t := range visible {
+		row := image.Rect(stack.Min.X, stack.Min.Y+i*notificationHeight, stack.Max.X, stack.Min.Y+(i+1)*notificationHeight)
+		toastAr := image.Rect(row.Min.X, row.Min.Y, row.Min.X+n.width(t, size.X), row.Max.Y)
+		ar, err := alignfor.Rectangle(row, toastAr, n.hAlign, align.VerticalTop)
+		if err != nil {
+			return err
+		}
+		if err := n.drawToast(term, t, ar); err != nil {
+			return err
+		}
+		t.area = ar
+	}
+	return nil
+}
+
+// drawToast draws a single notification into the area.
+func (n *notifier) drawToast(term terminalapi.Terminal, t *toast, ar image.Rectangle) error {
+	cvs, err := canvas.New(ar)
+	if err != nil {
+		return err
+	}
+	color := n.colors[t.level]
+	if err := draw.Border(cvs, cvs.Area(), draw.BorderCellOpts(cell.FgColor(color))); err != nil {
+		return err
+	}
+	if err := draw.Text(cvs, t.msg, image.Point{2, 1},
+		draw.TextCellOpts(cell.FgColor(color)),
+		draw.TextMaxX(cvs.Area().Max.X-2),
+		draw.TextOverrunMode(draw.OverrunModeThreeDot),
+	); err != nil {
+		return err
+	}
+	return cvs.Apply(term)
+}
@@ -0,0 +1,412 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package termdash
+
+import (
+	"fmt"
+	"image"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mum4k/termdash/align"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/internal/canvas/testcanvas"
+	"github.com/mum4k/termdash/internal/draw"
+	"github.com/mum4k/termdash/internal/draw/testdraw"
+	"github.com/mum4k/termdash/internal/event"
+	"github.com/mum4k/termdash/internal/event/eventqueue"
+	"github.com/mum4k/termdash/internal/event/testevent"
+	"github.com/mum4k/termdash/internal/faketerm"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+// mustDrawToast draws a notification into the area or panics.
+func mustDrawToast(ft *faketerm.Terminal, ar image.Rectangle, msg string, color cell.Color) {
+	cvs := testcanvas.MustNew(ar)
+	testdraw.MustBorder(cvs, cvs.Area(), draw.BorderCellOpts(cell.FgColor(color)))
+	testdraw.MustText(cvs, msg, image.Point{2, 1},
+		draw.TextCellOpts(cell.FgColor(color)),
+		draw.TextMaxX(cvs.Area().Max.X-2),
+		draw.TextOverrunMode(draw.OverrunModeThreeDot),
+	)
+	testcanvas.MustApply(cvs, ft)
+}
+
+// notification is a call to Notify.
+type notification struct {
+	msg   string
+	level NotificationLevel
+	d     time.Duration
+}
+
+func TestNotify(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc   string
+		size   image.Point
+		opts   []Option
+		notify []notification
+		events []terminalapi.Event
+		// The number of expected processed events, used for synchronization.
+		// Equals len(events) * number of subscribers for the event type.
+		wantProcessed int
+		want          func(size image.Point) *faketerm.Terminal
+		wantErr       bool
+	}{
+		{
+			desc: "fails on empty message",
+			size: image.Point{30, 10},
+			notify: []notification{
+				{msg: "", level: NotificationInfo},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantErr: true,
+		},
+		{
+			desc: "fails on unsupported level",
+			size: image.Point{30, 10},
+			notify: []notification{
+				{msg: "hello", level: NotificationLevel(-1)},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantErr: true,
+		},
+		{
+			desc: "fails on negative duration",
+			size: image.Point{30, 10},
+			notify: []notification{
+				{msg: "hello", level: NotificationInfo, d: -1},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantErr: true,
+		},
+		{
+			desc: "draws notification in the top right corner",
+			size: image.Point{30, 10},
+			notify: []notification{
+				{msg: "hello", level: NotificationInfo},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				mustDrawToast(ft, image.Rect(21, 0, 30, 3), "hello", cell.ColorBlue)
+				return ft
+			},
+		},
+		{
+			desc: "stacks notifications newest first in per level colors",
+			size: image.Point{30, 10},
+			notify: []notification{
+				{msg: "deploy finished", level: NotificationSuccess},
+				{msg: "scrape failed", level: NotificationError},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				mustDrawToast(ft, image.Rect(13, 0, 30, 3), "scrape failed", cell.ColorRed)
+				mustDrawToast(ft, image.Rect(11, 3, 30, 6), "deploy finished", cell.ColorGreen)
+				return ft
+			},
+		},
+		{
+			desc: "stacks notifications from the bottom left corner with custom color",
+			size: image.Point{30, 10},
+			opts: []Option{
+				NotificationAlign(align.HorizontalLeft, align.VerticalBottom),
+				NotificationColor(NotificationWarning, cell.ColorMagenta),
+			},
+			notify: []notification{
+				{msg: "first", level: NotificationWarning},
+				{msg: "second", level: NotificationInfo},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				mustDrawToast(ft, image.Rect(0, 4, 9, 7), "first", cell.ColorMagenta)
+				mustDrawToast(ft, image.Rect(0, 7, 10, 10), "second", cell.ColorBlue)
+				return ft
+			},
+		},
+		{
+			desc: "skips the oldest notifications that don't fit",
+			size: image.Point{30, 5},
+			notify: []notification{
+				{msg: "first", level: NotificationInfo},
+				{msg: "second", level: NotificationInfo},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				mustDrawToast(ft, image.Rect(20, 0, 30, 3), "second", cell.ColorBlue)
+				return ft
+			},
+		},
+		{
+			desc: "trims long messages",
+			size: image.Point{10, 5},
+			notify: []notification{
+				{msg: "deploy finished", level: NotificationInfo},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				mustDrawToast(ft, image.Rect(0, 0, 10, 3), "deploy finished", cell.ColorBlue)
+				return ft
+			},
+		},
+		{
+			desc: "left click dismisses the notification",
+			size: image.Point{30, 10},
+			notify: []notification{
+				{msg: "first", level: NotificationInfo},
+				{msg: "second", level: NotificationInfo},
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{25, 1}, Button: mouse.ButtonRight},
+				&terminalapi.Mouse{Position: image.Point{25, 1}, Button: mouse.ButtonLeft},
+			},
+			wantProcessed: 4,
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				mustDrawToast(ft, image.Rect(21, 0, 30, 3), "first", cell.ColorBlue)
+				return ft
+			},
+		},
+		{
+			desc: "dismiss key dismisses the newest notification",
+			size: image.Point{30, 10},
+			opts: []Option{
+				NotificationDismissKey(keyboard.KeyEsc),
+			},
+			notify: []notification{
+				{msg: "first", level: NotificationInfo},
+				{msg: "second", level: NotificationInfo},
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyEsc},
+			},
+			wantProcessed: 2,
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				mustDrawToast(ft, image.Rect(21, 0, 30, 3), "first", cell.ColorBlue)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			tc := tc
+			t.Parallel()
+
+			got, err := faketerm.New(tc.size, faketerm.WithEventQueue(eventqueue.New()))
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			cont, err := container.New(got)
+			if err != nil {
+				t.Fatalf("container.New => unexpected error: %v", err)
+			}
+
+			eds := event.NewDistributionSystem()
+			opts := tc.opts
+			opts = append(opts, withEDS(eds))
+			ctrl, err := NewController(got, cont, opts...)
+			if err != nil {
+				t.Fatalf("NewController => unexpected error: %v", err)
+			}
+			defer ctrl.Close()
+
+			for _, n := range tc.notify {
+				err := ctrl.Notify(n.msg, n.level, n.d)
+				if (err != nil) != tc.wantErr {
+					t.Errorf("Notify => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+				}
+				if err != nil {
+					return
+				}
+			}
+
+			for _, ev := range tc.events {
+				eds.Event(ev)
+			}
+			if err := testevent.WaitFor(5*time.Second, func() error {
+				if got, want := eds.Processed(), tc.wantProcessed; got != want {
+					return fmt.Errorf("the event distribution system processed %d events, want %d", got, want)
+				}
+				return nil
+			}); err != nil {
+				t.Fatalf("testevent.WaitFor => %v", err)
+			}
+
+			if diff := faketerm.Diff(tc.want(got.Size()), got); diff != "" {
+				t.Errorf("Notify => %v", diff)
+			}
+		})
+	}
+}
+
+func TestNotifyExpires(t *testing.T) {
+	t.Parallel()
+
+	eq := eventqueue.New()
+	got, err := faketerm.New(image.Point{30, 10}, faketerm.WithEventQueue(eq))
+	if err != nil {
+		t.Fatalf("faketerm.New => unexpected error: %v", err)
+	}
+	cont, err := container.New(got)
+	if err != nil {
+		t.Fatalf("container.New => unexpected error: %v", err)
+	}
+	ctrl, err := NewController(got, cont)
+	if err != nil {
+		t.Fatalf("NewController => unexpected error: %v", err)
+	}
+	defer ctrl.Close()
+
+	now := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctrl.td.mu.Lock()
+	ctrl.td.notifier.timeNow = func() time.Time { return now }
+	ctrl.td.mu.Unlock()
+
+	if err := ctrl.Notify("sticky", NotificationInfo, 0); err != nil {
+		t.Fatalf("Notify => unexpected error: %v", err)
+	}
+	if err := ctrl.Notify("brief", NotificationInfo, time.Hour); err != nil {
+		t.Fatalf("Notify => unexpected error: %v", err)
+	}
+
+	want := faketerm.MustNew(got.Size())
+	mustDrawToast(want, image.Rect(21, 0, 30, 3), "brief", cell.ColorBlue)
+	mustDrawToast(want, image.Rect(20, 3, 30, 6), "sticky", cell.ColorBlue)
+	if diff := faketerm.Diff(want, got); diff != "" {
+		t.Errorf("Notify => %v", diff)
+	}
+
+	ctrl.td.mu.Lock()
+	ctrl.td.notifier.timeNow = func() time.Time { return now.Add(time.Hour) }
+	ctrl.td.mu.Unlock()
+	if err := ctrl.Redraw(); err != nil {
+		t.Fatalf("Redraw => unexpected error: %v", err)
+	}
+
+	want = faketerm.MustNew(got.Size())
+	mustDrawToast(want, image.Rect(20, 0, 30, 3), "sticky", cell.ColorBlue)
+	if diff := faketerm.Diff(want, got); diff != "" {
+		t.Errorf("Redraw after expiry => %v", diff)
+	}
+}
+
+func TestNotifyConcurrentWithClose(t *testing.T) {
+	t.Parallel()
+
+	got, err := faketerm.New(image.Point{30, 10}, faketerm.WithEventQueue(eventqueue.New()))
+	if err != nil {
+		t.Fatalf("faketerm.New => unexpected error: %v", err)
+	}
+	cont, err := container.New(got)
+	if err != nil {
+		t.Fatalf("container.New => unexpected error: %v", err)
+	}
+	ctrl, err := NewController(got, cont)
+	if err != nil {
+		t.Fatalf("NewController => unexpected error: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				// Fails once the controller is closed.
+				ctrl.Notify("msg", NotificationInfo, time.Millisecond)
+			}
+		}()
+	}
+	ctrl.Close()
+	wg.Wait()
+
+	if err := ctrl.Notify("msg", NotificationInfo, 0); err == nil {
+		t.Errorf("Notify after Close => got nil error, want one")
+	}
+}
+
+func TestNotificationColorFailsOnUnsupportedLevel(t *testing.T) {
+	t.Parallel()
+
+	got, err := faketerm.New(image.Point{30, 10})
+	if err != nil {
+		t.Fatalf("faketerm.New => unexpected error: %v", err)
+	}
+	cont, err := container.New(got)
+	if err != nil {
+		t.Fatalf("container.New => unexpected error: %v", err)
+	}
+	if _, err := NewController(got, cont, NotificationColor(NotificationLevel(-1), cell.ColorRed)); err == nil {
+		t.Errorf("NewController => got nil error, want one")
+	}
+}
+
+func TestNotificationConsumesMouseEvents(t *testing.T) {
+	t.Parallel()
+
+	eq := eventqueue.New()
+	got, err := faketerm.New(image.Point{30, 10}, faketerm.WithEventQueue(eq))
+	if err != nil {
+		t.Fatalf("faketerm.New => unexpected error: %v", err)
+	}
+	cont, err := container.New(got)
+	if err != nil {
+		t.Fatalf("container.New => unexpected error: %v", err)
+	}
+	received := make(chan *terminalapi.Mouse, 2)
+	ctrl, err := NewController(got, cont, MouseSubscriber(func(m *terminalapi.Mouse) {
+		received <- m
+	}))
+	if err != nil {
+		t.Fatalf("NewController => unexpected error: %v", err)
+	}
+	defer ctrl.Close()
+
+	if err := ctrl.Notify("hello", NotificationInfo, 0); err != nil {
+		t.Fatalf("Notify => unexpected error: %v", err)
+	}
+	// Dismisses the notification without reaching the subscriber.
+	eq.Push(&terminalapi.Mouse{Position: image.Point{25, 1}, Button: mouse.ButtonLeft})
+	// Not on a notification anymore.
+	eq.Push(&terminalapi.Mouse{Position: image.Point{25, 1}, Button: mouse.ButtonRelease})
+
+	select {
+	case m := <-received:
+		if m.Button != mouse.ButtonRelease {
+			t.Errorf("MouseSubscriber => got %v, want the release", m)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("MouseSubscriber => timed out waiting for the mouse event")
+	}
+
+	ctrl.td.mu.Lock()
+	defer ctrl.td.mu.Unlock()
+	if n := len(ctrl.td.notifier.toasts); n != 0 {
+		t.Errorf("Notify => %d notifications remain after the click, want 0", n)
+	}
+}
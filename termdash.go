@@ -103,9 +103,12 @@ func withEDS(eds *event.DistributionSystem) Option {
 // Controller instead.
 // Blocks until the context expires.
 func Run(ctx context.Context, t terminalapi.Terminal, c *container.Container, opts ...Option) error {
-	td := newTermdash(t, c, opts...)
+	td, err := newTermdash(t, c, opts...)
+	if err != nil {
+		return err
+	}
 
-	err := td.start(ctx)
+	err = td.start(ctx)
 	// Only return the status (error or nil) after the termdash event
 	// processing goroutine actually exits.
 	td.stop()
@@ -118,6 +121,10 @@ func Run(ctx context.Context, t terminalapi.Terminal, c *container.Container, op
 type Controller struct {
 	td     *termdash
 	cancel context.CancelFunc
+
+	// notifyTD is the same termdash instance as td. Unlike td it is never
+	// reset, so Notify can run concurrently with Close.
+	notifyTD *termdash
 }
 
 // NewController initializes termdash and returns an instance of the controller.
@@ -125,10 +132,15 @@ type Controller struct {
 // option is ignored.
 // Close the controller when it isn't needed anymore.
 func NewController(t terminalapi.Terminal, c *container.Container, opts ...Option) (*Controller, error) {
+	td, err := newTermdash(t, c, opts...)
+	if err != nil {
+		return nil, err
+	}
 	ctx, cancel := context.WithCancel(context.Background())
 	ctrl := &Controller{
-		td:     newTermdash(t, c, opts...),
-		cancel: cancel,
+		td:       td,
+		cancel:   cancel,
+		notifyTD: td,
 	}
 
 	// stops when Close() is called.
@@ -179,6 +191,9 @@ type termdash struct {
 	// we're drawing it. Terminal needs to be cleared if its sized changed.
 	clearNeeded bool
 
+	// notifier tracks and draws notifications displayed above the container.
+	notifier *notifier
+
 	// mu protects termdash.
 	mu sync.Mutex
 
@@ -190,22 +205,26 @@ type termdash struct {
 }
 
 // newTermdash creates a new termdash.
-func newTermdash(t terminalapi.Terminal, c *container.Container, opts ...Option) *termdash {
+func newTermdash(t terminalapi.Terminal, c *container.Container, opts ...Option) (*termdash, error) {
 	td := &termdash{
 		term:           t,
 		container:      c,
 		eds:            event.NewDistributionSystem(),
 		closeCh:        make(chan struct{}),
 		exitCh:         make(chan struct{}),
+		notifier:       newNotifier(),
 		redrawInterval: DefaultRedrawInterval,
 	}
 
 	for _, opt := range opts {
 		opt.set(td)
 	}
+	if err := td.notifier.validate(); err != nil {
+		return nil, err
+	}
 	td.subscribers()
 	c.Subscribe(td.eds)
-	return td
+	return td, nil
 }
 
 // subscribers subscribes event receivers that live in this package to EDS.
@@ -226,8 +245,8 @@ func (td *termdash) subscribers() {
 	td.eds.Subscribe([]terminalapi.Event{
 		&terminalapi.Keyboard{},
 		&terminalapi.Mouse{},
-	}, func(terminalapi.Event) {
-		td.evRedraw()
+	}, func(ev terminalapi.Event) {
+		td.evRedraw(ev)
 	}, event.MaxRepetitive(0)) // No repetitive events that cause terminal redraw.
 
 	// Keyboard and Mouse subscribers specified via options.
@@ -264,6 +283,11 @@ func (td *termdash) setClearNeeded() {
 // redraw redraws the container and its widgets.
 // The caller must hold td.mu.
 func (td *termdash) redraw() error {
+	// Expired notifications leave content behind that the widgets might not
+	// overwrite.
+	if td.notifier.expire() {
+		td.clearNeeded = true
+	}
 	if td.clearNeeded {
 		if err := td.term.Clear(); err != nil {
 			return fmt.Errorf("term.Clear => error: %v", err)
@@ -274,6 +298,9 @@ func (td *termdash) redraw() error {
 	if err := td.container.Draw(); err != nil {
 		return fmt.Errorf("container.Draw => error: %v", err)
 	}
+	if err := td.notifier.draw(td.term); err != nil {
+		return fmt.Errorf("notifier.draw => error: %v", err)
+	}
 
 	if err := td.term.Flush(); err != nil {
 		return fmt.Errorf("term.Flush => error: %v", err)
@@ -282,10 +309,17 @@ func (td *termdash) redraw() error {
 }
 
 // evRedraw redraws the container and its widgets.
-func (td *termdash) evRedraw() error {
+// The event dismisses any notifications it targets.
+func (td *termdash) evRedraw(ev terminalapi.Event) error {
 	td.mu.Lock()
 	defer td.mu.Unlock()
 
+	// Dismissed notifications leave content behind that the widgets might
+	// not overwrite.
+	if td.notifier.dismiss(ev) {
+		td.clearNeeded = true
+	}
+
 	// Don't redraw immediately, give widgets that are performing enough time
 	// to update.
 	// We don't want to actually synchronize until all widgets update, we are
@@ -308,7 +342,7 @@ func (td *termdash) processEvents(ctx context.Context) {
 
 	for {
 		ev := td.term.Event(ctx)
-		if ev != nil {
+		if ev != nil && !td.evNotification(ev) {
 			td.eds.Event(ev)
 		}
 
@@ -359,4 +393,8 @@ func (td *termdash) start(ctx context.Context) error {
 func (td *termdash) stop() {
 	close(td.closeCh)
 	<-td.exitCh
+
+	td.mu.Lock()
+	defer td.mu.Unlock()
+	td.notifier.stop()
 }
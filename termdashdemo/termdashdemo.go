@@ -525,21 +525,6 @@ func periodic(ctx context.Context, interval time.Duration, fn func() error) {
 	}
 }
 
-// textState creates a rotated state for the text we are displaying.
-func textState(text string, capacity, step int) []rune {
-	if capacity == 0 {
-		return nil
-	}
-
-	var state []rune
-	for i := 0; i < capacity; i++ {
-		state = append(state, ' ')
-	}
-	state = append(state, []rune(text)...)
-	step = step % len(state)
-	return rotateRunes(state, step)
-}
-
 // newTextInput creates a new TextInput field that changes the text on the
 // SegmentDisplay.
 func newTextInput(updateText chan<- string) (*textinput.TextInput, error) {
@@ -560,9 +545,13 @@ func newTextInput(updateText chan<- string) (*textinput.TextInput, error) {
 }
 
 // newSegmentDisplay creates a new SegmentDisplay that initially shows the
-// Termdash name. Shows any text that is sent over the channel.
+// Termdash name. Shows any text that is sent over the channel, text that
+// doesn't fit scrolls across the display.
 func newSegmentDisplay(ctx context.Context, updateText <-chan string) (*segmentdisplay.SegmentDisplay, error) {
-	sd, err := segmentdisplay.New()
+	sd, err := segmentdisplay.New(
+		segmentdisplay.Marquee(segmentdisplay.MarqueeLoop),
+		segmentdisplay.MarqueePauseOnHover(),
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -578,40 +567,33 @@ func newSegmentDisplay(ctx context.Context, updateText <-chan string) (*segmentd
 		cell.ColorRed,
 	}
 
-	text := "Termdash"
-	step := 0
+	write := func(text string) error {
+		if text == "" {
+			sd.Reset()
+			return nil
+		}
+
+		var chunks []*segmentdisplay.TextChunk
+		for i, r := range text {
+			color := colors[i%len(colors)]
+			chunks = append(chunks, segmentdisplay.NewChunk(
+				string(r),
+				segmentdisplay.WriteCellOpts(cell.FgColor(color)),
+			))
+		}
+		return sd.Write(chunks)
+	}
+	if err := write("Termdash"); err != nil {
+		return nil, err
+	}
 
 	go func() {
-		ticker := time.NewTicker(500 * time.Millisecond)
-		defer ticker.Stop()
 		for {
 			select {
-			case <-ticker.C:
-				state := textState(text, sd.Capacity(), step)
-				var chunks []*segmentdisplay.TextChunk
-				for i := 0; i < sd.Capacity(); i++ {
-					if i >= len(state) {
-						break
-					}
-
-					color := colors[i%len(colors)]
-					chunks = append(chunks, segmentdisplay.NewChunk(
-						string(state[i]),
-						segmentdisplay.WriteCellOpts(cell.FgColor(color)),
-					))
-				}
-				if len(chunks) == 0 {
-					continue
-				}
-				if err := sd.Write(chunks); err != nil {
+			case t := <-updateText:
+				if err := write(t); err != nil {
 					panic(err)
 				}
-				step++
-
-			case t := <-updateText:
-				text = t
-				sd.Reset()
-				step = 0
 
 			case <-ctx.Done():
 				return
@@ -936,11 +918,3 @@ func rotateFloats(inputs []float64, step int) []float64 {
 	return append(inputs[step:], inputs[:step]...)
 }
 
-// rotateRunes returns a new slice with inputs rotated by step.
-// I.e. for a step of one:
-//   inputs[0] -> inputs[len(inputs)-1]
-//   inputs[1] -> inputs[0]
-// And so on.
-func rotateRunes(inputs []rune, step int) []rune {
-	return append(inputs[step:], inputs[:step]...)
-}
@@ -27,8 +27,19 @@ func cellColor(c cell.Color) tbx.Attribute {
 }
 
 // cellOptsToFg converts the cell options to the termbox foreground attribute.
+// Termbox carries the text attributes together with the foreground color.
 func cellOptsToFg(opts *cell.Options) tbx.Attribute {
-	return cellColor(opts.FgColor)
+	fg := cellColor(opts.FgColor)
+	if opts.Bold {
+		fg |= tbx.AttrBold
+	}
+	if opts.Underline {
+		fg |= tbx.AttrUnderline
+	}
+	if opts.Inverse {
+		fg |= tbx.AttrReverse
+	}
+	return fg
 }
 
 // cellOptsToBg converts the cell options to the termbox background attribute.
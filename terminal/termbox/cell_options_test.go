@@ -48,3 +48,36 @@ func TestCellColor(t *testing.T) {
 		})
 	}
 }
+
+func TestCellOptsToFg(t *testing.T) {
+	tests := []struct {
+		desc string
+		opts *cell.Options
+		want tbx.Attribute
+	}{
+		{
+			desc: "only color",
+			opts: cell.NewOptions(cell.FgColor(cell.ColorRed)),
+			want: tbx.ColorRed,
+		},
+		{
+			desc: "color and bold",
+			opts: cell.NewOptions(cell.FgColor(cell.ColorRed), cell.Bold()),
+			want: tbx.ColorRed | tbx.AttrBold,
+		},
+		{
+			desc: "all attributes",
+			opts: cell.NewOptions(cell.Bold(), cell.Underline(), cell.Inverse()),
+			want: tbx.ColorDefault | tbx.AttrBold | tbx.AttrUnderline | tbx.AttrReverse,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := cellOptsToFg(tc.opts)
+			if got != tc.want {
+				t.Errorf("cellOptsToFg => got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
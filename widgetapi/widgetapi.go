@@ -183,3 +183,27 @@ type Widget interface {
 	// Draw.
 	Options() Options
 }
+
+// Popup is an optional interface implemented by widgets that temporarily
+// display content outside of their canvas, e.g. the open list of options of a
+// dropdown.
+//
+// The infrastructure draws the popup above all the containers, right below
+// the widget's canvas or above it if there isn't enough space below. While
+// the popup is displayed, mouse events that fall onto it are only delivered
+// to PopupMouse.
+type Popup interface {
+	// PopupSize returns the size of the popup the widget wants to display or
+	// a zero size if the widget doesn't display a popup at the moment.
+	// Called on each redraw after the call to Draw.
+	PopupSize() image.Point
+
+	// DrawPopup draws the popup onto the provided canvas. The canvas can be
+	// smaller than the size returned by PopupSize if the terminal is too
+	// small.
+	DrawPopup(cvs *canvas.Canvas, meta *Meta) error
+
+	// PopupMouse is called when a mouse event happens on the popup. The
+	// coordinates are relative to the canvas provided to DrawPopup.
+	PopupMouse(m *terminalapi.Mouse) error
+}
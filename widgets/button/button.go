@@ -28,6 +28,7 @@ import (
 	"github.com/mum4k/termdash/internal/button"
 	"github.com/mum4k/termdash/internal/canvas"
 	"github.com/mum4k/termdash/internal/draw"
+	"github.com/mum4k/termdash/internal/markup"
 	"github.com/mum4k/termdash/mouse"
 	"github.com/mum4k/termdash/terminal/terminalapi"
 	"github.com/mum4k/termdash/widgetapi"
@@ -53,6 +54,9 @@ type CallbackFn func() error
 type Button struct {
 	// text in the text label displayed in the button.
 	text string
+	// chunks are the chunks of the text label, each with its own cell
+	// options.
+	chunks []*markup.Chunk
 
 	// mouseFSM tracks left mouse clicks.
 	mouseFSM *button.FSM
@@ -86,11 +90,28 @@ func New(text string, cFn CallbackFn, opts ...Option) (*Button, error) {
 	for _, o := range opts {
 		o.set(opt)
 	}
+
+	chunks := markup.Plain(text)
+	if opt.textMarkup {
+		c, err := markup.Parse(text)
+		if err != nil {
+			return nil, err
+		}
+		chunks = c
+
+		// Apply the options again so that the default width is based on the
+		// text without the markup.
+		opt = newOptions(markup.Text(chunks))
+		for _, o := range opts {
+			o.set(opt)
+		}
+	}
 	if err := opt.validate(); err != nil {
 		return nil, err
 	}
 	return &Button{
-		text:     text,
+		text:     markup.Text(chunks),
+		chunks:   chunks,
 		mouseFSM: button.NewFSM(mouse.ButtonLeft, image.ZR),
 		callback: cFn,
 		opts:     opt,
@@ -147,7 +168,7 @@ func (b *Button) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
 	if err != nil {
 		return err
 	}
-	return draw.Text(cvs, b.text, start,
+	return draw.TextChunks(cvs, b.chunks, start,
 		draw.TextOverrunMode(draw.OverrunModeThreeDot),
 		draw.TextMaxX(buttonAr.Max.X),
 		draw.TextCellOpts(cell.FgColor(b.opts.textColor)),
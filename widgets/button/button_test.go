@@ -115,6 +115,51 @@ func TestButton(t *testing.T) {
 			canvas:     image.Rect(0, 0, 1, 1),
 			wantNewErr: true,
 		},
+		{
+			desc:     "New fails with invalid text markup",
+			callback: &callbackTracker{},
+			text:     "[bold hello",
+			opts: []Option{
+				TextMarkup(),
+			},
+			canvas:     image.Rect(0, 0, 1, 1),
+			wantNewErr: true,
+		},
+		{
+			desc:     "draws text with markup",
+			callback: &callbackTracker{},
+			text:     "[fg=red]he[/]llo",
+			opts: []Option{
+				TextMarkup(),
+			},
+			canvas: image.Rect(0, 0, 8, 4),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+
+				// Shadow.
+				testcanvas.MustSetAreaCells(cvs, image.Rect(1, 1, 8, 4), 's', cell.BgColor(cell.ColorNumber(240)))
+
+				// Button.
+				testcanvas.MustSetAreaCells(cvs, image.Rect(0, 0, 7, 3), 'x', cell.BgColor(cell.ColorNumber(117)))
+
+				// Text.
+				testdraw.MustText(cvs, "he", image.Point{1, 1},
+					draw.TextCellOpts(
+						cell.FgColor(cell.ColorRed),
+						cell.BgColor(cell.ColorNumber(117))),
+				)
+				testdraw.MustText(cvs, "llo", image.Point{3, 1},
+					draw.TextCellOpts(
+						cell.FgColor(cell.ColorBlack),
+						cell.BgColor(cell.ColorNumber(117))),
+				)
+
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantCallback: &callbackTracker{},
+		},
 		{
 			desc:        "draw fails on canvas too small",
 			callback:    &callbackTracker{},
@@ -751,6 +796,19 @@ func TestOptions(t *testing.T) {
 				WantMouse:    widgetapi.MouseScopeGlobal,
 			},
 		},
+		{
+			desc: "width is based on the text without the markup",
+			text: "[fg=red,bold]hello[/] world",
+			opts: []Option{
+				TextMarkup(),
+			},
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{14, 4},
+				MaximumSize:  image.Point{14, 4},
+				WantKeyboard: widgetapi.KeyScopeNone,
+				WantMouse:    widgetapi.MouseScopeGlobal,
+			},
+		},
 		{
 			desc: "width specified via WidthFor",
 			text: "hello",
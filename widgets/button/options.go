@@ -50,6 +50,7 @@ type options struct {
 	key         keyboard.Key
 	keyScope    widgetapi.KeyScope
 	keyUpDelay  time.Duration
+	textMarkup  bool
 }
 
 // validate validates the provided options.
@@ -129,6 +130,19 @@ func WidthFor(text string) Option {
 	})
 }
 
+// TextMarkup instructs the button to interpret the text provided to New as
+// markup that sets the cell options of parts of the text, e.g.:
+//   "[bold]Save[/] (s)"
+// The cell options set by the markup are applied on top of the TextColor.
+// A literal '[' must be written as "[[". See the documentation of the
+// internal/markup package for the full syntax.
+// New returns an error if the markup is invalid.
+func TextMarkup() Option {
+	return option(func(opts *options) {
+		opts.textMarkup = true
+	})
+}
+
 // Key configures the keyboard key that presses the button.
 // The widget responds to this key only if its container if focused.
 // When not provided, the widget ignores all keyboard events.
@@ -0,0 +1,189 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package checkbox implements an interactive widget that can be checked and
+// unchecked.
+package checkbox
+
+import (
+	"image"
+	"sync"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/button"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/draw"
+	"github.com/mum4k/termdash/internal/runewidth"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// CallbackFn is the function called when the user checks or unchecks the
+// checkbox. The argument is the new state of the checkbox.
+//
+// The callback function must be thread-safe as the mouse or keyboard events
+// that change the state are processed in a separate goroutine.
+//
+// If the function returns an error, the widget will forward it back to the
+// termdash infrastructure which causes a panic, unless the user provided a
+// termdash.ErrorHandler.
+type CallbackFn func(checked bool) error
+
+// Checkbox displays a label with a mark that indicates whether it is checked.
+// The label is underlined while the container of the widget is focused.
+//
+// The user toggles the checkbox by a mouse click or by the space key while
+// its container is focused.
+//
+// Implements widgetapi.Widget. This object is thread-safe.
+type Checkbox struct {
+	// label is the text displayed next to the mark.
+	label string
+	// checked is the current state of the checkbox.
+	checked bool
+
+	// mouseFSM tracks left mouse clicks.
+	mouseFSM *button.FSM
+
+	// mu protects the widget.
+	mu sync.Mutex
+
+	// opts are the provided options.
+	opts *options
+}
+
+// New returns a new Checkbox that displays the provided label.
+func New(label string, opts ...Option) (*Checkbox, error) {
+	opt := newOptions()
+	for _, o := range opts {
+		o.set(opt)
+	}
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	return &Checkbox{
+		label:    label,
+		checked:  opt.checked,
+		mouseFSM: button.NewFSM(mouse.ButtonLeft, image.ZR),
+		opts:     opt,
+	}, nil
+}
+
+// Checked returns the current state of the checkbox.
+func (cb *Checkbox) Checked() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.checked
+}
+
+// SetChecked sets the state of the checkbox. Doesn't call the OnChange
+// callback.
+func (cb *Checkbox) SetChecked(checked bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.checked = checked
+}
+
+// toggle flips the state of the checkbox and calls the OnChange callback.
+// Caller must hold cb.mu.
+func (cb *Checkbox) toggle() error {
+	cb.checked = !cb.checked
+	if cb.opts.onChange != nil {
+		return cb.opts.onChange(cb.checked)
+	}
+	return nil
+}
+
+// markWidth is the width of the mark in cells, i.e. "[x]".
+const markWidth = 3
+
+// mark returns the mark for the provided state.
+func (cb *Checkbox) mark() string {
+	if cb.checked {
+		return "[" + string(cb.opts.checkedRune) + "]"
+	}
+	return "[ ]"
+}
+
+// Draw draws the Checkbox widget onto the canvas.
+// Implements widgetapi.Widget.Draw.
+func (cb *Checkbox) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cvsAr := cvs.Area()
+	cb.mouseFSM.UpdateArea(cvsAr)
+	if cvsAr.Dx() < markWidth {
+		return draw.ResizeNeeded(cvs)
+	}
+
+	if err := draw.Text(cvs, cb.mark(), image.Point{0, 0}, draw.TextCellOpts(cell.FgColor(cb.opts.markColor))); err != nil {
+		return err
+	}
+
+	if cvsAr.Dx() <= markWidth+1 || cb.label == "" {
+		return nil
+	}
+	labelOpts := []cell.Option{cell.FgColor(cb.opts.textColor)}
+	if meta.Focused {
+		labelOpts = append(labelOpts, cell.Underline())
+	}
+	return draw.Text(cvs, cb.label, image.Point{markWidth + 1, 0},
+		draw.TextCellOpts(labelOpts...),
+		draw.TextMaxX(cvsAr.Max.X),
+		draw.TextOverrunMode(draw.OverrunModeThreeDot),
+	)
+}
+
+// Keyboard toggles the checkbox on the space key.
+// Implements widgetapi.Widget.Keyboard.
+func (cb *Checkbox) Keyboard(k *terminalapi.Keyboard) error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if k.Key == keyboard.KeySpace {
+		return cb.toggle()
+	}
+	return nil
+}
+
+// Mouse toggles the checkbox if both the press and the release of the left
+// mouse button happen inside the widget.
+// Implements widgetapi.Widget.Mouse.
+func (cb *Checkbox) Mouse(m *terminalapi.Mouse) error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if clicked, _ := cb.mouseFSM.Event(m); clicked {
+		return cb.toggle()
+	}
+	return nil
+}
+
+// Options implements widgetapi.Widget.Options.
+func (cb *Checkbox) Options() widgetapi.Options {
+	// No need to lock, as the label is fixed when New is called.
+	width := markWidth
+	if cb.label != "" {
+		width += 1 + runewidth.StringWidth(cb.label)
+	}
+	return widgetapi.Options{
+		MinimumSize:  image.Point{markWidth, 1},
+		MaximumSize:  image.Point{width, 1},
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		WantMouse:    widgetapi.MouseScopeWidget,
+	}
+}
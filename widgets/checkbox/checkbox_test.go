@@ -0,0 +1,388 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkbox
+
+import (
+	"errors"
+	"image"
+	"sync"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/canvas/testcanvas"
+	"github.com/mum4k/termdash/internal/draw"
+	"github.com/mum4k/termdash/internal/draw/testdraw"
+	"github.com/mum4k/termdash/internal/faketerm"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// callbackTracker tracks calls to the callback.
+type callbackTracker struct {
+	// wantErr when set to true, makes callback return an error.
+	wantErr bool
+
+	// calls are the values the callback was called with.
+	calls []bool
+
+	// mu protects the tracker.
+	mu sync.Mutex
+}
+
+// callback is the callback function.
+func (ct *callbackTracker) callback(checked bool) error {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	if ct.wantErr {
+		return errors.New("ct.wantErr set to true")
+	}
+	ct.calls = append(ct.calls, checked)
+	return nil
+}
+
+func TestCheckbox(t *testing.T) {
+	tests := []struct {
+		desc      string
+		label     string
+		opts      []Option
+		callback  *callbackTracker
+		setter    func(*Checkbox)
+		events    []terminalapi.Event
+		canvas    image.Rectangle
+		meta      *widgetapi.Meta
+		want      func(size image.Point) *faketerm.Terminal
+		wantCalls []bool
+		// wantChecked is the expected state after the events.
+		wantChecked bool
+		wantNewErr  bool
+		wantDrawErr bool
+		wantEvErr   bool
+	}{
+		{
+			desc:  "New fails on wide checked rune",
+			label: "hello",
+			opts: []Option{
+				CheckedRune('世'),
+			},
+			canvas:     image.Rect(0, 0, 10, 1),
+			wantNewErr: true,
+		},
+		{
+			desc:   "draws unchecked checkbox",
+			label:  "hello",
+			canvas: image.Rect(0, 0, 10, 1),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "[ ]", image.Point{0, 0})
+				testdraw.MustText(cvs, "hello", image.Point{4, 0})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:  "draws initially checked checkbox with custom rune and colors",
+			label: "hello",
+			opts: []Option{
+				Checked(),
+				CheckedRune('v'),
+				MarkColor(cell.ColorRed),
+				TextColor(cell.ColorBlue),
+			},
+			canvas: image.Rect(0, 0, 10, 1),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "[v]", image.Point{0, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorRed)))
+				testdraw.MustText(cvs, "hello", image.Point{4, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorBlue)))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantChecked: true,
+		},
+		{
+			desc:   "underlines the label when focused",
+			label:  "hello",
+			canvas: image.Rect(0, 0, 10, 1),
+			meta:   &widgetapi.Meta{Focused: true},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "[ ]", image.Point{0, 0})
+				testdraw.MustText(cvs, "hello", image.Point{4, 0}, draw.TextCellOpts(cell.Underline()))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "trims the label that doesn't fit",
+			label:  "hello",
+			canvas: image.Rect(0, 0, 7, 1),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "[ ]", image.Point{0, 0})
+				testdraw.MustText(cvs, "he…", image.Point{4, 0})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "draw fails on canvas too small",
+			label:  "hello",
+			canvas: image.Rect(0, 0, 2, 1),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustResizeNeeded(cvs)
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:     "space key toggles the checkbox",
+			label:    "hello",
+			callback: &callbackTracker{},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeySpace},
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+			},
+			canvas: image.Rect(0, 0, 10, 1),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "[x]", image.Point{0, 0})
+				testdraw.MustText(cvs, "hello", image.Point{4, 0})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantCalls:   []bool{true},
+			wantChecked: true,
+		},
+		{
+			desc:     "mouse click toggles the checkbox",
+			label:    "hello",
+			opts:     []Option{Checked()},
+			callback: &callbackTracker{},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{5, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{5, 0}, Button: mouse.ButtonRelease},
+			},
+			canvas: image.Rect(0, 0, 10, 1),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "[ ]", image.Point{0, 0})
+				testdraw.MustText(cvs, "hello", image.Point{4, 0})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantCalls: []bool{false},
+		},
+		{
+			desc:     "release outside of the widget doesn't toggle the checkbox",
+			label:    "hello",
+			callback: &callbackTracker{},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{5, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{15, 0}, Button: mouse.ButtonRelease},
+			},
+			canvas: image.Rect(0, 0, 10, 1),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "[ ]", image.Point{0, 0})
+				testdraw.MustText(cvs, "hello", image.Point{4, 0})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:     "SetChecked doesn't call the callback",
+			label:    "hello",
+			callback: &callbackTracker{},
+			setter: func(cb *Checkbox) {
+				cb.SetChecked(true)
+			},
+			canvas: image.Rect(0, 0, 10, 1),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "[x]", image.Point{0, 0})
+				testdraw.MustText(cvs, "hello", image.Point{4, 0})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantChecked: true,
+		},
+		{
+			desc:     "forwards errors from the callback",
+			label:    "hello",
+			callback: &callbackTracker{wantErr: true},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeySpace},
+			},
+			canvas:      image.Rect(0, 0, 10, 1),
+			meta:        &widgetapi.Meta{},
+			wantEvErr:   true,
+			wantChecked: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			opts := tc.opts
+			if tc.callback != nil {
+				opts = append(opts, OnChange(tc.callback.callback))
+			}
+			cb, err := New(tc.label, opts...)
+			if (err != nil) != tc.wantNewErr {
+				t.Errorf("New => unexpected error: %v, wantNewErr: %v", err, tc.wantNewErr)
+			}
+			if err != nil {
+				return
+			}
+
+			{
+				// Draw once which initializes the mouse state machine with the current canvas area.
+				c, err := canvas.New(tc.canvas)
+				if err != nil {
+					t.Fatalf("canvas.New => unexpected error: %v", err)
+				}
+				if err := cb.Draw(c, tc.meta); err != nil && !tc.wantDrawErr {
+					t.Fatalf("Draw => unexpected error: %v", err)
+				}
+			}
+
+			if tc.setter != nil {
+				tc.setter(cb)
+			}
+			for _, ev := range tc.events {
+				var err error
+				switch e := ev.(type) {
+				case *terminalapi.Mouse:
+					err = cb.Mouse(e)
+				case *terminalapi.Keyboard:
+					err = cb.Keyboard(e)
+				default:
+					t.Fatalf("unsupported event type: %T", ev)
+				}
+				if (err != nil) != tc.wantEvErr {
+					t.Errorf("event %v => unexpected error: %v, wantEvErr: %v", ev, err, tc.wantEvErr)
+				}
+			}
+
+			if got := cb.Checked(); got != tc.wantChecked {
+				t.Errorf("Checked => %v, want %v", got, tc.wantChecked)
+			}
+			if tc.callback != nil {
+				if diff := pretty.Compare(tc.wantCalls, tc.callback.calls); diff != "" {
+					t.Errorf("CallbackFn => unexpected diff (-want, +got):\n%s", diff)
+				}
+			}
+			if tc.want == nil {
+				return
+			}
+
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+			err = cb.Draw(c, tc.meta)
+			if (err != nil) != tc.wantDrawErr {
+				t.Errorf("Draw => unexpected error: %v, wantDrawErr: %v", err, tc.wantDrawErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+		})
+	}
+}
+
+func TestOptions(t *testing.T) {
+	tests := []struct {
+		desc  string
+		label string
+		want  widgetapi.Options
+	}{
+		{
+			desc:  "width is based on the label",
+			label: "hello",
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{3, 1},
+				MaximumSize:  image.Point{9, 1},
+				WantKeyboard: widgetapi.KeyScopeFocused,
+				WantMouse:    widgetapi.MouseScopeWidget,
+			},
+		},
+		{
+			desc:  "width supports full-width unicode characters",
+			label: "世界",
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{3, 1},
+				MaximumSize:  image.Point{8, 1},
+				WantKeyboard: widgetapi.KeyScopeFocused,
+				WantMouse:    widgetapi.MouseScopeWidget,
+			},
+		},
+		{
+			desc: "only the mark without a label",
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{3, 1},
+				MaximumSize:  image.Point{3, 1},
+				WantKeyboard: widgetapi.KeyScopeFocused,
+				WantMouse:    widgetapi.MouseScopeWidget,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			cb, err := New(tc.label)
+			if err != nil {
+				t.Fatalf("New => unexpected error: %v", err)
+			}
+
+			got := cb.Options()
+			if diff := pretty.Compare(tc.want, got); diff != "" {
+				t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
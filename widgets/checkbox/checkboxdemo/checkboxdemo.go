@@ -0,0 +1,103 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary checkboxdemo shows the functionality of the checkbox widget.
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/termbox"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgets/checkbox"
+	"github.com/mum4k/termdash/widgets/text"
+)
+
+// newCheckbox returns a checkbox that logs its state changes into the text
+// widget.
+func newCheckbox(label string, log *text.Text, opts ...checkbox.Option) (*checkbox.Checkbox, error) {
+	opts = append(opts, checkbox.OnChange(func(checked bool) error {
+		return log.Write(fmt.Sprintf("%q checked: %v\n", label, checked))
+	}))
+	return checkbox.New(label, opts...)
+}
+
+func main() {
+	t, err := termbox.New()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	log, err := text.New(text.RollContent())
+	if err != nil {
+		panic(err)
+	}
+
+	autoRefresh, err := newCheckbox("Refresh automatically", log, checkbox.Checked())
+	if err != nil {
+		panic(err)
+	}
+	alerts, err := newCheckbox("Show alerts", log,
+		checkbox.MarkColor(cell.ColorRed),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	c, err := container.New(
+		t,
+		container.Border(linestyle.Light),
+		container.BorderTitle("PRESS Q TO QUIT"),
+		container.SplitHorizontal(
+			container.Top(
+				container.SplitHorizontal(
+					container.Top(
+						container.PlaceWidget(autoRefresh),
+					),
+					container.Bottom(
+						container.PlaceWidget(alerts),
+					),
+				),
+			),
+			container.Bottom(
+				container.Border(linestyle.Light),
+				container.BorderTitle("Click or press Space on a focused checkbox"),
+				container.PlaceWidget(log),
+			),
+			container.SplitPercent(30),
+		),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	quitter := func(k *terminalapi.Keyboard) {
+		if k.Key == 'q' || k.Key == 'Q' {
+			cancel()
+		}
+	}
+
+	if err := termdash.Run(ctx, t, c, termdash.KeyboardSubscriber(quitter), termdash.RedrawInterval(100*time.Millisecond)); err != nil {
+		panic(err)
+	}
+}
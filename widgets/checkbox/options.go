@@ -0,0 +1,105 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkbox
+
+// options.go contains configurable options for Checkbox.
+
+import (
+	"fmt"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/runewidth"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// options holds the provided options.
+type options struct {
+	checked     bool
+	checkedRune rune
+	markColor   cell.Color
+	textColor   cell.Color
+	onChange    CallbackFn
+}
+
+// validate validates the provided options.
+func (o *options) validate() error {
+	if got, want := runewidth.RuneWidth(o.checkedRune), 1; got != want {
+		return fmt.Errorf("invalid checked rune %q, must be %d cell wide, got %d", o.checkedRune, want, got)
+	}
+	return nil
+}
+
+// newOptions returns options with the default values set.
+func newOptions() *options {
+	return &options{
+		checkedRune: DefaultCheckedRune,
+		markColor:   cell.ColorDefault,
+		textColor:   cell.ColorDefault,
+	}
+}
+
+// Checked makes the checkbox initially checked.
+func Checked() Option {
+	return option(func(opts *options) {
+		opts.checked = true
+	})
+}
+
+// DefaultCheckedRune is the default for the CheckedRune option.
+const DefaultCheckedRune = 'x'
+
+// CheckedRune sets the rune displayed inside the mark when the checkbox is
+// checked. The rune must be one cell wide.
+// Defaults to DefaultCheckedRune.
+func CheckedRune(r rune) Option {
+	return option(func(opts *options) {
+		opts.checkedRune = r
+	})
+}
+
+// MarkColor sets the color of the mark.
+func MarkColor(c cell.Color) Option {
+	return option(func(opts *options) {
+		opts.markColor = c
+	})
+}
+
+// TextColor sets the color of the label.
+func TextColor(c cell.Color) Option {
+	return option(func(opts *options) {
+		opts.textColor = c
+	})
+}
+
+// OnChange sets a function that is called each time the user checks or
+// unchecks the checkbox.
+func OnChange(fn CallbackFn) Option {
+	return option(func(opts *options) {
+		opts.onChange = fn
+	})
+}
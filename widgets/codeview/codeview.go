@@ -0,0 +1,441 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package codeview implements a widget that displays source code.
+package codeview
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/runewidth"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// Marker marks a line in the gutter, e.g. a line with an error.
+type Marker struct {
+	// Rune is the displayed rune, it must occupy exactly one cell.
+	Rune rune
+	// Color is the color of the rune.
+	Color cell.Color
+}
+
+// Predefined markers.
+var (
+	// MarkerError marks a line with an error.
+	MarkerError = Marker{Rune: '✖', Color: cell.ColorRed}
+	// MarkerWarning marks a line with a warning.
+	MarkerWarning = Marker{Rune: '▲', Color: cell.ColorYellow}
+	// MarkerBreakpoint marks a line with a breakpoint.
+	MarkerBreakpoint = Marker{Rune: '●', Color: cell.ColorRed}
+)
+
+// glyph is a rune of the source code and the kind of its token.
+type glyph struct {
+	r    rune
+	kind TokenKind
+}
+
+// CodeView displays source code with line numbers, markers in the gutter and
+// syntax highlighting.
+//
+// One of the lines is the current line, which is highlighted. When the
+// widget is focused, the current line is moved with the arrow keys, PgUp,
+// PgDn, Home and End and lines that are too long can be scrolled with the
+// left and right arrow keys. The mouse wheel scrolls the content and
+// clicking on a line makes it the current line.
+//
+// Implements widgetapi.Widget. This object is thread-safe.
+type CodeView struct {
+	// lines are the lines of the source code with tabs expanded.
+	lines [][]glyph
+	// maxWidth is the width of the widest line in cells.
+	maxWidth int
+	// markers maps line numbers to their markers.
+	markers map[int]Marker
+
+	// current is the index of the current line.
+	current int
+	// first is the index of the first displayed line.
+	first int
+	// left is the number of cells of the lines scrolled out on the left.
+	left int
+	// follow indicates that the current line moved and should be scrolled
+	// into view on the next draw.
+	follow bool
+	// center indicates that the current line should be scrolled to the
+	// middle of the canvas on the next draw if it isn't visible.
+	center bool
+
+	// lastHeight is the height of the canvas during the last draw.
+	lastHeight int
+	// lastCodeWidth is the width available to the code during the last draw.
+	lastCodeWidth int
+
+	// mu protects the widget.
+	mu sync.Mutex
+
+	// opts are the provided options.
+	opts *options
+}
+
+// New returns a new CodeView without content.
+func New(opts ...Option) (*CodeView, error) {
+	opt := newOptions()
+	for _, o := range opts {
+		o.set(opt)
+	}
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	return &CodeView{
+		markers: map[int]Marker{},
+		opts:    opt,
+	}, nil
+}
+
+// validSource validates the source code, which must not contain control
+// characters other than newlines and tabs.
+func validSource(src string) error {
+	for _, r := range src {
+		if r == '\n' || r == '\t' {
+			continue
+		}
+		if unicode.IsControl(r) {
+			return fmt.Errorf("the source code cannot contain control characters, found: %q", r)
+		}
+	}
+	return nil
+}
+
+// SetContent replaces the displayed source code, makes the first line the
+// current line and scrolls to the top. The markers are kept.
+// The source code must not contain control characters other than newlines
+// and tabs. An empty source code clears the widget.
+func (cv *CodeView) SetContent(src string) error {
+	src = strings.Replace(src, "\r\n", "\n", -1)
+	if err := validSource(src); err != nil {
+		return err
+	}
+
+	toks := []Token{{Kind: TokenText, Text: src}}
+	if cv.opts.tokenizer != nil {
+		toks = cv.opts.tokenizer.Tokenize(src)
+	}
+	var b strings.Builder
+	for _, t := range toks {
+		b.WriteString(t.Text)
+	}
+	if b.String() != src {
+		return errors.New("the texts of the tokens returned by the tokenizer don't add up to the source code")
+	}
+	lines, maxWidth := splitLines(toks, cv.opts.tabWidth)
+
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+	cv.lines = lines
+	cv.maxWidth = maxWidth
+	cv.current = 0
+	cv.first = 0
+	cv.left = 0
+	return nil
+}
+
+// splitLines splits the tokens into lines of glyphs, expanding tabs.
+// A newline at the end of the last line doesn't start a new line.
+// Returns the lines and the width of the widest line.
+func splitLines(toks []Token, tabWidth int) ([][]glyph, int) {
+	var lines [][]glyph
+	var line []glyph
+	var col, maxWidth int
+	started := false
+	for _, t := range toks {
+		for _, r := range t.Text {
+			started = true
+			switch r {
+			case '\n':
+				lines = append(lines, line)
+				line = nil
+				col = 0
+				started = false
+			case '\t':
+				n := tabWidth - col%tabWidth
+				for i := 0; i < n; i++ {
+					line = append(line, glyph{r: ' ', kind: t.Kind})
+				}
+				col += n
+			default:
+				line = append(line, glyph{r: r, kind: t.Kind})
+				col += runewidth.RuneWidth(r)
+			}
+			if col > maxWidth {
+				maxWidth = col
+			}
+		}
+	}
+	if started {
+		lines = append(lines, line)
+	}
+	return lines, maxWidth
+}
+
+// SetMarker displays the marker in the gutter of the line, replacing any
+// existing marker of the line. Lines are numbered from one. The line doesn't
+// need to exist in the current content.
+func (cv *CodeView) SetMarker(line int, m Marker) error {
+	if line < 1 {
+		return fmt.Errorf("invalid line %d, lines are numbered from one", line)
+	}
+	if unicode.IsControl(m.Rune) || runewidth.RuneWidth(m.Rune) != 1 {
+		return fmt.Errorf("invalid marker rune %q, it must occupy exactly one cell", m.Rune)
+	}
+
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+	cv.markers[line] = m
+	return nil
+}
+
+// ClearMarker removes the marker of the line if it has one.
+func (cv *CodeView) ClearMarker(line int) {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+	delete(cv.markers, line)
+}
+
+// ClearMarkers removes the markers of all the lines.
+func (cv *CodeView) ClearMarkers() {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+	cv.markers = map[int]Marker{}
+}
+
+// GoTo makes the line the current line and scrolls it into the middle of the
+// widget if it isn't visible. Lines are numbered from one.
+func (cv *CodeView) GoTo(line int) error {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+
+	if line < 1 || line > len(cv.lines) {
+		return fmt.Errorf("invalid line %d, must be in range 1 <= line <= %d", line, len(cv.lines))
+	}
+	cv.current = line - 1
+	cv.center = true
+	return nil
+}
+
+// CurrentLine returns the number of the current line, numbered from one.
+// Returns zero if the widget has no content.
+func (cv *CodeView) CurrentLine() int {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+
+	if len(cv.lines) == 0 {
+		return 0
+	}
+	return cv.current + 1
+}
+
+// clamp returns the value limited to the range min <= v <= max, where max
+// takes precedence over min.
+func clamp(v, min, max int) int {
+	if v > max {
+		v = max
+	}
+	if v < min {
+		v = min
+	}
+	return v
+}
+
+// numberWidth returns the width of the line numbers in cells, zero if they
+// are hidden.
+func (cv *CodeView) numberWidth() int {
+	if cv.opts.hideLineNumbers {
+		return 0
+	}
+	return len(strconv.Itoa(len(cv.lines)))
+}
+
+// gutterWidth returns the width of the gutter with the markers and the line
+// numbers.
+func (cv *CodeView) gutterWidth() int {
+	// The marker followed by a space.
+	w := 2
+	if nw := cv.numberWidth(); nw > 0 {
+		// The line number followed by a space.
+		w += nw + 1
+	}
+	return w
+}
+
+// scroll updates the first displayed line and the horizontal scrolling
+// position to fit the canvas of the size.
+// Caller must hold cv.mu.
+func (cv *CodeView) scroll(height, codeWidth int) {
+	visible := cv.current >= cv.first && cv.current < cv.first+height
+	switch {
+	case cv.center && !visible:
+		cv.first = cv.current - height/2
+	case cv.follow && cv.current < cv.first:
+		cv.first = cv.current
+	case cv.follow && cv.current >= cv.first+height:
+		cv.first = cv.current - height + 1
+	}
+	cv.center = false
+	cv.follow = false
+
+	cv.first = clamp(cv.first, 0, len(cv.lines)-height)
+	cv.left = clamp(cv.left, 0, cv.maxWidth-codeWidth)
+}
+
+// Draw draws the CodeView widget onto the canvas.
+// Implements widgetapi.Widget.Draw.
+func (cv *CodeView) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+
+	width := cvs.Area().Dx()
+	height := cvs.Area().Dy()
+	gutter := cv.gutterWidth()
+	cv.lastHeight = height
+	cv.lastCodeWidth = width - gutter
+	cv.scroll(height, cv.lastCodeWidth)
+
+	numWidth := cv.numberWidth()
+	for y := 0; y < height && cv.first+y < len(cv.lines); y++ {
+		idx := cv.first + y
+		var bg []cell.Option
+		numOpts := []cell.Option{cell.FgColor(cv.opts.lineNumberColor)}
+		if idx == cv.current {
+			bg = []cell.Option{cell.BgColor(cv.opts.currentLineColor)}
+			numOpts = []cell.Option{cell.Bold()}
+			for x := 0; x < width; x++ {
+				if _, err := cvs.SetCell(image.Point{x, y}, ' ', bg...); err != nil {
+					return err
+				}
+			}
+		}
+
+		if m, ok := cv.markers[idx+1]; ok {
+			if _, err := cvs.SetCell(image.Point{0, y}, m.Rune, append([]cell.Option{cell.FgColor(m.Color)}, bg...)...); err != nil {
+				return err
+			}
+		}
+		if numWidth > 0 {
+			for i, r := range fmt.Sprintf("%*d", numWidth, idx+1) {
+				p := image.Point{2 + i, y}
+				if p.X >= width {
+					break
+				}
+				if _, err := cvs.SetCell(p, r, append(numOpts, bg...)...); err != nil {
+					return err
+				}
+			}
+		}
+
+		col := 0
+		for _, g := range cv.lines[idx] {
+			rw := runewidth.RuneWidth(g.r)
+			if col < cv.left {
+				// Skip cells scrolled out on the left.
+				col += rw
+				continue
+			}
+			x := gutter + col - cv.left
+			if x+rw > width {
+				break
+			}
+			opts := append(append([]cell.Option{}, cv.opts.tokenCellOpts[g.kind]...), bg...)
+			if _, err := cvs.SetCell(image.Point{x, y}, g.r, opts...); err != nil {
+				return err
+			}
+			col += rw
+		}
+	}
+	return nil
+}
+
+// moveCurrent moves the current line by the number of lines, up if
+// negative.
+// Caller must hold cv.mu.
+func (cv *CodeView) moveCurrent(lines int) {
+	cv.current = clamp(cv.current+lines, 0, len(cv.lines)-1)
+	cv.follow = true
+}
+
+// Keyboard moves the current line and scrolls the content horizontally.
+// Implements widgetapi.Widget.Keyboard.
+func (cv *CodeView) Keyboard(k *terminalapi.Keyboard) error {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+
+	switch k.Key {
+	case keyboard.KeyArrowUp:
+		cv.moveCurrent(-1)
+	case keyboard.KeyArrowDown:
+		cv.moveCurrent(1)
+	case keyboard.KeyPgUp:
+		cv.moveCurrent(-cv.lastHeight)
+	case keyboard.KeyPgDn:
+		cv.moveCurrent(cv.lastHeight)
+	case keyboard.KeyHome:
+		cv.moveCurrent(-len(cv.lines))
+	case keyboard.KeyEnd:
+		cv.moveCurrent(len(cv.lines))
+	case keyboard.KeyArrowLeft:
+		cv.left = clamp(cv.left-1, 0, cv.maxWidth-cv.lastCodeWidth)
+	case keyboard.KeyArrowRight:
+		cv.left = clamp(cv.left+1, 0, cv.maxWidth-cv.lastCodeWidth)
+	}
+	return nil
+}
+
+// Mouse scrolls the content and selects the current line.
+// Implements widgetapi.Widget.Mouse.
+func (cv *CodeView) Mouse(m *terminalapi.Mouse) error {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+
+	switch m.Button {
+	case mouse.ButtonWheelUp:
+		cv.first = clamp(cv.first-1, 0, len(cv.lines)-cv.lastHeight)
+	case mouse.ButtonWheelDown:
+		cv.first = clamp(cv.first+1, 0, len(cv.lines)-cv.lastHeight)
+	case mouse.ButtonLeft:
+		if idx := cv.first + m.Position.Y; idx < len(cv.lines) {
+			cv.current = idx
+		}
+	}
+	return nil
+}
+
+// Options implements widgetapi.Widget.Options.
+func (cv *CodeView) Options() widgetapi.Options {
+	return widgetapi.Options{
+		MinimumSize:  image.Point{1, 1},
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		WantMouse:    widgetapi.MouseScopeWidget,
+	}
+}
@@ -0,0 +1,393 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codeview
+
+import (
+	"image"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/canvas/testcanvas"
+	"github.com/mum4k/termdash/internal/draw"
+	"github.com/mum4k/termdash/internal/draw/testdraw"
+	"github.com/mum4k/termdash/internal/faketerm"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// text is a text drawn at a point.
+type text struct {
+	p    image.Point
+	text string
+	opts []cell.Option
+}
+
+// mustDraw returns a function that draws the texts on a fake terminal.
+func mustDraw(texts ...text) func(size image.Point) *faketerm.Terminal {
+	return func(size image.Point) *faketerm.Terminal {
+		ft := faketerm.MustNew(size)
+		cvs := testcanvas.MustNew(ft.Area())
+		for _, t := range texts {
+			testdraw.MustText(cvs, t.text, t.p, draw.TextCellOpts(t.opts...))
+		}
+		testcanvas.MustApply(cvs, ft)
+		return ft
+	}
+}
+
+var (
+	gray = cell.FgColor(cell.ColorNumber(240))
+	bg   = cell.BgColor(cell.ColorNumber(236))
+)
+
+func TestCodeView(t *testing.T) {
+	tests := []struct {
+		desc           string
+		opts           []Option
+		canvas         image.Rectangle
+		content        string
+		markers        map[int]Marker
+		goTo           int
+		events         []terminalapi.Event
+		want           func(size image.Point) *faketerm.Terminal
+		wantLine       int
+		wantNewErr     bool
+		wantContentErr bool
+		wantMarkerErr  bool
+		wantGoToErr    bool
+	}{
+		{
+			desc:       "fails on invalid tab width",
+			opts:       []Option{TabWidth(0)},
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on unknown token kind",
+			opts:       []Option{TokenCellOpts(TokenKind(-1), cell.Bold())},
+			wantNewErr: true,
+		},
+		{
+			desc:           "fails on control characters",
+			content:        "a\x07b",
+			wantContentErr: true,
+		},
+		{
+			desc: "fails when tokens don't add up to the source",
+			opts: []Option{Syntax(TokenizerFunc(func(string) []Token {
+				return []Token{{TokenText, "x"}}
+			}))},
+			content:        "a",
+			wantContentErr: true,
+		},
+		{
+			desc:          "fails on marker for line zero",
+			content:       "a",
+			markers:       map[int]Marker{0: MarkerError},
+			wantMarkerErr: true,
+		},
+		{
+			desc:          "fails on wide marker rune",
+			content:       "a",
+			markers:       map[int]Marker{1: {Rune: '世'}},
+			wantMarkerErr: true,
+		},
+		{
+			desc:        "fails to go to line out of range",
+			content:     "a\nb\n",
+			goTo:        3,
+			wantGoToErr: true,
+		},
+		{
+			desc:    "draws nothing without content",
+			canvas:  image.Rect(0, 0, 5, 2),
+			content: "",
+			want:    mustDraw(),
+		},
+		{
+			desc:     "draws line numbers and the current line",
+			canvas:   image.Rect(0, 0, 6, 3),
+			content:  "a\n\tb\n",
+			wantLine: 1,
+			want: mustDraw(
+				text{image.Point{0, 0}, "  1 a ", []cell.Option{bg}},
+				text{image.Point{2, 0}, "1", []cell.Option{cell.Bold(), bg}},
+				text{image.Point{2, 1}, "2", []cell.Option{gray}},
+				text{image.Point{4, 1}, "  ", nil},
+			),
+		},
+		{
+			desc:     "hides line numbers and uses custom colors",
+			opts:     []Option{HideLineNumbers(), CurrentLineColor(cell.ColorRed), TabWidth(2)},
+			canvas:   image.Rect(0, 0, 5, 2),
+			content:  "a\n\tb",
+			wantLine: 1,
+			want: mustDraw(
+				text{image.Point{0, 0}, "  a  ", []cell.Option{cell.BgColor(cell.ColorRed)}},
+				text{image.Point{2, 1}, "  b", nil},
+			),
+		},
+		{
+			desc:     "highlights tokens",
+			opts:     []Option{Syntax(GoTokenizer()), HideLineNumbers(), TokenCellOpts(TokenType, cell.Underline())},
+			canvas:   image.Rect(0, 0, 10, 2),
+			content:  "\nvar x int",
+			wantLine: 1,
+			want: mustDraw(
+				text{image.Point{0, 0}, "          ", []cell.Option{bg}},
+				text{image.Point{2, 1}, "var", []cell.Option{cell.FgColor(cell.ColorMagenta), cell.Bold()}},
+				text{image.Point{5, 1}, " x ", nil},
+				text{image.Point{8, 1}, "in", []cell.Option{cell.Underline()}},
+			),
+		},
+		{
+			desc:     "draws markers",
+			opts:     []Option{HideLineNumbers()},
+			canvas:   image.Rect(0, 0, 3, 2),
+			content:  "a\nb",
+			markers:  map[int]Marker{1: MarkerError, 2: MarkerBreakpoint, 3: MarkerWarning},
+			wantLine: 1,
+			want: mustDraw(
+				text{image.Point{0, 0}, "  a", []cell.Option{bg}},
+				text{image.Point{0, 0}, "✖", []cell.Option{cell.FgColor(cell.ColorRed), bg}},
+				text{image.Point{0, 1}, "●", []cell.Option{cell.FgColor(cell.ColorRed)}},
+				text{image.Point{2, 1}, "b", nil},
+			),
+		},
+		{
+			desc:     "keys move the current line and scroll",
+			opts:     []Option{HideLineNumbers()},
+			canvas:   image.Rect(0, 0, 3, 2),
+			content:  "a\nb\nc\nd",
+			wantLine: 3,
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+			},
+			want: mustDraw(
+				text{image.Point{2, 0}, "b", nil},
+				text{image.Point{0, 1}, "  c", []cell.Option{bg}},
+			),
+		},
+		{
+			desc:     "end and page up",
+			opts:     []Option{HideLineNumbers()},
+			canvas:   image.Rect(0, 0, 3, 2),
+			content:  "a\nb\nc\nd\ne",
+			wantLine: 3,
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyEnd},
+				&terminalapi.Keyboard{Key: keyboard.KeyPgUp},
+			},
+			want: mustDraw(
+				text{image.Point{2, 0}, "b", nil},
+				text{image.Point{0, 1}, "  c", []cell.Option{bg}},
+			),
+		},
+		{
+			desc:     "scrolls horizontally",
+			opts:     []Option{HideLineNumbers()},
+			canvas:   image.Rect(0, 0, 4, 1),
+			content:  "abcd",
+			wantLine: 1,
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowRight},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowRight},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowRight},
+			},
+			want: mustDraw(
+				text{image.Point{0, 0}, "  cd", []cell.Option{bg}},
+			),
+		},
+		{
+			desc:     "go to line centers it",
+			opts:     []Option{HideLineNumbers()},
+			canvas:   image.Rect(0, 0, 3, 3),
+			content:  "a\nb\nc\nd\ne\nf",
+			goTo:     5,
+			wantLine: 5,
+			want: mustDraw(
+				text{image.Point{2, 0}, "d", nil},
+				text{image.Point{0, 1}, "  e", []cell.Option{bg}},
+				text{image.Point{2, 2}, "f", nil},
+			),
+		},
+		{
+			desc:     "mouse wheel scrolls and click selects",
+			opts:     []Option{HideLineNumbers()},
+			canvas:   image.Rect(0, 0, 3, 2),
+			content:  "a\nb\nc",
+			wantLine: 3,
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Button: mouse.ButtonWheelDown},
+				&terminalapi.Mouse{Button: mouse.ButtonWheelDown},
+				&terminalapi.Mouse{Position: image.Point{1, 1}, Button: mouse.ButtonLeft},
+			},
+			want: mustDraw(
+				text{image.Point{2, 0}, "b", nil},
+				text{image.Point{0, 1}, "  c", []cell.Option{bg}},
+			),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			cv, err := New(tc.opts...)
+			if (err != nil) != tc.wantNewErr {
+				t.Errorf("New => unexpected error: %v, wantNewErr: %v", err, tc.wantNewErr)
+			}
+			if err != nil {
+				return
+			}
+
+			{
+				err := cv.SetContent(tc.content)
+				if (err != nil) != tc.wantContentErr {
+					t.Errorf("SetContent => unexpected error: %v, wantContentErr: %v", err, tc.wantContentErr)
+				}
+				if err != nil {
+					return
+				}
+			}
+
+			for line, m := range tc.markers {
+				err := cv.SetMarker(line, m)
+				if (err != nil) != tc.wantMarkerErr {
+					t.Errorf("SetMarker => unexpected error: %v, wantMarkerErr: %v", err, tc.wantMarkerErr)
+				}
+				if err != nil {
+					return
+				}
+			}
+
+			if tc.goTo > 0 {
+				err := cv.GoTo(tc.goTo)
+				if (err != nil) != tc.wantGoToErr {
+					t.Errorf("GoTo => unexpected error: %v, wantGoToErr: %v", err, tc.wantGoToErr)
+				}
+				if err != nil {
+					return
+				}
+			}
+
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+			if err := cv.Draw(c, &widgetapi.Meta{}); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+
+			for _, ev := range tc.events {
+				switch e := ev.(type) {
+				case *terminalapi.Keyboard:
+					if err := cv.Keyboard(e); err != nil {
+						t.Fatalf("Keyboard => unexpected error: %v", err)
+					}
+				case *terminalapi.Mouse:
+					if err := cv.Mouse(e); err != nil {
+						t.Fatalf("Mouse => unexpected error: %v", err)
+					}
+				default:
+					t.Fatalf("unsupported event type: %T", ev)
+				}
+			}
+
+			c, err = canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+			if err := cv.Draw(c, &widgetapi.Meta{}); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+			if got := cv.CurrentLine(); got != tc.wantLine {
+				t.Errorf("CurrentLine => %d, want %d", got, tc.wantLine)
+			}
+		})
+	}
+}
+
+func TestClearMarkers(t *testing.T) {
+	cv, err := New(HideLineNumbers())
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := cv.SetContent("a\nb\nc"); err != nil {
+		t.Fatalf("SetContent => unexpected error: %v", err)
+	}
+	for _, line := range []int{1, 2, 3} {
+		if err := cv.SetMarker(line, MarkerWarning); err != nil {
+			t.Fatalf("SetMarker => unexpected error: %v", err)
+		}
+	}
+	cv.ClearMarker(1)
+	cv.ClearMarkers()
+	if err := cv.SetMarker(3, MarkerBreakpoint); err != nil {
+		t.Fatalf("SetMarker => unexpected error: %v", err)
+	}
+
+	c, err := canvas.New(image.Rect(0, 0, 3, 3))
+	if err != nil {
+		t.Fatalf("canvas.New => unexpected error: %v", err)
+	}
+	if err := cv.Draw(c, &widgetapi.Meta{}); err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+	got, err := faketerm.New(c.Size())
+	if err != nil {
+		t.Fatalf("faketerm.New => unexpected error: %v", err)
+	}
+	if err := c.Apply(got); err != nil {
+		t.Fatalf("Apply => unexpected error: %v", err)
+	}
+	want := mustDraw(
+		text{image.Point{0, 0}, "  a", []cell.Option{bg}},
+		text{image.Point{2, 1}, "b", nil},
+		text{image.Point{0, 2}, "●", []cell.Option{cell.FgColor(cell.ColorRed)}},
+		text{image.Point{2, 2}, "c", nil},
+	)
+	if diff := faketerm.Diff(want(c.Size()), got); diff != "" {
+		t.Errorf("Draw => %v", diff)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	cv, err := New()
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	want := widgetapi.Options{
+		MinimumSize:  image.Point{1, 1},
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		WantMouse:    widgetapi.MouseScopeWidget,
+	}
+	if diff := pretty.Compare(want, cv.Options()); diff != "" {
+		t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+	}
+}
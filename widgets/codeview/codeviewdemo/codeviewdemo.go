@@ -0,0 +1,160 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary codeviewdemo shows the functionality of the codeview widget.
+package main
+
+import (
+	"context"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/termbox"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgets/codeview"
+)
+
+// goSource is the displayed Go source code.
+const goSource = `// Package retry retries failed operations.
+package retry
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Do calls fn until it succeeds, the attempts run out or ctx expires.
+func Do(ctx context.Context, attempts int, fn func() error) error {
+	var err error
+	backoff := 100 * time.Millisecond
+	for i := 0; i < attempts; i++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return fmt.Errorf("giving up after %d attempts: %v", attempts, err)
+}
+`
+
+// yamlSource is the displayed YAML document.
+const yamlSource = `# Deployment of the retry service.
+---
+name: retry
+replicas: 3
+enabled: yes
+image: &img registry.example.com/retry:1.4.2
+resources:
+  - cpu: 0.5
+    memory: "256Mi"
+command: |
+  retry --attempts 5
+`
+
+// jsonSource is the displayed JSON document.
+const jsonSource = `{
+  "name": "retry",
+  "attempts": 5,
+  "backoff": 0.1,
+  "jitter": true,
+  "tags": ["network", null]
+}
+`
+
+// newView returns a new code viewer with the content.
+func newView(src string, opts ...codeview.Option) *codeview.CodeView {
+	cv, err := codeview.New(opts...)
+	if err != nil {
+		panic(err)
+	}
+	if err := cv.SetContent(src); err != nil {
+		panic(err)
+	}
+	return cv
+}
+
+func main() {
+	t, err := termbox.New()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	goView := newView(goSource, codeview.Syntax(codeview.GoTokenizer()))
+	if err := goView.SetMarker(15, codeview.MarkerBreakpoint); err != nil {
+		panic(err)
+	}
+	if err := goView.SetMarker(25, codeview.MarkerError); err != nil {
+		panic(err)
+	}
+	if err := goView.GoTo(25); err != nil {
+		panic(err)
+	}
+
+	yamlView := newView(yamlSource, codeview.Syntax(codeview.YAMLTokenizer()), codeview.TabWidth(2))
+	if err := yamlView.SetMarker(5, codeview.MarkerWarning); err != nil {
+		panic(err)
+	}
+	jsonView := newView(jsonSource, codeview.Syntax(codeview.JSONTokenizer()), codeview.TabWidth(2))
+
+	c, err := container.New(
+		t,
+		container.Border(linestyle.Light),
+		container.BorderTitle("PRESS Q TO QUIT"),
+		container.SplitVertical(
+			container.Left(
+				container.Border(linestyle.Light),
+				container.BorderTitle("retry.go"),
+				container.PlaceWidget(goView),
+			),
+			container.Right(
+				container.SplitHorizontal(
+					container.Top(
+						container.Border(linestyle.Light),
+						container.BorderTitle("deployment.yaml"),
+						container.PlaceWidget(yamlView),
+					),
+					container.Bottom(
+						container.Border(linestyle.Light),
+						container.BorderTitle("config.json"),
+						container.PlaceWidget(jsonView),
+					),
+				),
+			),
+			container.SplitPercent(60),
+		),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	quitter := func(k *terminalapi.Keyboard) {
+		if k.Key == 'q' || k.Key == 'Q' {
+			cancel()
+		}
+	}
+
+	if err := termdash.Run(ctx, t, c, termdash.KeyboardSubscriber(quitter)); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codeview
+
+// golang.go contains the tokenizer of the Go programming language.
+
+import "unicode"
+
+// goKeywords are the keywords of Go.
+var goKeywords = map[string]bool{
+	"break": true, "case": true, "chan": true, "const": true,
+	"continue": true, "default": true, "defer": true, "else": true,
+	"fallthrough": true, "for": true, "func": true, "go": true, "goto": true,
+	"if": true, "import": true, "interface": true, "map": true,
+	"package": true, "range": true, "return": true, "select": true,
+	"struct": true, "switch": true, "type": true, "var": true,
+}
+
+// goTypes are the predeclared types of Go.
+var goTypes = map[string]bool{
+	"bool": true, "byte": true, "complex64": true, "complex128": true,
+	"error": true, "float32": true, "float64": true, "int": true,
+	"int8": true, "int16": true, "int32": true, "int64": true, "rune": true,
+	"string": true, "uint": true, "uint8": true, "uint16": true,
+	"uint32": true, "uint64": true, "uintptr": true,
+}
+
+// goLiterals are the predeclared constants of Go.
+var goLiterals = map[string]bool{
+	"true": true, "false": true, "nil": true, "iota": true,
+}
+
+// GoTokenizer returns a tokenizer of the Go programming language.
+func GoTokenizer() Tokenizer {
+	return TokenizerFunc(tokenizeGo)
+}
+
+// tokenizeGo splits Go source code into tokens.
+func tokenizeGo(src string) []Token {
+	var ts tokens
+	rs := []rune(src)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		var end int
+		var kind TokenKind
+		switch {
+		case hasPrefix(rs, i, "//"):
+			end, kind = scanWhile(rs, i, func(r rune) bool { return r != '\n' }), TokenComment
+
+		case hasPrefix(rs, i, "/*"):
+			end, kind = scanPast(rs, i+2, "*/"), TokenComment
+
+		case r == '"' || r == '\'':
+			end, kind = scanQuoted(rs, i, true, false), TokenString
+
+		case r == '`':
+			end, kind = scanQuoted(rs, i, false, true), TokenString
+
+		case r != '-' && isNumberStart(rs, i):
+			end, kind = scanNumber(rs, i), TokenNumber
+
+		case r == '_' || unicode.IsLetter(r):
+			end = scanWhile(rs, i, isIdentRune)
+			switch word := string(rs[i:end]); {
+			case goKeywords[word]:
+				kind = TokenKeyword
+			case goTypes[word]:
+				kind = TokenType
+			case goLiterals[word]:
+				kind = TokenLiteral
+			default:
+				kind = TokenText
+			}
+
+		default:
+			end, kind = i+1, TokenText
+		}
+		ts.add(kind, string(rs[i:end]))
+		i = end
+	}
+	return ts
+}
@@ -0,0 +1,61 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codeview
+
+// json.go contains the tokenizer of JSON.
+
+import "unicode"
+
+// JSONTokenizer returns a tokenizer of JSON documents.
+// Strings that are keys of objects are identified as TokenKey.
+func JSONTokenizer() Tokenizer {
+	return TokenizerFunc(tokenizeJSON)
+}
+
+// tokenizeJSON splits a JSON document into tokens.
+func tokenizeJSON(src string) []Token {
+	var ts tokens
+	rs := []rune(src)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		var end int
+		var kind TokenKind
+		switch {
+		case r == '"':
+			end, kind = scanQuoted(rs, i, true, false), TokenString
+			if next := scanWhile(rs, end, unicode.IsSpace); next < len(rs) && rs[next] == ':' {
+				kind = TokenKey
+			}
+
+		case isNumberStart(rs, i):
+			end, kind = scanNumber(rs, i), TokenNumber
+
+		case unicode.IsLetter(r):
+			end = scanWhile(rs, i, isIdentRune)
+			switch string(rs[i:end]) {
+			case "true", "false", "null":
+				kind = TokenLiteral
+			default:
+				kind = TokenText
+			}
+
+		default:
+			end, kind = i+1, TokenText
+		}
+		ts.add(kind, string(rs[i:end]))
+		i = end
+	}
+	return ts
+}
@@ -0,0 +1,129 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codeview
+
+// options.go contains configurable options for CodeView.
+
+import (
+	"fmt"
+
+	"github.com/mum4k/termdash/cell"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// options holds the provided options.
+type options struct {
+	tokenizer        Tokenizer
+	tokenCellOpts    map[TokenKind][]cell.Option
+	hideLineNumbers  bool
+	lineNumberColor  cell.Color
+	currentLineColor cell.Color
+	tabWidth         int
+}
+
+// validate validates the provided options.
+func (o *options) validate() error {
+	if o.tabWidth <= 0 {
+		return fmt.Errorf("invalid TabWidth(%d), must be a positive number", o.tabWidth)
+	}
+	for kind := range o.tokenCellOpts {
+		if _, ok := tokenKindNames[kind]; !ok {
+			return fmt.Errorf("unsupported TokenKind(%d)", kind)
+		}
+	}
+	return nil
+}
+
+// newOptions returns options with the default values set.
+func newOptions() *options {
+	return &options{
+		tokenCellOpts: map[TokenKind][]cell.Option{
+			TokenKeyword: {cell.FgColor(cell.ColorMagenta), cell.Bold()},
+			TokenType:    {cell.FgColor(cell.ColorCyan)},
+			TokenString:  {cell.FgColor(cell.ColorGreen)},
+			TokenNumber:  {cell.FgColor(cell.ColorYellow)},
+			TokenLiteral: {cell.FgColor(cell.ColorRed)},
+			TokenKey:     {cell.FgColor(cell.ColorBlue)},
+			TokenComment: {cell.FgColor(cell.ColorNumber(244))},
+		},
+		lineNumberColor:  cell.ColorNumber(240),
+		currentLineColor: cell.ColorNumber(236),
+		tabWidth:         DefaultTabWidth,
+	}
+}
+
+// Syntax sets the tokenizer that identifies the parts of the source code
+// that are highlighted. See GoTokenizer, YAMLTokenizer and JSONTokenizer for
+// the built-in tokenizers. The source code isn't highlighted by default.
+func Syntax(t Tokenizer) Option {
+	return option(func(opts *options) {
+		opts.tokenizer = t
+	})
+}
+
+// TokenCellOpts sets the cell options of the tokens of the kind, replacing
+// the default options of the kind.
+func TokenCellOpts(kind TokenKind, cellOpts ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.tokenCellOpts[kind] = cellOpts
+	})
+}
+
+// HideLineNumbers hides the line numbers in the gutter.
+func HideLineNumbers() Option {
+	return option(func(opts *options) {
+		opts.hideLineNumbers = true
+	})
+}
+
+// LineNumberColor sets the color of the line numbers.
+// Defaults to cell.ColorNumber(240).
+func LineNumberColor(c cell.Color) Option {
+	return option(func(opts *options) {
+		opts.lineNumberColor = c
+	})
+}
+
+// CurrentLineColor sets the background color of the current line.
+// Defaults to cell.ColorNumber(236).
+func CurrentLineColor(c cell.Color) Option {
+	return option(func(opts *options) {
+		opts.currentLineColor = c
+	})
+}
+
+// DefaultTabWidth is the default distance between tab stops in cells.
+const DefaultTabWidth = 4
+
+// TabWidth sets the distance between tab stops in cells.
+// Defaults to DefaultTabWidth.
+func TabWidth(cells int) Option {
+	return option(func(opts *options) {
+		opts.tabWidth = cells
+	})
+}
@@ -0,0 +1,209 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codeview
+
+// tokenizer.go contains the interface of tokenizers that identify the parts
+// of the source code that are highlighted.
+
+import (
+	"strings"
+	"unicode"
+)
+
+// TokenKind identifies the kind of a token.
+type TokenKind int
+
+// String implements fmt.Stringer()
+func (tk TokenKind) String() string {
+	if n, ok := tokenKindNames[tk]; ok {
+		return n
+	}
+	return "TokenKindUnknown"
+}
+
+// tokenKindNames maps TokenKind values to human readable names.
+var tokenKindNames = map[TokenKind]string{
+	TokenText:    "TokenText",
+	TokenKeyword: "TokenKeyword",
+	TokenType:    "TokenType",
+	TokenString:  "TokenString",
+	TokenNumber:  "TokenNumber",
+	TokenLiteral: "TokenLiteral",
+	TokenKey:     "TokenKey",
+	TokenComment: "TokenComment",
+}
+
+const (
+	// TokenText is text that isn't highlighted, e.g. identifiers, operators
+	// or space characters.
+	TokenText TokenKind = iota
+
+	// TokenKeyword is a keyword of the language, e.g. "func".
+	TokenKeyword
+
+	// TokenType is a name of a built-in type, e.g. "string", or a YAML tag,
+	// anchor or alias.
+	TokenType
+
+	// TokenString is a string or a character literal.
+	TokenString
+
+	// TokenNumber is a numeric literal.
+	TokenNumber
+
+	// TokenLiteral is a predeclared constant, e.g. "true" or "null".
+	TokenLiteral
+
+	// TokenKey is a key of a mapping or an object, e.g. in YAML or JSON.
+	TokenKey
+
+	// TokenComment is a comment.
+	TokenComment
+)
+
+// Token is a part of the source code.
+type Token struct {
+	// Kind is the kind of the token.
+	Kind TokenKind
+	// Text is the text of the token.
+	Text string
+}
+
+// Tokenizer splits source code into tokens.
+type Tokenizer interface {
+	// Tokenize returns the tokens of the source code. The texts of the
+	// returned tokens must add up to the source code.
+	Tokenize(src string) []Token
+}
+
+// TokenizerFunc is an adapter that allows the use of ordinary functions as
+// a Tokenizer.
+type TokenizerFunc func(src string) []Token
+
+// Tokenize implements Tokenizer.Tokenize.
+func (tf TokenizerFunc) Tokenize(src string) []Token {
+	return tf(src)
+}
+
+// tokens accumulates tokens, merging adjacent tokens of the same kind.
+type tokens []Token
+
+// add adds a token of the kind.
+func (ts *tokens) add(kind TokenKind, text string) {
+	if text == "" {
+		return
+	}
+	if n := len(*ts); n > 0 && (*ts)[n-1].Kind == kind {
+		(*ts)[n-1].Text += text
+		return
+	}
+	*ts = append(*ts, Token{Kind: kind, Text: text})
+}
+
+// isIdentRune determines if the rune can be part of an identifier.
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// scanWhile returns the index of the first rune at or after index i for which
+// the function returns false.
+func scanWhile(rs []rune, i int, fn func(rune) bool) int {
+	for i < len(rs) && fn(rs[i]) {
+		i++
+	}
+	return i
+}
+
+// hasPrefix determines if the runes starting at index i start with the
+// prefix.
+func hasPrefix(rs []rune, i int, prefix string) bool {
+	for _, r := range prefix {
+		if i >= len(rs) || rs[i] != r {
+			return false
+		}
+		i++
+	}
+	return true
+}
+
+// scanPast returns the index after the first occurrence of the terminator at
+// or after index i or the length of the runes if there is none.
+func scanPast(rs []rune, i int, term string) int {
+	for ; i < len(rs); i++ {
+		if hasPrefix(rs, i, term) {
+			return i + len([]rune(term))
+		}
+	}
+	return len(rs)
+}
+
+// scanQuoted returns the index after the string literal that starts with the
+// quote at index i. The literal ends at the closing quote, at the end of the
+// line unless multiline is true or at the end of the source. A backslash
+// escapes the next rune if escapes is true.
+func scanQuoted(rs []rune, i int, escapes, multiline bool) int {
+	quote := rs[i]
+	for i++; i < len(rs); i++ {
+		switch r := rs[i]; {
+		case escapes && r == '\\' && i+1 < len(rs) && rs[i+1] != '\n':
+			i++
+		case r == quote:
+			return i + 1
+		case r == '\n' && !multiline:
+			return i
+		}
+	}
+	return i
+}
+
+// isNumberStart determines if a number starts at index i.
+func isNumberStart(rs []rune, i int) bool {
+	if unicode.IsDigit(rs[i]) {
+		return true
+	}
+	return (rs[i] == '.' || rs[i] == '-') && i+1 < len(rs) && unicode.IsDigit(rs[i+1])
+}
+
+// scanNumber returns the index after the number that starts at index i.
+// Accepts decimal, hexadecimal, octal and binary numbers with fractions,
+// exponents and digit separators.
+func scanNumber(rs []rune, i int) int {
+	if rs[i] == '-' {
+		i++
+	}
+	for i < len(rs) {
+		r := rs[i]
+		switch {
+		case isIdentRune(r) || r == '.':
+			i++
+		case (r == '+' || r == '-') && strings.ContainsRune("eEpP", rs[i-1]) && !isHex(rs, i):
+			i++
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// isHex determines if the number before index i is hexadecimal, where 'e'
+// is a digit rather than an exponent.
+func isHex(rs []rune, i int) bool {
+	start := i
+	for start > 0 && (isIdentRune(rs[start-1]) || rs[start-1] == '.') {
+		start--
+	}
+	s := strings.ToLower(string(rs[start:i]))
+	return strings.HasPrefix(s, "0x") && !strings.ContainsRune(s, 'p')
+}
@@ -0,0 +1,181 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codeview
+
+import (
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestTokenizers(t *testing.T) {
+	tests := []struct {
+		desc      string
+		tokenizer Tokenizer
+		src       string
+		want      []Token
+	}{
+		{
+			desc:      "go with keywords, types and literals",
+			tokenizer: GoTokenizer(),
+			src:       "func f() int { return nil }",
+			want: []Token{
+				{TokenKeyword, "func"},
+				{TokenText, " f() "},
+				{TokenType, "int"},
+				{TokenText, " { "},
+				{TokenKeyword, "return"},
+				{TokenText, " "},
+				{TokenLiteral, "nil"},
+				{TokenText, " }"},
+			},
+		},
+		{
+			desc:      "go comments",
+			tokenizer: GoTokenizer(),
+			src:       "a // b\n/* c\nd */ e",
+			want: []Token{
+				{TokenText, "a "},
+				{TokenComment, "// b"},
+				{TokenText, "\n"},
+				{TokenComment, "/* c\nd */"},
+				{TokenText, " e"},
+			},
+		},
+		{
+			desc:      "go strings and runes",
+			tokenizer: GoTokenizer(),
+			src:       "\"a\\\"b\" 'c' `d\ne`",
+			want: []Token{
+				{TokenString, "\"a\\\"b\""},
+				{TokenText, " "},
+				{TokenString, "'c'"},
+				{TokenText, " "},
+				{TokenString, "`d\ne`"},
+			},
+		},
+		{
+			desc:      "go numbers",
+			tokenizer: GoTokenizer(),
+			src:       "x1 + 0x1F - 1.5e3",
+			want: []Token{
+				{TokenText, "x1 + "},
+				{TokenNumber, "0x1F"},
+				{TokenText, " - "},
+				{TokenNumber, "1.5e3"},
+			},
+		},
+		{
+			desc:      "go unterminated string",
+			tokenizer: GoTokenizer(),
+			src:       "\"a\nb",
+			want: []Token{
+				{TokenString, "\"a"},
+				{TokenText, "\nb"},
+			},
+		},
+		{
+			desc:      "json",
+			tokenizer: JSONTokenizer(),
+			src:       "{\"a\": [1, \"b\", true, null]}",
+			want: []Token{
+				{TokenText, "{"},
+				{TokenKey, "\"a\""},
+				{TokenText, ": ["},
+				{TokenNumber, "1"},
+				{TokenText, ", "},
+				{TokenString, "\"b\""},
+				{TokenText, ", "},
+				{TokenLiteral, "true"},
+				{TokenText, ", "},
+				{TokenLiteral, "null"},
+				{TokenText, "]}"},
+			},
+		},
+		{
+			desc:      "yaml mappings and sequences",
+			tokenizer: YAMLTokenizer(),
+			src:       "---\na: 1 # c\nb:\n  - x\n  - \"y\"\nc: &d yes\n",
+			want: []Token{
+				{TokenKeyword, "---"},
+				{TokenText, "\n"},
+				{TokenKey, "a"},
+				{TokenText, ": "},
+				{TokenNumber, "1"},
+				{TokenText, " "},
+				{TokenComment, "# c"},
+				{TokenText, "\n"},
+				{TokenKey, "b"},
+				{TokenText, ":\n  - x\n  - "},
+				{TokenString, "\"y\""},
+				{TokenText, "\n"},
+				{TokenKey, "c"},
+				{TokenText, ": "},
+				{TokenType, "&d"},
+				{TokenText, " "},
+				{TokenLiteral, "yes"},
+				{TokenText, "\n"},
+			},
+		},
+		{
+			desc:      "yaml block scalar",
+			tokenizer: YAMLTokenizer(),
+			src:       "a: |\n  b: 1\n\nc: 2",
+			want: []Token{
+				{TokenKey, "a"},
+				{TokenText, ": |\n  "},
+				{TokenString, "b: 1"},
+				{TokenText, "\n\n"},
+				{TokenKey, "c"},
+				{TokenText, ": "},
+				{TokenNumber, "2"},
+			},
+		},
+		{
+			desc:      "yaml plain scalar with colon",
+			tokenizer: YAMLTokenizer(),
+			src:       "url: http://a:8080",
+			want: []Token{
+				{TokenKey, "url"},
+				{TokenText, ": http://a:8080"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := tc.tokenizer.Tokenize(tc.src)
+			if diff := pretty.Compare(tc.want, got); diff != "" {
+				t.Errorf("Tokenize => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTokenKindString(t *testing.T) {
+	tests := []struct {
+		kind TokenKind
+		want string
+	}{
+		{TokenText, "TokenText"},
+		{TokenComment, "TokenComment"},
+		{TokenKind(-1), "TokenKindUnknown"},
+	}
+	for _, tc := range tests {
+		if got := tc.kind.String(); got != tc.want {
+			t.Errorf("String => %q, want %q", got, tc.want)
+		}
+	}
+}
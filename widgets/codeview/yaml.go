@@ -0,0 +1,190 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codeview
+
+// yaml.go contains the tokenizer of YAML.
+
+import "strings"
+
+// yamlLiterals are the plain scalars that YAML interprets as booleans or
+// null values.
+var yamlLiterals = map[string]bool{
+	"true": true, "false": true, "yes": true, "no": true, "on": true,
+	"off": true, "null": true, "~": true,
+}
+
+// YAMLTokenizer returns a tokenizer of YAML documents.
+// Keys of mappings are identified as TokenKey, the contents of block scalars
+// as TokenString and tags, anchors and aliases as TokenType.
+func YAMLTokenizer() Tokenizer {
+	return TokenizerFunc(tokenizeYAML)
+}
+
+// yamlTokenizer splits a YAML document into tokens line by line.
+type yamlTokenizer struct {
+	// ts are the tokens.
+	ts tokens
+	// blockIndent is the indentation of the line that started a block
+	// scalar or -1 outside of block scalars.
+	blockIndent int
+}
+
+// tokenizeYAML splits a YAML document into tokens.
+func tokenizeYAML(src string) []Token {
+	yt := &yamlTokenizer{blockIndent: -1}
+	for _, line := range strings.SplitAfter(src, "\n") {
+		yt.line(line)
+	}
+	return yt.ts
+}
+
+// isSpace determines if the rune is a space character within a line.
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t'
+}
+
+// line tokenizes a single line including its newline character.
+func (yt *yamlTokenizer) line(line string) {
+	rs := []rune(strings.TrimSuffix(line, "\n"))
+	defer func() {
+		if strings.HasSuffix(line, "\n") {
+			yt.ts.add(TokenText, "\n")
+		}
+	}()
+
+	indent := scanWhile(rs, 0, isSpace)
+	if yt.blockIndent >= 0 {
+		if indent == len(rs) || indent > yt.blockIndent {
+			yt.ts.add(TokenText, string(rs[:indent]))
+			yt.ts.add(TokenString, string(rs[indent:]))
+			return
+		}
+		yt.blockIndent = -1
+	}
+	yt.ts.add(TokenText, string(rs[:indent]))
+
+	i := indent
+	if rest := string(rs[i:]); rest == "---" || rest == "..." || strings.HasPrefix(rest, "--- ") {
+		yt.ts.add(TokenKeyword, string(rs[i:i+3]))
+		i += 3
+	}
+	for i < len(rs) && rs[i] == '-' && (i+1 == len(rs) || isSpace(rs[i+1])) {
+		// Entries of sequences.
+		end := scanWhile(rs, i+1, isSpace)
+		yt.ts.add(TokenText, string(rs[i:end]))
+		i = end
+	}
+	if end := yamlKeyEnd(rs, i); end > i {
+		yt.ts.add(TokenKey, string(rs[i:end]))
+		i = end
+	}
+	if yt.value(rs, i) {
+		yt.blockIndent = indent
+	}
+}
+
+// yamlKeyEnd returns the index after the key of a mapping that starts at
+// index i or i if there is no key.
+func yamlKeyEnd(rs []rune, i int) int {
+	isSep := func(j int) bool {
+		return j < len(rs) && rs[j] == ':' && (j+1 == len(rs) || isSpace(rs[j+1]))
+	}
+	if i >= len(rs) {
+		return i
+	}
+	if r := rs[i]; r == '"' || r == '\'' {
+		end := scanQuoted(rs, i, r == '"', false)
+		if isSep(end) {
+			return end
+		}
+		return i
+	}
+	if r := rs[i]; r == '[' || r == '{' || r == '#' {
+		return i
+	}
+	for j := i; j < len(rs); j++ {
+		switch {
+		case rs[j] == '#' && isSpace(rs[j-1]):
+			return i
+		case isSep(j):
+			return j
+		}
+	}
+	return i
+}
+
+// value tokenizes the rest of the line starting at index i.
+// Returns true if the value starts a block scalar.
+func (yt *yamlTokenizer) value(rs []rune, i int) bool {
+	block := false
+	for i < len(rs) {
+		r := rs[i]
+		var end int
+		var kind TokenKind
+		switch {
+		case isSpace(r) || r == ':' || r == ',' || r == '[' || r == ']' || r == '{' || r == '}':
+			end, kind = i+1, TokenText
+
+		case r == '#' && (i == 0 || isSpace(rs[i-1])):
+			end, kind = len(rs), TokenComment
+
+		case r == '"' || r == '\'':
+			end, kind = scanQuoted(rs, i, r == '"', false), TokenString
+
+		case r == '&' || r == '*' || r == '!':
+			end = scanWhile(rs, i, func(r rune) bool { return !isSpace(r) && r != ',' && r != ']' && r != '}' })
+			kind = TokenType
+
+		case (r == '|' || r == '>') && strings.Trim(strings.TrimSpace(string(rs[i+1:])), "+-0123456789") == "":
+			end, kind = len(rs), TokenText
+			block = true
+
+		default:
+			end = yamlPlainEnd(rs, i)
+			kind = yamlPlainKind(string(rs[i:end]))
+		}
+		yt.ts.add(kind, string(rs[i:end]))
+		i = end
+	}
+	return block
+}
+
+// yamlPlainEnd returns the index after the plain scalar that starts at index
+// i, excluding trailing space characters.
+func yamlPlainEnd(rs []rune, i int) int {
+	end := i
+	for j := i; j < len(rs); j++ {
+		r := rs[j]
+		if (r == '#' && isSpace(rs[j-1])) || r == ',' || r == ']' || r == '}' {
+			break
+		}
+		if !isSpace(r) {
+			end = j + 1
+		}
+	}
+	return end
+}
+
+// yamlPlainKind returns the kind of the plain scalar.
+func yamlPlainKind(s string) TokenKind {
+	if yamlLiterals[strings.ToLower(s)] {
+		return TokenLiteral
+	}
+	rs := []rune(s)
+	if len(rs) > 0 && isNumberStart(rs, 0) && scanNumber(rs, 0) == len(rs) {
+		return TokenNumber
+	}
+	return TokenText
+}
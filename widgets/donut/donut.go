@@ -28,7 +28,9 @@ import (
 	"github.com/mum4k/termdash/internal/area"
 	"github.com/mum4k/termdash/internal/canvas"
 	"github.com/mum4k/termdash/internal/canvas/braille"
+	"github.com/mum4k/termdash/internal/circle"
 	"github.com/mum4k/termdash/internal/draw"
+	"github.com/mum4k/termdash/internal/markup"
 	"github.com/mum4k/termdash/internal/runewidth"
 	"github.com/mum4k/termdash/terminal/terminalapi"
 	"github.com/mum4k/termdash/widgetapi"
@@ -169,7 +171,7 @@ func (d *Donut) holeRadius(donutRadius int) int {
 // The mid point addresses coordinates in pixels on a braille canvas.
 // The donutAr is the cell area for the donut itself.
 func (d *Donut) drawText(cvs *canvas.Canvas, donutAr image.Rectangle, mid image.Point, holeR int) error {
-	cells, first := availableCells(mid, holeR)
+	cells, first := circle.AvailableCells(mid, holeR)
 	t := d.progressText()
 	needCells := runewidth.StringWidth(t)
 	if cells < needCells {
@@ -196,12 +198,12 @@ func (d *Donut) drawText(cvs *canvas.Canvas, donutAr image.Rectangle, mid image.
 
 // drawLabel draws the text label in the area.
 func (d *Donut) drawLabel(cvs *canvas.Canvas, labelAr image.Rectangle) error {
-	start, err := alignfor.Text(labelAr, d.opts.label, d.opts.labelAlign, align.VerticalMiddle)
+	start, err := alignfor.Text(labelAr, markup.Text(d.opts.labelChunks), d.opts.labelAlign, align.VerticalMiddle)
 	if err != nil {
 		return err
 	}
-	if err := draw.Text(
-		cvs, d.opts.label, start,
+	if err := draw.TextChunks(
+		cvs, d.opts.labelChunks, start,
 		draw.TextOverrunMode(draw.OverrunModeThreeDot),
 		draw.TextMaxX(labelAr.Max.X),
 		draw.TextCellOpts(d.opts.labelCellOpts...),
@@ -224,7 +226,7 @@ func (d *Donut) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
 	}
 
 	var donutAr, labelAr image.Rectangle
-	if len(d.opts.label) > 0 {
+	if len(d.opts.labelChunks) > 0 {
 		d, l, err := donutAndLabel(cvs.Area())
 		if err != nil {
 			return err
@@ -247,7 +249,7 @@ func (d *Donut) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
 		return fmt.Errorf("braille.New => %v", err)
 	}
 
-	mid, r := midAndRadius(bc.Area())
+	mid, r := circle.MidAndRadius(bc.Area())
 	if err := draw.BrailleCircle(bc, mid, r,
 		draw.BrailleCircleFilled(),
 		draw.BrailleCircleArcOnly(startA, endA),
@@ -788,6 +788,72 @@ func TestDonut(t *testing.T) {
 				return ft
 			},
 		},
+		{
+			desc: "New fails on invalid label markup",
+			opts: []Option{
+				Label("[fg=purple]hi"),
+				LabelMarkup(),
+			},
+			canvas:     image.Rect(0, 0, 7, 7),
+			wantNewErr: true,
+		},
+		{
+			desc: "Percent fails on invalid label markup",
+			update: func(d *Donut) error {
+				return d.Percent(100, Label("[bold"), LabelMarkup())
+			},
+			canvas:        image.Rect(0, 0, 7, 7),
+			wantUpdateErr: true,
+		},
+		{
+			desc: "text label with markup",
+			opts: []Option{
+				Label(
+					"[fg=green]h[/]i",
+					cell.BgColor(cell.ColorBlue),
+				),
+				LabelMarkup(),
+			},
+			canvas: image.Rect(0, 0, 7, 7),
+			update: func(d *Donut) error {
+				return d.Percent(100, HolePercent(80))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				bc := testbraille.MustNew(c.Area())
+
+				testdraw.MustBrailleCircle(bc, image.Point{6, 13}, 6, draw.BrailleCircleFilled())
+				testdraw.MustBrailleCircle(bc, image.Point{6, 13}, 5,
+					draw.BrailleCircleFilled(),
+					draw.BrailleCircleClearPixels(),
+				)
+				testbraille.MustCopyTo(bc, c)
+
+				testdraw.MustText(c, "100%", image.Point{2, 3})
+
+				testdraw.MustText(
+					c,
+					"h",
+					image.Point{2, 6},
+					draw.TextCellOpts(
+						cell.FgColor(cell.ColorGreen),
+						cell.BgColor(cell.ColorBlue),
+					),
+				)
+				testdraw.MustText(
+					c,
+					"i",
+					image.Point{3, 6},
+					draw.TextCellOpts(
+						cell.BgColor(cell.ColorBlue),
+					),
+				)
+
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
 		{
 			desc: "text label too long, gets trimmed",
 			opts: []Option{
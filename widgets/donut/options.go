@@ -21,6 +21,7 @@ import (
 
 	"github.com/mum4k/termdash/align"
 	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/markup"
 )
 
 // Option is used to provide options.
@@ -48,6 +49,10 @@ type options struct {
 	labelCellOpts []cell.Option
 	labelAlign    align.Horizontal
 	label         string
+	labelMarkup   bool
+	// labelChunks is the label split into chunks with their cell options,
+	// populated by validate.
+	labelChunks []*markup.Chunk
 
 	// The angle in degrees that represents 0 and 100% of the progress.
 	startAngle int
@@ -66,6 +71,14 @@ func (o *options) validate() error {
 		return fmt.Errorf("invalid start angle %d, must be in range %d <= angle < %d", o.startAngle, min, max)
 	}
 
+	o.labelChunks = markup.Plain(o.label)
+	if o.labelMarkup {
+		chunks, err := markup.Parse(o.label)
+		if err != nil {
+			return fmt.Errorf("invalid Label: %v", err)
+		}
+		o.labelChunks = chunks
+	}
 	return nil
 }
 
@@ -172,6 +185,19 @@ func Label(text string, cOpts ...cell.Option) Option {
 	})
 }
 
+// LabelMarkup instructs the Donut to interpret the text provided via the
+// Label option as markup that sets the cell options of parts of the text,
+// e.g.:
+//   "[fg=red]5[/] of 10 nodes"
+// The cell options set by the markup are applied on top of the cell options
+// provided via the Label option. A literal '[' must be written as "[[". See
+// the documentation of the internal/markup package for the full syntax.
+func LabelMarkup() Option {
+	return option(func(opts *options) {
+		opts.labelMarkup = true
+	})
+}
+
 // DefaultLabelAlign is the default value for the LabelAlign option.
 const DefaultLabelAlign = align.HorizontalCenter
 
@@ -0,0 +1,483 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dropdown implements a widget that selects one of several options
+// from a list that opens on demand.
+package dropdown
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/button"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/draw"
+	"github.com/mum4k/termdash/internal/runewidth"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// CallbackFn is the function called when the user selects an option. The
+// arguments are the index and the label of the selected option.
+//
+// The callback function must be thread-safe as the mouse or keyboard events
+// that select options are processed in a separate goroutine.
+//
+// If the function returns an error, the widget will forward it back to the
+// termdash infrastructure which causes a panic, unless the user provided a
+// termdash.ErrorHandler.
+type CallbackFn func(selected int, label string) error
+
+// Dropdown displays the selected option on a single line. When opened, it
+// displays a list of all the options above the neighbouring containers.
+//
+// The list is opened by a mouse click on the widget or by the enter key while
+// its container is focused. While open, the arrow keys move the highlight,
+// the enter key or a mouse click selects the option, typed characters filter
+// the options and the escape key or a mouse click outside of the list closes
+// it.
+//
+// Implements widgetapi.Widget and widgetapi.Popup. This object is
+// thread-safe.
+type Dropdown struct {
+	// labels are the labels of the options.
+	labels []string
+	// selected is the index of the selected option.
+	selected int
+
+	// open asserts whether the list of options is open.
+	open bool
+	// filter is the text typed while the list is open.
+	filter string
+	// matches are indexes of options that match the filter.
+	matches []int
+	// highlight is the index into matches of the highlighted option.
+	highlight int
+	// offset is the index into matches of the first displayed option.
+	offset int
+
+	// width is the width of the canvas on the last call to Draw.
+	width int
+	// listArea is the area of the options within the popup canvas on the last
+	// call to DrawPopup.
+	listArea image.Rectangle
+
+	// mouseFSM tracks left mouse clicks on the widget.
+	mouseFSM *button.FSM
+
+	// mu protects the widget.
+	mu sync.Mutex
+
+	// opts are the provided options.
+	opts *options
+}
+
+// New returns a new Dropdown with options of the provided labels.
+// At least one label must be provided.
+func New(labels []string, opts ...Option) (*Dropdown, error) {
+	if len(labels) == 0 {
+		return nil, errors.New("at least one label must be provided")
+	}
+
+	opt := newOptions()
+	for _, o := range opts {
+		o.set(opt)
+	}
+	if err := opt.validate(len(labels)); err != nil {
+		return nil, err
+	}
+	return &Dropdown{
+		labels:   labels,
+		selected: opt.selected,
+		mouseFSM: button.NewFSM(mouse.ButtonLeft, image.ZR),
+		opts:     opt,
+	}, nil
+}
+
+// Selected returns the index of the selected option.
+func (d *Dropdown) Selected() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.selected
+}
+
+// SetSelected selects the option at the provided index. Doesn't call the
+// OnSelect callback.
+func (d *Dropdown) SetSelected(i int) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := validateIndex(i, len(d.labels)); err != nil {
+		return err
+	}
+	d.selected = i
+	return nil
+}
+
+// IsOpen asserts whether the list of options is open.
+func (d *Dropdown) IsOpen() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.open
+}
+
+// validateIndex validates the index of an option.
+func validateIndex(i, count int) error {
+	if i < 0 || i >= count {
+		return fmt.Errorf("invalid option index %d, must be in range 0 <= i < %d", i, count)
+	}
+	return nil
+}
+
+// openList opens the list of options with the selected option highlighted.
+// Caller must hold d.mu.
+func (d *Dropdown) openList() {
+	d.open = true
+	d.setFilter("")
+	for i, m := range d.matches {
+		if m == d.selected {
+			d.highlight = i
+		}
+	}
+	d.scrollToHighlight()
+}
+
+// closeList closes the list of options.
+// Caller must hold d.mu.
+func (d *Dropdown) closeList() {
+	d.open = false
+	d.setFilter("")
+}
+
+// setFilter sets the filter and updates the matching options.
+// Options match if their label contains the filter ignoring case.
+// Caller must hold d.mu.
+func (d *Dropdown) setFilter(filter string) {
+	d.filter = filter
+	d.matches = nil
+	lower := strings.ToLower(filter)
+	for i, l := range d.labels {
+		if strings.Contains(strings.ToLower(l), lower) {
+			d.matches = append(d.matches, i)
+		}
+	}
+	d.highlight = 0
+	d.offset = 0
+}
+
+// moveHighlight moves the highlight by the provided number of options.
+// Caller must hold d.mu.
+func (d *Dropdown) moveHighlight(by int) {
+	d.highlight += by
+	if d.highlight < 0 {
+		d.highlight = 0
+	}
+	if max := len(d.matches) - 1; d.highlight > max {
+		d.highlight = max
+	}
+	if d.highlight < 0 {
+		d.highlight = 0
+	}
+	d.scrollToHighlight()
+}
+
+// scrollToHighlight adjusts the offset so that the highlighted option is
+// visible.
+// Caller must hold d.mu.
+func (d *Dropdown) scrollToHighlight() {
+	if d.highlight < d.offset {
+		d.offset = d.highlight
+	}
+	if last := d.offset + d.opts.maxVisible - 1; d.highlight > last {
+		d.offset = d.highlight - d.opts.maxVisible + 1
+	}
+}
+
+// scroll scrolls the list by the provided number of options without moving
+// the highlight.
+// Caller must hold d.mu.
+func (d *Dropdown) scroll(by int) {
+	d.offset += by
+	if max := len(d.matches) - d.opts.maxVisible; d.offset > max {
+		d.offset = max
+	}
+	if d.offset < 0 {
+		d.offset = 0
+	}
+}
+
+// selectMatch selects the option at the provided index into matches, closes
+// the list and calls the OnSelect callback.
+// Caller must hold d.mu.
+func (d *Dropdown) selectMatch(i int) error {
+	if i < 0 || i >= len(d.matches) {
+		return nil
+	}
+	d.selected = d.matches[i]
+	d.closeList()
+	if d.opts.onSelect != nil {
+		return d.opts.onSelect(d.selected, d.labels[d.selected])
+	}
+	return nil
+}
+
+// arrowWidth is the width of the arrow displayed at the right edge including
+// the space before it.
+const arrowWidth = 2
+
+// Draw draws the selected option onto the canvas.
+// Implements widgetapi.Widget.Draw.
+func (d *Dropdown) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	cvsAr := cvs.Area()
+	d.mouseFSM.UpdateArea(cvsAr)
+	d.width = cvsAr.Dx()
+	if cvsAr.Dx() < arrowWidth+1 {
+		return draw.ResizeNeeded(cvs)
+	}
+
+	arrow := '▼'
+	if d.open {
+		arrow = '▲'
+	}
+	if _, err := cvs.SetCell(image.Point{cvsAr.Max.X - 1, 0}, arrow, cell.FgColor(d.opts.arrowColor)); err != nil {
+		return err
+	}
+
+	textOpts := []cell.Option{cell.FgColor(d.opts.textColor)}
+	if meta.Focused {
+		textOpts = append(textOpts, cell.Underline())
+	}
+	return draw.Text(cvs, d.labels[d.selected], image.Point{0, 0},
+		draw.TextCellOpts(textOpts...),
+		draw.TextMaxX(cvsAr.Max.X-arrowWidth),
+		draw.TextOverrunMode(draw.OverrunModeThreeDot),
+	)
+}
+
+// listRows returns the number of rows displayed in the open list.
+// Caller must hold d.mu.
+func (d *Dropdown) listRows() int {
+	rows := len(d.matches)
+	if rows > d.opts.maxVisible {
+		rows = d.opts.maxVisible
+	}
+	if rows == 0 {
+		rows = 1 // For the text indicating there are no matches.
+	}
+	return rows
+}
+
+// PopupSize returns the size of the open list.
+// Implements widgetapi.Popup.PopupSize.
+func (d *Dropdown) PopupSize() image.Point {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.open {
+		return image.ZP
+	}
+
+	width := d.width
+	for _, l := range d.labels {
+		if w := runewidth.StringWidth(l) + 2; w > width { // The border.
+			width = w
+		}
+	}
+	return image.Point{width, d.listRows() + 2}
+}
+
+// noMatchesText is displayed in the open list when no option matches the
+// filter.
+const noMatchesText = "no matches"
+
+// DrawPopup draws the open list of options onto the canvas.
+// Implements widgetapi.Popup.DrawPopup.
+func (d *Dropdown) DrawPopup(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	cvsAr := cvs.Area()
+	d.listArea = image.ZR
+	if cvsAr.Dx() < 3 || cvsAr.Dy() < 3 {
+		return draw.ResizeNeeded(cvs)
+	}
+
+	borderOpts := []draw.BorderOption{
+		draw.BorderLineStyle(linestyle.Light),
+		draw.BorderCellOpts(cell.FgColor(d.opts.borderColor)),
+	}
+	if d.filter != "" {
+		borderOpts = append(borderOpts, draw.BorderTitle(d.filter, draw.OverrunModeThreeDot, cell.FgColor(d.opts.textColor)))
+	}
+	if err := draw.Border(cvs, cvsAr, borderOpts...); err != nil {
+		return err
+	}
+
+	d.listArea = image.Rect(1, 1, cvsAr.Max.X-1, cvsAr.Max.Y-1)
+	if len(d.matches) == 0 {
+		return draw.Text(cvs, noMatchesText, d.listArea.Min,
+			draw.TextCellOpts(cell.FgColor(d.opts.borderColor)),
+			draw.TextMaxX(d.listArea.Max.X),
+			draw.TextOverrunMode(draw.OverrunModeThreeDot),
+		)
+	}
+
+	for row := 0; row < d.listArea.Dy(); row++ {
+		i := d.offset + row
+		if i >= len(d.matches) {
+			break
+		}
+
+		textOpts := []cell.Option{cell.FgColor(d.opts.textColor)}
+		y := d.listArea.Min.Y + row
+		if i == d.highlight {
+			rowAr := image.Rect(d.listArea.Min.X, y, d.listArea.Max.X, y+1)
+			if err := cvs.SetAreaCells(rowAr, ' ', cell.BgColor(d.opts.highlightColor)); err != nil {
+				return err
+			}
+			textOpts = append(textOpts, cell.BgColor(d.opts.highlightColor))
+		}
+		if err := draw.Text(cvs, d.labels[d.matches[i]], image.Point{d.listArea.Min.X, y},
+			draw.TextCellOpts(textOpts...),
+			draw.TextMaxX(d.listArea.Max.X),
+			draw.TextOverrunMode(draw.OverrunModeThreeDot),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Keyboard opens the list on the enter key and navigates or filters the
+// options while it is open.
+// Implements widgetapi.Widget.Keyboard.
+func (d *Dropdown) Keyboard(k *terminalapi.Keyboard) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.open {
+		if k.Key == keyboard.KeyEnter || k.Key == keyboard.KeyArrowDown {
+			d.openList()
+		}
+		return nil
+	}
+
+	switch k.Key {
+	case keyboard.KeyEnter:
+		return d.selectMatch(d.highlight)
+
+	case keyboard.KeyEsc:
+		d.closeList()
+
+	case keyboard.KeyArrowUp:
+		d.moveHighlight(-1)
+
+	case keyboard.KeyArrowDown:
+		d.moveHighlight(1)
+
+	case keyboard.KeyPgUp:
+		d.moveHighlight(-d.opts.maxVisible)
+
+	case keyboard.KeyPgDn:
+		d.moveHighlight(d.opts.maxVisible)
+
+	case keyboard.KeyBackspace, keyboard.KeyBackspace2:
+		if r := []rune(d.filter); len(r) > 0 {
+			d.setFilter(string(r[:len(r)-1]))
+		}
+
+	default:
+		if k.Key < 0 || !unicode.IsPrint(rune(k.Key)) {
+			// Ignore special keys and unsupported runes.
+			return nil
+		}
+		d.setFilter(d.filter + string(k.Key))
+	}
+	return nil
+}
+
+// Mouse opens the list on a click on the widget and closes it on a click
+// outside of the list.
+// Implements widgetapi.Widget.Mouse.
+func (d *Dropdown) Mouse(m *terminalapi.Mouse) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if clicked, _ := d.mouseFSM.Event(m); clicked {
+		if d.open {
+			d.closeList()
+		} else {
+			d.openList()
+		}
+		return nil
+	}
+
+	// Events on the list are delivered to PopupMouse, so this is a click
+	// somewhere outside of the widget and the list.
+	if d.open && m.Button == mouse.ButtonLeft && m.Position == (image.Point{-1, -1}) {
+		d.closeList()
+	}
+	return nil
+}
+
+// PopupMouse selects the clicked option and scrolls the list on the mouse
+// wheel.
+// Implements widgetapi.Popup.PopupMouse.
+func (d *Dropdown) PopupMouse(m *terminalapi.Mouse) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch m.Button {
+	case mouse.ButtonWheelUp:
+		d.scroll(-1)
+
+	case mouse.ButtonWheelDown:
+		d.scroll(1)
+
+	case mouse.ButtonLeft:
+		if !m.Position.In(d.listArea) {
+			return nil
+		}
+		return d.selectMatch(d.offset + m.Position.Y - d.listArea.Min.Y)
+	}
+	return nil
+}
+
+// Options implements widgetapi.Widget.Options.
+func (d *Dropdown) Options() widgetapi.Options {
+	// No need to lock, as the labels are fixed when New is called.
+	var width int
+	for _, l := range d.labels {
+		if w := runewidth.StringWidth(l); w > width {
+			width = w
+		}
+	}
+	return widgetapi.Options{
+		MinimumSize:  image.Point{arrowWidth + 1, 1},
+		MaximumSize:  image.Point{width + arrowWidth, 1},
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		WantMouse:    widgetapi.MouseScopeGlobal,
+	}
+}
@@ -0,0 +1,685 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dropdown
+
+import (
+	"errors"
+	"image"
+	"sync"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/canvas/testcanvas"
+	"github.com/mum4k/termdash/internal/draw"
+	"github.com/mum4k/termdash/internal/draw/testdraw"
+	"github.com/mum4k/termdash/internal/faketerm"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// selection is one call to the callback.
+type selection struct {
+	selected int
+	label    string
+}
+
+// callbackTracker tracks calls to the callback.
+type callbackTracker struct {
+	// wantErr when set to true, makes callback return an error.
+	wantErr bool
+
+	// calls are the values the callback was called with.
+	calls []selection
+
+	// mu protects the tracker.
+	mu sync.Mutex
+}
+
+// callback is the callback function.
+func (ct *callbackTracker) callback(selected int, label string) error {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	if ct.wantErr {
+		return errors.New("ct.wantErr set to true")
+	}
+	ct.calls = append(ct.calls, selection{selected, label})
+	return nil
+}
+
+// fruits are labels used in the tests.
+var fruits = []string{"apple", "banana", "cherry"}
+
+// mustListBorder draws the expected border of the open list.
+func mustListBorder(cvs *canvas.Canvas, opts ...draw.BorderOption) {
+	testdraw.MustBorder(cvs, cvs.Area(), append([]draw.BorderOption{
+		draw.BorderLineStyle(linestyle.Light),
+		draw.BorderCellOpts(cell.FgColor(cell.ColorNumber(240))),
+	}, opts...)...)
+}
+
+// mustHighlight draws the expected highlighted option on the specified row of
+// the open list.
+func mustHighlight(cvs *canvas.Canvas, label string, y int) {
+	testcanvas.MustSetAreaCells(cvs, image.Rect(1, y, cvs.Area().Max.X-1, y+1), ' ', cell.BgColor(cell.ColorBlue))
+	testdraw.MustText(cvs, label, image.Point{1, y}, draw.TextCellOpts(cell.BgColor(cell.ColorBlue)))
+}
+
+func TestDropdown(t *testing.T) {
+	tests := []struct {
+		desc     string
+		labels   []string
+		opts     []Option
+		callback *callbackTracker
+		setter   func(*Dropdown) error
+		events   []terminalapi.Event
+		// popupEvents are delivered to PopupMouse after the events.
+		popupEvents []*terminalapi.Mouse
+		canvas      image.Rectangle
+		meta        *widgetapi.Meta
+		want        func(size image.Point) *faketerm.Terminal
+		// wantPopup is the expected popup, nil if the list should be closed.
+		wantPopup    func(size image.Point) *faketerm.Terminal
+		wantCalls    []selection
+		wantSelected int
+		wantNewErr   bool
+		wantSetErr   bool
+		wantDrawErr  bool
+		wantEvErr    bool
+	}{
+		{
+			desc:       "New fails without labels",
+			canvas:     image.Rect(0, 0, 10, 1),
+			wantNewErr: true,
+		},
+		{
+			desc:   "New fails on selected index out of range",
+			labels: fruits,
+			opts: []Option{
+				Selected(3),
+			},
+			canvas:     image.Rect(0, 0, 10, 1),
+			wantNewErr: true,
+		},
+		{
+			desc:   "New fails on zero MaxVisible",
+			labels: fruits,
+			opts: []Option{
+				MaxVisible(0),
+			},
+			canvas:     image.Rect(0, 0, 10, 1),
+			wantNewErr: true,
+		},
+		{
+			desc:   "draws the selected option",
+			labels: fruits,
+			canvas: image.Rect(0, 0, 10, 1),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "apple", image.Point{0, 0})
+				testcanvas.MustSetCell(cvs, image.Point{9, 0}, '▼')
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "draws initially selected option with custom colors and focus",
+			labels: fruits,
+			opts: []Option{
+				Selected(1),
+				TextColor(cell.ColorRed),
+				ArrowColor(cell.ColorGreen),
+			},
+			canvas: image.Rect(0, 0, 10, 1),
+			meta:   &widgetapi.Meta{Focused: true},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "banana", image.Point{0, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorRed), cell.Underline()))
+				testcanvas.MustSetCell(cvs, image.Point{9, 0}, '▼', cell.FgColor(cell.ColorGreen))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantSelected: 1,
+		},
+		{
+			desc:   "trims the option that doesn't fit",
+			labels: fruits,
+			opts: []Option{
+				Selected(1),
+			},
+			canvas: image.Rect(0, 0, 6, 1),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "ban…", image.Point{0, 0})
+				testcanvas.MustSetCell(cvs, image.Point{5, 0}, '▼')
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantSelected: 1,
+		},
+		{
+			desc:   "requests resize when the canvas is too small",
+			labels: fruits,
+			canvas: image.Rect(0, 0, 2, 1),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustResizeNeeded(cvs)
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "enter opens the list with the selected option highlighted",
+			labels: fruits,
+			opts: []Option{
+				Selected(1),
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+			},
+			canvas: image.Rect(0, 0, 10, 1),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "banana", image.Point{0, 0})
+				testcanvas.MustSetCell(cvs, image.Point{9, 0}, '▲')
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantPopup: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustListBorder(cvs)
+				testdraw.MustText(cvs, "apple", image.Point{1, 1})
+				mustHighlight(cvs, "banana", 2)
+				testdraw.MustText(cvs, "cherry", image.Point{1, 3})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantSelected: 1,
+		},
+		{
+			desc:   "the list is at least as wide as the longest option",
+			labels: fruits,
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+			},
+			canvas: image.Rect(0, 0, 4, 1),
+			meta:   &widgetapi.Meta{},
+			wantPopup: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustListBorder(cvs)
+				mustHighlight(cvs, "apple", 1)
+				testdraw.MustText(cvs, "banana", image.Point{1, 2})
+				testdraw.MustText(cvs, "cherry", image.Point{1, 3})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:     "arrow keys and enter select an option",
+			labels:   fruits,
+			callback: &callbackTracker{},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowUp},
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+			},
+			canvas: image.Rect(0, 0, 10, 1),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustText(cvs, "banana", image.Point{0, 0})
+				testcanvas.MustSetCell(cvs, image.Point{9, 0}, '▼')
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantCalls:    []selection{{1, "banana"}},
+			wantSelected: 1,
+		},
+		{
+			desc:     "escape closes the list without selecting",
+			labels:   fruits,
+			callback: &callbackTracker{},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+				&terminalapi.Keyboard{Key: keyboard.KeyEsc},
+			},
+			canvas: image.Rect(0, 0, 10, 1),
+			meta:   &widgetapi.Meta{},
+		},
+		{
+			desc:   "typing filters the options ignoring case",
+			labels: fruits,
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+				&terminalapi.Keyboard{Key: 'A'},
+				&terminalapi.Keyboard{Key: 'N'},
+			},
+			canvas: image.Rect(0, 0, 10, 1),
+			meta:   &widgetapi.Meta{},
+			wantPopup: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustListBorder(cvs, draw.BorderTitle("AN", draw.OverrunModeThreeDot, cell.FgColor(cell.ColorDefault)))
+				mustHighlight(cvs, "banana", 1)
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "backspace removes characters from the filter",
+			labels: fruits,
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+				&terminalapi.Keyboard{Key: 'e'},
+				&terminalapi.Keyboard{Key: 'r'},
+				&terminalapi.Keyboard{Key: keyboard.KeyBackspace2},
+			},
+			canvas: image.Rect(0, 0, 10, 1),
+			meta:   &widgetapi.Meta{},
+			wantPopup: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustListBorder(cvs, draw.BorderTitle("e", draw.OverrunModeThreeDot, cell.FgColor(cell.ColorDefault)))
+				mustHighlight(cvs, "apple", 1)
+				testdraw.MustText(cvs, "cherry", image.Point{1, 2})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "special keys don't change the filter",
+			labels: fruits,
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+				&terminalapi.Keyboard{Key: 'e'},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowLeft},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowRight},
+				&terminalapi.Keyboard{Key: keyboard.KeyHome},
+				&terminalapi.Keyboard{Key: keyboard.KeyEnd},
+				&terminalapi.Keyboard{Key: keyboard.KeyTab},
+				&terminalapi.Keyboard{Key: keyboard.KeyDelete},
+				&terminalapi.Keyboard{Key: keyboard.KeyF1},
+				&terminalapi.Keyboard{Key: keyboard.KeyF12},
+			},
+			canvas: image.Rect(0, 0, 10, 1),
+			meta:   &widgetapi.Meta{},
+			wantPopup: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustListBorder(cvs, draw.BorderTitle("e", draw.OverrunModeThreeDot, cell.FgColor(cell.ColorDefault)))
+				mustHighlight(cvs, "apple", 1)
+				testdraw.MustText(cvs, "cherry", image.Point{1, 2})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:     "enter selects the highlighted filtered option",
+			labels:   fruits,
+			callback: &callbackTracker{},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+				&terminalapi.Keyboard{Key: 'h'},
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+			},
+			canvas:       image.Rect(0, 0, 10, 1),
+			meta:         &widgetapi.Meta{},
+			wantCalls:    []selection{{2, "cherry"}},
+			wantSelected: 2,
+		},
+		{
+			desc:     "indicates when nothing matches the filter",
+			labels:   fruits,
+			callback: &callbackTracker{},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+				&terminalapi.Keyboard{Key: 'x'},
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+			},
+			canvas: image.Rect(0, 0, 12, 1),
+			meta:   &widgetapi.Meta{},
+			wantPopup: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustListBorder(cvs, draw.BorderTitle("x", draw.OverrunModeThreeDot, cell.FgColor(cell.ColorDefault)))
+				testdraw.MustText(cvs, "no matches", image.Point{1, 1}, draw.TextCellOpts(cell.FgColor(cell.ColorNumber(240))))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "scrolls the list to keep the highlighted option visible",
+			labels: fruits,
+			opts: []Option{
+				MaxVisible(2),
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+				&terminalapi.Keyboard{Key: keyboard.KeyPgDn},
+			},
+			canvas: image.Rect(0, 0, 10, 1),
+			meta:   &widgetapi.Meta{},
+			wantPopup: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustListBorder(cvs)
+				testdraw.MustText(cvs, "banana", image.Point{1, 1})
+				mustHighlight(cvs, "cherry", 2)
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "mouse wheel scrolls the list",
+			labels: fruits,
+			opts: []Option{
+				MaxVisible(2),
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+			},
+			popupEvents: []*terminalapi.Mouse{
+				{Button: mouse.ButtonWheelDown},
+				{Button: mouse.ButtonWheelDown},
+			},
+			canvas: image.Rect(0, 0, 10, 1),
+			meta:   &widgetapi.Meta{},
+			wantPopup: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustListBorder(cvs)
+				testdraw.MustText(cvs, "banana", image.Point{1, 1})
+				testdraw.MustText(cvs, "cherry", image.Point{1, 2})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:     "mouse click opens the list and click on an option selects it",
+			labels:   fruits,
+			callback: &callbackTracker{},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{0, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{0, 0}, Button: mouse.ButtonRelease},
+			},
+			popupEvents: []*terminalapi.Mouse{
+				{Position: image.Point{2, 3}, Button: mouse.ButtonLeft},
+			},
+			canvas:       image.Rect(0, 0, 10, 1),
+			meta:         &widgetapi.Meta{},
+			wantCalls:    []selection{{2, "cherry"}},
+			wantSelected: 2,
+		},
+		{
+			desc:     "mouse click on the border of the list is ignored",
+			labels:   fruits,
+			callback: &callbackTracker{},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+			},
+			popupEvents: []*terminalapi.Mouse{
+				{Position: image.Point{0, 2}, Button: mouse.ButtonLeft},
+			},
+			canvas: image.Rect(0, 0, 10, 1),
+			meta:   &widgetapi.Meta{},
+			wantPopup: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustListBorder(cvs)
+				mustHighlight(cvs, "apple", 1)
+				testdraw.MustText(cvs, "banana", image.Point{1, 2})
+				testdraw.MustText(cvs, "cherry", image.Point{1, 3})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:     "mouse click outside of the widget closes the list",
+			labels:   fruits,
+			callback: &callbackTracker{},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+				&terminalapi.Mouse{Position: image.Point{-1, -1}, Button: mouse.ButtonLeft},
+			},
+			canvas: image.Rect(0, 0, 10, 1),
+			meta:   &widgetapi.Meta{},
+		},
+		{
+			desc:   "SetSelected selects the option",
+			labels: fruits,
+			setter: func(d *Dropdown) error {
+				return d.SetSelected(2)
+			},
+			canvas:       image.Rect(0, 0, 10, 1),
+			meta:         &widgetapi.Meta{},
+			wantSelected: 2,
+		},
+		{
+			desc:   "SetSelected fails on index out of range",
+			labels: fruits,
+			setter: func(d *Dropdown) error {
+				return d.SetSelected(-1)
+			},
+			canvas:     image.Rect(0, 0, 10, 1),
+			meta:       &widgetapi.Meta{},
+			wantSetErr: true,
+		},
+		{
+			desc:     "forwards errors from the callback",
+			labels:   fruits,
+			callback: &callbackTracker{wantErr: true},
+			setter: func(d *Dropdown) error {
+				return d.Keyboard(&terminalapi.Keyboard{Key: keyboard.KeyEnter})
+			},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyEnter},
+			},
+			canvas:    image.Rect(0, 0, 10, 1),
+			meta:      &widgetapi.Meta{},
+			wantEvErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			opts := tc.opts
+			if tc.callback != nil {
+				opts = append(opts, OnSelect(tc.callback.callback))
+			}
+			d, err := New(tc.labels, opts...)
+			if (err != nil) != tc.wantNewErr {
+				t.Errorf("New => unexpected error: %v, wantNewErr: %v", err, tc.wantNewErr)
+			}
+			if err != nil {
+				return
+			}
+
+			{
+				// Draw once which initializes the mouse state machine with the current canvas area.
+				c, err := canvas.New(tc.canvas)
+				if err != nil {
+					t.Fatalf("canvas.New => unexpected error: %v", err)
+				}
+				if err := d.Draw(c, tc.meta); err != nil && !tc.wantDrawErr {
+					t.Fatalf("Draw => unexpected error: %v", err)
+				}
+			}
+
+			if tc.setter != nil {
+				err := tc.setter(d)
+				if (err != nil) != tc.wantSetErr {
+					t.Errorf("setter => unexpected error: %v, wantSetErr: %v", err, tc.wantSetErr)
+				}
+			}
+			for _, ev := range tc.events {
+				var err error
+				switch e := ev.(type) {
+				case *terminalapi.Mouse:
+					err = d.Mouse(e)
+				case *terminalapi.Keyboard:
+					err = d.Keyboard(e)
+				default:
+					t.Fatalf("unsupported event type: %T", ev)
+				}
+				if (err != nil) != tc.wantEvErr {
+					t.Errorf("event %v => unexpected error: %v, wantEvErr: %v", ev, err, tc.wantEvErr)
+				}
+			}
+
+			for _, ev := range tc.popupEvents {
+				// Draw the popup first, it records the area of the options.
+				if size := d.PopupSize(); !size.Eq(image.ZP) {
+					c, err := canvas.New(image.Rectangle{Max: size})
+					if err != nil {
+						t.Fatalf("canvas.New => unexpected error: %v", err)
+					}
+					if err := d.DrawPopup(c, tc.meta); err != nil {
+						t.Fatalf("DrawPopup => unexpected error: %v", err)
+					}
+				}
+				if err := d.PopupMouse(ev); (err != nil) != tc.wantEvErr {
+					t.Errorf("PopupMouse(%v) => unexpected error: %v, wantEvErr: %v", ev, err, tc.wantEvErr)
+				}
+			}
+
+			if got := d.Selected(); got != tc.wantSelected {
+				t.Errorf("Selected => %v, want %v", got, tc.wantSelected)
+			}
+			if got, want := d.IsOpen(), tc.wantPopup != nil; got != want {
+				t.Errorf("IsOpen => %v, want %v", got, want)
+			}
+			if tc.callback != nil {
+				if diff := pretty.Compare(tc.wantCalls, tc.callback.calls); diff != "" {
+					t.Errorf("CallbackFn => unexpected diff (-want, +got):\n%s", diff)
+				}
+			}
+
+			if tc.want != nil {
+				c, err := canvas.New(tc.canvas)
+				if err != nil {
+					t.Fatalf("canvas.New => unexpected error: %v", err)
+				}
+				err = d.Draw(c, tc.meta)
+				if (err != nil) != tc.wantDrawErr {
+					t.Errorf("Draw => unexpected error: %v, wantDrawErr: %v", err, tc.wantDrawErr)
+				}
+				if err != nil {
+					return
+				}
+
+				got, err := faketerm.New(c.Size())
+				if err != nil {
+					t.Fatalf("faketerm.New => unexpected error: %v", err)
+				}
+				if err := c.Apply(got); err != nil {
+					t.Fatalf("Apply => unexpected error: %v", err)
+				}
+				if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+					t.Errorf("Draw => %v", diff)
+				}
+			}
+
+			size := d.PopupSize()
+			if tc.wantPopup == nil {
+				if !size.Eq(image.ZP) {
+					t.Errorf("PopupSize => %v, want %v", size, image.ZP)
+				}
+				return
+			}
+			c, err := canvas.New(image.Rectangle{Max: size})
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+			if err := d.DrawPopup(c, tc.meta); err != nil {
+				t.Fatalf("DrawPopup => unexpected error: %v", err)
+			}
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			want := tc.wantPopup(size)
+			if diff := faketerm.Diff(want, got); diff != "" {
+				t.Errorf("DrawPopup => %v", diff)
+			}
+		})
+	}
+}
+
+func TestOptions(t *testing.T) {
+	tests := []struct {
+		desc   string
+		labels []string
+		want   widgetapi.Options
+	}{
+		{
+			desc:   "width is based on the longest label",
+			labels: fruits,
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{3, 1},
+				MaximumSize:  image.Point{8, 1},
+				WantKeyboard: widgetapi.KeyScopeFocused,
+				WantMouse:    widgetapi.MouseScopeGlobal,
+			},
+		},
+		{
+			desc:   "width supports full-width unicode characters",
+			labels: []string{"世界"},
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{3, 1},
+				MaximumSize:  image.Point{6, 1},
+				WantKeyboard: widgetapi.KeyScopeFocused,
+				WantMouse:    widgetapi.MouseScopeGlobal,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			d, err := New(tc.labels)
+			if err != nil {
+				t.Fatalf("New => unexpected error: %v", err)
+			}
+
+			got := d.Options()
+			if diff := pretty.Compare(tc.want, got); diff != "" {
+				t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary dropdowndemo shows the functionality of the dropdown widget.
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/termbox"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgets/dropdown"
+	"github.com/mum4k/termdash/widgets/text"
+)
+
+// newDropdown returns a dropdown that logs selections into the text widget.
+func newDropdown(name string, labels []string, log *text.Text, opts ...dropdown.Option) (*dropdown.Dropdown, error) {
+	opts = append(opts, dropdown.OnSelect(func(selected int, label string) error {
+		return log.Write(fmt.Sprintf("%s: selected %q\n", name, label))
+	}))
+	return dropdown.New(labels, opts...)
+}
+
+func main() {
+	t, err := termbox.New()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	log, err := text.New(text.RollContent())
+	if err != nil {
+		panic(err)
+	}
+
+	region, err := newDropdown("Region", []string{
+		"us-east1", "us-west1", "us-central1", "europe-west1", "europe-north1",
+		"asia-east1", "asia-south1", "australia-southeast1",
+	}, log, dropdown.MaxVisible(5))
+	if err != nil {
+		panic(err)
+	}
+	size, err := newDropdown("Size", []string{"small", "medium", "large"}, log,
+		dropdown.Selected(1),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	c, err := container.New(
+		t,
+		container.Border(linestyle.Light),
+		container.BorderTitle("PRESS Q TO QUIT"),
+		container.SplitHorizontal(
+			container.Top(
+				container.SplitVertical(
+					container.Left(
+						container.Border(linestyle.Light),
+						container.BorderTitle("Region"),
+						container.PlaceWidget(region),
+					),
+					container.Right(
+						container.Border(linestyle.Light),
+						container.BorderTitle("Size"),
+						container.PlaceWidget(size),
+					),
+				),
+			),
+			container.Bottom(
+				container.Border(linestyle.Light),
+				container.BorderTitle("Click or press enter on a focused dropdown, type to filter"),
+				container.PlaceWidget(log),
+			),
+			container.SplitPercent(20),
+		),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	quitter := func(k *terminalapi.Keyboard) {
+		if k.Key == 'q' || k.Key == 'Q' {
+			cancel()
+		}
+	}
+
+	if err := termdash.Run(ctx, t, c, termdash.KeyboardSubscriber(quitter), termdash.RedrawInterval(100*time.Millisecond)); err != nil {
+		panic(err)
+	}
+}
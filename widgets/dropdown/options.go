@@ -0,0 +1,130 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dropdown
+
+// options.go contains configurable options for Dropdown.
+
+import (
+	"fmt"
+
+	"github.com/mum4k/termdash/cell"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// options holds the provided options.
+type options struct {
+	selected       int
+	maxVisible     int
+	textColor      cell.Color
+	arrowColor     cell.Color
+	borderColor    cell.Color
+	highlightColor cell.Color
+	onSelect       CallbackFn
+}
+
+// validate validates the provided options.
+func (o *options) validate(count int) error {
+	if err := validateIndex(o.selected, count); err != nil {
+		return err
+	}
+	if min := 1; o.maxVisible < min {
+		return fmt.Errorf("invalid MaxVisible %d, must be %d <= MaxVisible", o.maxVisible, min)
+	}
+	return nil
+}
+
+// newOptions returns options with the default values set.
+func newOptions() *options {
+	return &options{
+		maxVisible:     DefaultMaxVisible,
+		textColor:      cell.ColorDefault,
+		arrowColor:     cell.ColorDefault,
+		borderColor:    cell.ColorNumber(240),
+		highlightColor: cell.ColorBlue,
+	}
+}
+
+// Selected sets the index of the initially selected option.
+// Defaults to the first option.
+func Selected(i int) Option {
+	return option(func(opts *options) {
+		opts.selected = i
+	})
+}
+
+// DefaultMaxVisible is the default for the MaxVisible option.
+const DefaultMaxVisible = 8
+
+// MaxVisible sets the maximum number of options displayed in the open list at
+// once, the list scrolls to display the others. Must be a positive integer.
+// Defaults to DefaultMaxVisible.
+func MaxVisible(rows int) Option {
+	return option(func(opts *options) {
+		opts.maxVisible = rows
+	})
+}
+
+// TextColor sets the color of the option labels.
+func TextColor(c cell.Color) Option {
+	return option(func(opts *options) {
+		opts.textColor = c
+	})
+}
+
+// ArrowColor sets the color of the arrow that indicates the widget can be
+// opened.
+func ArrowColor(c cell.Color) Option {
+	return option(func(opts *options) {
+		opts.arrowColor = c
+	})
+}
+
+// BorderColor sets the color of the border around the open list.
+// Defaults to color number 240.
+func BorderColor(c cell.Color) Option {
+	return option(func(opts *options) {
+		opts.borderColor = c
+	})
+}
+
+// HighlightColor sets the background color of the highlighted option in the
+// open list. Defaults to blue.
+func HighlightColor(c cell.Color) Option {
+	return option(func(opts *options) {
+		opts.highlightColor = c
+	})
+}
+
+// OnSelect sets a function that is called each time the user selects an
+// option from the list, even if it is the same option that was selected
+// before.
+func OnSelect(fn CallbackFn) Option {
+	return option(func(opts *options) {
+		opts.onSelect = fn
+	})
+}
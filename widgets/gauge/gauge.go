@@ -58,7 +58,8 @@ const (
 // Gauge displays the progress of an operation.
 //
 // Draws a rectangle, a progress bar with optional display of percentage and /
-// or text label.
+// or text label. The progress bar fills up either horizontally or vertically,
+// its color can change with the progress and it can display a target marker.
 //
 // Implements widgetapi.Widget. This object is thread-safe.
 type Gauge struct {
@@ -105,8 +106,8 @@ func (g *Gauge) Absolute(done, total int, opts ...Option) error {
 			"and total must be a non-zero positive number", done, total)
 	}
 
-	for _, opt := range opts {
-		opt.set(g.opts)
+	if err := g.setOptions(opts...); err != nil {
+		return err
 	}
 
 	g.pt = progressTypeAbsolute
@@ -115,6 +116,21 @@ func (g *Gauge) Absolute(done, total int, opts ...Option) error {
 	return nil
 }
 
+// setOptions validates the provided options and applies them if they are
+// valid.
+// Caller must hold g.mu.
+func (g *Gauge) setOptions(opts ...Option) error {
+	newOpts := *g.opts
+	for _, opt := range opts {
+		opt.set(&newOpts)
+	}
+	if err := newOpts.validate(); err != nil {
+		return err
+	}
+	g.opts = &newOpts
+	return nil
+}
+
 // Percent sets the current progress in percentage.
 // The provided value must be between 0 and 100.
 // Provided options override values set when New() was called.
@@ -126,8 +142,8 @@ func (g *Gauge) Percent(p int, opts ...Option) error {
 		return fmt.Errorf("invalid percentage, p(%d) must be 0 <= p <= 100", p)
 	}
 
-	for _, opt := range opts {
-		opt.set(g.opts)
+	if err := g.setOptions(opts...); err != nil {
+		return err
 	}
 
 	g.pt = progressTypePercent
@@ -136,12 +152,43 @@ func (g *Gauge) Percent(p int, opts ...Option) error {
 	return nil
 }
 
-// width determines the required width of the gauge drawn on the provided area
-// in order to represent the current progress.
-func (g *Gauge) width(ar image.Rectangle) int {
+// progressArea determines the area of the gauge filled up in order to
+// represent the current progress within the usable area.
+func (g *Gauge) progressArea(usable image.Rectangle) image.Rectangle {
+	if g.total == 0 {
+		// The progress wasn't set yet.
+		return image.ZR
+	}
 	mult := float32(g.current) / float32(g.total)
-	width := float32(ar.Dx()) * mult
-	return int(width)
+	if g.opts.vertical {
+		height := int(float32(usable.Dy()) * mult)
+		return image.Rect(usable.Min.X, usable.Max.Y-height, usable.Max.X, usable.Max.Y)
+	}
+	width := int(float32(usable.Dx()) * mult)
+	return image.Rect(usable.Min.X, usable.Min.Y, usable.Min.X+width, usable.Max.Y)
+}
+
+// percent returns the current progress as a percentage.
+func (g *Gauge) percent() float64 {
+	if g.total == 0 {
+		return 0
+	}
+	return float64(g.current) / float64(g.total) * 100
+}
+
+// color returns the color of the gauge for the current progress, i.e. the
+// color of the highest threshold the progress reached or the color set by the
+// Color option if it didn't reach any.
+func (g *Gauge) color() cell.Color {
+	color := g.opts.color
+	highest := -1
+	for p, c := range g.opts.thresholds {
+		if p > highest && g.percent() >= float64(p) {
+			color = c
+			highest = p
+		}
+	}
+	return color
 }
 
 // hasBorder determines of the gauge has a border.
@@ -175,21 +222,35 @@ func (g *Gauge) progressText() string {
 
 // gaugeText returns full text to be displayed within the gauge, i.e. the
 // progress text and the optional label.
-func (g *Gauge) gaugeText() string {
+// Also returns the cell options for each rune of the text that were set by
+// the markup of the label.
+func (g *Gauge) gaugeText() (string, [][]cell.Option) {
 	var b strings.Builder
-	b.WriteString(g.progressText())
-	if g.opts.textLabel != "" {
+	var runeOpts [][]cell.Option
+	write := func(s string, opts []cell.Option) {
+		b.WriteString(s)
+		for range s {
+			runeOpts = append(runeOpts, opts)
+		}
+	}
+
+	write(g.progressText(), nil)
+	if len(g.opts.textLabelChunks) > 0 {
 		if b.Len() > 0 {
-			b.WriteString(" ")
+			write(" ", nil)
+		}
+		write("(", nil)
+		for _, ch := range g.opts.textLabelChunks {
+			write(ch.Text, ch.Opts)
 		}
-		b.WriteString(fmt.Sprintf("(%s)", g.opts.textLabel))
+		write(")", nil)
 	}
-	return b.String()
+	return b.String(), runeOpts
 }
 
 // drawText draws the text enumerating the progress and the text label.
 func (g *Gauge) drawText(cvs *canvas.Canvas, progress image.Rectangle) error {
-	text := g.gaugeText()
+	text, runeOpts := g.gaugeText()
 	if text == "" {
 		return nil
 	}
@@ -205,7 +266,9 @@ func (g *Gauge) drawText(cvs *canvas.Canvas, progress image.Rectangle) error {
 		return err
 	}
 
-	for _, r := range trimmed {
+	// The trimmed text is a prefix of the original text, except for the last
+	// rune that can be replaced by the overrun character.
+	for i, r := range []rune(trimmed) {
 		if !cur.In(ar) {
 			break
 		}
@@ -224,7 +287,7 @@ func (g *Gauge) drawText(cvs *canvas.Canvas, progress image.Rectangle) error {
 			)
 			if err := draw.Rectangle(cvs, fixup,
 				draw.RectChar(g.opts.gaugeChar),
-				draw.RectCellOpts(cell.BgColor(g.opts.color)),
+				draw.RectCellOpts(cell.BgColor(g.color())),
 			); err != nil {
 				return err
 			}
@@ -237,6 +300,7 @@ func (g *Gauge) drawText(cvs *canvas.Canvas, progress image.Rectangle) error {
 		} else {
 			cellOpts = append(cellOpts, cell.FgColor(g.opts.emptyTextColor))
 		}
+		cellOpts = append(cellOpts, runeOpts[i]...)
 
 		cells, err := cvs.SetCell(cur, r, cellOpts...)
 		if err != nil {
@@ -274,23 +338,59 @@ func (g *Gauge) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
 	}
 
 	usable := g.usable(cvs)
-	progress := image.Rect(
-		usable.Min.X,
-		usable.Min.Y,
-		usable.Min.X+g.width(usable),
-		usable.Max.Y,
-	)
-	if progress.Dx() > 0 {
+	progress := g.progressArea(usable)
+	if !progress.Empty() {
 		if err := draw.Rectangle(cvs, progress,
 			draw.RectChar(g.opts.gaugeChar),
-			draw.RectCellOpts(cell.BgColor(g.opts.color)),
+			draw.RectCellOpts(cell.BgColor(g.color())),
 		); err != nil {
 			return err
 		}
 	}
+	if err := g.drawTarget(cvs, usable); err != nil {
+		return err
+	}
 	return g.drawText(cvs, progress)
 }
 
+// drawTarget draws the target marker line across the gauge.
+func (g *Gauge) drawTarget(cvs *canvas.Canvas, usable image.Rectangle) error {
+	if !g.opts.hasTarget {
+		return nil
+	}
+
+	var (
+		line image.Rectangle
+		r    rune
+	)
+	if g.opts.vertical {
+		y := usable.Max.Y - 1 - usable.Dy()*g.opts.target/100
+		if y < usable.Min.Y {
+			y = usable.Min.Y
+		}
+		line = image.Rect(usable.Min.X, y, usable.Max.X, y+1)
+		r = '─'
+	} else {
+		x := usable.Min.X + usable.Dx()*g.opts.target/100
+		if x >= usable.Max.X {
+			x = usable.Max.X - 1
+		}
+		line = image.Rect(x, usable.Min.Y, x+1, usable.Max.Y)
+		r = '│'
+	}
+
+	for y := line.Min.Y; y < line.Max.Y; y++ {
+		for x := line.Min.X; x < line.Max.X; x++ {
+			// Only the provided cell options are set, so the marker keeps
+			// the background color of the gauge it crosses.
+			if _, err := cvs.SetCell(image.Point{x, y}, r, g.opts.targetCellOpts...); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // Keyboard input isn't supported on the Gauge widget.
 func (g *Gauge) Keyboard(k *terminalapi.Keyboard) error {
 	return errors.New("the Gauge widget doesn't support keyboard events")
@@ -303,6 +403,9 @@ func (g *Gauge) Mouse(m *terminalapi.Mouse) error {
 
 // maxSize determines the maximum size of the canvas.
 func (g *Gauge) maxSize() image.Point {
+	if g.opts.vertical {
+		return image.Point{0, 0} // Unlimited.
+	}
 	maxHeight := g.opts.height
 	if g.hasBorder() {
 		// Add the required space for the border.
@@ -421,6 +421,66 @@ func TestGauge(t *testing.T) {
 				return ft
 			},
 		},
+		{
+			desc: "fails on invalid text label markup",
+			opts: []Option{
+				TextLabel("[purple]label"),
+				TextLabelMarkup(),
+			},
+			canvas: image.Rect(0, 0, 10, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantErr: true,
+		},
+		{
+			desc: "update fails on invalid text label markup",
+			opts: []Option{
+				TextLabelMarkup(),
+			},
+			percent: &percentCall{
+				p: 100,
+				opts: []Option{
+					TextLabel("[/]label"),
+				},
+			},
+			canvas: image.Rect(0, 0, 10, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc: "gauge with text label markup",
+			opts: []Option{
+				Char('o'),
+				HideTextProgress(),
+				TextLabel("[fg=red,bold]la[/]bel"),
+				TextLabelMarkup(),
+			},
+			percent: &percentCall{p: 100},
+			canvas:  image.Rect(0, 0, 10, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(0, 0, 10, 3),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(cell.ColorGreen)),
+				)
+				testdraw.MustText(c, "(", image.Point{1, 1},
+					draw.TextCellOpts(cell.FgColor(cell.ColorBlack)),
+				)
+				testdraw.MustText(c, "la", image.Point{2, 1},
+					draw.TextCellOpts(cell.FgColor(cell.ColorRed), cell.Bold()),
+				)
+				testdraw.MustText(c, "bel)", image.Point{4, 1},
+					draw.TextCellOpts(cell.FgColor(cell.ColorBlack)),
+				)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
 		{
 			desc: "gauge with text label, full-width runes",
 			opts: []Option{
@@ -742,6 +802,274 @@ func TestGauge(t *testing.T) {
 				return ft
 			},
 		},
+		{
+			desc: "fails on threshold below zero",
+			opts: []Option{
+				Threshold(-1, cell.ColorRed),
+			},
+			canvas: image.Rect(0, 0, 10, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantErr: true,
+		},
+		{
+			desc: "fails on threshold above hundred",
+			opts: []Option{
+				Threshold(101, cell.ColorRed),
+			},
+			canvas: image.Rect(0, 0, 10, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantErr: true,
+		},
+		{
+			desc: "fails on target marker above hundred",
+			opts: []Option{
+				TargetMarker(101),
+			},
+			canvas: image.Rect(0, 0, 10, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantErr: true,
+		},
+		{
+			desc: "update fails on invalid threshold",
+			percent: &percentCall{
+				p: 35,
+				opts: []Option{
+					Threshold(-1, cell.ColorRed),
+				},
+			},
+			canvas: image.Rect(0, 0, 10, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc: "vertical gauge fills from the bottom",
+			opts: []Option{
+				Char('o'),
+				Vertical(),
+				HideTextProgress(),
+			},
+			percent: &percentCall{p: 50},
+			canvas:  image.Rect(0, 0, 3, 10),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(0, 5, 3, 10),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(cell.ColorGreen)),
+				)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "vertical gauge with border and progress text",
+			opts: []Option{
+				Char('o'),
+				Vertical(),
+				Border(linestyle.Light),
+			},
+			percent: &percentCall{p: 25},
+			canvas:  image.Rect(0, 0, 5, 10),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustBorder(c, image.Rect(0, 0, 5, 10))
+				testdraw.MustRectangle(c, image.Rect(1, 7, 4, 9),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(cell.ColorGreen)),
+				)
+				testdraw.MustText(c, "25%", image.Point{1, 4})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "uses the default color below the lowest threshold",
+			opts: []Option{
+				Char('o'),
+				HideTextProgress(),
+				Threshold(70, cell.ColorYellow),
+				Threshold(90, cell.ColorRed),
+			},
+			percent: &percentCall{p: 60},
+			canvas:  image.Rect(0, 0, 10, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(0, 0, 6, 3),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(cell.ColorGreen)),
+				)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "uses the threshold color when progress equals the threshold",
+			opts: []Option{
+				Char('o'),
+				HideTextProgress(),
+				Threshold(70, cell.ColorYellow),
+				Threshold(90, cell.ColorRed),
+			},
+			percent: &percentCall{p: 70},
+			canvas:  image.Rect(0, 0, 10, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(0, 0, 7, 3),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(cell.ColorYellow)),
+				)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "uses the color of the highest reached threshold",
+			opts: []Option{
+				Char('o'),
+				HideTextProgress(),
+				Threshold(90, cell.ColorRed),
+				Threshold(70, cell.ColorYellow),
+			},
+			absolute: &absoluteCall{done: 19, total: 20},
+			canvas:   image.Rect(0, 0, 10, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(0, 0, 9, 3),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(cell.ColorRed)),
+				)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "thresholds can be cleared",
+			opts: []Option{
+				Char('o'),
+				HideTextProgress(),
+				Threshold(70, cell.ColorYellow),
+			},
+			percent: &percentCall{
+				p:    80,
+				opts: []Option{ClearThresholds()},
+			},
+			canvas: image.Rect(0, 0, 10, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(0, 0, 8, 3),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(cell.ColorGreen)),
+				)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "draws target marker on horizontal gauge",
+			opts: []Option{
+				Char('o'),
+				HideTextProgress(),
+				TargetMarker(50, cell.FgColor(cell.ColorRed)),
+			},
+			percent: &percentCall{p: 70},
+			canvas:  image.Rect(0, 0, 10, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(0, 0, 7, 3),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(cell.ColorGreen)),
+				)
+				for y := 0; y < 3; y++ {
+					testcanvas.MustSetCell(c, image.Point{5, y}, '│',
+						cell.FgColor(cell.ColorRed),
+						cell.BgColor(cell.ColorGreen),
+					)
+				}
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "draws target marker at hundred percent on the last cell",
+			opts: []Option{
+				Char('o'),
+				HideTextProgress(),
+				TargetMarker(100),
+			},
+			percent: &percentCall{p: 0},
+			canvas:  image.Rect(0, 0, 10, 2),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testcanvas.MustSetCell(c, image.Point{9, 0}, '│')
+				testcanvas.MustSetCell(c, image.Point{9, 1}, '│')
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "draws target marker on vertical gauge",
+			opts: []Option{
+				Char('o'),
+				Vertical(),
+				HideTextProgress(),
+				TargetMarker(80),
+			},
+			percent: &percentCall{p: 50},
+			canvas:  image.Rect(0, 0, 3, 10),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustRectangle(c, image.Rect(0, 5, 3, 10),
+					draw.RectChar('o'),
+					draw.RectCellOpts(cell.BgColor(cell.ColorGreen)),
+				)
+				for x := 0; x < 3; x++ {
+					testcanvas.MustSetCell(c, image.Point{x, 1}, '─')
+				}
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "target marker can be hidden",
+			opts: []Option{
+				Char('o'),
+				HideTextProgress(),
+				TargetMarker(50),
+			},
+			percent: &percentCall{
+				p:    0,
+				opts: []Option{HideTargetMarker()},
+			},
+			canvas: image.Rect(0, 0, 10, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -804,6 +1132,49 @@ func TestGauge(t *testing.T) {
 	}
 }
 
+func TestUpdateKeepsOptionsOnError(t *testing.T) {
+	g, err := New(Char('o'))
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := g.Percent(35); err != nil {
+		t.Fatalf("Percent => unexpected error: %v", err)
+	}
+	if err := g.Percent(50, Char('x'), Height(-1)); err == nil {
+		t.Errorf("Percent => got nil error, want an error on negative height")
+	}
+	if err := g.Absolute(5, 10, Char('x'), TextLabel("[/]label"), TextLabelMarkup()); err == nil {
+		t.Errorf("Absolute => got nil error, want an error on invalid text label markup")
+	}
+
+	c, err := canvas.New(image.Rect(0, 0, 10, 3))
+	if err != nil {
+		t.Fatalf("canvas.New => unexpected error: %v", err)
+	}
+	if err := g.Draw(c, &widgetapi.Meta{}); err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+	got, err := faketerm.New(c.Size())
+	if err != nil {
+		t.Fatalf("faketerm.New => unexpected error: %v", err)
+	}
+	if err := c.Apply(got); err != nil {
+		t.Fatalf("Apply => unexpected error: %v", err)
+	}
+
+	want := faketerm.MustNew(c.Size())
+	wantCvs := testcanvas.MustNew(want.Area())
+	testdraw.MustRectangle(wantCvs, image.Rect(0, 0, 3, 3),
+		draw.RectChar('o'),
+		draw.RectCellOpts(cell.BgColor(cell.ColorGreen)),
+	)
+	testdraw.MustText(wantCvs, "35%", image.Point{3, 1})
+	testcanvas.MustApply(wantCvs, want)
+	if diff := faketerm.Diff(want, got); diff != "" {
+		t.Errorf("Draw => %v", diff)
+	}
+}
+
 func TestOptions(t *testing.T) {
 	tests := []struct {
 		desc string
@@ -844,6 +1215,19 @@ func TestOptions(t *testing.T) {
 				WantMouse:    widgetapi.MouseScopeNone,
 			},
 		},
+		{
+			desc: "maximum size is unlimited for vertical gauge",
+			opts: []Option{
+				Vertical(),
+				Height(2),
+			},
+			want: widgetapi.Options{
+				MaximumSize:  image.Point{0, 0}, // Unlimited.
+				MinimumSize:  image.Point{1, 1},
+				WantKeyboard: widgetapi.KeyScopeNone,
+				WantMouse:    widgetapi.MouseScopeNone,
+			},
+		},
 	}
 
 	for _, tc := range tests {
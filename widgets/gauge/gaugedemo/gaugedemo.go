@@ -130,6 +130,20 @@ func main() {
 	}
 	go playGauge(ctx, withLabel, 3, 500*time.Millisecond, playTypePercent)
 
+	tank, err := gauge.New(
+		gauge.Vertical(),
+		gauge.Border(linestyle.Light),
+		gauge.BorderTitle("Tank level"),
+		gauge.Color(cell.ColorGreen),
+		gauge.Threshold(70, cell.ColorYellow),
+		gauge.Threshold(90, cell.ColorRed),
+		gauge.TargetMarker(80, cell.FgColor(cell.ColorWhite)),
+	)
+	if err != nil {
+		panic(err)
+	}
+	go playGauge(ctx, tank, 4, 250*time.Millisecond, playTypePercent)
+
 	c, err := container.New(
 		t,
 		container.SplitVertical(
@@ -145,7 +159,9 @@ func main() {
 							container.Left(
 								container.PlaceWidget(absolute),
 							),
-							container.Right(),
+							container.Right(
+								container.PlaceWidget(tank),
+							),
 						),
 					),
 				),
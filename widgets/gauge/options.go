@@ -22,6 +22,7 @@ import (
 	"github.com/mum4k/termdash/align"
 	"github.com/mum4k/termdash/cell"
 	"github.com/mum4k/termdash/internal/draw"
+	"github.com/mum4k/termdash/internal/markup"
 	"github.com/mum4k/termdash/linestyle"
 )
 
@@ -37,6 +38,7 @@ type options struct {
 	hideTextProgress bool
 	height           int
 	textLabel        string
+	textLabelMarkup  bool
 	hTextAlign       align.Horizontal
 	vTextAlign       align.Vertical
 	color            cell.Color
@@ -47,6 +49,19 @@ type options struct {
 	borderCellOpts    []cell.Option
 	borderTitle       string
 	borderTitleHAlign align.Horizontal
+	vertical          bool
+	// thresholds maps percentages to the colors of the gauge at or above
+	// them.
+	thresholds map[int]cell.Color
+	// target is the percentage at which the target marker is drawn, only
+	// valid if hasTarget is true.
+	target         int
+	hasTarget      bool
+	targetCellOpts []cell.Option
+
+	// textLabelChunks is the text label split into chunks with their cell
+	// options, populated by validate.
+	textLabelChunks []*markup.Chunk
 }
 
 // newOptions returns options with the default values set.
@@ -66,6 +81,23 @@ func (o *options) validate() error {
 	if got, min := o.height, 0; got < min {
 		return fmt.Errorf("invalid Height %d, must be %d <= Height", got, min)
 	}
+	for p := range o.thresholds {
+		if min, max := 0, 100; p < min || p > max {
+			return fmt.Errorf("invalid Threshold %d, must be in range %d <= percent <= %d", p, min, max)
+		}
+	}
+	if min, max := 0, 100; o.hasTarget && (o.target < min || o.target > max) {
+		return fmt.Errorf("invalid TargetMarker %d, must be in range %d <= percent <= %d", o.target, min, max)
+	}
+
+	o.textLabelChunks = markup.Plain(o.textLabel)
+	if o.textLabelMarkup {
+		chunks, err := markup.Parse(o.textLabel)
+		if err != nil {
+			return fmt.Errorf("invalid TextLabel: %v", err)
+		}
+		o.textLabelChunks = chunks
+	}
 	return nil
 }
 
@@ -108,6 +140,7 @@ func HideTextProgress() Option {
 
 // Height sets the height of the drawn Gauge. Must be a positive number.
 // Defaults to zero which means the height of the container.
+// Only applies to gauges with the horizontal orientation.
 func Height(height int) Option {
 	return option(func(opts *options) {
 		opts.height = height
@@ -123,10 +156,25 @@ func TextLabel(text string) Option {
 	})
 }
 
+// TextLabelMarkup instructs the Gauge to interpret the text provided via the
+// TextLabel option as markup that sets the cell options of parts of the text,
+// e.g.:
+//   "[bold]disk[/] usage"
+// The cell options set by the markup are applied on top of the
+// FilledTextColor and EmptyTextColor. A literal '[' must be written as "[[".
+// See the documentation of the internal/markup package for the full syntax.
+func TextLabelMarkup() Option {
+	return option(func(opts *options) {
+		opts.textLabelMarkup = true
+	})
+}
+
 // DefaultColor is the default value for the Color option.
 const DefaultColor = cell.ColorGreen
 
 // Color sets the color of the gauge.
+// If thresholds are provided, this is the color used below the lowest
+// threshold.
 func Color(c cell.Color) Option {
 	return option(func(opts *options) {
 		opts.color = c
@@ -201,3 +249,64 @@ func BorderTitleAlign(h align.Horizontal) Option {
 		opts.borderTitleHAlign = h
 	})
 }
+
+// Horizontal configures the gauge to fill up from left to right.
+// This is the default orientation.
+func Horizontal() Option {
+	return option(func(opts *options) {
+		opts.vertical = false
+	})
+}
+
+// Vertical configures the gauge to fill up from the bottom to the top.
+func Vertical() Option {
+	return option(func(opts *options) {
+		opts.vertical = true
+	})
+}
+
+// Threshold sets the color of the gauge when the progress reaches or exceeds
+// the specified percentage. Can be provided multiple times to define multiple
+// color bands, e.g. to draw the gauge green below 70%, yellow below 90% and
+// red otherwise:
+//   gauge.Color(cell.ColorGreen),
+//   gauge.Threshold(70, cell.ColorYellow),
+//   gauge.Threshold(90, cell.ColorRed),
+// Providing the same percentage again replaces its color. The percentage must
+// be in range 0 <= percent <= 100.
+func Threshold(percent int, c cell.Color) Option {
+	return option(func(opts *options) {
+		thresholds := map[int]cell.Color{}
+		for p, c := range opts.thresholds {
+			thresholds[p] = c
+		}
+		thresholds[percent] = c
+		opts.thresholds = thresholds
+	})
+}
+
+// ClearThresholds removes all the thresholds set by previous options, the
+// gauge is then always drawn with the color set by the Color option.
+func ClearThresholds() Option {
+	return option(func(opts *options) {
+		opts.thresholds = nil
+	})
+}
+
+// TargetMarker draws a marker line across the gauge at the specified
+// percentage, e.g. to show a target level or a budget. The percentage must be
+// in range 0 <= percent <= 100. The cell options are applied to the marker.
+func TargetMarker(percent int, cOpts ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.target = percent
+		opts.hasTarget = true
+		opts.targetCellOpts = cOpts
+	})
+}
+
+// HideTargetMarker disables the display of the target marker.
+func HideTargetMarker() Option {
+	return option(func(opts *options) {
+		opts.hasTarget = false
+	})
+}
@@ -0,0 +1,506 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package heatmap implements a widget that displays a matrix of values as a
+// grid of colored cells.
+package heatmap
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"strconv"
+	"sync"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/area"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/draw"
+	"github.com/mum4k/termdash/internal/numbers"
+	"github.com/mum4k/termdash/internal/runewidth"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// HeatMap displays a matrix of values as a grid of colored cells.
+//
+// Each value is mapped onto a color of a gradient, the rows and the columns
+// of the matrix can have labels. A legend on the right side shows the colors
+// of the gradient and the range of the values.
+//
+// Clicking on a cell displays its value on the status line under the heat
+// map. The terminal only reports the mouse position together with button
+// events, so the value isn't updated by merely moving the mouse.
+//
+// Implements widgetapi.Widget. This object is thread-safe.
+type HeatMap struct {
+	// values are the values provided on a call to Values(), values[row][col].
+	values [][]float64
+	// xLabels are the labels of the columns.
+	xLabels []string
+	// yLabels are the labels of the rows.
+	yLabels []string
+
+	// selected is the column (X) and row (Y) of the cell the user clicked on
+	// or nil if no cell is selected.
+	selected *image.Point
+
+	// lastLayout is the layout of the heat map as of the last call to Draw.
+	// Used to map mouse events onto the cells.
+	lastLayout *layout
+
+	// mu protects the HeatMap.
+	mu sync.Mutex
+
+	// opts are the provided options.
+	opts *options
+}
+
+// New returns a new HeatMap.
+func New(opts ...Option) (*HeatMap, error) {
+	opt := newOptions()
+	for _, o := range opts {
+		o.set(opt)
+	}
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	return &HeatMap{
+		opts: opt,
+	}, nil
+}
+
+// Values sets the values to be displayed by the HeatMap.
+//
+// The values are a matrix indexed as values[row][col], all the rows must have
+// the same number of columns. The first row is displayed at the top. NaN
+// values are allowed and represent cells without a value which aren't
+// colored.
+// The labels of the columns (xLabels) and rows (yLabels) are optional, if
+// provided their count must match the number of columns and rows
+// respectively.
+// Provided options override values set when New() was called.
+func (hm *HeatMap) Values(xLabels, yLabels []string, values [][]float64, opts ...Option) error {
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+
+	if err := validateValues(xLabels, yLabels, values); err != nil {
+		return err
+	}
+	newOpts := *hm.opts
+	for _, opt := range opts {
+		opt.set(&newOpts)
+	}
+	if err := newOpts.validate(); err != nil {
+		return err
+	}
+
+	// Copy to avoid external modifications. See #174.
+	v := make([][]float64, len(values))
+	for i, row := range values {
+		v[i] = make([]float64, len(row))
+		copy(v[i], row)
+	}
+	hm.values = v
+	hm.xLabels = append([]string(nil), xLabels...)
+	hm.yLabels = append([]string(nil), yLabels...)
+	hm.opts = &newOpts
+	// The layout is stale until the next draw.
+	hm.lastLayout = nil
+	if s := hm.selected; s != nil && (s.Y >= hm.rows() || s.X >= hm.cols()) {
+		hm.selected = nil
+	}
+	return nil
+}
+
+// rows returns the number of rows in the matrix.
+func (hm *HeatMap) rows() int {
+	return len(hm.values)
+}
+
+// cols returns the number of columns in the matrix.
+func (hm *HeatMap) cols() int {
+	if len(hm.values) == 0 {
+		return 0
+	}
+	return len(hm.values[0])
+}
+
+// scale returns the range of values that is mapped onto the gradient.
+func (hm *HeatMap) scale() (min, max float64) {
+	if vr := hm.opts.valueRange; vr != nil {
+		return vr.min, vr.max
+	}
+
+	var all []float64
+	for _, row := range hm.values {
+		all = append(all, row...)
+	}
+	min, max = numbers.MinMax(all)
+	if math.IsNaN(min) {
+		return 0, 0 // All the values are NaN.
+	}
+	return min, max
+}
+
+// colorIdx returns the index of the gradient color for the value when the
+// gradient has n colors and covers the range of values from min to max.
+func colorIdx(v, min, max float64, n int) int {
+	if max <= min {
+		return n - 1
+	}
+	idx := int((v - min) / (max - min) * float64(n))
+	switch {
+	case idx < 0:
+		return 0
+	case idx >= n:
+		return n - 1
+	default:
+		return idx
+	}
+}
+
+// color returns the color that represents the value.
+func (hm *HeatMap) color(v, min, max float64) cell.Color {
+	return hm.opts.gradient[colorIdx(v, min, max, len(hm.opts.gradient))]
+}
+
+// layout are the areas of the canvas that contain individual parts of the
+// heat map.
+type layout struct {
+	// grid is the area that contains the colored cells.
+	grid image.Rectangle
+	// cellSize is the size of a single colored cell.
+	cellSize image.Point
+	// legend is the area that contains the legend or image.ZR if the legend
+	// is hidden.
+	legend image.Rectangle
+	// status is the line under the heat map that displays the value of the
+	// selected cell.
+	status image.Rectangle
+}
+
+// legendBarWidth is the width of the color bar in the legend.
+const legendBarWidth = 2
+
+// yLabelsWidth returns the width of the area needed for the labels on the Y
+// axis including the gap between the labels and the grid.
+func (hm *HeatMap) yLabelsWidth() int {
+	var w int
+	for _, l := range hm.yLabels {
+		if lw := runewidth.StringWidth(l); lw > w {
+			w = lw
+		}
+	}
+	if w > 0 {
+		w++ // Gap between the labels and the grid.
+	}
+	return w
+}
+
+// xLabelsHeight returns the height of the area needed for the labels on the X
+// axis.
+func (hm *HeatMap) xLabelsHeight() int {
+	for _, l := range hm.xLabels {
+		if l != "" {
+			return 1
+		}
+	}
+	return 0
+}
+
+// legendWidth returns the width of the area needed for the legend including
+// the gap between the grid and the legend.
+func (hm *HeatMap) legendWidth() int {
+	if hm.opts.hideLegend {
+		return 0
+	}
+	min, max := hm.scale()
+	labelWidth := runewidth.StringWidth(hm.opts.valueFormatter(max))
+	if w := runewidth.StringWidth(hm.opts.valueFormatter(min)); w > labelWidth {
+		labelWidth = w
+	}
+	// Gap, the color bar, gap and the labels.
+	return 1 + legendBarWidth + 1 + labelWidth
+}
+
+// minSize determines the minimum required size of the canvas.
+func (hm *HeatMap) minSize() image.Point {
+	if hm.rows() == 0 {
+		return image.Point{1, 1}
+	}
+	return image.Point{
+		hm.yLabelsWidth() + hm.cols() + hm.legendWidth(),
+		hm.rows() + hm.xLabelsHeight() + 1, // One line for the status.
+	}
+}
+
+// newLayout determines the layout of the heat map on the canvas.
+// Returns nil if the canvas is too small.
+func (hm *HeatMap) newLayout(cvsAr image.Rectangle) *layout {
+	yLabelsWidth := hm.yLabelsWidth()
+	legendWidth := hm.legendWidth()
+	gridWidth := cvsAr.Dx() - yLabelsWidth - legendWidth
+	gridHeight := cvsAr.Dy() - hm.xLabelsHeight() - 1
+
+	cellSize := image.Point{gridWidth / hm.cols(), gridHeight / hm.rows()}
+	if cellSize.X < 1 || cellSize.Y < 1 {
+		return nil
+	}
+
+	gridMin := image.Point{cvsAr.Min.X + yLabelsWidth, cvsAr.Min.Y}
+	grid := image.Rectangle{
+		Min: gridMin,
+		Max: gridMin.Add(image.Point{cellSize.X * hm.cols(), cellSize.Y * hm.rows()}),
+	}
+	var legend image.Rectangle
+	if legendWidth > 0 {
+		legend = image.Rect(grid.Max.X, grid.Min.Y, grid.Max.X+legendWidth, grid.Max.Y)
+	}
+	return &layout{
+		grid:     grid,
+		cellSize: cellSize,
+		legend:   legend,
+		status:   image.Rect(cvsAr.Min.X, cvsAr.Max.Y-1, cvsAr.Max.X, cvsAr.Max.Y),
+	}
+}
+
+// cellArea returns the area of the cell in the specified column and row.
+func (l *layout) cellArea(col, row int) image.Rectangle {
+	min := l.grid.Min.Add(image.Point{col * l.cellSize.X, row * l.cellSize.Y})
+	return image.Rectangle{Min: min, Max: min.Add(l.cellSize)}
+}
+
+// Draw draws the HeatMap widget onto the canvas.
+// Implements widgetapi.Widget.Draw.
+func (hm *HeatMap) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+
+	hm.lastLayout = nil
+	if hm.rows() == 0 {
+		return nil
+	}
+
+	needAr, err := area.FromSize(hm.minSize())
+	if err != nil {
+		return err
+	}
+	l := hm.newLayout(cvs.Area())
+	if !needAr.In(cvs.Area()) || l == nil {
+		return draw.ResizeNeeded(cvs)
+	}
+	hm.lastLayout = l
+
+	if err := hm.drawCells(cvs, l); err != nil {
+		return err
+	}
+	if err := hm.drawYLabels(cvs, l); err != nil {
+		return err
+	}
+	if err := hm.drawXLabels(cvs, l); err != nil {
+		return err
+	}
+	if !l.legend.Eq(image.ZR) {
+		if err := hm.drawLegend(cvs, l); err != nil {
+			return err
+		}
+	}
+	return hm.drawStatus(cvs, l)
+}
+
+// drawCells draws the colored cells.
+func (hm *HeatMap) drawCells(cvs *canvas.Canvas, l *layout) error {
+	min, max := hm.scale()
+	for row, values := range hm.values {
+		for col, v := range values {
+			if math.IsNaN(v) {
+				continue
+			}
+			if err := draw.Rectangle(cvs, l.cellArea(col, row),
+				draw.RectCellOpts(cell.BgColor(hm.color(v, min, max))),
+				draw.RectChar(' '),
+			); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// drawYLabels draws the labels of the rows, each in the middle of its row.
+func (hm *HeatMap) drawYLabels(cvs *canvas.Canvas, l *layout) error {
+	for row, label := range hm.yLabels {
+		if label == "" {
+			continue
+		}
+		ar := l.cellArea(0, row)
+		start := image.Point{cvs.Area().Min.X, ar.Min.Y + ar.Dy()/2}
+		if err := draw.Text(cvs, label, start,
+			draw.TextMaxX(l.grid.Min.X-1),
+			draw.TextOverrunMode(draw.OverrunModeThreeDot),
+			draw.TextCellOpts(hm.opts.yLabelCellOpts...),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// drawXLabels draws the labels of the columns under the grid. Each label
+// starts at its column, labels that would overlap the previous label are
+// skipped.
+func (hm *HeatMap) drawXLabels(cvs *canvas.Canvas, l *layout) error {
+	nextFree := l.grid.Min.X
+	for col, label := range hm.xLabels {
+		if label == "" {
+			continue
+		}
+		start := image.Point{l.cellArea(col, 0).Min.X, l.grid.Max.Y}
+		if start.X < nextFree {
+			continue
+		}
+		if err := draw.Text(cvs, label, start,
+			draw.TextOverrunMode(draw.OverrunModeTrim),
+			draw.TextCellOpts(hm.opts.xLabelCellOpts...),
+		); err != nil {
+			return err
+		}
+		nextFree = start.X + runewidth.StringWidth(label) + 1
+	}
+	return nil
+}
+
+// drawLegend draws the color bar with the range of values.
+func (hm *HeatMap) drawLegend(cvs *canvas.Canvas, l *layout) error {
+	min, max := hm.scale()
+	barX := l.legend.Min.X + 1
+	height := l.legend.Dy()
+	for i := 0; i < height; i++ {
+		// The value in the middle of the range covered by this line of the
+		// bar, the largest value is at the top.
+		v := max - (float64(i)+0.5)/float64(height)*(max-min)
+		bar := image.Rect(barX, l.legend.Min.Y+i, barX+legendBarWidth, l.legend.Min.Y+i+1)
+		if err := draw.Rectangle(cvs, bar,
+			draw.RectCellOpts(cell.BgColor(hm.color(v, min, max))),
+			draw.RectChar(' '),
+		); err != nil {
+			return err
+		}
+	}
+
+	labelX := barX + legendBarWidth + 1
+	if err := draw.Text(cvs, hm.opts.valueFormatter(max), image.Point{labelX, l.legend.Min.Y}); err != nil {
+		return err
+	}
+	if height > 1 {
+		if err := draw.Text(cvs, hm.opts.valueFormatter(min), image.Point{labelX, l.legend.Max.Y - 1}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// label returns the label at the index or the index if there is no label.
+func label(labels []string, idx int) string {
+	if idx < len(labels) && labels[idx] != "" {
+		return labels[idx]
+	}
+	return strconv.Itoa(idx)
+}
+
+// drawStatus draws the value of the selected cell on the status line.
+func (hm *HeatMap) drawStatus(cvs *canvas.Canvas, l *layout) error {
+	s := hm.selected
+	if s == nil {
+		return nil
+	}
+
+	value := "no value"
+	if v := hm.values[s.Y][s.X]; !math.IsNaN(v) {
+		value = hm.opts.valueFormatter(v)
+	}
+	text := fmt.Sprintf("%s / %s: %s", label(hm.yLabels, s.Y), label(hm.xLabels, s.X), value)
+	return draw.Text(cvs, text, l.status.Min,
+		draw.TextMaxX(l.status.Max.X),
+		draw.TextOverrunMode(draw.OverrunModeThreeDot),
+	)
+}
+
+// Keyboard input isn't supported on the HeatMap widget.
+func (*HeatMap) Keyboard(k *terminalapi.Keyboard) error {
+	return errors.New("the HeatMap widget doesn't support keyboard events")
+}
+
+// Mouse selects the cell the user clicked on.
+// Implements widgetapi.Widget.Mouse.
+func (hm *HeatMap) Mouse(m *terminalapi.Mouse) error {
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+
+	l := hm.lastLayout
+	if m.Button != mouse.ButtonLeft || l == nil {
+		return nil
+	}
+	if !m.Position.In(l.grid) {
+		hm.selected = nil
+		return nil
+	}
+	rel := m.Position.Sub(l.grid.Min)
+	sel := image.Point{rel.X / l.cellSize.X, rel.Y / l.cellSize.Y}
+	if sel.Y >= hm.rows() || sel.X >= hm.cols() {
+		hm.selected = nil
+		return nil
+	}
+	hm.selected = &sel
+	return nil
+}
+
+// Options implements widgetapi.Widget.Options.
+func (hm *HeatMap) Options() widgetapi.Options {
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+
+	return widgetapi.Options{
+		MinimumSize:  hm.minSize(),
+		WantKeyboard: widgetapi.KeyScopeNone,
+		WantMouse:    widgetapi.MouseScopeWidget,
+	}
+}
+
+// validateValues validates the provided values and labels.
+func validateValues(xLabels, yLabels []string, values [][]float64) error {
+	var cols int
+	for i, row := range values {
+		if i == 0 {
+			cols = len(row)
+			if cols == 0 {
+				return errors.New("invalid values, the rows must have at least one value")
+			}
+			continue
+		}
+		if got := len(row); got != cols {
+			return fmt.Errorf("invalid values, all rows must have the same number of values, values[0] has %d, values[%d] has %d", cols, i, got)
+		}
+	}
+	if got, want := len(xLabels), cols; got > 0 && got != want {
+		return fmt.Errorf("invalid xLabels, got %d labels, must be one per column, there are %d columns", got, want)
+	}
+	if got, want := len(yLabels), len(values); got > 0 && got != want {
+		return fmt.Errorf("invalid yLabels, got %d labels, must be one per row, there are %d rows", got, want)
+	}
+	return nil
+}
@@ -0,0 +1,674 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package heatmap
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/canvas/testcanvas"
+	"github.com/mum4k/termdash/internal/draw"
+	"github.com/mum4k/termdash/internal/draw/testdraw"
+	"github.com/mum4k/termdash/internal/faketerm"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// mustCell draws a heat map cell with the specified color in the area.
+func mustCell(c *canvas.Canvas, ar image.Rectangle, color cell.Color) {
+	testdraw.MustRectangle(c, ar,
+		draw.RectCellOpts(cell.BgColor(color)),
+		draw.RectChar(' '),
+	)
+}
+
+func TestHeatMap(t *testing.T) {
+	tests := []struct {
+		desc          string
+		opts          []Option
+		update        func(*HeatMap) error // update gets called before drawing of the widget.
+		mouse         []*terminalapi.Mouse // mouse events sent after the first draw.
+		canvas        image.Rectangle
+		want          func(size image.Point) *faketerm.Terminal
+		wantErr       bool
+		wantUpdateErr bool // whether to expect an error on a call to the update function
+	}{
+		{
+			desc: "fails on gradient with a single color",
+			opts: []Option{
+				Gradient(cell.ColorRed),
+			},
+			canvas:  image.Rect(0, 0, 3, 3),
+			wantErr: true,
+		},
+		{
+			desc: "fails on value range where minimum isn't smaller than maximum",
+			opts: []Option{
+				ValueRange(1, 1),
+			},
+			canvas:  image.Rect(0, 0, 3, 3),
+			wantErr: true,
+		},
+		{
+			desc: "fails on value range with NaN",
+			opts: []Option{
+				ValueRange(math.NaN(), 1),
+			},
+			canvas:  image.Rect(0, 0, 3, 3),
+			wantErr: true,
+		},
+		{
+			desc: "fails on nil value formatter",
+			opts: []Option{
+				ValueFormatter(nil),
+			},
+			canvas:  image.Rect(0, 0, 3, 3),
+			wantErr: true,
+		},
+		{
+			desc: "update fails on rows of different length",
+			update: func(hm *HeatMap) error {
+				return hm.Values(nil, nil, [][]float64{{1, 2}, {3}})
+			},
+			canvas:        image.Rect(0, 0, 3, 3),
+			wantUpdateErr: true,
+		},
+		{
+			desc: "update fails on empty rows",
+			update: func(hm *HeatMap) error {
+				return hm.Values(nil, nil, [][]float64{{}})
+			},
+			canvas:        image.Rect(0, 0, 3, 3),
+			wantUpdateErr: true,
+		},
+		{
+			desc: "update fails when X labels don't match the columns",
+			update: func(hm *HeatMap) error {
+				return hm.Values([]string{"a"}, nil, [][]float64{{1, 2}})
+			},
+			canvas:        image.Rect(0, 0, 3, 3),
+			wantUpdateErr: true,
+		},
+		{
+			desc: "update fails when Y labels don't match the rows",
+			update: func(hm *HeatMap) error {
+				return hm.Values(nil, []string{"a", "b"}, [][]float64{{1, 2}})
+			},
+			canvas:        image.Rect(0, 0, 3, 3),
+			wantUpdateErr: true,
+		},
+		{
+			desc: "update fails on invalid options",
+			update: func(hm *HeatMap) error {
+				return hm.Values(nil, nil, [][]float64{{1, 2}}, Gradient())
+			},
+			canvas:        image.Rect(0, 0, 3, 3),
+			wantUpdateErr: true,
+		},
+		{
+			desc:   "draws empty for no values",
+			canvas: image.Rect(0, 0, 3, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+		},
+		{
+			desc: "draws resize needed when canvas is too small",
+			opts: []Option{
+				HideLegend(),
+			},
+			update: func(hm *HeatMap) error {
+				return hm.Values(nil, nil, [][]float64{{1, 2, 3}})
+			},
+			canvas: image.Rect(0, 0, 2, 2),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustResizeNeeded(c)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "draws the cells, NaN values aren't colored",
+			opts: []Option{
+				Gradient(cell.ColorBlue, cell.ColorRed),
+				HideLegend(),
+			},
+			update: func(hm *HeatMap) error {
+				return hm.Values(nil, nil, [][]float64{
+					{0, 1},
+					{1, math.NaN()},
+				})
+			},
+			canvas: image.Rect(0, 0, 4, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				mustCell(c, image.Rect(0, 0, 2, 1), cell.ColorBlue)
+				mustCell(c, image.Rect(2, 0, 4, 1), cell.ColorRed)
+				mustCell(c, image.Rect(0, 1, 2, 2), cell.ColorRed)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "the cells fill the available space",
+			opts: []Option{
+				Gradient(cell.ColorBlue, cell.ColorRed),
+				HideLegend(),
+			},
+			update: func(hm *HeatMap) error {
+				return hm.Values(nil, nil, [][]float64{
+					{0, 1},
+					{1, 0},
+				})
+			},
+			canvas: image.Rect(0, 0, 7, 6),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				mustCell(c, image.Rect(0, 0, 3, 2), cell.ColorBlue)
+				mustCell(c, image.Rect(3, 0, 6, 2), cell.ColorRed)
+				mustCell(c, image.Rect(0, 2, 3, 4), cell.ColorRed)
+				mustCell(c, image.Rect(3, 2, 6, 4), cell.ColorBlue)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "maps values onto all the colors of the gradient",
+			opts: []Option{
+				Gradient(cell.ColorBlue, cell.ColorGreen, cell.ColorRed),
+				HideLegend(),
+			},
+			update: func(hm *HeatMap) error {
+				return hm.Values(nil, nil, [][]float64{
+					{0, 3, 4, 6, 9},
+				})
+			},
+			canvas: image.Rect(0, 0, 5, 2),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				mustCell(c, image.Rect(0, 0, 1, 1), cell.ColorBlue)
+				mustCell(c, image.Rect(1, 0, 2, 1), cell.ColorGreen)
+				mustCell(c, image.Rect(2, 0, 3, 1), cell.ColorGreen)
+				mustCell(c, image.Rect(3, 0, 4, 1), cell.ColorRed)
+				mustCell(c, image.Rect(4, 0, 5, 1), cell.ColorRed)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "all values equal use the last color",
+			opts: []Option{
+				Gradient(cell.ColorBlue, cell.ColorRed),
+				HideLegend(),
+			},
+			update: func(hm *HeatMap) error {
+				return hm.Values(nil, nil, [][]float64{{4, 4}})
+			},
+			canvas: image.Rect(0, 0, 2, 2),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				mustCell(c, image.Rect(0, 0, 2, 1), cell.ColorRed)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "custom value range, values outside of it are capped",
+			opts: []Option{
+				Gradient(cell.ColorBlue, cell.ColorRed),
+				HideLegend(),
+				ValueRange(0, 10),
+			},
+			update: func(hm *HeatMap) error {
+				return hm.Values(nil, nil, [][]float64{{4, 4, -5, 20}})
+			},
+			canvas: image.Rect(0, 0, 4, 2),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				mustCell(c, image.Rect(0, 0, 2, 1), cell.ColorBlue)
+				mustCell(c, image.Rect(2, 0, 3, 1), cell.ColorBlue)
+				mustCell(c, image.Rect(3, 0, 4, 1), cell.ColorRed)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "options provided to Values override those provided to New",
+			opts: []Option{
+				Gradient(cell.ColorBlue, cell.ColorRed),
+			},
+			update: func(hm *HeatMap) error {
+				return hm.Values(nil, nil, [][]float64{{0, 1}}, HideLegend(), Gradient(cell.ColorGreen, cell.ColorYellow))
+			},
+			canvas: image.Rect(0, 0, 2, 2),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				mustCell(c, image.Rect(0, 0, 1, 1), cell.ColorGreen)
+				mustCell(c, image.Rect(1, 0, 2, 1), cell.ColorYellow)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "draws labels",
+			opts: []Option{
+				Gradient(cell.ColorBlue, cell.ColorRed),
+				HideLegend(),
+				XLabelCellOpts(cell.FgColor(cell.ColorGreen)),
+				YLabelCellOpts(cell.FgColor(cell.ColorYellow)),
+			},
+			update: func(hm *HeatMap) error {
+				return hm.Values(
+					[]string{"a", "b"},
+					[]string{"r1", "r2"},
+					[][]float64{
+						{0, 1},
+						{1, 0},
+					},
+				)
+			},
+			canvas: image.Rect(0, 0, 7, 4),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				mustCell(c, image.Rect(3, 0, 5, 1), cell.ColorBlue)
+				mustCell(c, image.Rect(5, 0, 7, 1), cell.ColorRed)
+				mustCell(c, image.Rect(3, 1, 5, 2), cell.ColorRed)
+				mustCell(c, image.Rect(5, 1, 7, 2), cell.ColorBlue)
+
+				yOpts := draw.TextCellOpts(cell.FgColor(cell.ColorYellow))
+				testdraw.MustText(c, "r1", image.Point{0, 0}, yOpts)
+				testdraw.MustText(c, "r2", image.Point{0, 1}, yOpts)
+				xOpts := draw.TextCellOpts(cell.FgColor(cell.ColorGreen))
+				testdraw.MustText(c, "a", image.Point{3, 2}, xOpts)
+				testdraw.MustText(c, "b", image.Point{5, 2}, xOpts)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "Y labels are in the middle of the rows",
+			opts: []Option{
+				Gradient(cell.ColorBlue, cell.ColorRed),
+				HideLegend(),
+			},
+			update: func(hm *HeatMap) error {
+				return hm.Values(nil, []string{"r"}, [][]float64{{0}})
+			},
+			canvas: image.Rect(0, 0, 3, 4),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				mustCell(c, image.Rect(2, 0, 3, 3), cell.ColorRed)
+				testdraw.MustText(c, "r", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "skips X labels that overlap the previous label",
+			opts: []Option{
+				Gradient(cell.ColorBlue, cell.ColorRed),
+				HideLegend(),
+			},
+			update: func(hm *HeatMap) error {
+				return hm.Values(
+					[]string{"abc", "d", "e"},
+					nil,
+					[][]float64{{0, 0, 0}},
+				)
+			},
+			canvas: image.Rect(0, 0, 6, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				mustCell(c, image.Rect(0, 0, 6, 1), cell.ColorRed)
+				testdraw.MustText(c, "abc", image.Point{0, 1})
+				testdraw.MustText(c, "e", image.Point{4, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "draws the legend",
+			opts: []Option{
+				Gradient(cell.ColorBlue, cell.ColorRed),
+			},
+			update: func(hm *HeatMap) error {
+				return hm.Values(nil, nil, [][]float64{{0, 10}})
+			},
+			canvas: image.Rect(0, 0, 10, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				mustCell(c, image.Rect(0, 0, 2, 2), cell.ColorBlue)
+				mustCell(c, image.Rect(2, 0, 4, 2), cell.ColorRed)
+
+				mustCell(c, image.Rect(5, 0, 7, 1), cell.ColorRed)
+				mustCell(c, image.Rect(5, 1, 7, 2), cell.ColorBlue)
+				testdraw.MustText(c, "10", image.Point{8, 0})
+				testdraw.MustText(c, "0", image.Point{8, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "legend uses the value formatter",
+			opts: []Option{
+				Gradient(cell.ColorBlue, cell.ColorRed),
+				ValueFormatter(func(v float64) string {
+					return fmt.Sprintf("%.0fms", v)
+				}),
+			},
+			update: func(hm *HeatMap) error {
+				return hm.Values(nil, nil, [][]float64{{0, 10}})
+			},
+			canvas: image.Rect(0, 0, 12, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				mustCell(c, image.Rect(0, 0, 2, 2), cell.ColorBlue)
+				mustCell(c, image.Rect(2, 0, 4, 2), cell.ColorRed)
+
+				mustCell(c, image.Rect(5, 0, 7, 1), cell.ColorRed)
+				mustCell(c, image.Rect(5, 1, 7, 2), cell.ColorBlue)
+				testdraw.MustText(c, "10ms", image.Point{8, 0})
+				testdraw.MustText(c, "0ms", image.Point{8, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "clicking on a cell displays its value",
+			opts: []Option{
+				Gradient(cell.ColorBlue, cell.ColorRed),
+				HideLegend(),
+			},
+			update: func(hm *HeatMap) error {
+				return hm.Values(
+					[]string{"a", "b"},
+					[]string{"r1", "r2"},
+					[][]float64{
+						{0, 1},
+						{1, 0.5},
+					},
+				)
+			},
+			mouse: []*terminalapi.Mouse{
+				{Position: image.Point{6, 1}, Button: mouse.ButtonLeft},
+				{Position: image.Point{6, 1}, Button: mouse.ButtonRelease},
+			},
+			canvas: image.Rect(0, 0, 15, 4),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				mustCell(c, image.Rect(3, 0, 9, 1), cell.ColorBlue)
+				mustCell(c, image.Rect(9, 0, 15, 1), cell.ColorRed)
+				mustCell(c, image.Rect(3, 1, 9, 2), cell.ColorRed)
+				mustCell(c, image.Rect(9, 1, 15, 2), cell.ColorRed)
+
+				testdraw.MustText(c, "r1", image.Point{0, 0})
+				testdraw.MustText(c, "r2", image.Point{0, 1})
+				testdraw.MustText(c, "a", image.Point{3, 2})
+				testdraw.MustText(c, "b", image.Point{9, 2})
+				testdraw.MustText(c, "r2 / a: 1", image.Point{0, 3})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "clicking on a cell without value or labels",
+			opts: []Option{
+				Gradient(cell.ColorBlue, cell.ColorRed),
+				HideLegend(),
+			},
+			update: func(hm *HeatMap) error {
+				return hm.Values(nil, nil, [][]float64{
+					{0, math.NaN()},
+				})
+			},
+			mouse: []*terminalapi.Mouse{
+				{Position: image.Point{10, 0}, Button: mouse.ButtonLeft},
+			},
+			canvas: image.Rect(0, 0, 20, 2),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				mustCell(c, image.Rect(0, 0, 10, 1), cell.ColorRed)
+				testdraw.MustText(c, "0 / 1: no value", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "clicking outside of the cells clears the selection",
+			opts: []Option{
+				Gradient(cell.ColorBlue, cell.ColorRed),
+				HideLegend(),
+			},
+			update: func(hm *HeatMap) error {
+				return hm.Values(nil, nil, [][]float64{{0, 1}})
+			},
+			mouse: []*terminalapi.Mouse{
+				{Position: image.Point{0, 0}, Button: mouse.ButtonLeft},
+				{Position: image.Point{0, 1}, Button: mouse.ButtonLeft},
+			},
+			canvas: image.Rect(0, 0, 20, 2),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				mustCell(c, image.Rect(0, 0, 10, 1), cell.ColorBlue)
+				mustCell(c, image.Rect(10, 0, 20, 1), cell.ColorRed)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			hm, err := New(tc.opts...)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("New => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+
+			if tc.update != nil {
+				err = tc.update(hm)
+				if (err != nil) != tc.wantUpdateErr {
+					t.Errorf("update => unexpected error: %v, wantUpdateErr: %v", err, tc.wantUpdateErr)
+				}
+				if err != nil {
+					return
+				}
+			}
+
+			if len(tc.mouse) > 0 {
+				// The widget maps the mouse events onto the last drawn cells.
+				if err := hm.Draw(testcanvas.MustNew(tc.canvas), nil); err != nil {
+					t.Fatalf("Draw => unexpected error: %v", err)
+				}
+				for _, m := range tc.mouse {
+					if err := hm.Mouse(m); err != nil {
+						t.Fatalf("Mouse => unexpected error: %v", err)
+					}
+				}
+			}
+
+			if err := hm.Draw(c, nil); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+		})
+	}
+}
+
+func TestMouseAfterValuesShrink(t *testing.T) {
+	hm, err := New(HideLegend())
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	big := make([][]float64, 10)
+	for i := range big {
+		big[i] = make([]float64, 10)
+	}
+	if err := hm.Values(nil, nil, big); err != nil {
+		t.Fatalf("Values => unexpected error: %v", err)
+	}
+	ar := image.Rect(0, 0, 20, 11)
+	if err := hm.Draw(testcanvas.MustNew(ar), nil); err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+
+	if err := hm.Values(nil, nil, [][]float64{{0, 1}, {2, 3}}); err != nil {
+		t.Fatalf("Values => unexpected error: %v", err)
+	}
+	// Cell (9, 9) of the previous values.
+	if err := hm.Mouse(&terminalapi.Mouse{Position: image.Point{19, 9}, Button: mouse.ButtonLeft}); err != nil {
+		t.Fatalf("Mouse => unexpected error: %v", err)
+	}
+	if hm.selected != nil {
+		t.Errorf("Mouse => selected %v, want no selection", *hm.selected)
+	}
+	if err := hm.Draw(testcanvas.MustNew(ar), nil); err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+}
+
+func TestKeyboard(t *testing.T) {
+	hm, err := New()
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := hm.Keyboard(&terminalapi.Keyboard{}); err == nil {
+		t.Errorf("Keyboard => got nil err, wanted one")
+	}
+}
+
+func TestOptions(t *testing.T) {
+	tests := []struct {
+		desc   string
+		create func() (*HeatMap, error)
+		want   widgetapi.Options
+	}{
+		{
+			desc: "minimum size for no values",
+			create: func() (*HeatMap, error) {
+				return New()
+			},
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{1, 1},
+				WantKeyboard: widgetapi.KeyScopeNone,
+				WantMouse:    widgetapi.MouseScopeWidget,
+			},
+		},
+		{
+			desc: "minimum size without labels and legend",
+			create: func() (*HeatMap, error) {
+				hm, err := New(HideLegend())
+				if err != nil {
+					return nil, err
+				}
+				return hm, hm.Values(nil, nil, [][]float64{{1, 2, 3}, {4, 5, 6}})
+			},
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{3, 3},
+				WantKeyboard: widgetapi.KeyScopeNone,
+				WantMouse:    widgetapi.MouseScopeWidget,
+			},
+		},
+		{
+			desc: "minimum size with labels and legend",
+			create: func() (*HeatMap, error) {
+				hm, err := New()
+				if err != nil {
+					return nil, err
+				}
+				return hm, hm.Values(
+					[]string{"a", "b", "c"},
+					[]string{"row1", "row2"},
+					[][]float64{{1, 2, 3}, {4, 5, 600}},
+				)
+			},
+			want: widgetapi.Options{
+				// 5 for the Y labels, 3 columns and 7 for the legend.
+				MinimumSize:  image.Point{15, 4},
+				WantKeyboard: widgetapi.KeyScopeNone,
+				WantMouse:    widgetapi.MouseScopeWidget,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			hm, err := tc.create()
+			if err != nil {
+				t.Fatalf("create => unexpected error: %v", err)
+			}
+
+			got := hm.Options()
+			if diff := pretty.Compare(tc.want, got); diff != "" {
+				t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
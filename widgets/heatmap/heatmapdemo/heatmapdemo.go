@@ -0,0 +1,117 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary heatmapdemo displays a HeatMap widget.
+// Exist when 'q' is pressed.
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/termbox"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgets/heatmap"
+)
+
+// buckets are the latency buckets displayed as the rows of the heat map.
+var buckets = []string{">1s", "500ms", "200ms", "100ms", "50ms", "20ms", "10ms"}
+
+// playHeatMap continuously adds a new column of latency counts to the heat
+// map once every delay, rolling the older columns to the left.
+// Exits when the context expires.
+func playHeatMap(ctx context.Context, hm *heatmap.HeatMap, delay time.Duration) {
+	const columns = 30
+
+	values := make([][]float64, len(buckets))
+	for i := range values {
+		values[i] = make([]float64, columns)
+		for j := range values[i] {
+			values[i][j] = math.NaN()
+		}
+	}
+	xLabels := make([]string, columns)
+
+	ticker := time.NewTicker(delay)
+	defer ticker.Stop()
+	for step := 0; ; step++ {
+		select {
+		case <-ticker.C:
+			for i := range values {
+				// Most requests fall into the middle buckets.
+				mid := float64(len(buckets)) / 2
+				weight := math.Exp(-math.Pow(float64(i)-mid, 2) / 4)
+				values[i] = append(values[i][1:], float64(rand.Intn(100))*weight)
+			}
+			label := ""
+			if step%5 == 0 {
+				label = fmt.Sprintf("%ds", step)
+			}
+			xLabels = append(xLabels[1:], label)
+
+			if err := hm.Values(xLabels, buckets, values); err != nil {
+				panic(err)
+			}
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func main() {
+	t, err := termbox.New(termbox.ColorMode(terminalapi.ColorMode256))
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	hm, err := heatmap.New(
+		heatmap.ValueRange(0, 100),
+		heatmap.ValueFormatter(func(v float64) string {
+			return fmt.Sprintf("%.0f", v)
+		}),
+	)
+	if err != nil {
+		panic(err)
+	}
+	go playHeatMap(ctx, hm, 1*time.Second)
+
+	c, err := container.New(
+		t,
+		container.Border(linestyle.Light),
+		container.BorderTitle("PRESS Q TO QUIT, CLICK ON A CELL TO SEE ITS VALUE"),
+		container.PlaceWidget(hm),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	quitter := func(k *terminalapi.Keyboard) {
+		if k.Key == 'q' || k.Key == 'Q' {
+			cancel()
+		}
+	}
+
+	if err := termdash.Run(ctx, t, c, termdash.KeyboardSubscriber(quitter)); err != nil {
+		panic(err)
+	}
+}
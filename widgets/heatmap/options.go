@@ -0,0 +1,160 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package heatmap
+
+// options.go contains configurable options for HeatMap.
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/mum4k/termdash/cell"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// valueRange is the range of values set by the ValueRange option.
+type valueRange struct {
+	min, max float64
+}
+
+// options holds the provided options.
+type options struct {
+	gradient       []cell.Color
+	valueRange     *valueRange
+	hideLegend     bool
+	valueFormatter func(float64) string
+	xLabelCellOpts []cell.Option
+	yLabelCellOpts []cell.Option
+}
+
+// validate validates the provided options.
+func (o *options) validate() error {
+	if got, min := len(o.gradient), 2; got < min {
+		return fmt.Errorf("invalid Gradient with %d colors, must have at least %d colors", got, min)
+	}
+	if vr := o.valueRange; vr != nil {
+		if math.IsNaN(vr.min) || math.IsNaN(vr.max) {
+			return fmt.Errorf("invalid ValueRange(%v, %v), the values must not be NaN", vr.min, vr.max)
+		}
+		if vr.min >= vr.max {
+			return fmt.Errorf("invalid ValueRange(%v, %v), the minimum must be smaller than the maximum", vr.min, vr.max)
+		}
+	}
+	if o.valueFormatter == nil {
+		return errors.New("the ValueFormatter cannot be nil")
+	}
+	return nil
+}
+
+// newOptions returns options with the default values set.
+func newOptions() *options {
+	return &options{
+		gradient:       defaultGradient(),
+		valueFormatter: defaultValueFormatter,
+	}
+}
+
+// defaultGradient returns the default gradient which goes from blue through
+// cyan, green and yellow to red.
+func defaultGradient() []cell.Color {
+	return []cell.Color{
+		cell.ColorRGB6(0, 0, 5),
+		cell.ColorRGB6(0, 2, 5),
+		cell.ColorRGB6(0, 4, 5),
+		cell.ColorRGB6(0, 5, 3),
+		cell.ColorRGB6(0, 5, 0),
+		cell.ColorRGB6(3, 5, 0),
+		cell.ColorRGB6(5, 5, 0),
+		cell.ColorRGB6(5, 3, 0),
+		cell.ColorRGB6(5, 1, 0),
+		cell.ColorRGB6(5, 0, 0),
+	}
+}
+
+// defaultValueFormatter is the default formatter of values displayed in the
+// legend and the status line.
+func defaultValueFormatter(v float64) string {
+	return strconv.FormatFloat(v, 'g', 4, 64)
+}
+
+// Gradient sets the colors used to display the values, ordered from the color
+// of the smallest value to the color of the largest value. The range of values
+// is split into equally sized buckets, one per color. At least two colors must
+// be provided. Use cell.ColorRGB6 or cell.ColorNumber to pick colors from the
+// 256 color palette and make sure the terminal is set to the
+// terminalapi.ColorMode256 mode.
+// Defaults to ten colors going from blue through green and yellow to red.
+func Gradient(colors ...cell.Color) Option {
+	return option(func(opts *options) {
+		opts.gradient = colors
+	})
+}
+
+// ValueRange sets the range of values that is mapped onto the gradient instead
+// of determining the range from the smallest and the largest provided value.
+// Values outside of the range get the color of the nearest end of the range.
+// Useful to keep the colors stable when the values are continuously updated.
+// The minimum must be smaller than the maximum.
+func ValueRange(min, max float64) Option {
+	return option(func(opts *options) {
+		opts.valueRange = &valueRange{min: min, max: max}
+	})
+}
+
+// HideLegend hides the color scale legend that is by default displayed on the
+// right side of the heat map.
+func HideLegend() Option {
+	return option(func(opts *options) {
+		opts.hideLegend = true
+	})
+}
+
+// ValueFormatter sets the function that formats the values displayed in the
+// legend and in the status line. Defaults to the shortest representation with
+// at most four significant digits.
+func ValueFormatter(f func(float64) string) Option {
+	return option(func(opts *options) {
+		opts.valueFormatter = f
+	})
+}
+
+// XLabelCellOpts set the cell options for the labels on the X axis.
+func XLabelCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.xLabelCellOpts = co
+	})
+}
+
+// YLabelCellOpts set the cell options for the labels on the Y axis.
+func YLabelCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.yLabelCellOpts = co
+	})
+}
@@ -0,0 +1,127 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package histogram
+
+// buckets.go contains code that splits the samples into buckets and
+// determines how the buckets are displayed.
+
+import (
+	"math"
+
+	"github.com/mum4k/termdash/internal/numbers"
+)
+
+// scale maps the values of the samples onto relative positions on the
+// horizontal axis of the histogram.
+type scale struct {
+	// min is the value at the start of the axis.
+	min float64
+	// max is the value at the end of the axis.
+	max float64
+	// exponential indicates an exponential scale, otherwise the scale is
+	// linear.
+	exponential bool
+}
+
+// newScale returns a scale that covers all the samples.
+// The samples must all be positive for an exponential scale.
+func newScale(samples []float64, exponential bool) *scale {
+	min, max := numbers.MinMax(samples)
+	if max <= min {
+		// All the samples have the same value, the scale needs a non-zero
+		// range.
+		if exponential {
+			max = min * 2
+		} else {
+			max = min + 1
+		}
+	}
+	return &scale{
+		min:         min,
+		max:         max,
+		exponential: exponential,
+	}
+}
+
+// position returns the relative position of the value on the axis in the
+// range 0 <= position <= 1.
+func (s *scale) position(v float64) float64 {
+	var p float64
+	if s.exponential {
+		p = math.Log(v/s.min) / math.Log(s.max/s.min)
+	} else {
+		p = (v - s.min) / (s.max - s.min)
+	}
+	return math.Max(0, math.Min(1, p))
+}
+
+// value returns the value at the relative position on the axis.
+func (s *scale) value(position float64) float64 {
+	if s.exponential {
+		return s.min * math.Pow(s.max/s.min, position)
+	}
+	return s.min + position*(s.max-s.min)
+}
+
+// bucketCounts splits the scale into n buckets and returns the number of
+// samples in each bucket. The last bucket includes its upper boundary.
+func bucketCounts(samples []float64, s *scale, n int) []int {
+	counts := make([]int, n)
+	for _, v := range samples {
+		idx := int(s.position(v) * float64(n))
+		if idx >= n {
+			idx = n - 1
+		}
+		counts[idx]++
+	}
+	return counts
+}
+
+// percentile returns the p-th percentile of the sorted samples using the
+// nearest-rank method.
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// blocks are the characters used to draw the partially filled top cell of a
+// bar, ordered by how much of the cell they fill.
+var blocks = []rune{'▁', '▂', '▃', '▄', '▅', '▆', '▇'}
+
+// barHeight determines how many cells of the bar representing the count are
+// fully filled and which block character should be drawn in the cell on top
+// of them, given the largest count and the height available to the bars.
+// The returned rune is zero if no partially filled cell should be drawn.
+func barHeight(count, maxCount, height int) (full int, part rune) {
+	if count <= 0 || maxCount <= 0 || height <= 0 {
+		return 0, 0
+	}
+
+	// Each cell is split into eighths, the full block being the last one.
+	const parts = 8
+	eighths := int(math.Round(float64(count) / float64(maxCount) * float64(height*parts)))
+	if eighths == 0 {
+		// Make sure that buckets with samples are visible.
+		eighths = 1
+	}
+	full = eighths / parts
+	if rem := eighths % parts; rem > 0 {
+		part = blocks[rem-1]
+	}
+	return full, part
+}
@@ -0,0 +1,208 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package histogram
+
+import (
+	"math"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestScale(t *testing.T) {
+	tests := []struct {
+		desc         string
+		samples      []float64
+		exponential  bool
+		want         *scale
+		values       []float64 // values to convert to positions.
+		wantPosition []float64
+	}{
+		{
+			desc:         "linear scale",
+			samples:      []float64{10, 0, 20},
+			want:         &scale{min: 0, max: 20},
+			values:       []float64{0, 5, 10, 20},
+			wantPosition: []float64{0, 0.25, 0.5, 1},
+		},
+		{
+			desc:         "linear scale with all samples equal",
+			samples:      []float64{5, 5},
+			want:         &scale{min: 5, max: 6},
+			values:       []float64{5, 6},
+			wantPosition: []float64{0, 1},
+		},
+		{
+			desc:         "exponential scale",
+			samples:      []float64{1, 100, 10},
+			exponential:  true,
+			want:         &scale{min: 1, max: 100, exponential: true},
+			values:       []float64{1, 10, 100},
+			wantPosition: []float64{0, 0.5, 1},
+		},
+		{
+			desc:         "exponential scale with all samples equal",
+			samples:      []float64{3},
+			exponential:  true,
+			want:         &scale{min: 3, max: 6, exponential: true},
+			values:       []float64{3, 6},
+			wantPosition: []float64{0, 1},
+		},
+		{
+			desc:         "positions are clamped",
+			samples:      []float64{0, 10},
+			want:         &scale{min: 0, max: 10},
+			values:       []float64{-5, 15},
+			wantPosition: []float64{0, 1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := newScale(tc.samples, tc.exponential)
+			if diff := pretty.Compare(tc.want, got); diff != "" {
+				t.Fatalf("newScale => unexpected diff (-want, +got):\n%s", diff)
+			}
+
+			for i, v := range tc.values {
+				pos := got.position(v)
+				if want := tc.wantPosition[i]; math.Abs(pos-want) > 1e-9 {
+					t.Errorf("position(%v) => %v, want %v", v, pos, want)
+				}
+				if pos == 0 || pos == 1 {
+					continue // Clamped values cannot be converted back.
+				}
+				if back := got.value(pos); math.Abs(back-v) > 1e-9 {
+					t.Errorf("value(%v) => %v, want %v", pos, back, v)
+				}
+			}
+		})
+	}
+}
+
+func TestBucketCounts(t *testing.T) {
+	tests := []struct {
+		desc        string
+		samples     []float64
+		exponential bool
+		n           int
+		want        []int
+	}{
+		{
+			desc:    "single bucket",
+			samples: []float64{1, 2, 3},
+			n:       1,
+			want:    []int{3},
+		},
+		{
+			desc:    "linear buckets",
+			samples: []float64{0, 1, 2, 2, 3, 3, 3, 3},
+			n:       4,
+			want:    []int{1, 1, 2, 4},
+		},
+		{
+			desc:    "the last bucket includes its upper boundary",
+			samples: []float64{0, 10},
+			n:       2,
+			want:    []int{1, 1},
+		},
+		{
+			desc:        "exponential buckets",
+			samples:     []float64{1, 2, 9, 10, 50, 100},
+			exponential: true,
+			n:           2,
+			want:        []int{3, 3},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			s := newScale(tc.samples, tc.exponential)
+			got := bucketCounts(tc.samples, s, tc.n)
+			if diff := pretty.Compare(tc.want, got); diff != "" {
+				t.Errorf("bucketCounts => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	tests := []struct {
+		p    float64
+		want float64
+	}{
+		{p: 0.1, want: 1},
+		{p: 50, want: 5},
+		{p: 90, want: 9},
+		{p: 99, want: 10},
+		{p: 100, want: 10},
+	}
+
+	for _, tc := range tests {
+		if got := percentile(sorted, tc.p); got != tc.want {
+			t.Errorf("percentile(%v) => %v, want %v", tc.p, got, tc.want)
+		}
+	}
+}
+
+func TestBarHeight(t *testing.T) {
+	tests := []struct {
+		desc     string
+		count    int
+		maxCount int
+		height   int
+		wantFull int
+		wantPart rune
+	}{
+		{
+			desc:     "zero count",
+			count:    0,
+			maxCount: 10,
+			height:   5,
+		},
+		{
+			desc:     "the largest count fills the height",
+			count:    10,
+			maxCount: 10,
+			height:   5,
+			wantFull: 5,
+		},
+		{
+			desc:     "half of the height",
+			count:    5,
+			maxCount: 10,
+			height:   3,
+			wantFull: 1,
+			wantPart: '▄',
+		},
+		{
+			desc:     "small counts are visible",
+			count:    1,
+			maxCount: 1000,
+			height:   2,
+			wantPart: '▁',
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			gotFull, gotPart := barHeight(tc.count, tc.maxCount, tc.height)
+			if gotFull != tc.wantFull || gotPart != tc.wantPart {
+				t.Errorf("barHeight => (%d, %q), want (%d, %q)", gotFull, gotPart, tc.wantFull, tc.wantPart)
+			}
+		})
+	}
+}
@@ -0,0 +1,350 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package histogram implements a widget that displays the distribution of
+// sample values.
+package histogram
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/area"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/draw"
+	"github.com/mum4k/termdash/internal/runewidth"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// Histogram displays the distribution of sample values.
+//
+// The range of the sample values is split into buckets, one bar per bucket
+// shows how many samples fall into it. The number of buckets adjusts to the
+// width of the canvas. The boundaries of the buckets are displayed under the
+// bars and the histogram can optionally display markers at selected
+// percentiles.
+//
+// Implements widgetapi.Widget. This object is thread-safe.
+type Histogram struct {
+	// samples are the sample values provided to Values() or Add().
+	samples []float64
+
+	// mu protects the Histogram.
+	mu sync.Mutex
+
+	// opts are the provided options.
+	opts *options
+}
+
+// New returns a new Histogram.
+func New(opts ...Option) (*Histogram, error) {
+	opt := newOptions()
+	for _, o := range opts {
+		o.set(opt)
+	}
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	return &Histogram{
+		opts: opt,
+	}, nil
+}
+
+// Values replaces the samples displayed by the Histogram.
+// The samples must be valid numbers, i.e. not NaN or infinity, and must be
+// positive if the ExponentialBuckets option is provided.
+// Provided options override values set when New() was called.
+func (h *Histogram) Values(samples []float64, opts ...Option) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	newOpts := *h.opts
+	for _, opt := range opts {
+		opt.set(&newOpts)
+	}
+	if err := newOpts.validate(); err != nil {
+		return err
+	}
+	if err := validateSamples(samples, &newOpts); err != nil {
+		return err
+	}
+
+	// Copy to avoid external modifications. See #174.
+	h.samples = append([]float64(nil), samples...)
+	h.opts = &newOpts
+	h.dropOldSamples()
+	return nil
+}
+
+// Add adds the samples to the samples already displayed by the Histogram.
+// The samples must follow the same rules as described for Values.
+// See also the MaxSamples option.
+func (h *Histogram) Add(samples ...float64) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := validateSamples(samples, h.opts); err != nil {
+		return err
+	}
+	h.samples = append(h.samples, samples...)
+	h.dropOldSamples()
+	return nil
+}
+
+// dropOldSamples drops the oldest samples so that the histogram doesn't keep
+// more samples than allowed by the MaxSamples option.
+func (h *Histogram) dropOldSamples() {
+	if max := h.opts.maxSamples; max > 0 && len(h.samples) > max {
+		h.samples = append([]float64(nil), h.samples[len(h.samples)-max:]...)
+	}
+}
+
+// layout are the areas of the canvas that contain individual parts of the
+// histogram.
+type layout struct {
+	// buckets is the number of buckets.
+	buckets int
+	// bars is the area that contains the bars.
+	bars image.Rectangle
+	// labels is the line with the labels of the bucket boundaries.
+	labels image.Rectangle
+	// percentiles is the line with the labels of the percentile markers or
+	// image.ZR if there are no percentiles.
+	percentiles image.Rectangle
+}
+
+// newLayout determines the layout of the histogram on the canvas.
+// Returns nil if the canvas is too small.
+func (h *Histogram) newLayout(cvsAr image.Rectangle) *layout {
+	buckets := cvsAr.Dx() / h.opts.barWidth
+	if max := h.opts.maxBuckets; max > 0 && buckets > max {
+		buckets = max
+	}
+
+	bars := image.Rect(
+		cvsAr.Min.X,
+		cvsAr.Min.Y,
+		cvsAr.Min.X+buckets*h.opts.barWidth,
+		cvsAr.Max.Y-1, // One line for the labels.
+	)
+	var percentiles image.Rectangle
+	if len(h.opts.percentiles) > 0 {
+		percentiles = image.Rect(cvsAr.Min.X, cvsAr.Min.Y, cvsAr.Max.X, cvsAr.Min.Y+1)
+		bars.Min.Y++
+	}
+	if buckets < 1 || bars.Dy() < 1 {
+		return nil
+	}
+	return &layout{
+		buckets:     buckets,
+		bars:        bars,
+		labels:      image.Rect(cvsAr.Min.X, cvsAr.Max.Y-1, cvsAr.Max.X, cvsAr.Max.Y),
+		percentiles: percentiles,
+	}
+}
+
+// minSize determines the minimum required size of the canvas.
+func (h *Histogram) minSize() image.Point {
+	if len(h.samples) == 0 {
+		return image.Point{1, 1}
+	}
+	height := 2 // One line for the bars and one for the labels.
+	if len(h.opts.percentiles) > 0 {
+		height++
+	}
+	return image.Point{h.opts.barWidth, height}
+}
+
+// Draw draws the Histogram widget onto the canvas.
+// Implements widgetapi.Widget.Draw.
+func (h *Histogram) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.samples) == 0 {
+		return nil
+	}
+
+	needAr, err := area.FromSize(h.minSize())
+	if err != nil {
+		return err
+	}
+	l := h.newLayout(cvs.Area())
+	if !needAr.In(cvs.Area()) || l == nil {
+		return draw.ResizeNeeded(cvs)
+	}
+
+	s := newScale(h.samples, h.opts.exponential)
+	if err := h.drawBars(cvs, l, s); err != nil {
+		return err
+	}
+	if err := h.drawLabels(cvs, l, s); err != nil {
+		return err
+	}
+	return h.drawPercentiles(cvs, l, s)
+}
+
+// drawBars draws one bar per bucket.
+func (h *Histogram) drawBars(cvs *canvas.Canvas, l *layout, s *scale) error {
+	counts := bucketCounts(h.samples, s, l.buckets)
+	var maxCount int
+	for _, c := range counts {
+		if c > maxCount {
+			maxCount = c
+		}
+	}
+
+	for i, c := range counts {
+		full, part := barHeight(c, maxCount, l.bars.Dy())
+		minX := l.bars.Min.X + i*h.opts.barWidth
+		maxX := minX + h.opts.barWidth
+		top := l.bars.Max.Y - full
+		if full > 0 {
+			if err := draw.Rectangle(cvs, image.Rect(minX, top, maxX, l.bars.Max.Y),
+				draw.RectCellOpts(cell.BgColor(h.opts.barColor)),
+				draw.RectChar(' '),
+			); err != nil {
+				return err
+			}
+		}
+		if part != 0 {
+			if err := cvs.SetAreaCells(image.Rect(minX, top-1, maxX, top), part,
+				cell.FgColor(h.opts.barColor),
+			); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// drawLabels draws the lower boundaries of the buckets under the bars. Each
+// label starts at its bar, labels that would overlap the previous label are
+// skipped.
+func (h *Histogram) drawLabels(cvs *canvas.Canvas, l *layout, s *scale) error {
+	nextFree := l.labels.Min.X
+	for i := 0; i < l.buckets; i++ {
+		start := image.Point{l.bars.Min.X + i*h.opts.barWidth, l.labels.Min.Y}
+		if start.X < nextFree {
+			continue
+		}
+		text := h.opts.valueFormatter(s.value(float64(i) / float64(l.buckets)))
+		if err := draw.Text(cvs, text, start,
+			draw.TextOverrunMode(draw.OverrunModeTrim),
+			draw.TextCellOpts(h.opts.labelCellOpts...),
+		); err != nil {
+			return err
+		}
+		nextFree = start.X + runewidth.StringWidth(text) + 1
+	}
+	return nil
+}
+
+// marker is a percentile marker.
+type marker struct {
+	// label is the label of the marker.
+	label string
+	// x is the horizontal position of the marker.
+	x int
+}
+
+// drawPercentiles draws the markers at the values of the percentiles. Labels
+// that would overlap the previous label are skipped.
+func (h *Histogram) drawPercentiles(cvs *canvas.Canvas, l *layout, s *scale) error {
+	if len(h.opts.percentiles) == 0 {
+		return nil
+	}
+
+	sorted := append([]float64(nil), h.samples...)
+	sort.Float64s(sorted)
+	var markers []*marker
+	for _, p := range h.opts.percentiles {
+		x := l.bars.Min.X + int(s.position(percentile(sorted, p))*float64(l.bars.Dx()))
+		if x >= l.bars.Max.X {
+			x = l.bars.Max.X - 1
+		}
+		markers = append(markers, &marker{
+			label: fmt.Sprintf("p%v", p),
+			x:     x,
+		})
+	}
+	sort.SliceStable(markers, func(i, j int) bool {
+		return markers[i].x < markers[j].x
+	})
+
+	opts := []cell.Option{cell.FgColor(h.opts.percentileColor)}
+	nextFree := l.percentiles.Min.X
+	for _, m := range markers {
+		for y := l.bars.Min.Y; y < l.bars.Max.Y; y++ {
+			// Only the foreground color is set, so the marker keeps the
+			// background color of the bar it crosses.
+			if _, err := cvs.SetCell(image.Point{m.x, y}, '│', opts...); err != nil {
+				return err
+			}
+		}
+
+		if m.x < nextFree {
+			continue
+		}
+		if err := draw.Text(cvs, m.label, image.Point{m.x, l.percentiles.Min.Y},
+			draw.TextOverrunMode(draw.OverrunModeTrim),
+			draw.TextCellOpts(opts...),
+		); err != nil {
+			return err
+		}
+		nextFree = m.x + runewidth.StringWidth(m.label) + 1
+	}
+	return nil
+}
+
+// Keyboard input isn't supported on the Histogram widget.
+func (*Histogram) Keyboard(k *terminalapi.Keyboard) error {
+	return errors.New("the Histogram widget doesn't support keyboard events")
+}
+
+// Mouse input isn't supported on the Histogram widget.
+func (*Histogram) Mouse(m *terminalapi.Mouse) error {
+	return errors.New("the Histogram widget doesn't support mouse events")
+}
+
+// Options implements widgetapi.Widget.Options.
+func (h *Histogram) Options() widgetapi.Options {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return widgetapi.Options{
+		MinimumSize:  h.minSize(),
+		WantKeyboard: widgetapi.KeyScopeNone,
+		WantMouse:    widgetapi.MouseScopeNone,
+	}
+}
+
+// validateSamples validates the provided samples.
+func validateSamples(samples []float64, opts *options) error {
+	for i, v := range samples {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("invalid samples[%d]: %v, the samples must be valid numbers", i, v)
+		}
+		if opts.exponential && v <= 0 {
+			return fmt.Errorf("invalid samples[%d]: %v, the samples must be positive with ExponentialBuckets", i, v)
+		}
+	}
+	return nil
+}
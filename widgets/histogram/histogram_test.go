@@ -0,0 +1,478 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package histogram
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/canvas/testcanvas"
+	"github.com/mum4k/termdash/internal/draw"
+	"github.com/mum4k/termdash/internal/draw/testdraw"
+	"github.com/mum4k/termdash/internal/faketerm"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// mustBar draws a fully filled part of a bar with the specified color in the
+// area.
+func mustBar(c *canvas.Canvas, ar image.Rectangle, color cell.Color) {
+	testdraw.MustRectangle(c, ar,
+		draw.RectCellOpts(cell.BgColor(color)),
+		draw.RectChar(' '),
+	)
+}
+
+func TestHistogram(t *testing.T) {
+	tests := []struct {
+		desc          string
+		opts          []Option
+		update        func(*Histogram) error // update gets called before drawing of the widget.
+		canvas        image.Rectangle
+		want          func(size image.Point) *faketerm.Terminal
+		wantErr       bool
+		wantUpdateErr bool // whether to expect an error on a call to the update function
+	}{
+		{
+			desc: "fails on bar width too small",
+			opts: []Option{
+				BarWidth(0),
+			},
+			canvas:  image.Rect(0, 0, 3, 3),
+			wantErr: true,
+		},
+		{
+			desc: "fails on negative max buckets",
+			opts: []Option{
+				MaxBuckets(-1),
+			},
+			canvas:  image.Rect(0, 0, 3, 3),
+			wantErr: true,
+		},
+		{
+			desc: "fails on negative max samples",
+			opts: []Option{
+				MaxSamples(-1),
+			},
+			canvas:  image.Rect(0, 0, 3, 3),
+			wantErr: true,
+		},
+		{
+			desc: "fails on zero percentile",
+			opts: []Option{
+				Percentiles(50, 0),
+			},
+			canvas:  image.Rect(0, 0, 3, 3),
+			wantErr: true,
+		},
+		{
+			desc: "fails on percentile above 100",
+			opts: []Option{
+				Percentiles(101),
+			},
+			canvas:  image.Rect(0, 0, 3, 3),
+			wantErr: true,
+		},
+		{
+			desc: "fails on nil value formatter",
+			opts: []Option{
+				ValueFormatter(nil),
+			},
+			canvas:  image.Rect(0, 0, 3, 3),
+			wantErr: true,
+		},
+		{
+			desc: "update fails on NaN sample",
+			update: func(h *Histogram) error {
+				return h.Values([]float64{1, math.NaN()})
+			},
+			canvas:        image.Rect(0, 0, 3, 3),
+			wantUpdateErr: true,
+		},
+		{
+			desc: "update fails on infinite sample",
+			update: func(h *Histogram) error {
+				return h.Add(math.Inf(-1))
+			},
+			canvas:        image.Rect(0, 0, 3, 3),
+			wantUpdateErr: true,
+		},
+		{
+			desc: "update fails on zero sample with exponential buckets",
+			opts: []Option{
+				ExponentialBuckets(),
+			},
+			update: func(h *Histogram) error {
+				return h.Add(1, 0)
+			},
+			canvas:        image.Rect(0, 0, 3, 3),
+			wantUpdateErr: true,
+		},
+		{
+			desc: "update fails on negative sample when exponential buckets are provided to Values",
+			update: func(h *Histogram) error {
+				return h.Values([]float64{-1}, ExponentialBuckets())
+			},
+			canvas:        image.Rect(0, 0, 3, 3),
+			wantUpdateErr: true,
+		},
+		{
+			desc: "update fails on invalid options",
+			update: func(h *Histogram) error {
+				return h.Values([]float64{1}, BarWidth(0))
+			},
+			canvas:        image.Rect(0, 0, 3, 3),
+			wantUpdateErr: true,
+		},
+		{
+			desc:   "draws empty for no samples",
+			canvas: image.Rect(0, 0, 3, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+		},
+		{
+			desc: "draws resize needed when canvas is too small",
+			update: func(h *Histogram) error {
+				return h.Values([]float64{1, 2})
+			},
+			canvas: image.Rect(0, 0, 1, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustResizeNeeded(c)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "draws resize needed when there is no space for bars under percentile labels",
+			opts: []Option{
+				Percentiles(50),
+			},
+			update: func(h *Histogram) error {
+				return h.Values([]float64{1, 2})
+			},
+			canvas: image.Rect(0, 0, 3, 2),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustResizeNeeded(c)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "draws linear buckets with partially filled cells",
+			opts: []Option{
+				MaxBuckets(4),
+			},
+			update: func(h *Histogram) error {
+				return h.Values([]float64{0, 1, 2, 2, 3, 3, 3, 3})
+			},
+			canvas: image.Rect(0, 0, 8, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testcanvas.MustSetCell(c, image.Point{0, 1}, '▄', cell.FgColor(DefaultBarColor))
+				testcanvas.MustSetCell(c, image.Point{1, 1}, '▄', cell.FgColor(DefaultBarColor))
+				mustBar(c, image.Rect(2, 1, 3, 2), DefaultBarColor)
+				mustBar(c, image.Rect(3, 0, 4, 2), DefaultBarColor)
+
+				testdraw.MustText(c, "0", image.Point{0, 2})
+				testdraw.MustText(c, "1.5", image.Point{2, 2})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "draws wider bars with custom colors and labels",
+			opts: []Option{
+				BarWidth(2),
+				BarColor(cell.ColorBlue),
+				LabelCellOpts(cell.FgColor(cell.ColorGreen)),
+				ValueFormatter(func(v float64) string {
+					return fmt.Sprintf("%.0f", v)
+				}),
+			},
+			update: func(h *Histogram) error {
+				return h.Values([]float64{0, 10})
+			},
+			canvas: image.Rect(0, 0, 4, 2),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				mustBar(c, image.Rect(0, 0, 4, 1), cell.ColorBlue)
+				testdraw.MustText(c, "0", image.Point{0, 1}, draw.TextCellOpts(cell.FgColor(cell.ColorGreen)))
+				testdraw.MustText(c, "5", image.Point{2, 1}, draw.TextCellOpts(cell.FgColor(cell.ColorGreen)))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "draws exponential buckets",
+			opts: []Option{
+				BarWidth(2),
+				ExponentialBuckets(),
+			},
+			update: func(h *Histogram) error {
+				return h.Values([]float64{1, 10, 100})
+			},
+			canvas: image.Rect(0, 0, 4, 2),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testcanvas.MustSetAreaCells(c, image.Rect(0, 0, 2, 1), '▄', cell.FgColor(DefaultBarColor))
+				mustBar(c, image.Rect(2, 0, 4, 1), DefaultBarColor)
+				testdraw.MustText(c, "1", image.Point{0, 1})
+				testdraw.MustText(c, "10", image.Point{2, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "options provided to Values override the options provided to New",
+			opts: []Option{
+				BarColor(cell.ColorBlue),
+			},
+			update: func(h *Histogram) error {
+				return h.Values([]float64{5}, BarColor(cell.ColorGreen))
+			},
+			canvas: image.Rect(0, 0, 1, 2),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				mustBar(c, image.Rect(0, 0, 1, 1), cell.ColorGreen)
+				testdraw.MustText(c, "5", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "drops the oldest samples over MaxSamples",
+			opts: []Option{
+				MaxSamples(2),
+			},
+			update: func(h *Histogram) error {
+				for _, v := range []float64{0, 10, 10} {
+					if err := h.Add(v); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			canvas: image.Rect(0, 0, 2, 2),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				// Both remaining samples are in the first bucket.
+				mustBar(c, image.Rect(0, 0, 1, 1), DefaultBarColor)
+				testdraw.MustText(c, "10", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "draws percentile markers",
+			opts: []Option{
+				Percentiles(50, 90),
+				PercentileColor(cell.ColorYellow),
+			},
+			update: func(h *Histogram) error {
+				return h.Add(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+			},
+			canvas: image.Rect(0, 0, 10, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				mustBar(c, image.Rect(0, 1, 10, 2), DefaultBarColor)
+				testcanvas.MustSetCell(c, image.Point{4, 1}, '│', cell.FgColor(cell.ColorYellow), cell.BgColor(DefaultBarColor))
+				testcanvas.MustSetCell(c, image.Point{8, 1}, '│', cell.FgColor(cell.ColorYellow), cell.BgColor(DefaultBarColor))
+				testdraw.MustText(c, "p50", image.Point{4, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorYellow)))
+				testdraw.MustText(c, "p9", image.Point{8, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorYellow)))
+
+				testdraw.MustText(c, "1", image.Point{0, 2})
+				testdraw.MustText(c, "2.8", image.Point{2, 2})
+				testdraw.MustText(c, "6.4", image.Point{6, 2})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "skips overlapping percentile labels",
+			opts: []Option{
+				Percentiles(99, 90),
+			},
+			update: func(h *Histogram) error {
+				return h.Add(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+			},
+			canvas: image.Rect(0, 0, 10, 3),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				mustBar(c, image.Rect(0, 1, 10, 2), DefaultBarColor)
+				testcanvas.MustSetCell(c, image.Point{8, 1}, '│', cell.FgColor(DefaultPercentileColor), cell.BgColor(DefaultBarColor))
+				testcanvas.MustSetCell(c, image.Point{9, 1}, '│', cell.FgColor(DefaultPercentileColor), cell.BgColor(DefaultBarColor))
+				testdraw.MustText(c, "p9", image.Point{8, 0}, draw.TextCellOpts(cell.FgColor(DefaultPercentileColor)))
+
+				testdraw.MustText(c, "1", image.Point{0, 2})
+				testdraw.MustText(c, "2.8", image.Point{2, 2})
+				testdraw.MustText(c, "6.4", image.Point{6, 2})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			h, err := New(tc.opts...)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("New => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+
+			if tc.update != nil {
+				err = tc.update(h)
+				if (err != nil) != tc.wantUpdateErr {
+					t.Errorf("update => unexpected error: %v, wantUpdateErr: %v", err, tc.wantUpdateErr)
+				}
+				if err != nil {
+					return
+				}
+			}
+
+			if err := h.Draw(c, nil); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+		})
+	}
+}
+
+func TestKeyboard(t *testing.T) {
+	h, err := New()
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := h.Keyboard(&terminalapi.Keyboard{}); err == nil {
+		t.Errorf("Keyboard => got nil err, wanted one")
+	}
+}
+
+func TestMouse(t *testing.T) {
+	h, err := New()
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := h.Mouse(&terminalapi.Mouse{}); err == nil {
+		t.Errorf("Mouse => got nil err, wanted one")
+	}
+}
+
+func TestOptions(t *testing.T) {
+	tests := []struct {
+		desc   string
+		create func() (*Histogram, error)
+		want   widgetapi.Options
+	}{
+		{
+			desc: "minimum size for no samples",
+			create: func() (*Histogram, error) {
+				return New(BarWidth(3))
+			},
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{1, 1},
+				WantKeyboard: widgetapi.KeyScopeNone,
+				WantMouse:    widgetapi.MouseScopeNone,
+			},
+		},
+		{
+			desc: "minimum size with samples",
+			create: func() (*Histogram, error) {
+				h, err := New(BarWidth(3))
+				if err != nil {
+					return nil, err
+				}
+				return h, h.Add(1, 2, 3)
+			},
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{3, 2},
+				WantKeyboard: widgetapi.KeyScopeNone,
+				WantMouse:    widgetapi.MouseScopeNone,
+			},
+		},
+		{
+			desc: "minimum size with percentiles",
+			create: func() (*Histogram, error) {
+				h, err := New(Percentiles(50))
+				if err != nil {
+					return nil, err
+				}
+				return h, h.Add(1, 2, 3)
+			},
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{1, 3},
+				WantKeyboard: widgetapi.KeyScopeNone,
+				WantMouse:    widgetapi.MouseScopeNone,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			h, err := tc.create()
+			if err != nil {
+				t.Fatalf("create => unexpected error: %v", err)
+			}
+
+			got := h.Options()
+			if diff := pretty.Compare(tc.want, got); diff != "" {
+				t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary histogramdemo displays a couple of Histogram widgets.
+// Exist when 'q' is pressed.
+package main
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/termbox"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgets/histogram"
+)
+
+// playHistogram continuously adds new samples to the histogram once every
+// delay, the samples are generated by the provided function.
+// Exits when the context expires.
+func playHistogram(ctx context.Context, h *histogram.Histogram, delay time.Duration, sample func() float64) {
+	ticker := time.NewTicker(delay)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			var samples []float64
+			for i := 0; i < 20; i++ {
+				samples = append(samples, sample())
+			}
+			if err := h.Add(samples...); err != nil {
+				panic(err)
+			}
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func main() {
+	t, err := termbox.New()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	normal, err := histogram.New(
+		histogram.BarWidth(2),
+		histogram.MaxSamples(5000),
+		histogram.Percentiles(50, 90, 99),
+	)
+	if err != nil {
+		panic(err)
+	}
+	go playHistogram(ctx, normal, 100*time.Millisecond, func() float64 {
+		return rand.NormFloat64()*15 + 100
+	})
+
+	latency, err := histogram.New(
+		histogram.ExponentialBuckets(),
+		histogram.BarWidth(2),
+		histogram.BarColor(cell.ColorBlue),
+		histogram.MaxSamples(5000),
+		histogram.Percentiles(50, 90, 99),
+	)
+	if err != nil {
+		panic(err)
+	}
+	go playHistogram(ctx, latency, 100*time.Millisecond, func() float64 {
+		// Latencies in milliseconds, most requests are fast with a long tail
+		// of slow ones.
+		return 1 + rand.ExpFloat64()*rand.ExpFloat64()*20
+	})
+
+	c, err := container.New(
+		t,
+		container.Border(linestyle.Light),
+		container.BorderTitle("PRESS Q TO QUIT"),
+		container.SplitHorizontal(
+			container.Top(
+				container.Border(linestyle.Light),
+				container.BorderTitle("Linear buckets"),
+				container.PlaceWidget(normal),
+			),
+			container.Bottom(
+				container.Border(linestyle.Light),
+				container.BorderTitle("Exponential buckets (latency in ms)"),
+				container.PlaceWidget(latency),
+			),
+		),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	quitter := func(k *terminalapi.Keyboard) {
+		if k.Key == 'q' || k.Key == 'Q' {
+			cancel()
+		}
+	}
+
+	if err := termdash.Run(ctx, t, c, termdash.KeyboardSubscriber(quitter)); err != nil {
+		panic(err)
+	}
+}
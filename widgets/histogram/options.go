@@ -0,0 +1,185 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package histogram
+
+// options.go contains configurable options for Histogram.
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/mum4k/termdash/cell"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// options holds the provided options.
+type options struct {
+	exponential     bool
+	barWidth        int
+	maxBuckets      int
+	maxSamples      int
+	barColor        cell.Color
+	labelCellOpts   []cell.Option
+	percentiles     []float64
+	percentileColor cell.Color
+	valueFormatter  func(float64) string
+}
+
+// validate validates the provided options.
+func (o *options) validate() error {
+	if got, min := o.barWidth, 1; got < min {
+		return fmt.Errorf("invalid BarWidth %d, must be %d <= BarWidth", got, min)
+	}
+	if got, min := o.maxBuckets, 0; got < min {
+		return fmt.Errorf("invalid MaxBuckets %d, must be %d <= MaxBuckets", got, min)
+	}
+	if got, min := o.maxSamples, 0; got < min {
+		return fmt.Errorf("invalid MaxSamples %d, must be %d <= MaxSamples", got, min)
+	}
+	for _, p := range o.percentiles {
+		if min, max := 0.0, 100.0; !(p > min && p <= max) {
+			return fmt.Errorf("invalid percentile %v, must be %v < percentile <= %v", p, min, max)
+		}
+	}
+	if o.valueFormatter == nil {
+		return errors.New("the ValueFormatter cannot be nil")
+	}
+	return nil
+}
+
+// newOptions returns options with the default values set.
+func newOptions() *options {
+	return &options{
+		barWidth:        DefaultBarWidth,
+		barColor:        DefaultBarColor,
+		percentileColor: DefaultPercentileColor,
+		valueFormatter:  defaultValueFormatter,
+	}
+}
+
+// defaultValueFormatter is the default formatter of the bucket boundaries.
+func defaultValueFormatter(v float64) string {
+	return strconv.FormatFloat(v, 'g', 3, 64)
+}
+
+// LinearBuckets splits the range of the samples into buckets of equal size.
+// This is the default.
+func LinearBuckets() Option {
+	return option(func(opts *options) {
+		opts.exponential = false
+	})
+}
+
+// ExponentialBuckets splits the range of the samples into buckets whose size
+// grows exponentially, i.e. the ratio of the upper and the lower boundary is
+// the same for all the buckets. Useful for values like latencies that span
+// multiple orders of magnitude.
+// All the samples must be positive when this option is provided.
+func ExponentialBuckets() Option {
+	return option(func(opts *options) {
+		opts.exponential = true
+	})
+}
+
+// DefaultBarWidth is the default value for the BarWidth option.
+const DefaultBarWidth = 1
+
+// BarWidth sets the width of the bar representing each bucket in cells.
+// The number of buckets is determined by how many bars fit the width of the
+// canvas.
+func BarWidth(width int) Option {
+	return option(func(opts *options) {
+		opts.barWidth = width
+	})
+}
+
+// MaxBuckets limits the number of buckets. By default the histogram uses as
+// many buckets as fit the width of the canvas. Zero means no limit.
+func MaxBuckets(buckets int) Option {
+	return option(func(opts *options) {
+		opts.maxBuckets = buckets
+	})
+}
+
+// MaxSamples limits the number of samples the histogram keeps, when more
+// samples are added, the oldest ones are dropped. Useful when continuously
+// adding samples with the Add method. Zero means no limit, which is the
+// default.
+func MaxSamples(samples int) Option {
+	return option(func(opts *options) {
+		opts.maxSamples = samples
+	})
+}
+
+// DefaultBarColor is the default value for the BarColor option.
+const DefaultBarColor = cell.ColorRed
+
+// BarColor sets the color of the bars.
+func BarColor(c cell.Color) Option {
+	return option(func(opts *options) {
+		opts.barColor = c
+	})
+}
+
+// LabelCellOpts sets the cell options of the labels of the bucket boundaries
+// displayed under the bars.
+func LabelCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.labelCellOpts = co
+	})
+}
+
+// Percentiles displays markers at the values of the specified percentiles of
+// the samples, e.g. Percentiles(50, 90, 99). Each marker is a vertical line
+// labeled with the percentile, e.g. "p90". The percentiles must be in the
+// range 0 < percentile <= 100.
+func Percentiles(percentiles ...float64) Option {
+	return option(func(opts *options) {
+		opts.percentiles = percentiles
+	})
+}
+
+// DefaultPercentileColor is the default value for the PercentileColor option.
+const DefaultPercentileColor = cell.ColorYellow
+
+// PercentileColor sets the color of the percentile markers and their labels.
+func PercentileColor(c cell.Color) Option {
+	return option(func(opts *options) {
+		opts.percentileColor = c
+	})
+}
+
+// ValueFormatter sets the function that formats the bucket boundaries
+// displayed under the bars. Defaults to the shortest representation with at
+// most three significant digits.
+func ValueFormatter(f func(float64) string) Option {
+	return option(func(opts *options) {
+		opts.valueFormatter = f
+	})
+}
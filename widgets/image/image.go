@@ -0,0 +1,204 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package image implements a widget that displays images.
+package image
+
+import (
+	"errors"
+	"image"
+	"sync"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/canvas/braille"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// Image displays an image scaled to fit the canvas while keeping its aspect
+// ratio, centered within the canvas.
+//
+// Implements widgetapi.Widget. This object is thread-safe.
+type Image struct {
+	// img is the displayed image.
+	img image.Image
+
+	// scaled is the image scaled to the size of the last canvas, nil if it
+	// needs to be scaled again.
+	scaled *scaledImage
+
+	// mu protects the widget.
+	mu sync.Mutex
+
+	// opts are the provided options.
+	opts *options
+}
+
+// New returns a new Image widget that displays nothing until an image is
+// provided by calling Set.
+func New(opts ...Option) (*Image, error) {
+	opt := newOptions()
+	for _, o := range opts {
+		o.set(opt)
+	}
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	return &Image{
+		opts: opt,
+	}, nil
+}
+
+// Set sets the image to display. The image must not be empty.
+// The widget doesn't copy the image, it must not be modified after this call.
+func (i *Image) Set(img image.Image) error {
+	if img == nil {
+		return errors.New("the image cannot be nil")
+	}
+	if img.Bounds().Empty() {
+		return errors.New("the image cannot be empty")
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.img = img
+	i.scaled = nil
+	return nil
+}
+
+// Reset removes the image, the widget displays nothing.
+func (i *Image) Reset() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.img = nil
+	i.scaled = nil
+}
+
+// pixelSize returns the number of pixels the canvas can display in the
+// current mode.
+// Caller must hold i.mu.
+func (i *Image) pixelSize(cvsAr image.Rectangle) image.Point {
+	if i.opts.mode == ModeBraille {
+		return image.Point{cvsAr.Dx() * braille.ColMult, cvsAr.Dy() * braille.RowMult}
+	}
+	return image.Point{cvsAr.Dx(), cvsAr.Dy() * 2}
+}
+
+// Draw draws the image onto the canvas, scaling it if the size of the canvas
+// changed since the last call.
+// Implements widgetapi.Widget.Draw.
+func (i *Image) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.img == nil {
+		return nil
+	}
+
+	cvsAr := cvs.Area()
+	area := i.pixelSize(cvsAr)
+	if i.scaled == nil || i.scaled.area != area {
+		i.scaled = scale(i.img, area)
+	}
+
+	if i.opts.mode == ModeBraille {
+		return i.drawBraille(cvs)
+	}
+	return i.drawHalfBlock(cvs)
+}
+
+// Runes used in ModeHalfBlock, their foreground displays one pixel and their
+// background the other pixel.
+const (
+	upperHalfBlockRune = '▀'
+	lowerHalfBlockRune = '▄'
+)
+
+// pixelColor returns the cell color of the pixel at the point of the scaled
+// image and asserts whether the pixel should be displayed.
+func pixelColor(s *scaledImage, p image.Point) (cell.Color, bool) {
+	px, ok := s.at(p)
+	if !ok {
+		return cell.ColorDefault, false
+	}
+	return cell.ColorRGB24(int(px.R), int(px.G), int(px.B)), true
+}
+
+// drawHalfBlock draws the scaled image in ModeHalfBlock.
+// Caller must hold i.mu.
+func (i *Image) drawHalfBlock(cvs *canvas.Canvas) error {
+	cvsAr := cvs.Area()
+	for y := cvsAr.Min.Y; y < cvsAr.Max.Y; y++ {
+		for x := cvsAr.Min.X; x < cvsAr.Max.X; x++ {
+			upper, upperOK := pixelColor(i.scaled, image.Point{x, 2 * y})
+			lower, lowerOK := pixelColor(i.scaled, image.Point{x, 2*y + 1})
+
+			var (
+				r    rune
+				opts []cell.Option
+			)
+			switch {
+			case upperOK && lowerOK:
+				r, opts = upperHalfBlockRune, []cell.Option{cell.FgColor(upper), cell.BgColor(lower)}
+			case upperOK:
+				r, opts = upperHalfBlockRune, []cell.Option{cell.FgColor(upper)}
+			case lowerOK:
+				r, opts = lowerHalfBlockRune, []cell.Option{cell.FgColor(lower)}
+			default:
+				continue
+			}
+			if _, err := cvs.SetCell(image.Point{x, y}, r, opts...); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// drawBraille draws the scaled image in ModeBraille.
+// Caller must hold i.mu.
+func (i *Image) drawBraille(cvs *canvas.Canvas) error {
+	bc, err := braille.New(cvs.Area())
+	if err != nil {
+		return err
+	}
+
+	dots := i.scaled.dots(i.opts.threshold, !i.opts.noDither, i.opts.invert)
+	for _, p := range dots {
+		if err := bc.SetPixel(p, cell.FgColor(i.opts.color)); err != nil {
+			return err
+		}
+	}
+	return bc.CopyTo(cvs)
+}
+
+// Keyboard input isn't supported on the Image widget.
+func (*Image) Keyboard(k *terminalapi.Keyboard) error {
+	return errors.New("the Image widget doesn't support keyboard events")
+}
+
+// Mouse input isn't supported on the Image widget.
+func (*Image) Mouse(m *terminalapi.Mouse) error {
+	return errors.New("the Image widget doesn't support mouse events")
+}
+
+// Options implements widgetapi.Widget.Options.
+func (*Image) Options() widgetapi.Options {
+	return widgetapi.Options{
+		MinimumSize:  image.Point{1, 1},
+		WantKeyboard: widgetapi.KeyScopeNone,
+		WantMouse:    widgetapi.MouseScopeNone,
+	}
+}
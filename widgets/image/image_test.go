@@ -0,0 +1,335 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package image
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/canvas/braille/testbraille"
+	"github.com/mum4k/termdash/internal/canvas/testcanvas"
+	"github.com/mum4k/termdash/internal/faketerm"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// newImage returns an image with the pixels in rows.
+func newImage(rows ...[]color.Color) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, len(rows[0]), len(rows)))
+	for y, row := range rows {
+		for x, c := range row {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+// uniformImage returns an image of the size filled with the color.
+func uniformImage(size image.Point, c color.Color) image.Image {
+	img := image.NewRGBA(image.Rectangle{Max: size})
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+var (
+	red         = color.RGBA{0xff, 0, 0, 0xff}
+	green       = color.RGBA{0, 0xff, 0, 0xff}
+	blue        = color.RGBA{0, 0, 0xff, 0xff}
+	white       = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	black       = color.RGBA{0, 0, 0, 0xff}
+	transparent = color.RGBA{}
+)
+
+// cellColor returns the cell color of the color as the widget displays it.
+func cellColor(c color.RGBA) cell.Color {
+	return cell.ColorRGB24(int(c.R), int(c.G), int(c.B))
+}
+
+func TestImage(t *testing.T) {
+	tests := []struct {
+		desc string
+		opts []Option
+		// sizes are sizes of canvases drawn before the one in canvas.
+		sizes      []image.Point
+		img        image.Image
+		reset      bool
+		canvas     image.Rectangle
+		want       func(size image.Point) *faketerm.Terminal
+		wantNewErr bool
+		wantSetErr bool
+	}{
+		{
+			desc: "New fails on unsupported mode",
+			opts: []Option{
+				DisplayMode(Mode(-1)),
+			},
+			wantNewErr: true,
+		},
+		{
+			desc: "New fails on threshold too low",
+			opts: []Option{
+				Threshold(0),
+			},
+			wantNewErr: true,
+		},
+		{
+			desc: "New fails on threshold too high",
+			opts: []Option{
+				Threshold(256),
+			},
+			wantNewErr: true,
+		},
+		{
+			desc:       "Set fails on nil image",
+			canvas:     image.Rect(0, 0, 2, 1),
+			wantSetErr: true,
+		},
+		{
+			desc:       "Set fails on empty image",
+			img:        image.NewRGBA(image.ZR),
+			canvas:     image.Rect(0, 0, 2, 1),
+			wantSetErr: true,
+		},
+		{
+			desc:   "draws nothing without an image",
+			canvas: image.Rect(0, 0, 2, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+		},
+		{
+			desc: "draws two pixels per cell with half blocks",
+			img: newImage(
+				[]color.Color{red, green},
+				[]color.Color{blue, white},
+			),
+			canvas: image.Rect(0, 0, 2, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testcanvas.MustSetCell(cvs, image.Point{0, 0}, '▀', cell.FgColor(cellColor(red)), cell.BgColor(cellColor(blue)))
+				testcanvas.MustSetCell(cvs, image.Point{1, 0}, '▀', cell.FgColor(cellColor(green)), cell.BgColor(cellColor(white)))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc: "keeps the aspect ratio and centers the image",
+			img: newImage(
+				[]color.Color{red, green},
+				[]color.Color{blue, white},
+			),
+			canvas: image.Rect(0, 0, 4, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testcanvas.MustSetCell(cvs, image.Point{1, 0}, '▀', cell.FgColor(cellColor(red)), cell.BgColor(cellColor(blue)))
+				testcanvas.MustSetCell(cvs, image.Point{2, 0}, '▀', cell.FgColor(cellColor(green)), cell.BgColor(cellColor(white)))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "scales the image up",
+			img:    uniformImage(image.Point{1, 1}, red),
+			canvas: image.Rect(0, 0, 2, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testcanvas.MustSetAreaCells(cvs, cvs.Area(), '▀', cell.FgColor(cellColor(red)), cell.BgColor(cellColor(red)))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc: "scales the image down by averaging the pixels",
+			img: newImage(
+				[]color.Color{black, white, red, red},
+				[]color.Color{white, black, red, red},
+				[]color.Color{blue, blue, green, green},
+				[]color.Color{blue, blue, green, green},
+			),
+			canvas: image.Rect(0, 0, 2, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				gray := color.RGBA{0x7f, 0x7f, 0x7f, 0xff}
+				testcanvas.MustSetCell(cvs, image.Point{0, 0}, '▀', cell.FgColor(cellColor(gray)), cell.BgColor(cellColor(blue)))
+				testcanvas.MustSetCell(cvs, image.Point{1, 0}, '▀', cell.FgColor(cellColor(red)), cell.BgColor(cellColor(green)))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc: "doesn't display transparent pixels",
+			img: newImage(
+				[]color.Color{red, transparent},
+				[]color.Color{transparent, blue},
+			),
+			canvas: image.Rect(0, 0, 2, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testcanvas.MustSetCell(cvs, image.Point{0, 0}, '▀', cell.FgColor(cellColor(red)))
+				testcanvas.MustSetCell(cvs, image.Point{1, 0}, '▄', cell.FgColor(cellColor(blue)))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "scales the image again when the canvas is resized",
+			img:    uniformImage(image.Point{1, 1}, red),
+			sizes:  []image.Point{{1, 1}, {4, 4}},
+			canvas: image.Rect(0, 0, 2, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testcanvas.MustSetAreaCells(cvs, cvs.Area(), '▀', cell.FgColor(cellColor(red)), cell.BgColor(cellColor(red)))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "Reset removes the image",
+			img:    uniformImage(image.Point{1, 1}, red),
+			reset:  true,
+			canvas: image.Rect(0, 0, 2, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+		},
+		{
+			desc: "draws braille dots for light pixels",
+			opts: []Option{
+				DisplayMode(ModeBraille),
+				Color(cell.ColorRed),
+			},
+			img: newImage(
+				[]color.Color{white, black},
+				[]color.Color{black, white},
+			),
+			canvas: image.Rect(0, 0, 1, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				bc := testbraille.MustNew(ft.Area())
+				// The image is scaled to two by two pixels centered vertically.
+				for _, p := range []image.Point{{0, 1}, {1, 2}} {
+					testbraille.MustSetPixel(bc, p, cell.FgColor(cell.ColorRed))
+				}
+				testbraille.MustApply(bc, ft)
+				return ft
+			},
+		},
+		{
+			desc: "draws braille dots for dark pixels when inverted",
+			opts: []Option{
+				DisplayMode(ModeBraille),
+				Invert(),
+			},
+			img: newImage(
+				[]color.Color{white, black},
+				[]color.Color{black, white},
+			),
+			canvas: image.Rect(0, 0, 1, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				bc := testbraille.MustNew(ft.Area())
+				for _, p := range []image.Point{{1, 1}, {0, 2}} {
+					testbraille.MustSetPixel(bc, p)
+				}
+				testbraille.MustApply(bc, ft)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			img, err := New(tc.opts...)
+			if (err != nil) != tc.wantNewErr {
+				t.Errorf("New => unexpected error: %v, wantNewErr: %v", err, tc.wantNewErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if tc.img != nil || tc.wantSetErr {
+				err := img.Set(tc.img)
+				if (err != nil) != tc.wantSetErr {
+					t.Errorf("Set => unexpected error: %v, wantSetErr: %v", err, tc.wantSetErr)
+				}
+				if err != nil {
+					return
+				}
+			}
+			if tc.reset {
+				img.Reset()
+			}
+
+			for _, size := range tc.sizes {
+				c, err := canvas.New(image.Rectangle{Max: size})
+				if err != nil {
+					t.Fatalf("canvas.New => unexpected error: %v", err)
+				}
+				if err := img.Draw(c, &widgetapi.Meta{}); err != nil {
+					t.Fatalf("Draw => unexpected error: %v", err)
+				}
+			}
+
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+			if err := img.Draw(c, &widgetapi.Meta{}); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+		})
+	}
+}
+
+func TestOptions(t *testing.T) {
+	img, err := New()
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+
+	want := widgetapi.Options{
+		MinimumSize:  image.Point{1, 1},
+		WantKeyboard: widgetapi.KeyScopeNone,
+		WantMouse:    widgetapi.MouseScopeNone,
+	}
+	got := img.Options()
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary imagedemo shows the functionality of the image widget.
+package main
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"math"
+	"time"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/termbox"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	tdimage "github.com/mum4k/termdash/widgets/image"
+)
+
+// logo returns an image of a ring with a hue gradient on a transparent
+// background.
+func logo(size int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	mid := float64(size) / 2
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			dx, dy := float64(x)-mid+0.5, float64(y)-mid+0.5
+			dist := math.Hypot(dx, dy) / mid
+			if dist > 1 || dist < 0.55 {
+				continue
+			}
+			hue := (math.Atan2(dy, dx) + math.Pi) / (2 * math.Pi)
+			img.Set(x, y, hueColor(hue))
+		}
+	}
+	return img
+}
+
+// hueColor returns a fully saturated color of the hue in range 0-1.
+func hueColor(hue float64) color.Color {
+	channel := func(offset float64) uint8 {
+		v := math.Abs(math.Mod(hue*6+offset, 6)-3) - 1
+		return uint8(math.Max(0, math.Min(1, v)) * 0xff)
+	}
+	return color.RGBA{channel(0), channel(4), channel(2), 0xff}
+}
+
+// gradient returns an image with a horizontal gradient from black to white.
+func gradient(width, height int) image.Image {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetGray(x, y, color.Gray{uint8(x * 0xff / (width - 1))})
+		}
+	}
+	return img
+}
+
+// newImage returns an image widget displaying the image.
+func newImage(img image.Image, opts ...tdimage.Option) *tdimage.Image {
+	w, err := tdimage.New(opts...)
+	if err != nil {
+		panic(err)
+	}
+	if err := w.Set(img); err != nil {
+		panic(err)
+	}
+	return w
+}
+
+func main() {
+	t, err := termbox.New()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ring := logo(200)
+	grad := gradient(256, 32)
+
+	c, err := container.New(
+		t,
+		container.Border(linestyle.Light),
+		container.BorderTitle("PRESS Q TO QUIT"),
+		container.SplitVertical(
+			container.Left(
+				container.SplitHorizontal(
+					container.Top(
+						container.Border(linestyle.Light),
+						container.BorderTitle("Half blocks"),
+						container.PlaceWidget(newImage(ring)),
+					),
+					container.Bottom(
+						container.Border(linestyle.Light),
+						container.BorderTitle("Half blocks"),
+						container.PlaceWidget(newImage(grad)),
+					),
+					container.SplitPercent(70),
+				),
+			),
+			container.Right(
+				container.SplitHorizontal(
+					container.Top(
+						container.Border(linestyle.Light),
+						container.BorderTitle("Braille"),
+						container.PlaceWidget(newImage(ring, tdimage.DisplayMode(tdimage.ModeBraille))),
+					),
+					container.Bottom(
+						container.Border(linestyle.Light),
+						container.BorderTitle("Braille with dithering"),
+						container.PlaceWidget(newImage(grad, tdimage.DisplayMode(tdimage.ModeBraille))),
+					),
+					container.SplitPercent(70),
+				),
+			),
+		),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	quitter := func(k *terminalapi.Keyboard) {
+		if k.Key == 'q' || k.Key == 'Q' {
+			cancel()
+		}
+	}
+
+	if err := termdash.Run(ctx, t, c, termdash.KeyboardSubscriber(quitter), termdash.RedrawInterval(time.Second)); err != nil {
+		panic(err)
+	}
+}
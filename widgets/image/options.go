@@ -0,0 +1,138 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package image
+
+// options.go contains configurable options for Image.
+
+import (
+	"fmt"
+
+	"github.com/mum4k/termdash/cell"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// options holds the provided options.
+type options struct {
+	mode      Mode
+	threshold int
+	noDither  bool
+	invert    bool
+	color     cell.Color
+}
+
+// validate validates the provided options.
+func (o *options) validate() error {
+	if _, ok := modeNames[o.mode]; !ok {
+		return fmt.Errorf("unsupported Mode(%d)", o.mode)
+	}
+	if min, max := 1, 255; o.threshold < min || o.threshold > max {
+		return fmt.Errorf("invalid Threshold %d, must be in range %d <= Threshold <= %d", o.threshold, min, max)
+	}
+	return nil
+}
+
+// newOptions returns options with the default values set.
+func newOptions() *options {
+	return &options{
+		mode:      ModeHalfBlock,
+		threshold: DefaultThreshold,
+		color:     cell.ColorDefault,
+	}
+}
+
+// Mode determines how the image is rendered.
+type Mode int
+
+// String implements fmt.Stringer()
+func (m Mode) String() string {
+	if n, ok := modeNames[m]; ok {
+		return n
+	}
+	return "ModeUnknown"
+}
+
+// modeNames maps Mode values to human readable names.
+var modeNames = map[Mode]string{
+	ModeHalfBlock: "ModeHalfBlock",
+	ModeBraille:   "ModeBraille",
+}
+
+const (
+	// ModeHalfBlock renders the image in color, each cell displays two pixels
+	// above each other using the upper half block character with the
+	// foreground and background colors of the pixels.
+	ModeHalfBlock Mode = iota
+
+	// ModeBraille renders the image in monochrome on the braille canvas, each
+	// cell displays two by four pixels. Pixels lighter than the threshold are
+	// displayed as dots.
+	ModeBraille
+)
+
+// DisplayMode sets how the image is rendered.
+// Defaults to ModeHalfBlock.
+func DisplayMode(m Mode) Option {
+	return option(func(opts *options) {
+		opts.mode = m
+	})
+}
+
+// DefaultThreshold is the default value for the Threshold option.
+const DefaultThreshold = 128
+
+// Threshold sets the luminance in range 1-255 from which pixels are displayed
+// as dots in ModeBraille. Defaults to DefaultThreshold.
+func Threshold(t int) Option {
+	return option(func(opts *options) {
+		opts.threshold = t
+	})
+}
+
+// NoDither disables dithering in ModeBraille. By default the error of
+// rounding each pixel to a dot or no dot is diffused to the neighbouring
+// pixels, which represents shades of gray by the density of dots.
+func NoDither() Option {
+	return option(func(opts *options) {
+		opts.noDither = true
+	})
+}
+
+// Invert displays pixels darker than the threshold as dots in ModeBraille.
+// Useful for images with dark content on a light background.
+func Invert() Option {
+	return option(func(opts *options) {
+		opts.invert = true
+	})
+}
+
+// Color sets the color of the dots in ModeBraille.
+func Color(c cell.Color) Option {
+	return option(func(opts *options) {
+		opts.color = c
+	})
+}
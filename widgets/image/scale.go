@@ -0,0 +1,179 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package image
+
+// scale.go contains code that scales images to the size of the canvas.
+
+import (
+	"image"
+	"image/color"
+)
+
+// opaqueAlpha is the smallest alpha value of a pixel that is displayed.
+const opaqueAlpha = 0x80
+
+// scaledImage is an image scaled to fit an area of pixels.
+type scaledImage struct {
+	// area is the size of the area in pixels the image was scaled for.
+	area image.Point
+	// rect is the position of the scaled image within the area.
+	rect image.Rectangle
+	// pixels are the pixels of the scaled image in rows.
+	pixels []color.RGBA
+}
+
+// scale scales the image to fit the area of pixels keeping its aspect ratio
+// and centers it within the area. The color of each scaled pixel is the
+// average of the pixels of the image it covers.
+func scale(img image.Image, area image.Point) *scaledImage {
+	b := img.Bounds()
+	iw, ih := b.Dx(), b.Dy()
+
+	// Fit the width or the height of the area, whichever limits the image.
+	tw, th := area.X, area.Y
+	if iw*area.Y > ih*area.X {
+		th = (ih*area.X + iw/2) / iw
+	} else {
+		tw = (iw*area.Y + ih/2) / ih
+	}
+	if tw < 1 {
+		tw = 1
+	}
+	if th < 1 {
+		th = 1
+	}
+
+	min := image.Point{(area.X - tw) / 2, (area.Y - th) / 2}
+	s := &scaledImage{
+		area:   area,
+		rect:   image.Rectangle{min, min.Add(image.Point{tw, th})},
+		pixels: make([]color.RGBA, 0, tw*th),
+	}
+	for ty := 0; ty < th; ty++ {
+		y0, y1 := span(ty, th, ih)
+		for tx := 0; tx < tw; tx++ {
+			x0, x1 := span(tx, tw, iw)
+			s.pixels = append(s.pixels, average(img, image.Rect(b.Min.X+x0, b.Min.Y+y0, b.Min.X+x1, b.Min.Y+y1)))
+		}
+	}
+	return s
+}
+
+// span returns the range of source pixels covered by the target pixel at the
+// index when scaling from the source size to the target size. The range
+// covers at least one source pixel.
+func span(idx, target, source int) (from, to int) {
+	from = idx * source / target
+	to = (idx + 1) * source / target
+	if to <= from {
+		to = from + 1
+	}
+	return from, to
+}
+
+// average returns the average color of the pixels of the image in the area.
+func average(img image.Image, ar image.Rectangle) color.RGBA {
+	var r, g, b, a, count uint64
+	for y := ar.Min.Y; y < ar.Max.Y; y++ {
+		for x := ar.Min.X; x < ar.Max.X; x++ {
+			pr, pg, pb, pa := img.At(x, y).RGBA()
+			r += uint64(pr)
+			g += uint64(pg)
+			b += uint64(pb)
+			a += uint64(pa)
+			count++
+		}
+	}
+	if a == 0 {
+		return color.RGBA{}
+	}
+	// The colors are alpha-premultiplied, dividing by the sum of alpha
+	// values yields the average of the visible colors.
+	return color.RGBA{
+		R: uint8(r * 0xff / a),
+		G: uint8(g * 0xff / a),
+		B: uint8(b * 0xff / a),
+		A: uint8(a / count >> 8),
+	}
+}
+
+// at returns the pixel at the point of the area and asserts whether the pixel
+// should be displayed. Points outside of the scaled image and transparent
+// pixels aren't displayed.
+func (s *scaledImage) at(p image.Point) (color.RGBA, bool) {
+	if !p.In(s.rect) {
+		return color.RGBA{}, false
+	}
+	rel := p.Sub(s.rect.Min)
+	px := s.pixels[rel.Y*s.rect.Dx()+rel.X]
+	return px, px.A >= opaqueAlpha
+}
+
+// luminance returns the perceived brightness of the color in range 0-255.
+func luminance(c color.RGBA) float64 {
+	return float64((299*int(c.R) + 587*int(c.G) + 114*int(c.B) + 500) / 1000)
+}
+
+// dots returns the points of the area displayed as dots in ModeBraille.
+// Pixels with luminance equal to or above the threshold are displayed, or
+// those below the threshold if invert is true. When dither is true, the error
+// of rounding each pixel is diffused to its neighbours using the
+// Floyd–Steinberg algorithm.
+func (s *scaledImage) dots(threshold int, dither, invert bool) []image.Point {
+	w, h := s.rect.Dx(), s.rect.Dy()
+	values := make([]float64, len(s.pixels))
+	for i, px := range s.pixels {
+		l := luminance(px)
+		if invert {
+			l = 255 - l
+		}
+		values[i] = l
+	}
+
+	// diffuse adds the fraction of the error to the pixel at the coordinates.
+	diffuse := func(x, y int, err float64) {
+		if x < 0 || x >= w || y >= h {
+			return
+		}
+		values[y*w+x] += err
+	}
+
+	var dots []image.Point
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i := y*w + x
+			if s.pixels[i].A < opaqueAlpha {
+				continue
+			}
+
+			v := values[i]
+			var err float64
+			if v >= float64(threshold) {
+				dots = append(dots, s.rect.Min.Add(image.Point{x, y}))
+				err = v - 255
+			} else {
+				err = v
+			}
+			if !dither {
+				continue
+			}
+			diffuse(x+1, y, err*7/16)
+			diffuse(x-1, y+1, err*3/16)
+			diffuse(x, y+1, err*5/16)
+			diffuse(x+1, y+1, err*1/16)
+		}
+	}
+	return dots
+}
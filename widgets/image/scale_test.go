@@ -0,0 +1,139 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package image
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestScale(t *testing.T) {
+	tests := []struct {
+		desc     string
+		img      image.Image
+		area     image.Point
+		wantRect image.Rectangle
+	}{
+		{
+			desc:     "fits the width of the area",
+			img:      uniformImage(image.Point{4, 2}, red),
+			area:     image.Point{8, 8},
+			wantRect: image.Rect(0, 2, 8, 6),
+		},
+		{
+			desc:     "fits the height of the area",
+			img:      uniformImage(image.Point{2, 4}, red),
+			area:     image.Point{8, 8},
+			wantRect: image.Rect(2, 0, 6, 8),
+		},
+		{
+			desc:     "keeps at least one pixel",
+			img:      uniformImage(image.Point{100, 1}, red),
+			area:     image.Point{4, 4},
+			wantRect: image.Rect(0, 1, 4, 2),
+		},
+		{
+			desc:     "image with bounds not at the origin",
+			img:      uniformImage(image.Point{4, 4}, red).(*image.RGBA).SubImage(image.Rect(2, 2, 4, 4)),
+			area:     image.Point{4, 4},
+			wantRect: image.Rect(0, 0, 4, 4),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := scale(tc.img, tc.area)
+			if got.rect != tc.wantRect {
+				t.Errorf("scale => rect %v, want %v", got.rect, tc.wantRect)
+			}
+			if got, want := len(got.pixels), tc.wantRect.Dx()*tc.wantRect.Dy(); got != want {
+				t.Errorf("scale => %d pixels, want %d", got, want)
+			}
+			for i, px := range got.pixels {
+				if px != red {
+					t.Errorf("scale => pixel %d is %v, want %v", i, px, red)
+				}
+			}
+		})
+	}
+}
+
+func TestDots(t *testing.T) {
+	gray := color.RGBA{0x80, 0x80, 0x80, 0xff}
+	darkGray := color.RGBA{0x40, 0x40, 0x40, 0xff}
+
+	tests := []struct {
+		desc      string
+		img       image.Image
+		threshold int
+		dither    bool
+		invert    bool
+		wantDots  int
+	}{
+		{
+			desc:      "without dithering all pixels above the threshold are dots",
+			img:       uniformImage(image.Point{8, 8}, gray),
+			threshold: 128,
+			wantDots:  64,
+		},
+		{
+			desc:      "without dithering no pixels below the threshold are dots",
+			img:       uniformImage(image.Point{8, 8}, darkGray),
+			threshold: 128,
+			wantDots:  0,
+		},
+		{
+			desc:      "lower threshold",
+			img:       uniformImage(image.Point{8, 8}, darkGray),
+			threshold: 64,
+			wantDots:  64,
+		},
+		{
+			desc:      "dithering represents gray by the density of dots",
+			img:       uniformImage(image.Point{8, 8}, darkGray),
+			threshold: 128,
+			dither:    true,
+			// About a quarter, the error diffused past the edges is lost.
+			wantDots: 15,
+		},
+		{
+			desc:      "dithering with inverted luminance",
+			img:       uniformImage(image.Point{8, 8}, darkGray),
+			threshold: 128,
+			dither:    true,
+			invert:    true,
+			wantDots:  49,
+		},
+		{
+			desc:      "transparent pixels are never dots",
+			img:       uniformImage(image.Point{8, 8}, transparent),
+			threshold: 128,
+			dither:    true,
+			invert:    true,
+			wantDots:  0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			s := scale(tc.img, tc.img.Bounds().Size())
+			got := s.dots(tc.threshold, tc.dither, tc.invert)
+			if len(got) != tc.wantDots {
+				t.Errorf("dots => %d dots, want %d", len(got), tc.wantDots)
+			}
+		})
+	}
+}
@@ -19,8 +19,8 @@ import (
 	"math"
 
 	"github.com/mum4k/termdash/cell"
-	"github.com/mum4k/termdash/widgets/linechart/internal/axes"
-	"github.com/mum4k/termdash/widgets/linechart/internal/zoom"
+	"github.com/mum4k/termdash/internal/axes"
+	"github.com/mum4k/termdash/internal/zoom"
 )
 
 // options.go contains configurable options for LineChart.
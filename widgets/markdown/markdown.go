@@ -0,0 +1,239 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package markdown implements a widget that displays rendered Markdown.
+package markdown
+
+import (
+	"image"
+	"strings"
+	"sync"
+
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/canvas/buffer"
+	"github.com/mum4k/termdash/internal/runewidth"
+	"github.com/mum4k/termdash/internal/wrap"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// tabWidth is the distance between tab stops in Markdown documents.
+const tabWidth = 4
+
+// Markdown displays a Markdown document rendered into styled text that wraps
+// at the width of the canvas.
+//
+// Supports a subset of CommonMark, i.e. ATX and setext headings, paragraphs,
+// emphasis, strong emphasis, code spans, links, fenced and indented code
+// blocks, block quotes, ordered and unordered lists and thematic breaks, as
+// well as tables from GitHub Flavored Markdown. Emphasis is displayed as
+// underlined text, because terminals don't portably support italic text.
+//
+// The content can be scrolled with the keyboard when the widget is focused
+// and with the mouse wheel.
+//
+// Implements widgetapi.Widget. This object is thread-safe.
+type Markdown struct {
+	// blocks are the parsed blocks of the document.
+	blocks []*block
+	// changed indicates that the document changed since the last draw.
+	changed bool
+
+	// lines are the rendered lines of the document.
+	lines [][]*buffer.Cell
+	// links maps the cells of links to their destinations.
+	links map[*buffer.Cell]string
+	// lastWidth is the width of the canvas the lines were rendered for.
+	lastWidth int
+	// lastHeight is the height of the canvas during the last draw.
+	lastHeight int
+
+	// first is the index of the first displayed line.
+	first int
+
+	// clickable maps points on the canvas to destinations of links drawn on
+	// them.
+	clickable map[image.Point]string
+	// pressed is the destination of the link the left mouse button was
+	// pressed on, empty if none.
+	pressed string
+
+	// mu protects the widget.
+	mu sync.Mutex
+
+	// opts are the provided options.
+	opts *options
+}
+
+// New returns a new Markdown widget without content.
+func New(opts ...Option) (*Markdown, error) {
+	opt := newOptions()
+	for _, o := range opts {
+		o.set(opt)
+	}
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	return &Markdown{
+		opts: opt,
+	}, nil
+}
+
+// SetContent replaces the displayed document and scrolls to its top.
+// The text must not contain control characters other than newlines and tabs.
+// An empty text clears the widget.
+func (md *Markdown) SetContent(text string) error {
+	text = strings.Replace(text, "\r\n", "\n", -1)
+	text = wrap.ExpandTabs(text, tabWidth)
+	if text != "" {
+		if err := wrap.ValidText(text); err != nil {
+			return err
+		}
+	}
+	blocks := parseBlocks(strings.Split(text, "\n"))
+
+	md.mu.Lock()
+	defer md.mu.Unlock()
+	md.blocks = blocks
+	md.changed = true
+	md.first = 0
+	return nil
+}
+
+// normalizeFirst returns the first line limited so that the content fills as
+// much of the canvas height as possible.
+func normalizeFirst(first, lines, height int) int {
+	if max := lines - height; first > max {
+		first = max
+	}
+	if first < 0 {
+		return 0
+	}
+	return first
+}
+
+// Draw draws the Markdown widget onto the canvas.
+// Implements widgetapi.Widget.Draw.
+func (md *Markdown) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	md.mu.Lock()
+	defer md.mu.Unlock()
+
+	width := cvs.Area().Dx()
+	height := cvs.Area().Dy()
+	if md.changed || width != md.lastWidth {
+		lines, links, err := render(md.blocks, width, md.opts)
+		if err != nil {
+			return err
+		}
+		md.lines, md.links = lines, links
+		md.changed = false
+	}
+	md.lastWidth = width
+	md.lastHeight = height
+	md.first = normalizeFirst(md.first, len(md.lines), height)
+
+	md.clickable = map[image.Point]string{}
+	for y := 0; y < height && md.first+y < len(md.lines); y++ {
+		x := 0
+		for _, c := range md.lines[md.first+y] {
+			if x+runewidth.RuneWidth(c.Rune) > width {
+				break // Lines with full-width runes may exceed narrow canvases.
+			}
+			cells, err := cvs.SetCell(image.Point{x, y}, c.Rune, c.Opts)
+			if err != nil {
+				return err
+			}
+			if url, ok := md.links[c]; ok {
+				for i := 0; i < cells; i++ {
+					md.clickable[image.Point{x + i, y}] = url
+				}
+			}
+			x += cells
+		}
+	}
+	return nil
+}
+
+// scroll scrolls the content by the number of lines, up if negative.
+// Caller must hold md.mu.
+func (md *Markdown) scroll(lines int) {
+	md.first = normalizeFirst(md.first+lines, len(md.lines), md.lastHeight)
+}
+
+// Keyboard scrolls the content.
+// Implements widgetapi.Widget.Keyboard.
+func (md *Markdown) Keyboard(k *terminalapi.Keyboard) error {
+	md.mu.Lock()
+	defer md.mu.Unlock()
+
+	switch k.Key {
+	case md.opts.keyUp:
+		md.scroll(-1)
+	case md.opts.keyDown:
+		md.scroll(1)
+	case md.opts.keyPgUp:
+		md.scroll(-md.lastHeight)
+	case md.opts.keyPgDown:
+		md.scroll(md.lastHeight)
+	}
+	return nil
+}
+
+// Mouse scrolls the content and follows links.
+// Implements widgetapi.Widget.Mouse.
+func (md *Markdown) Mouse(m *terminalapi.Mouse) error {
+	url := md.mouse(m)
+	if url == "" || md.opts.onLinkClick == nil {
+		return nil
+	}
+	// Called without holding md.mu so that the function can use the widget.
+	return md.opts.onLinkClick(url)
+}
+
+// mouse processes the mouse event and returns the destination of the link
+// that was clicked or an empty string if no link was clicked.
+func (md *Markdown) mouse(m *terminalapi.Mouse) string {
+	md.mu.Lock()
+	defer md.mu.Unlock()
+
+	url, onLink := md.clickable[m.Position]
+	switch m.Button {
+	case mouse.ButtonLeft:
+		md.pressed = url
+		return ""
+	case mouse.ButtonRelease:
+		pressed := md.pressed
+		md.pressed = ""
+		if onLink && url == pressed {
+			return url
+		}
+		return ""
+	case md.opts.mouseUpButton:
+		md.scroll(-1)
+	case md.opts.mouseDownButton:
+		md.scroll(1)
+	}
+	md.pressed = ""
+	return ""
+}
+
+// Options implements widgetapi.Widget.Options.
+func (md *Markdown) Options() widgetapi.Options {
+	return widgetapi.Options{
+		MinimumSize:  image.Point{1, 1},
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		WantMouse:    widgetapi.MouseScopeWidget,
+	}
+}
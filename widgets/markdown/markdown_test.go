@@ -0,0 +1,455 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package markdown
+
+import (
+	"errors"
+	"image"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/canvas/testcanvas"
+	"github.com/mum4k/termdash/internal/draw"
+	"github.com/mum4k/termdash/internal/draw/testdraw"
+	"github.com/mum4k/termdash/internal/faketerm"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// text is a text drawn at a point.
+type text struct {
+	p    image.Point
+	text string
+	opts []cell.Option
+}
+
+// mustDraw returns a function that draws the texts on a fake terminal.
+func mustDraw(texts ...text) func(size image.Point) *faketerm.Terminal {
+	return func(size image.Point) *faketerm.Terminal {
+		ft := faketerm.MustNew(size)
+		cvs := testcanvas.MustNew(ft.Area())
+		for _, t := range texts {
+			testdraw.MustText(cvs, t.text, t.p, draw.TextCellOpts(t.opts...))
+		}
+		testcanvas.MustApply(cvs, ft)
+		return ft
+	}
+}
+
+var (
+	gray   = cell.FgColor(cell.ColorNumber(240))
+	yellow = cell.FgColor(cell.ColorYellow)
+)
+
+func TestMarkdown(t *testing.T) {
+	tests := []struct {
+		desc           string
+		opts           []Option
+		canvas         image.Rectangle
+		content        string
+		events         []terminalapi.Event
+		want           func(size image.Point) *faketerm.Terminal
+		wantLinks      []string
+		wantNewErr     bool
+		wantContentErr bool
+	}{
+		{
+			desc:       "fails on duplicate scroll keys",
+			opts:       []Option{ScrollKeys('a', 'a', 'b', 'c')},
+			wantNewErr: true,
+		},
+		{
+			desc:       "fails on duplicate scroll buttons",
+			opts:       []Option{ScrollMouseButtons(mouse.ButtonLeft, mouse.ButtonLeft)},
+			wantNewErr: true,
+		},
+		{
+			desc:           "fails on control characters",
+			content:        "a\x07b",
+			wantContentErr: true,
+		},
+		{
+			desc:    "draws nothing without content",
+			canvas:  image.Rect(0, 0, 5, 2),
+			content: "",
+			want:    mustDraw(),
+		},
+		{
+			desc:    "heading and wrapped paragraph",
+			canvas:  image.Rect(0, 0, 10, 5),
+			content: "Title\n=====\nsome words here to wrap",
+			want: mustDraw(
+				text{image.Point{0, 0}, "Title", []cell.Option{cell.Bold(), cell.Underline(), cell.FgColor(cell.ColorCyan)}},
+				text{image.Point{0, 2}, "some words", nil},
+				text{image.Point{0, 3}, "here to", nil},
+				text{image.Point{0, 4}, "wrap", nil},
+			),
+		},
+		{
+			desc:    "full-width runes wider than the canvas",
+			canvas:  image.Rect(0, 0, 1, 5),
+			content: "世界 a",
+			want: mustDraw(
+				text{image.Point{0, 2}, "a", nil},
+			),
+		},
+		{
+			desc:    "headings with custom color",
+			opts:    []Option{HeadingColor(cell.ColorRed)},
+			canvas:  image.Rect(0, 0, 5, 3),
+			content: "## a\n### b",
+			want: mustDraw(
+				text{image.Point{0, 0}, "a", []cell.Option{cell.Bold(), cell.FgColor(cell.ColorRed)}},
+				text{image.Point{0, 2}, "b", []cell.Option{cell.Bold()}},
+			),
+		},
+		{
+			desc:    "inline styles",
+			canvas:  image.Rect(0, 0, 20, 1),
+			content: "a **b** *c* `d` [e](u)",
+			want: mustDraw(
+				text{image.Point{0, 0}, "a b c d e", nil},
+				text{image.Point{2, 0}, "b", []cell.Option{cell.Bold()}},
+				text{image.Point{4, 0}, "c", []cell.Option{cell.Underline()}},
+				text{image.Point{6, 0}, "d", []cell.Option{yellow}},
+				text{image.Point{8, 0}, "e", []cell.Option{cell.FgColor(cell.ColorBlue), cell.Underline()}},
+			),
+		},
+		{
+			desc:    "hard line break",
+			canvas:  image.Rect(0, 0, 10, 2),
+			content: "a  \nb",
+			want: mustDraw(
+				text{image.Point{0, 0}, "a", nil},
+				text{image.Point{0, 1}, "b", nil},
+			),
+		},
+		{
+			desc:    "lists",
+			canvas:  image.Rect(0, 0, 10, 4),
+			content: "- a\n- b\n\n  9. c\n  10. d",
+			want: mustDraw(
+				text{image.Point{0, 0}, "• a", nil},
+				text{image.Point{0, 1}, "• b", nil},
+				text{image.Point{0, 2}, "   9. c", nil},
+				text{image.Point{0, 3}, "  10. d", nil},
+			),
+		},
+		{
+			desc:    "list items wrap with indentation",
+			canvas:  image.Rect(0, 0, 6, 2),
+			content: "* one two",
+			want: mustDraw(
+				text{image.Point{0, 0}, "• one", nil},
+				text{image.Point{0, 1}, "  two", nil},
+			),
+		},
+		{
+			desc:    "code block and block quote",
+			canvas:  image.Rect(0, 0, 10, 4),
+			content: "```\nx := 1\n```\n> q\n>\n> r",
+			want: mustDraw(
+				text{image.Point{0, 0}, "  ", nil},
+				text{image.Point{2, 0}, "x := 1", []cell.Option{yellow}},
+				text{image.Point{0, 2}, "│ ", []cell.Option{gray}},
+				text{image.Point{2, 2}, "q", nil},
+				text{image.Point{0, 3}, "│ ", []cell.Option{gray}},
+			),
+		},
+		{
+			desc:    "code lines wrap at runes",
+			canvas:  image.Rect(0, 0, 5, 2),
+			content: "    abcdef",
+			want: mustDraw(
+				text{image.Point{0, 0}, "  ", nil},
+				text{image.Point{2, 0}, "abc", []cell.Option{yellow}},
+				text{image.Point{0, 1}, "  ", nil},
+				text{image.Point{2, 1}, "def", []cell.Option{yellow}},
+			),
+		},
+		{
+			desc:    "table",
+			canvas:  image.Rect(0, 0, 12, 3),
+			content: "| a | bb |\n|---|--:|\n| ccc | d |",
+			want: mustDraw(
+				text{image.Point{0, 0}, "a  ", nil},
+				text{image.Point{0, 0}, "a", []cell.Option{cell.Bold()}},
+				text{image.Point{3, 0}, " │ ", []cell.Option{gray}},
+				text{image.Point{6, 0}, "bb", []cell.Option{cell.Bold()}},
+				text{image.Point{0, 1}, "────┼───", []cell.Option{gray}},
+				text{image.Point{0, 2}, "ccc", nil},
+				text{image.Point{3, 2}, " │ ", []cell.Option{gray}},
+				text{image.Point{6, 2}, " d", nil},
+			),
+		},
+		{
+			desc:    "table shrinks the widest column",
+			canvas:  image.Rect(0, 0, 7, 3),
+			content: "| abcdef | x |\n| :-: | --- |\n| ab | y |",
+			want: mustDraw(
+				text{image.Point{0, 0}, "ab…", []cell.Option{cell.Bold()}},
+				text{image.Point{3, 0}, " │ ", []cell.Option{gray}},
+				text{image.Point{6, 0}, "x", []cell.Option{cell.Bold()}},
+				text{image.Point{0, 1}, "────┼──", []cell.Option{gray}},
+				text{image.Point{0, 2}, "ab ", nil},
+				text{image.Point{3, 2}, " │ ", []cell.Option{gray}},
+				text{image.Point{6, 2}, "y", nil},
+			),
+		},
+		{
+			desc:    "thematic break",
+			opts:    []Option{LineColor(cell.ColorGreen)},
+			canvas:  image.Rect(0, 0, 5, 1),
+			content: "***",
+			want: mustDraw(
+				text{image.Point{0, 0}, "─────", []cell.Option{cell.FgColor(cell.ColorGreen)}},
+			),
+		},
+		{
+			desc:    "scrolls down with the keyboard",
+			canvas:  image.Rect(0, 0, 3, 2),
+			content: "a\n\nb\n\nc",
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+			},
+			want: mustDraw(
+				text{image.Point{0, 0}, "b", nil},
+			),
+		},
+		{
+			desc:    "doesn't scroll past the last line",
+			canvas:  image.Rect(0, 0, 3, 2),
+			content: "a\n\nb\n\nc",
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyPgDn},
+				&terminalapi.Keyboard{Key: keyboard.KeyPgDn},
+			},
+			want: mustDraw(
+				text{image.Point{0, 1}, "c", nil},
+			),
+		},
+		{
+			desc:    "scrolls up with the keyboard",
+			canvas:  image.Rect(0, 0, 3, 2),
+			content: "a\n\nb\n\nc",
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyPgDn},
+				&terminalapi.Keyboard{Key: keyboard.KeyPgDn},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowUp},
+				&terminalapi.Keyboard{Key: keyboard.KeyPgUp},
+			},
+			want: mustDraw(
+				text{image.Point{0, 0}, "a", nil},
+			),
+		},
+		{
+			desc:    "scrolls with custom keys and the mouse",
+			opts:    []Option{ScrollKeys('k', 'j', 'u', 'd')},
+			canvas:  image.Rect(0, 0, 3, 2),
+			content: "a\n\nb\n\nc",
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+				&terminalapi.Keyboard{Key: 'd'},
+				&terminalapi.Keyboard{Key: 'k'},
+				&terminalapi.Mouse{Button: mouse.ButtonWheelUp},
+				&terminalapi.Mouse{Button: mouse.ButtonWheelDown},
+			},
+			want: mustDraw(
+				text{image.Point{0, 1}, "b", nil},
+			),
+		},
+		{
+			desc: "clicking on a link calls the function",
+			opts: []Option{
+				OnLinkClick(func(string) error { return nil }),
+			},
+			canvas:  image.Rect(0, 0, 10, 1),
+			content: "x [go](http://go) <a@b>",
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{2, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{3, 0}, Button: mouse.ButtonRelease},
+				// Released outside of the link.
+				&terminalapi.Mouse{Position: image.Point{2, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{4, 0}, Button: mouse.ButtonRelease},
+				// Released on a different link.
+				&terminalapi.Mouse{Position: image.Point{3, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{5, 0}, Button: mouse.ButtonRelease},
+				&terminalapi.Mouse{Position: image.Point{6, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{5, 0}, Button: mouse.ButtonRelease},
+			},
+			want: mustDraw(
+				text{image.Point{0, 0}, "x go a@b", nil},
+				text{image.Point{2, 0}, "go", []cell.Option{cell.FgColor(cell.ColorBlue), cell.Underline()}},
+				text{image.Point{5, 0}, "a@b", []cell.Option{cell.FgColor(cell.ColorBlue), cell.Underline()}},
+			),
+			wantLinks: []string{"http://go", "a@b"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			var gotLinks []string
+			opts := append(tc.opts, OnLinkClick(func(url string) error {
+				gotLinks = append(gotLinks, url)
+				return nil
+			}))
+			md, err := New(opts...)
+			if (err != nil) != tc.wantNewErr {
+				t.Errorf("New => unexpected error: %v, wantNewErr: %v", err, tc.wantNewErr)
+			}
+			if err != nil {
+				return
+			}
+
+			{
+				err := md.SetContent(tc.content)
+				if (err != nil) != tc.wantContentErr {
+					t.Errorf("SetContent => unexpected error: %v, wantContentErr: %v", err, tc.wantContentErr)
+				}
+				if err != nil {
+					return
+				}
+			}
+
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+			if err := md.Draw(c, &widgetapi.Meta{}); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+
+			for _, ev := range tc.events {
+				switch e := ev.(type) {
+				case *terminalapi.Keyboard:
+					if err := md.Keyboard(e); err != nil {
+						t.Fatalf("Keyboard => unexpected error: %v", err)
+					}
+				case *terminalapi.Mouse:
+					if err := md.Mouse(e); err != nil {
+						t.Fatalf("Mouse => unexpected error: %v", err)
+					}
+				default:
+					t.Fatalf("unsupported event type: %T", ev)
+				}
+			}
+
+			c, err = canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+			if err := md.Draw(c, &widgetapi.Meta{}); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+			if diff := pretty.Compare(tc.wantLinks, gotLinks); diff != "" {
+				t.Errorf("OnLinkClick => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSetContentResetsScrolling(t *testing.T) {
+	md, err := New()
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := md.SetContent("a\n\nb\n\nc"); err != nil {
+		t.Fatalf("SetContent => unexpected error: %v", err)
+	}
+	c, err := canvas.New(image.Rect(0, 0, 3, 2))
+	if err != nil {
+		t.Fatalf("canvas.New => unexpected error: %v", err)
+	}
+	if err := md.Draw(c, &widgetapi.Meta{}); err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+	if err := md.Keyboard(&terminalapi.Keyboard{Key: keyboard.KeyPgDn}); err != nil {
+		t.Fatalf("Keyboard => unexpected error: %v", err)
+	}
+	if err := md.SetContent("d\r\ne"); err != nil {
+		t.Fatalf("SetContent => unexpected error: %v", err)
+	}
+	if err := md.Draw(c, &widgetapi.Meta{}); err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+
+	got, err := faketerm.New(c.Size())
+	if err != nil {
+		t.Fatalf("faketerm.New => unexpected error: %v", err)
+	}
+	if err := c.Apply(got); err != nil {
+		t.Fatalf("Apply => unexpected error: %v", err)
+	}
+	want := mustDraw(text{image.Point{0, 0}, "d e", nil})
+	if diff := faketerm.Diff(want(c.Size()), got); diff != "" {
+		t.Errorf("Draw => %v", diff)
+	}
+}
+
+func TestLinkClickError(t *testing.T) {
+	md, err := New(OnLinkClick(func(string) error {
+		return errors.New("failed")
+	}))
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := md.SetContent("[a](b)"); err != nil {
+		t.Fatalf("SetContent => unexpected error: %v", err)
+	}
+	c, err := canvas.New(image.Rect(0, 0, 3, 1))
+	if err != nil {
+		t.Fatalf("canvas.New => unexpected error: %v", err)
+	}
+	if err := md.Draw(c, &widgetapi.Meta{}); err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+	if err := md.Mouse(&terminalapi.Mouse{Button: mouse.ButtonLeft}); err != nil {
+		t.Fatalf("Mouse => unexpected error: %v", err)
+	}
+	if err := md.Mouse(&terminalapi.Mouse{Button: mouse.ButtonRelease}); err == nil {
+		t.Errorf("Mouse => got nil error, want the error from the function")
+	}
+}
+
+func TestOptions(t *testing.T) {
+	md, err := New()
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	want := widgetapi.Options{
+		MinimumSize:  image.Point{1, 1},
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		WantMouse:    widgetapi.MouseScopeWidget,
+	}
+	if diff := pretty.Compare(want, md.Options()); diff != "" {
+		t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary markdowndemo shows the functionality of the markdown widget.
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/termbox"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgets/markdown"
+	"github.com/mum4k/termdash/widgets/text"
+)
+
+// runbook is the displayed document.
+const runbook = "# Disk full on a database host\n" +
+	"\n" +
+	"The alert fires when the **data volume** is more than *90%* full. " +
+	"Follow the steps below and see the [storage guide](https://example.com/storage) " +
+	"for background.\n" +
+	"\n" +
+	"## Triage\n" +
+	"\n" +
+	"1. Check the usage of the volumes:\n" +
+	"\n" +
+	"   ```\n" +
+	"   df -h /var/lib/db\n" +
+	"   ```\n" +
+	"2. Find the largest directories with `du -sh *`.\n" +
+	"3. Decide on the mitigation:\n" +
+	"   - old backups can be removed\n" +
+	"   - logs can be rotated\n" +
+	"     with `logrotate -f`\n" +
+	"\n" +
+	"> Never remove files from the `wal` directory, the database cannot\n" +
+	"> recover without them.\n" +
+	"\n" +
+	"## Thresholds\n" +
+	"\n" +
+	"| Usage | Severity | Action |\n" +
+	"|------:|:--------:|:-------|\n" +
+	"| 80%   | warning  | ticket |\n" +
+	"| 90%   | critical | page   |\n" +
+	"| 95%   | critical | page and escalate |\n" +
+	"\n" +
+	"---\n" +
+	"\n" +
+	"Questions go to <oncall@example.com>.\n"
+
+func main() {
+	t, err := termbox.New()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	status, err := text.New()
+	if err != nil {
+		panic(err)
+	}
+	if err := status.Write("Click on a link."); err != nil {
+		panic(err)
+	}
+
+	md, err := markdown.New(
+		markdown.OnLinkClick(func(url string) error {
+			return status.Write(fmt.Sprintf("Clicked on %s", url), text.WriteReplace())
+		}),
+	)
+	if err != nil {
+		panic(err)
+	}
+	if err := md.SetContent(runbook); err != nil {
+		panic(err)
+	}
+
+	c, err := container.New(
+		t,
+		container.Border(linestyle.Light),
+		container.BorderTitle("PRESS Q TO QUIT"),
+		container.SplitHorizontal(
+			container.Top(
+				container.Border(linestyle.Light),
+				container.BorderTitle("Runbook"),
+				container.PlaceWidget(md),
+			),
+			container.Bottom(
+				container.PlaceWidget(status),
+			),
+			container.SplitPercent(90),
+		),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	quitter := func(k *terminalapi.Keyboard) {
+		if k.Key == 'q' || k.Key == 'Q' {
+			cancel()
+		}
+	}
+
+	if err := termdash.Run(ctx, t, c, termdash.KeyboardSubscriber(quitter)); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package markdown
+
+// options.go contains configurable options for Markdown.
+
+import (
+	"fmt"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// options holds the provided options.
+type options struct {
+	headingColor cell.Color
+	codeColor    cell.Color
+	linkColor    cell.Color
+	quoteColor   cell.Color
+	lineColor    cell.Color
+
+	mouseUpButton   mouse.Button
+	mouseDownButton mouse.Button
+	keyUp           keyboard.Key
+	keyDown         keyboard.Key
+	keyPgUp         keyboard.Key
+	keyPgDown       keyboard.Key
+
+	onLinkClick LinkClickFn
+}
+
+// validate validates the provided options.
+func (o *options) validate() error {
+	keys := map[keyboard.Key]bool{
+		o.keyUp:     true,
+		o.keyDown:   true,
+		o.keyPgUp:   true,
+		o.keyPgDown: true,
+	}
+	if len(keys) != 4 {
+		return fmt.Errorf("invalid ScrollKeys(up:%v, down:%v, pageUp:%v, pageDown:%v), the keys must be unique", o.keyUp, o.keyDown, o.keyPgUp, o.keyPgDown)
+	}
+	if o.mouseUpButton == o.mouseDownButton {
+		return fmt.Errorf("invalid ScrollMouseButtons(up:%v, down:%v), the buttons must be unique", o.mouseUpButton, o.mouseDownButton)
+	}
+	return nil
+}
+
+// newOptions returns options with the default values set.
+func newOptions() *options {
+	return &options{
+		headingColor:    cell.ColorCyan,
+		codeColor:       cell.ColorYellow,
+		linkColor:       cell.ColorBlue,
+		quoteColor:      cell.ColorNumber(240),
+		lineColor:       cell.ColorNumber(240),
+		mouseUpButton:   DefaultScrollMouseButtonUp,
+		mouseDownButton: DefaultScrollMouseButtonDown,
+		keyUp:           DefaultScrollKeyUp,
+		keyDown:         DefaultScrollKeyDown,
+		keyPgUp:         DefaultScrollKeyPageUp,
+		keyPgDown:       DefaultScrollKeyPageDown,
+	}
+}
+
+// HeadingColor sets the color of the text of level one and two headings.
+// All headings are bold, level one headings are also underlined.
+// Defaults to cell.ColorCyan.
+func HeadingColor(c cell.Color) Option {
+	return option(func(opts *options) {
+		opts.headingColor = c
+	})
+}
+
+// CodeColor sets the color of code spans and code blocks.
+// Defaults to cell.ColorYellow.
+func CodeColor(c cell.Color) Option {
+	return option(func(opts *options) {
+		opts.codeColor = c
+	})
+}
+
+// LinkColor sets the color of links, which are also underlined.
+// Defaults to cell.ColorBlue.
+func LinkColor(c cell.Color) Option {
+	return option(func(opts *options) {
+		opts.linkColor = c
+	})
+}
+
+// QuoteColor sets the color of the bar on the left of block quotes.
+// Defaults to cell.ColorNumber(240).
+func QuoteColor(c cell.Color) Option {
+	return option(func(opts *options) {
+		opts.quoteColor = c
+	})
+}
+
+// LineColor sets the color of thematic breaks and of the lines of tables.
+// Defaults to cell.ColorNumber(240).
+func LineColor(c cell.Color) Option {
+	return option(func(opts *options) {
+		opts.lineColor = c
+	})
+}
+
+// The default mouse buttons for content scrolling.
+const (
+	DefaultScrollMouseButtonUp   = mouse.ButtonWheelUp
+	DefaultScrollMouseButtonDown = mouse.ButtonWheelDown
+)
+
+// ScrollMouseButtons configures the mouse buttons that scroll the content.
+// The provided buttons must be unique, e.g. the same button cannot be both up
+// and down.
+func ScrollMouseButtons(up, down mouse.Button) Option {
+	return option(func(opts *options) {
+		opts.mouseUpButton = up
+		opts.mouseDownButton = down
+	})
+}
+
+// The default keys for content scrolling.
+const (
+	DefaultScrollKeyUp       = keyboard.KeyArrowUp
+	DefaultScrollKeyDown     = keyboard.KeyArrowDown
+	DefaultScrollKeyPageUp   = keyboard.KeyPgUp
+	DefaultScrollKeyPageDown = keyboard.KeyPgDn
+)
+
+// ScrollKeys configures the keyboard keys that scroll the content.
+// The provided keys must be unique, e.g. the same key cannot be both up and
+// down.
+func ScrollKeys(up, down, pageUp, pageDown keyboard.Key) Option {
+	return option(func(opts *options) {
+		opts.keyUp = up
+		opts.keyDown = down
+		opts.keyPgUp = pageUp
+		opts.keyPgDown = pageDown
+	})
+}
+
+// LinkClickFn is called with the destination of a link when the user clicks
+// on it.
+type LinkClickFn func(url string) error
+
+// OnLinkClick sets a function that is called when the user clicks on a link
+// with the left mouse button. The function is called synchronously and must
+// not block. An error returned by the function is fatal, see
+// termdash.ErrorHandler.
+func OnLinkClick(fn LinkClickFn) Option {
+	return option(func(opts *options) {
+		opts.onLinkClick = fn
+	})
+}
@@ -0,0 +1,868 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package markdown
+
+// parse.go contains a parser of a subset of CommonMark and of the tables
+// from GitHub Flavored Markdown.
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// blockKind identifies the kind of a block.
+type blockKind int
+
+const (
+	blockParagraph blockKind = iota
+	blockHeading
+	blockCode
+	blockQuote
+	blockList
+	blockTable
+	blockRule
+)
+
+// align is the alignment of a table column.
+type align int
+
+const (
+	alignLeft align = iota
+	alignCenter
+	alignRight
+)
+
+// block is a parsed block of the document.
+type block struct {
+	// kind is the kind of the block.
+	kind blockKind
+
+	// text is the inline text of paragraphs and headings.
+	text string
+	// level is the level of headings, one to six.
+	level int
+
+	// lines are the lines of code blocks.
+	lines []string
+
+	// children are the blocks of block quotes.
+	children []*block
+
+	// items are the blocks of each item of lists.
+	items [][]*block
+	// ordered indicates that the list is numbered.
+	ordered bool
+	// start is the number of the first item of ordered lists.
+	start int
+
+	// header are the inline texts of the header cells of tables.
+	header []string
+	// aligns are the alignments of the columns of tables.
+	aligns []align
+	// rows are the inline texts of the body cells of tables.
+	rows [][]string
+}
+
+// parseBlocks parses the lines into blocks.
+func parseBlocks(lines []string) []*block {
+	var blocks []*block
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		var b *block
+		switch {
+		case isBlank(line):
+			i++
+			continue
+
+		case indentOf(line) >= 4:
+			b, i = parseIndentedCode(lines, i)
+
+		case isFence(line):
+			b, i = parseFencedCode(lines, i)
+
+		case isATXHeading(line):
+			b, i = parseATXHeading(line), i+1
+
+		case isRule(line):
+			b, i = &block{kind: blockRule}, i+1
+
+		case isQuote(line):
+			b, i = parseQuote(lines, i)
+
+		case isListItem(line):
+			b, i = parseList(lines, i)
+
+		case isTable(lines, i):
+			b, i = parseTable(lines, i)
+
+		default:
+			b, i = parseParagraph(lines, i)
+		}
+		blocks = append(blocks, b)
+	}
+	return blocks
+}
+
+// indentOf returns the number of leading space characters on the line.
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// isBlank determines if the line contains only space characters.
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+// interruptsParagraph determines if the line starts a new block when it
+// follows a line of a paragraph.
+func interruptsParagraph(line string) bool {
+	if isBlank(line) || indentOf(line) >= 4 {
+		return isBlank(line)
+	}
+	if isFence(line) || isATXHeading(line) || isRule(line) || isQuote(line) {
+		return true
+	}
+	if m, ok := listMarker(line); ok {
+		// Only lists with content and ordered lists starting at one can
+		// interrupt a paragraph.
+		return !isBlank(itemContent(line, m.contentIndent)) && (!m.ordered || m.start == 1)
+	}
+	return false
+}
+
+// parseParagraph parses a paragraph starting on the line at index i.
+// Returns the block and the index of the first line after it.
+func parseParagraph(lines []string, i int) (*block, int) {
+	text := []string{strings.TrimLeft(lines[i], " ")}
+	for i++; i < len(lines); i++ {
+		line := lines[i]
+		if level := setextLevel(line); level > 0 {
+			return &block{
+				kind:  blockHeading,
+				level: level,
+				text:  strings.TrimSpace(strings.Join(text, "\n")),
+			}, i + 1
+		}
+		if interruptsParagraph(line) {
+			break
+		}
+		// Keep trailing space characters that indicate a hard line break.
+		text = append(text, strings.TrimLeft(line, " "))
+	}
+	return &block{
+		kind: blockParagraph,
+		text: strings.TrimSpace(strings.Join(text, "\n")),
+	}, i
+}
+
+// setextLevel returns the level of the heading if the line underlines a
+// setext heading or zero if it doesn't.
+func setextLevel(line string) int {
+	if indentOf(line) >= 4 {
+		return 0
+	}
+	l := strings.TrimSpace(line)
+	switch {
+	case l == "":
+		return 0
+	case strings.Trim(l, "=") == "":
+		return 1
+	case strings.Trim(l, "-") == "":
+		return 2
+	}
+	return 0
+}
+
+// isATXHeading determines if the line is a heading starting with '#'.
+func isATXHeading(line string) bool {
+	if indentOf(line) >= 4 {
+		return false
+	}
+	l := strings.TrimLeft(line, " ")
+	n := len(l) - len(strings.TrimLeft(l, "#"))
+	return n >= 1 && n <= 6 && (len(l) == n || l[n] == ' ')
+}
+
+// parseATXHeading parses a heading starting with '#'.
+func parseATXHeading(line string) *block {
+	l := strings.TrimSpace(line)
+	rest := strings.TrimLeft(l, "#")
+	level := len(l) - len(rest)
+
+	// Remove the optional closing sequence.
+	rest = strings.TrimSpace(rest)
+	if trimmed := strings.TrimRight(rest, "#"); trimmed == "" || strings.HasSuffix(trimmed, " ") {
+		rest = strings.TrimSpace(trimmed)
+	}
+	return &block{
+		kind:  blockHeading,
+		level: level,
+		text:  rest,
+	}
+}
+
+// isRule determines if the line is a thematic break, e.g. "---".
+func isRule(line string) bool {
+	if indentOf(line) >= 4 {
+		return false
+	}
+	l := strings.Replace(strings.TrimSpace(line), " ", "", -1)
+	if len(l) < 3 {
+		return false
+	}
+	c := l[0]
+	return (c == '-' || c == '*' || c == '_') && strings.Trim(l, string(c)) == ""
+}
+
+// fence returns the character and the length of a code fence on the line
+// or zero length if the line isn't a code fence.
+func fence(line string) (byte, int) {
+	if indentOf(line) >= 4 {
+		return 0, 0
+	}
+	l := strings.TrimLeft(line, " ")
+	if l == "" || (l[0] != '`' && l[0] != '~') {
+		return 0, 0
+	}
+	c := l[0]
+	n := len(l) - len(strings.TrimLeft(l, string(c)))
+	if n < 3 || (c == '`' && strings.ContainsRune(l[n:], '`')) {
+		return 0, 0
+	}
+	return c, n
+}
+
+// isFence determines if the line opens a fenced code block.
+func isFence(line string) bool {
+	_, n := fence(line)
+	return n > 0
+}
+
+// parseFencedCode parses a fenced code block starting on the line at index
+// i. Returns the block and the index of the first line after it.
+func parseFencedCode(lines []string, i int) (*block, int) {
+	c, n := fence(lines[i])
+	indent := indentOf(lines[i])
+	b := &block{kind: blockCode}
+	for i++; i < len(lines); i++ {
+		line := lines[i]
+		if fc, fn := fence(line); fc == c && fn >= n && isBlank(strings.TrimLeft(line, " ")[fn:]) {
+			return b, i + 1
+		}
+		strip := indentOf(line)
+		if strip > indent {
+			strip = indent
+		}
+		b.lines = append(b.lines, line[strip:])
+	}
+	// Unclosed code blocks continue until the end of the document.
+	return b, i
+}
+
+// parseIndentedCode parses a code block indented by four spaces starting on
+// the line at index i. Returns the block and the index of the first line
+// after it.
+func parseIndentedCode(lines []string, i int) (*block, int) {
+	b := &block{kind: blockCode}
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case isBlank(line):
+			b.lines = append(b.lines, "")
+		case indentOf(line) >= 4:
+			b.lines = append(b.lines, line[4:])
+		default:
+			return trimCode(b), i
+		}
+	}
+	return trimCode(b), i
+}
+
+// trimCode removes trailing blank lines from the code block.
+func trimCode(b *block) *block {
+	for len(b.lines) > 0 && b.lines[len(b.lines)-1] == "" {
+		b.lines = b.lines[:len(b.lines)-1]
+	}
+	return b
+}
+
+// quoteContent returns the content of a block quote line without the '>'
+// marker and true or false if the line isn't part of a block quote.
+func quoteContent(line string) (string, bool) {
+	if indentOf(line) >= 4 {
+		return "", false
+	}
+	l := strings.TrimLeft(line, " ")
+	if !strings.HasPrefix(l, ">") {
+		return "", false
+	}
+	l = l[1:]
+	if strings.HasPrefix(l, " ") {
+		l = l[1:]
+	}
+	return l, true
+}
+
+// isQuote determines if the line starts a block quote.
+func isQuote(line string) bool {
+	_, ok := quoteContent(line)
+	return ok
+}
+
+// parseQuote parses a block quote starting on the line at index i.
+// Returns the block and the index of the first line after it.
+func parseQuote(lines []string, i int) (*block, int) {
+	var content []string
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if c, ok := quoteContent(line); ok {
+			content = append(content, c)
+			continue
+		}
+		// Lazy continuation lines of a paragraph don't need the marker.
+		if len(content) > 0 && !isBlank(content[len(content)-1]) && !interruptsParagraph(line) {
+			content = append(content, line)
+			continue
+		}
+		break
+	}
+	return &block{
+		kind:     blockQuote,
+		children: parseBlocks(content),
+	}, i
+}
+
+// marker is the marker of a list item.
+type marker struct {
+	// ordered indicates a numbered item.
+	ordered bool
+	// start is the number of a numbered item.
+	start int
+	// delim is the bullet character or the delimiter after the number.
+	delim byte
+	// contentIndent is the indentation of the content of the item.
+	contentIndent int
+}
+
+// listMarker returns the marker of the list item that starts on the line and
+// true or false if the line doesn't start a list item.
+func listMarker(line string) (marker, bool) {
+	indent := indentOf(line)
+	if indent >= 4 {
+		return marker{}, false
+	}
+	l := line[indent:]
+
+	var m marker
+	var width int
+	switch {
+	case l == "":
+		return marker{}, false
+	case l[0] == '-' || l[0] == '*' || l[0] == '+':
+		m.delim = l[0]
+		width = 1
+	default:
+		digits := len(l) - len(strings.TrimLeft(l, "0123456789"))
+		if digits == 0 || digits > 9 || digits == len(l) || (l[digits] != '.' && l[digits] != ')') {
+			return marker{}, false
+		}
+		n, err := strconv.Atoi(l[:digits])
+		if err != nil {
+			return marker{}, false
+		}
+		m.ordered, m.start, m.delim = true, n, l[digits]
+		width = digits + 1
+	}
+
+	rest := l[width:]
+	if rest != "" && rest[0] != ' ' {
+		return marker{}, false
+	}
+	spaces := indentOf(rest)
+	if spaces == 0 || spaces > 4 || spaces == len(rest) {
+		// Content indented by more than four spaces starts with an indented
+		// code block, so only one space belongs to the marker.
+		spaces = 1
+	}
+	m.contentIndent = indent + width + spaces
+	return m, true
+}
+
+// isListItem determines if the line starts a list item.
+func isListItem(line string) bool {
+	_, ok := listMarker(line)
+	return ok && !isRule(line)
+}
+
+// sameList determines if the markers belong to items of the same list.
+func sameList(a, b marker) bool {
+	return a.ordered == b.ordered && a.delim == b.delim
+}
+
+// itemContent returns the line without the indentation of item content.
+func itemContent(line string, indent int) string {
+	if len(line) < indent {
+		return ""
+	}
+	return line[indent:]
+}
+
+// parseList parses a list starting on the line at index i.
+// Returns the block and the index of the first line after it.
+func parseList(lines []string, i int) (*block, int) {
+	first, _ := listMarker(lines[i])
+	b := &block{
+		kind:    blockList,
+		ordered: first.ordered,
+		start:   first.start,
+	}
+
+	for i < len(lines) {
+		m, _ := listMarker(lines[i])
+		content := []string{itemContent(lines[i], m.contentIndent)}
+		j := i + 1
+	item:
+		for ; j < len(lines); j++ {
+			line := lines[j]
+			switch {
+			case isBlank(line):
+				k := j
+				for k < len(lines) && isBlank(lines[k]) {
+					k++
+				}
+				if k == len(lines) || indentOf(lines[k]) < m.contentIndent {
+					break item
+				}
+				content = append(content, "")
+
+			case indentOf(line) >= m.contentIndent:
+				content = append(content, itemContent(line, m.contentIndent))
+
+			case !isBlank(content[len(content)-1]) && !interruptsParagraph(line) && !isListItem(line):
+				// A lazy continuation line of a paragraph.
+				content = append(content, line)
+
+			default:
+				break item
+			}
+		}
+		b.items = append(b.items, parseBlocks(content))
+
+		// Blank lines may separate the items.
+		k := j
+		for k < len(lines) && isBlank(lines[k]) {
+			k++
+		}
+		next, ok := listMarker(itemLine(lines, k))
+		if !ok || !sameList(first, next) || isRule(lines[k]) {
+			return b, j
+		}
+		i = k
+	}
+	return b, i
+}
+
+// itemLine returns the line at the index or an empty line if the index is
+// out of range.
+func itemLine(lines []string, i int) string {
+	if i >= len(lines) {
+		return ""
+	}
+	return lines[i]
+}
+
+// splitRow splits a line of a table into the trimmed content of its cells.
+func splitRow(line string) []string {
+	l := strings.TrimSpace(line)
+	l = strings.TrimPrefix(l, "|")
+	if strings.HasSuffix(l, "|") && !strings.HasSuffix(l, `\|`) {
+		l = l[:len(l)-1]
+	}
+
+	var cells []string
+	var cur strings.Builder
+	for i := 0; i < len(l); i++ {
+		switch {
+		case l[i] == '\\' && i+1 < len(l) && l[i+1] == '|':
+			cur.WriteByte('|')
+			i++
+		case l[i] == '|':
+			cells = append(cells, strings.TrimSpace(cur.String()))
+			cur.Reset()
+		default:
+			cur.WriteByte(l[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cur.String()))
+}
+
+// delimiterAligns returns the column alignments if the line is the delimiter
+// row of a table, e.g. "| :--- | ---: |", or nil otherwise.
+func delimiterAligns(line string) []align {
+	if indentOf(line) >= 4 || !strings.ContainsAny(line, "|-") {
+		return nil
+	}
+	var aligns []align
+	for _, c := range splitRow(line) {
+		left := strings.HasPrefix(c, ":")
+		right := strings.HasSuffix(c, ":")
+		dashes := strings.Trim(c, ":")
+		if dashes == "" || strings.Trim(dashes, "-") != "" {
+			return nil
+		}
+		switch {
+		case left && right:
+			aligns = append(aligns, alignCenter)
+		case right:
+			aligns = append(aligns, alignRight)
+		default:
+			aligns = append(aligns, alignLeft)
+		}
+	}
+	return aligns
+}
+
+// isTable determines if a table starts on the line at index i.
+func isTable(lines []string, i int) bool {
+	if i+1 >= len(lines) || !strings.Contains(lines[i], "|") || indentOf(lines[i]) >= 4 {
+		return false
+	}
+	aligns := delimiterAligns(lines[i+1])
+	return aligns != nil && len(aligns) == len(splitRow(lines[i]))
+}
+
+// parseTable parses a table starting on the line at index i.
+// Returns the block and the index of the first line after it.
+func parseTable(lines []string, i int) (*block, int) {
+	b := &block{
+		kind:   blockTable,
+		header: splitRow(lines[i]),
+		aligns: delimiterAligns(lines[i+1]),
+	}
+	for i += 2; i < len(lines); i++ {
+		line := lines[i]
+		if isBlank(line) || !strings.Contains(line, "|") {
+			break
+		}
+		row := splitRow(line)
+		for len(row) < len(b.header) {
+			row = append(row, "")
+		}
+		b.rows = append(b.rows, row[:len(b.header)])
+	}
+	return b, i
+}
+
+// inlineStyle is the style of inline text.
+type inlineStyle struct {
+	// strong indicates strong emphasis, e.g. "**text**".
+	strong bool
+	// emph indicates emphasis, e.g. "*text*".
+	emph bool
+	// code indicates a code span, e.g. "`text`".
+	code bool
+}
+
+// inline is a part of inline text with the same style.
+type inline struct {
+	// text is the text of the part.
+	text string
+	// style is the style of the text.
+	style inlineStyle
+	// url is the destination if the text is a link.
+	url string
+}
+
+// inlineParser parses inline text.
+type inlineParser struct {
+	// res are the parsed parts.
+	res []inline
+}
+
+// parseInline parses the inline text of a paragraph or a heading.
+// Newline characters that represent hard line breaks are preserved, soft
+// line breaks are replaced with space characters.
+func parseInline(text string) []inline {
+	p := &inlineParser{}
+	p.parse([]rune(text), inlineStyle{}, "")
+	return p.res
+}
+
+// emit adds the text to the parsed parts, merging it with the last part if
+// they have the same style.
+func (p *inlineParser) emit(text string, style inlineStyle, url string) {
+	if text == "" {
+		return
+	}
+	if n := len(p.res); n > 0 && p.res[n-1].style == style && p.res[n-1].url == url {
+		p.res[n-1].text += text
+		return
+	}
+	p.res = append(p.res, inline{text: text, style: style, url: url})
+}
+
+// isPunct determines if the rune is an ASCII punctuation character that can
+// be escaped with a backslash.
+func isPunct(r rune) bool {
+	return r < utf8.RuneSelf && unicode.IsPunct(r) || strings.ContainsRune("$+<=>^`|~", r)
+}
+
+// runLen returns the number of repeated runes starting at index i.
+func runLen(rs []rune, i int) int {
+	n := 0
+	for i+n < len(rs) && rs[i+n] == rs[i] {
+		n++
+	}
+	return n
+}
+
+// parse parses the runes with the style and the link destination.
+func (p *inlineParser) parse(rs []rune, style inlineStyle, url string) {
+	var buf []rune
+	flush := func() {
+		p.emit(string(buf), style, url)
+		buf = buf[:0]
+	}
+
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		switch {
+		case r == '\\' && i+1 < len(rs) && rs[i+1] == '\n':
+			buf = append(buf, '\n')
+			i++
+
+		case r == '\\' && i+1 < len(rs) && isPunct(rs[i+1]):
+			buf = append(buf, rs[i+1])
+			i++
+
+		case r == '\n':
+			end := len(buf)
+			for end > 0 && buf[end-1] == ' ' {
+				end--
+			}
+			hard := len(buf)-end >= 2
+			buf = buf[:end]
+			if hard {
+				buf = append(buf, '\n')
+			} else {
+				buf = append(buf, ' ')
+			}
+
+		case r == '`':
+			n := runLen(rs, i)
+			end := closingBackticks(rs, i+n, n)
+			if end < 0 {
+				buf = append(buf, rs[i:i+n]...)
+				i += n - 1
+				continue
+			}
+			flush()
+			p.emit(codeSpan(rs[i+n:end]), inlineStyle{code: true}, url)
+			i = end + n - 1
+
+		case (r == '[' || r == '!' && i+1 < len(rs) && rs[i+1] == '[') && url == "":
+			start := i
+			if r == '!' {
+				start++
+			}
+			textEnd, dest, end, ok := link(rs, start)
+			if !ok {
+				buf = append(buf, r)
+				continue
+			}
+			flush()
+			p.parse(rs[start+1:textEnd], style, dest)
+			i = end
+
+		case r == '<' && url == "":
+			dest, end, ok := autolink(rs, i)
+			if !ok {
+				buf = append(buf, r)
+				continue
+			}
+			flush()
+			p.emit(dest, style, dest)
+			i = end
+
+		case r == '*' || r == '_':
+			n := runLen(rs, i)
+			k, end := emphasis(rs, i, n)
+			if k == 0 {
+				buf = append(buf, rs[i:i+n]...)
+				i += n - 1
+				continue
+			}
+			// Any extra delimiter runes are literal.
+			buf = append(buf, rs[i:i+n-k]...)
+			flush()
+			inner := style
+			switch k {
+			case 1:
+				inner.emph = true
+			case 2:
+				inner.strong = true
+			default:
+				inner.emph, inner.strong = true, true
+			}
+			p.parse(rs[i+n:end], inner, url)
+			i = end + k - 1
+
+		default:
+			buf = append(buf, r)
+		}
+	}
+	flush()
+}
+
+// closingBackticks returns the index of the run of exactly n backticks that
+// closes a code span starting at index i or -1 if there isn't one.
+func closingBackticks(rs []rune, i, n int) int {
+	for i < len(rs) {
+		if rs[i] != '`' {
+			i++
+			continue
+		}
+		l := runLen(rs, i)
+		if l == n {
+			return i
+		}
+		i += l
+	}
+	return -1
+}
+
+// codeSpan returns the content of a code span.
+func codeSpan(rs []rune) string {
+	s := strings.Replace(string(rs), "\n", " ", -1)
+	if len(s) >= 2 && s[0] == ' ' && s[len(s)-1] == ' ' && strings.TrimSpace(s) != "" {
+		s = s[1 : len(s)-1]
+	}
+	return s
+}
+
+// link parses a link whose text starts with '[' at index i, e.g.
+// "[text](url)". Returns the index of the closing ']', the destination, the
+// index of the closing ')' and true if the runes form a link.
+func link(rs []rune, i int) (int, string, int, bool) {
+	depth := 0
+	textEnd := -1
+	for j := i; j < len(rs) && textEnd < 0; j++ {
+		switch rs[j] {
+		case '\\':
+			j++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				textEnd = j
+			}
+		}
+	}
+	if textEnd < 0 || textEnd+1 >= len(rs) || rs[textEnd+1] != '(' {
+		return 0, "", 0, false
+	}
+
+	depth = 0
+	for j := textEnd + 1; j < len(rs); j++ {
+		switch rs[j] {
+		case '\\':
+			j++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				fields := strings.Fields(string(rs[textEnd+2 : j]))
+				if len(fields) == 0 {
+					return 0, "", 0, false
+				}
+				// The optional title after the destination is ignored.
+				dest := strings.TrimSuffix(strings.TrimPrefix(fields[0], "<"), ">")
+				return textEnd, dest, j, true
+			}
+		}
+	}
+	return 0, "", 0, false
+}
+
+// autolink parses an autolink starting with '<' at index i, e.g.
+// "<https://example.com>". Returns the destination, the index of the
+// closing '>' and true if the runes form an autolink.
+func autolink(rs []rune, i int) (string, int, bool) {
+	for j := i + 1; j < len(rs); j++ {
+		switch r := rs[j]; {
+		case r == '>':
+			dest := string(rs[i+1 : j])
+			if strings.Contains(dest, "://") || strings.HasPrefix(dest, "mailto:") || strings.Contains(dest, "@") {
+				return dest, j, true
+			}
+			return "", 0, false
+		case unicode.IsSpace(r) || r == '<':
+			return "", 0, false
+		}
+	}
+	return "", 0, false
+}
+
+// emphasis finds the end of emphasis opened by the run of n delimiter runes
+// at index i. Returns the number of delimiter runes used, one for emphasis,
+// two for strong emphasis or three for both, and the index of the closing
+// run. Returns zero if the run doesn't open emphasis.
+func emphasis(rs []rune, i, n int) (int, int) {
+	c := rs[i]
+	after := i + n
+	if after >= len(rs) || unicode.IsSpace(rs[after]) {
+		return 0, 0
+	}
+	if c == '_' && i > 0 && isWordRune(rs[i-1]) {
+		// Underscores within words don't open emphasis.
+		return 0, 0
+	}
+
+	k := n
+	if k > 3 {
+		k = 3
+	}
+	for j := after; j < len(rs); j++ {
+		switch {
+		case rs[j] == '\\':
+			j++
+		case rs[j] == '`':
+			// Delimiters within code spans don't close emphasis.
+			l := runLen(rs, j)
+			if end := closingBackticks(rs, j+l, l); end >= 0 {
+				j = end + l - 1
+			} else {
+				j += l - 1
+			}
+		case rs[j] == c:
+			l := runLen(rs, j)
+			closes := !unicode.IsSpace(rs[j-1]) && (c == '*' || j+l >= len(rs) || !isWordRune(rs[j+l]))
+			if closes && l == k {
+				return k, j
+			}
+			j += l - 1
+		}
+	}
+	return 0, 0
+}
+
+// isWordRune determines if the rune is a letter or a digit.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
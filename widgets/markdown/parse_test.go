@@ -0,0 +1,424 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestParseBlocks(t *testing.T) {
+	tests := []struct {
+		desc string
+		text string
+		want []*block
+	}{
+		{
+			desc: "empty document",
+			text: "",
+		},
+		{
+			desc: "paragraphs",
+			text: "first line\nsecond line\n\n  next paragraph  ",
+			want: []*block{
+				{kind: blockParagraph, text: "first line\nsecond line"},
+				{kind: blockParagraph, text: "next paragraph"},
+			},
+		},
+		{
+			desc: "paragraph keeps trailing space of hard line breaks",
+			text: "line  \nnext",
+			want: []*block{
+				{kind: blockParagraph, text: "line  \nnext"},
+			},
+		},
+		{
+			desc: "ATX headings",
+			text: "# One\n## Two ##\n###### Six\n####### Seven\n#NoSpace",
+			want: []*block{
+				{kind: blockHeading, level: 1, text: "One"},
+				{kind: blockHeading, level: 2, text: "Two"},
+				{kind: blockHeading, level: 6, text: "Six"},
+				{kind: blockParagraph, text: "####### Seven\n#NoSpace"},
+			},
+		},
+		{
+			desc: "setext headings",
+			text: "One\n===\n\nTwo\nlines\n---",
+			want: []*block{
+				{kind: blockHeading, level: 1, text: "One"},
+				{kind: blockHeading, level: 2, text: "Two\nlines"},
+			},
+		},
+		{
+			desc: "heading interrupts a paragraph",
+			text: "text\n# Heading",
+			want: []*block{
+				{kind: blockParagraph, text: "text"},
+				{kind: blockHeading, level: 1, text: "Heading"},
+			},
+		},
+		{
+			desc: "thematic breaks",
+			text: "---\n* * *\n___",
+			want: []*block{
+				{kind: blockRule},
+				{kind: blockRule},
+				{kind: blockRule},
+			},
+		},
+		{
+			desc: "fenced code block",
+			text: "  ```go\n  func main() {\n    fmt.Println()\n }\n  ```\nafter",
+			want: []*block{
+				{kind: blockCode, lines: []string{"func main() {", "  fmt.Println()", "}"}},
+				{kind: blockParagraph, text: "after"},
+			},
+		},
+		{
+			desc: "fenced code block with tildes and a longer closing fence",
+			text: "~~~\n```\n~~~~",
+			want: []*block{
+				{kind: blockCode, lines: []string{"```"}},
+			},
+		},
+		{
+			desc: "unclosed fenced code block",
+			text: "```\ncode",
+			want: []*block{
+				{kind: blockCode, lines: []string{"code"}},
+			},
+		},
+		{
+			desc: "indented code block",
+			text: "    a\n\n      b\n\n\nc",
+			want: []*block{
+				{kind: blockCode, lines: []string{"a", "", "  b"}},
+				{kind: blockParagraph, text: "c"},
+			},
+		},
+		{
+			desc: "indented lines continue a paragraph",
+			text: "a\n    b",
+			want: []*block{
+				{kind: blockParagraph, text: "a\nb"},
+			},
+		},
+		{
+			desc: "block quote with lazy continuation",
+			text: "> # Title\n> quoted\nlazy\n\nafter",
+			want: []*block{
+				{
+					kind: blockQuote,
+					children: []*block{
+						{kind: blockHeading, level: 1, text: "Title"},
+						{kind: blockParagraph, text: "quoted\nlazy"},
+					},
+				},
+				{kind: blockParagraph, text: "after"},
+			},
+		},
+		{
+			desc: "nested block quotes",
+			text: "> a\n>> b",
+			want: []*block{
+				{
+					kind: blockQuote,
+					children: []*block{
+						{kind: blockParagraph, text: "a"},
+						{
+							kind: blockQuote,
+							children: []*block{
+								{kind: blockParagraph, text: "b"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc: "unordered list",
+			text: "- a\n- b\n  continued\n\n- c\nlazy",
+			want: []*block{
+				{
+					kind: blockList,
+					items: [][]*block{
+						{{kind: blockParagraph, text: "a"}},
+						{{kind: blockParagraph, text: "b\ncontinued"}},
+						{{kind: blockParagraph, text: "c\nlazy"}},
+					},
+				},
+			},
+		},
+		{
+			desc: "ordered list",
+			text: "3. a\n4. b",
+			want: []*block{
+				{
+					kind:    blockList,
+					ordered: true,
+					start:   3,
+					items: [][]*block{
+						{{kind: blockParagraph, text: "a"}},
+						{{kind: blockParagraph, text: "b"}},
+					},
+				},
+			},
+		},
+		{
+			desc: "different bullet starts a new list",
+			text: "- a\n* b",
+			want: []*block{
+				{
+					kind:  blockList,
+					items: [][]*block{{{kind: blockParagraph, text: "a"}}},
+				},
+				{
+					kind:  blockList,
+					items: [][]*block{{{kind: blockParagraph, text: "b"}}},
+				},
+			},
+		},
+		{
+			desc: "nested lists and blocks in items",
+			text: "1. a\n   - b\n\n   ```\n   code\n   ```\n2. c",
+			want: []*block{
+				{
+					kind:    blockList,
+					ordered: true,
+					start:   1,
+					items: [][]*block{
+						{
+							{kind: blockParagraph, text: "a"},
+							{
+								kind:  blockList,
+								items: [][]*block{{{kind: blockParagraph, text: "b"}}},
+							},
+							{kind: blockCode, lines: []string{"code"}},
+						},
+						{{kind: blockParagraph, text: "c"}},
+					},
+				},
+			},
+		},
+		{
+			desc: "list ends at unindented paragraph after a blank line",
+			text: "- a\n\nafter",
+			want: []*block{
+				{
+					kind:  blockList,
+					items: [][]*block{{{kind: blockParagraph, text: "a"}}},
+				},
+				{kind: blockParagraph, text: "after"},
+			},
+		},
+		{
+			desc: "ordered list not starting at one doesn't interrupt a paragraph",
+			text: "in 2019\n2. place",
+			want: []*block{
+				{kind: blockParagraph, text: "in 2019\n2. place"},
+			},
+		},
+		{
+			desc: "empty bullet item doesn't interrupt a paragraph",
+			text: "a\n*",
+			want: []*block{
+				{kind: blockParagraph, text: "a\n*"},
+			},
+		},
+		{
+			desc: "empty plus item doesn't interrupt a paragraph",
+			text: "a\n+",
+			want: []*block{
+				{kind: blockParagraph, text: "a\n+"},
+			},
+		},
+		{
+			desc: "empty ordered item doesn't interrupt a paragraph",
+			text: "a\n1.",
+			want: []*block{
+				{kind: blockParagraph, text: "a\n1."},
+			},
+		},
+		{
+			desc: "empty parenthesis item doesn't interrupt a paragraph",
+			text: "Steps:\n1)",
+			want: []*block{
+				{kind: blockParagraph, text: "Steps:\n1)"},
+			},
+		},
+		{
+			desc: "table",
+			text: "| Name | Size | Note |\n|:-----|-----:|:----:|\n| a | 1 | x \\| y |\n| b |\n\nafter",
+			want: []*block{
+				{
+					kind:   blockTable,
+					header: []string{"Name", "Size", "Note"},
+					aligns: []align{alignLeft, alignRight, alignCenter},
+					rows: [][]string{
+						{"a", "1", "x | y"},
+						{"b", "", ""},
+					},
+				},
+				{kind: blockParagraph, text: "after"},
+			},
+		},
+		{
+			desc: "table without outer pipes",
+			text: "a | b\n--- | ---\n1 | 2 | 3",
+			want: []*block{
+				{
+					kind:   blockTable,
+					header: []string{"a", "b"},
+					aligns: []align{alignLeft, alignLeft},
+					rows:   [][]string{{"1", "2"}},
+				},
+			},
+		},
+		{
+			desc: "delimiter row must match the header",
+			text: "a | b\n---",
+			want: []*block{
+				{kind: blockHeading, level: 2, text: "a | b"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := parseBlocks(strings.Split(tc.text, "\n"))
+			if diff := pretty.Compare(tc.want, got); diff != "" {
+				t.Errorf("parseBlocks => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseInline(t *testing.T) {
+	tests := []struct {
+		desc string
+		text string
+		want []inline
+	}{
+		{
+			desc: "plain text",
+			text: "hello world",
+			want: []inline{{text: "hello world"}},
+		},
+		{
+			desc: "soft and hard line breaks",
+			text: "a \nb  \nc\\\nd",
+			want: []inline{{text: "a b\nc\nd"}},
+		},
+		{
+			desc: "emphasis and strong emphasis",
+			text: "*a* _b_ **c** __d__ ***e***",
+			want: []inline{
+				{text: "a", style: inlineStyle{emph: true}},
+				{text: " "},
+				{text: "b", style: inlineStyle{emph: true}},
+				{text: " "},
+				{text: "c", style: inlineStyle{strong: true}},
+				{text: " "},
+				{text: "d", style: inlineStyle{strong: true}},
+				{text: " "},
+				{text: "e", style: inlineStyle{strong: true, emph: true}},
+			},
+		},
+		{
+			desc: "nested emphasis",
+			text: "*a **b** c*",
+			want: []inline{
+				{text: "a ", style: inlineStyle{emph: true}},
+				{text: "b", style: inlineStyle{emph: true, strong: true}},
+				{text: " c", style: inlineStyle{emph: true}},
+			},
+		},
+		{
+			desc: "unmatched and spaced delimiters are literal",
+			text: "a * b *c snake_case_name **d",
+			want: []inline{
+				{text: "a * b *c snake_case_name **d"},
+			},
+		},
+		{
+			desc: "code spans",
+			text: "use `fmt.Println` or `` a`b `` but not `this",
+			want: []inline{
+				{text: "use "},
+				{text: "fmt.Println", style: inlineStyle{code: true}},
+				{text: " or "},
+				{text: "a`b", style: inlineStyle{code: true}},
+				{text: " but not `this"},
+			},
+		},
+		{
+			desc: "delimiters in code spans are literal",
+			text: "*a `*` b*",
+			want: []inline{
+				{text: "a ", style: inlineStyle{emph: true}},
+				{text: "*", style: inlineStyle{code: true}},
+				{text: " b", style: inlineStyle{emph: true}},
+			},
+		},
+		{
+			desc: "backslash escapes",
+			text: `\*not emphasis\* \a`,
+			want: []inline{{text: `*not emphasis* \a`}},
+		},
+		{
+			desc: "links",
+			text: "see [the **docs**](https://example.com \"Title\") and [x](<a b>)",
+			want: []inline{
+				{text: "see "},
+				{text: "the ", url: "https://example.com"},
+				{text: "docs", style: inlineStyle{strong: true}, url: "https://example.com"},
+				{text: " and "},
+				{text: "x", url: "a"},
+			},
+		},
+		{
+			desc: "images display the alternative text as a link",
+			text: "![logo](logo.png)",
+			want: []inline{{text: "logo", url: "logo.png"}},
+		},
+		{
+			desc: "incomplete links are literal",
+			text: "[a] [b]( ) [c",
+			want: []inline{{text: "[a] [b]( ) [c"}},
+		},
+		{
+			desc: "autolinks",
+			text: "<https://example.com> <me@example.com> <not a link>",
+			want: []inline{
+				{text: "https://example.com", url: "https://example.com"},
+				{text: " "},
+				{text: "me@example.com", url: "me@example.com"},
+				{text: " <not a link>"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := parseInline(tc.text)
+			if diff := pretty.Compare(tc.want, got); diff != "" {
+				t.Errorf("parseInline => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
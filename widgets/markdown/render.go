@@ -0,0 +1,327 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package markdown
+
+// render.go renders parsed blocks into lines of cells.
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/canvas/buffer"
+	"github.com/mum4k/termdash/internal/runewidth"
+	"github.com/mum4k/termdash/internal/wrap"
+)
+
+// bullets are the markers of unordered list items at increasing depths.
+var bullets = []string{"•", "◦", "▪"}
+
+// renderer renders blocks into lines of cells.
+type renderer struct {
+	// opts are the options of the widget.
+	opts *options
+	// links maps cells of links to their destinations.
+	links map[*buffer.Cell]string
+	// depth is the nesting depth of the rendered lists.
+	depth int
+}
+
+// render renders the blocks into lines no wider than the width.
+// Returns the lines and the destinations of the cells that belong to links.
+func render(blocks []*block, width int, opts *options) ([][]*buffer.Cell, map[*buffer.Cell]string, error) {
+	r := &renderer{
+		opts:  opts,
+		links: map[*buffer.Cell]string{},
+	}
+	lines, err := r.blocks(blocks, width, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	return lines, r.links, nil
+}
+
+// blocks renders the blocks. If spaced is true, the blocks are separated by
+// empty lines.
+func (r *renderer) blocks(blocks []*block, width int, spaced bool) ([][]*buffer.Cell, error) {
+	var lines [][]*buffer.Cell
+	for i, b := range blocks {
+		ls, err := r.block(b, width)
+		if err != nil {
+			return nil, err
+		}
+		if spaced && i > 0 {
+			lines = append(lines, nil)
+		}
+		lines = append(lines, ls...)
+	}
+	return lines, nil
+}
+
+// block renders a single block.
+func (r *renderer) block(b *block, width int) ([][]*buffer.Cell, error) {
+	if width < 1 {
+		// Nested blocks keep at least one cell for their content.
+		width = 1
+	}
+	switch b.kind {
+	case blockParagraph:
+		return wrapCells(r.inlineCells(b.text), width, wrap.AtWords)
+
+	case blockHeading:
+		opts := []cell.Option{cell.Bold()}
+		if b.level <= 2 {
+			opts = append(opts, cell.FgColor(r.opts.headingColor))
+		}
+		if b.level == 1 {
+			opts = append(opts, cell.Underline())
+		}
+		return wrapCells(r.inlineCells(b.text, opts...), width, wrap.AtWords)
+
+	case blockCode:
+		return r.code(b, width)
+
+	case blockQuote:
+		return r.quote(b, width)
+
+	case blockList:
+		return r.list(b, width)
+
+	case blockTable:
+		return r.table(b, width), nil
+
+	case blockRule:
+		return [][]*buffer.Cell{
+			buffer.NewCells(strings.Repeat("─", width), cell.FgColor(r.opts.lineColor)),
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported block kind %d", b.kind)
+	}
+}
+
+// wrapCells wraps the cells into lines of the width.
+func wrapCells(cells []*buffer.Cell, width int, m wrap.Mode) ([][]*buffer.Cell, error) {
+	if len(cells) == 0 {
+		return [][]*buffer.Cell{nil}, nil
+	}
+	return wrap.Cells(cells, width, m)
+}
+
+// cellsWidth returns the width of the cells in cells on the terminal.
+func cellsWidth(cells []*buffer.Cell) int {
+	var w int
+	for _, c := range cells {
+		w += runewidth.RuneWidth(c.Rune)
+	}
+	return w
+}
+
+// inlineCells parses the inline text and returns its cells with the options
+// applied to all of them.
+func (r *renderer) inlineCells(text string, opts ...cell.Option) []*buffer.Cell {
+	var cells []*buffer.Cell
+	for _, in := range parseInline(text) {
+		cOpts := append([]cell.Option{}, opts...)
+		if in.style.strong {
+			cOpts = append(cOpts, cell.Bold())
+		}
+		if in.style.emph {
+			// Terminals don't portably support italic text.
+			cOpts = append(cOpts, cell.Underline())
+		}
+		if in.style.code {
+			cOpts = append(cOpts, cell.FgColor(r.opts.codeColor))
+		}
+		if in.url != "" {
+			cOpts = append(cOpts, cell.FgColor(r.opts.linkColor), cell.Underline())
+		}
+
+		for _, c := range buffer.NewCells(in.text, cOpts...) {
+			if in.url != "" && c.Rune != '\n' {
+				r.links[c] = in.url
+			}
+			cells = append(cells, c)
+		}
+	}
+	return cells
+}
+
+// prefixed returns the lines with the prefix added to the first line and
+// the indent added to the other lines.
+func prefixed(lines [][]*buffer.Cell, prefix, indent string, opts ...cell.Option) [][]*buffer.Cell {
+	if len(lines) == 0 {
+		lines = [][]*buffer.Cell{nil}
+	}
+	res := make([][]*buffer.Cell, len(lines))
+	for i, l := range lines {
+		p := indent
+		if i == 0 {
+			p = prefix
+		}
+		res[i] = append(buffer.NewCells(p, opts...), l...)
+	}
+	return res
+}
+
+// code renders a code block indented by two cells, wrapping long lines at
+// rune boundaries.
+func (r *renderer) code(b *block, width int) ([][]*buffer.Cell, error) {
+	const indent = "  "
+	var lines [][]*buffer.Cell
+	for _, l := range b.lines {
+		wr, err := wrapCells(buffer.NewCells(l, cell.FgColor(r.opts.codeColor)), width-len(indent), wrap.AtRunes)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, prefixed(wr, indent, indent)...)
+	}
+	return lines, nil
+}
+
+// quote renders a block quote with a bar on the left.
+func (r *renderer) quote(b *block, width int) ([][]*buffer.Cell, error) {
+	const bar = "│ "
+	children, err := r.blocks(b.children, width-len([]rune(bar)), true)
+	if err != nil {
+		return nil, err
+	}
+	return prefixed(children, bar, bar, cell.FgColor(r.opts.quoteColor)), nil
+}
+
+// list renders a list with a marker before each item.
+func (r *renderer) list(b *block, width int) ([][]*buffer.Cell, error) {
+	markers := make([]string, len(b.items))
+	markerWidth := 0
+	for i := range b.items {
+		if b.ordered {
+			markers[i] = fmt.Sprintf("%d.", b.start+i)
+		} else {
+			markers[i] = bullets[r.depth%len(bullets)]
+		}
+		if w := runewidth.StringWidth(markers[i]) + 1; w > markerWidth {
+			markerWidth = w
+		}
+	}
+
+	r.depth++
+	defer func() { r.depth-- }()
+
+	var lines [][]*buffer.Cell
+	for i, item := range b.items {
+		ls, err := r.blocks(item, width-markerWidth, false)
+		if err != nil {
+			return nil, err
+		}
+		// Numbers are aligned to the right.
+		prefix := fmt.Sprintf("%*s ", markerWidth-1, markers[i])
+		lines = append(lines, prefixed(ls, prefix, strings.Repeat(" ", markerWidth))...)
+	}
+	return lines, nil
+}
+
+// tableSeparator separates the columns of tables.
+const tableSeparator = " │ "
+
+// table renders a table, shrinking its widest columns if it doesn't fit the
+// width.
+func (r *renderer) table(b *block, width int) [][]*buffer.Cell {
+	header := make([][]*buffer.Cell, len(b.header))
+	colWidths := make([]int, len(b.header))
+	for i, h := range b.header {
+		header[i] = r.inlineCells(h, cell.Bold())
+		colWidths[i] = cellsWidth(header[i])
+	}
+	rows := make([][][]*buffer.Cell, len(b.rows))
+	for i, row := range b.rows {
+		rows[i] = make([][]*buffer.Cell, len(row))
+		for j, c := range row {
+			rows[i][j] = r.inlineCells(c)
+			if w := cellsWidth(rows[i][j]); w > colWidths[j] {
+				colWidths[j] = w
+			}
+		}
+	}
+
+	sepWidth := runewidth.StringWidth(tableSeparator)
+	for {
+		total := sepWidth * (len(colWidths) - 1)
+		widest := 0
+		for i, w := range colWidths {
+			total += w
+			if w > colWidths[widest] {
+				widest = i
+			}
+		}
+		if total <= width || colWidths[widest] <= 1 {
+			break
+		}
+		colWidths[widest]--
+	}
+
+	lineOpts := cell.FgColor(r.opts.lineColor)
+	var lines [][]*buffer.Cell
+	for i, row := range append([][][]*buffer.Cell{header, nil}, rows...) {
+		var line []*buffer.Cell
+		for j, w := range colWidths {
+			if i == 1 {
+				// The line between the header and the body.
+				if j > 0 {
+					line = append(line, buffer.NewCells("─┼─", lineOpts)...)
+				}
+				line = append(line, buffer.NewCells(strings.Repeat("─", w), lineOpts)...)
+				continue
+			}
+			if j > 0 {
+				line = append(line, buffer.NewCells(tableSeparator, lineOpts)...)
+			}
+			line = append(line, fit(row[j], w, b.aligns[j])...)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// fit returns the cells padded to the width according to the alignment.
+// Cells that don't fit are truncated and end with an ellipsis that has the
+// options of the first truncated cell.
+func fit(cells []*buffer.Cell, width int, a align) []*buffer.Cell {
+	if cellsWidth(cells) > width {
+		var res []*buffer.Cell
+		w := 0
+		for _, c := range cells {
+			rw := runewidth.RuneWidth(c.Rune)
+			if w+rw > width-1 {
+				res = append(res, &buffer.Cell{Rune: '…', Opts: c.Opts})
+				break
+			}
+			res = append(res, c)
+			w += rw
+		}
+		cells = res
+	}
+
+	pad := width - cellsWidth(cells)
+	var left int
+	switch a {
+	case alignCenter:
+		left = pad / 2
+	case alignRight:
+		left = pad
+	}
+	res := buffer.NewCells(strings.Repeat(" ", left))
+	res = append(res, cells...)
+	return append(res, buffer.NewCells(strings.Repeat(" ", pad-left))...)
+}
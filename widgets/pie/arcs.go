@@ -0,0 +1,103 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pie
+
+// arcs.go calculates the parts of the circle that represent the slices.
+
+import (
+	"math"
+)
+
+// fullCircle is the number of degrees in a full circle.
+const fullCircle = 360
+
+// arc is the part of the circle that represents one slice.
+// The arc is expressed in degrees relative to the start angle of the chart,
+// in the direction in which the slices follow each other.
+type arc struct {
+	// from is the offset of the start of the arc.
+	from int
+	// to is the offset of the end of the arc.
+	to int
+}
+
+// newArcs splits the full circle into contiguous arcs with sizes
+// proportional to the values. The values must be zero or positive and their
+// sum must be positive.
+func newArcs(values []float64) []*arc {
+	var total float64
+	for _, v := range values {
+		total += v
+	}
+
+	var (
+		res  []*arc
+		sum  float64
+		from int
+	)
+	for _, v := range values {
+		sum += v
+		to := int(math.Round(sum / total * fullCircle))
+		res = append(res, &arc{from: from, to: to})
+		from = to
+	}
+	return res
+}
+
+// empty asserts whether the arc is too small to be drawn.
+func (a *arc) empty() bool {
+	return a.from == a.to
+}
+
+// angles returns the starting and the ending angle of the arc in the
+// counter-clockwise direction as expected by draw.BrailleCircleArcOnly.
+func (a *arc) angles(startAngle, direction int) (start, end int) {
+	if a.to-a.from == fullCircle {
+		return 0, fullCircle
+	}
+
+	if direction > 0 {
+		start, end = startAngle+a.from, startAngle+a.to
+	} else {
+		start, end = startAngle-a.to, startAngle-a.from
+	}
+	start, end = normalizeAngle(start), normalizeAngle(end)
+	if end == 0 {
+		end = fullCircle
+	}
+	return start, end
+}
+
+// normalizeAngle returns the same angle in the range 0 <= angle < 360.
+func normalizeAngle(angle int) int {
+	return (angle%fullCircle + fullCircle) % fullCircle
+}
+
+// angleOffset returns the offset of the angle from the start angle in the
+// direction in which the slices follow each other.
+func angleOffset(angle, startAngle, direction int) int {
+	return normalizeAngle(direction * (angle - startAngle))
+}
+
+// arcAt returns the index of the arc that contains the offset or -1 if no
+// such arc exists.
+func arcAt(arcs []*arc, offset int) int {
+	for i, a := range arcs {
+		if offset >= a.from && offset < a.to {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,169 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pie
+
+import (
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestNewArcs(t *testing.T) {
+	tests := []struct {
+		desc   string
+		values []float64
+		want   []*arc
+	}{
+		{
+			desc:   "single value",
+			values: []float64{5},
+			want:   []*arc{{0, 360}},
+		},
+		{
+			desc:   "equal values",
+			values: []float64{1, 1, 1, 1},
+			want:   []*arc{{0, 90}, {90, 180}, {180, 270}, {270, 360}},
+		},
+		{
+			desc:   "zero values are empty",
+			values: []float64{1, 0, 3},
+			want:   []*arc{{0, 90}, {90, 90}, {90, 360}},
+		},
+		{
+			desc:   "rounds to whole degrees",
+			values: []float64{1, 1, 1},
+			want:   []*arc{{0, 120}, {120, 240}, {240, 360}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := newArcs(tc.values)
+			if diff := pretty.Compare(tc.want, got); diff != "" {
+				t.Errorf("newArcs => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestArcAngles(t *testing.T) {
+	tests := []struct {
+		desc       string
+		arc        *arc
+		startAngle int
+		direction  int
+		wantStart  int
+		wantEnd    int
+	}{
+		{
+			desc:       "full circle",
+			arc:        &arc{0, 360},
+			startAngle: 90,
+			direction:  -1,
+			wantStart:  0,
+			wantEnd:    360,
+		},
+		{
+			desc:       "counter-clockwise",
+			arc:        &arc{10, 20},
+			startAngle: 90,
+			direction:  1,
+			wantStart:  100,
+			wantEnd:    110,
+		},
+		{
+			desc:       "clockwise",
+			arc:        &arc{10, 20},
+			startAngle: 90,
+			direction:  -1,
+			wantStart:  70,
+			wantEnd:    80,
+		},
+		{
+			desc:       "clockwise across the zero angle",
+			arc:        &arc{80, 100},
+			startAngle: 90,
+			direction:  -1,
+			wantStart:  350,
+			wantEnd:    10,
+		},
+		{
+			desc:       "counter-clockwise ending at the zero angle",
+			arc:        &arc{0, 90},
+			startAngle: 270,
+			direction:  1,
+			wantStart:  270,
+			wantEnd:    360,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			gotStart, gotEnd := tc.arc.angles(tc.startAngle, tc.direction)
+			if gotStart != tc.wantStart || gotEnd != tc.wantEnd {
+				t.Errorf("angles => %d, %d, want %d, %d", gotStart, gotEnd, tc.wantStart, tc.wantEnd)
+			}
+		})
+	}
+}
+
+func TestArcAt(t *testing.T) {
+	arcs := []*arc{{0, 90}, {90, 90}, {90, 360}}
+	tests := []struct {
+		desc       string
+		angle      int
+		startAngle int
+		direction  int
+		want       int
+	}{
+		{
+			desc:       "the start angle is in the first arc",
+			angle:      90,
+			startAngle: 90,
+			direction:  -1,
+			want:       0,
+		},
+		{
+			desc:       "clockwise from the start angle",
+			angle:      45,
+			startAngle: 90,
+			direction:  -1,
+			want:       0,
+		},
+		{
+			desc:       "counter-clockwise from the start angle",
+			angle:      135,
+			startAngle: 90,
+			direction:  -1,
+			want:       2,
+		},
+		{
+			desc:       "counter-clockwise direction",
+			angle:      135,
+			startAngle: 90,
+			direction:  1,
+			want:       0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := arcAt(arcs, angleOffset(tc.angle, tc.startAngle, tc.direction))
+			if got != tc.want {
+				t.Errorf("arcAt => %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pie
+
+// options.go contains configurable options for Pie.
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mum4k/termdash/cell"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// options holds the provided options.
+type options struct {
+	holePercent int
+	hideLegend  bool
+
+	textCellOpts   []cell.Option
+	legendCellOpts []cell.Option
+
+	// The angle in degrees where the first slice starts.
+	startAngle int
+	// The direction in which the slices follow each other.
+	// Positive for counter-clockwise, negative for clockwise.
+	direction int
+
+	percentFormatter func(float64) string
+}
+
+// validate validates the provided options.
+func (o *options) validate() error {
+	if min, max := 0, 100; o.holePercent < min || o.holePercent > max {
+		return fmt.Errorf("invalid hole percent %d, must be in range %d <= p <= %d", o.holePercent, min, max)
+	}
+
+	if min, max := 0, 360; o.startAngle < min || o.startAngle >= max {
+		return fmt.Errorf("invalid start angle %d, must be in range %d <= angle < %d", o.startAngle, min, max)
+	}
+	if o.percentFormatter == nil {
+		return errors.New("the PercentFormatter cannot be nil")
+	}
+	return nil
+}
+
+// newOptions returns options with the default values set.
+func newOptions() *options {
+	return &options{
+		holePercent: DefaultHolePercent,
+		startAngle:  DefaultStartAngle,
+		direction:   -1,
+		textCellOpts: []cell.Option{
+			cell.FgColor(cell.ColorDefault),
+			cell.BgColor(cell.ColorDefault),
+		},
+		percentFormatter: defaultPercentFormatter,
+	}
+}
+
+// defaultPercentFormatter is the default formatter of the percentages of the
+// slices.
+func defaultPercentFormatter(p float64) string {
+	return fmt.Sprintf("%.1f%%", p)
+}
+
+// DefaultHolePercent is the default value for the HolePercent option.
+const DefaultHolePercent = 40
+
+// HolePercent sets the size of the "hole" inside the chart as a percentage of
+// the chart's radius. The percentage of the selected slice is displayed
+// inside the hole if it is large enough.
+// Setting this to zero disables the hole so that the chart becomes a pie
+// chart. Valid range is 0 <= p <= 100.
+func HolePercent(p int) Option {
+	return option(func(opts *options) {
+		opts.holePercent = p
+	})
+}
+
+// HideLegend disables the display of the legend next to the chart.
+func HideLegend() Option {
+	return option(func(opts *options) {
+		opts.hideLegend = true
+	})
+}
+
+// TextCellOpts sets cell options on cells that contain the percentage of the
+// selected slice displayed inside the hole.
+func TextCellOpts(cOpts ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.textCellOpts = cOpts
+	})
+}
+
+// LegendCellOpts sets cell options on cells that contain the labels and the
+// percentages of the slices in the legend.
+func LegendCellOpts(cOpts ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.legendCellOpts = cOpts
+	})
+}
+
+// DefaultStartAngle is the default value for the StartAngle option.
+const DefaultStartAngle = 90
+
+// StartAngle sets the angle in degrees where the first slice starts.
+// Valid values are in range 0 <= angle < 360.
+// Angles start at the X axis and grow counter-clockwise.
+func StartAngle(angle int) Option {
+	return option(func(opts *options) {
+		opts.startAngle = angle
+	})
+}
+
+// Clockwise places the slices after each other in the clockwise direction.
+// This is the default option.
+func Clockwise() Option {
+	return option(func(opts *options) {
+		opts.direction = -1
+	})
+}
+
+// CounterClockwise places the slices after each other in the counter-clockwise
+// direction.
+func CounterClockwise() Option {
+	return option(func(opts *options) {
+		opts.direction = 1
+	})
+}
+
+// PercentFormatter sets the function that formats the percentages of the
+// slices displayed in the legend and inside the hole. The function receives
+// the percentage in the range 0 <= p <= 100. Defaults to one decimal place,
+// e.g. "42.5%".
+func PercentFormatter(f func(float64) string) Option {
+	return option(func(opts *options) {
+		opts.percentFormatter = f
+	})
+}
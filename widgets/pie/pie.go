@@ -0,0 +1,431 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pie is a widget that displays multiple values as slices of a
+// circle.
+package pie
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"sync"
+
+	"github.com/mum4k/termdash/align"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/alignfor"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/canvas/braille"
+	"github.com/mum4k/termdash/internal/circle"
+	"github.com/mum4k/termdash/internal/draw"
+	"github.com/mum4k/termdash/internal/numbers/trig"
+	"github.com/mum4k/termdash/internal/runewidth"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// Slice is one value displayed on the chart.
+type Slice struct {
+	// Label identifies the slice in the legend.
+	Label string
+	// Value is the size of the slice, must be zero or positive.
+	// The slice takes the part of the circle proportional to its share on
+	// the sum of all the values.
+	Value float64
+	// Color is the color of the slice. If set to cell.ColorDefault, the color
+	// is chosen from DefaultColors based on the position of the slice.
+	Color cell.Color
+}
+
+// DefaultColors are the colors assigned to slices that don't specify one.
+var DefaultColors = []cell.Color{
+	cell.ColorBlue,
+	cell.ColorRed,
+	cell.ColorGreen,
+	cell.ColorYellow,
+	cell.ColorMagenta,
+	cell.ColorCyan,
+}
+
+// legendMarker is the character that displays the color of a slice in the
+// legend.
+const legendMarker = '█'
+
+// layout records where the chart was drawn on the last call to Draw, it is
+// used to map mouse events onto the slices.
+type layout struct {
+	// pieAr is the cell area of the braille canvas with the chart.
+	pieAr image.Rectangle
+	// mid is the mid point of the chart in pixels on the braille canvas.
+	mid image.Point
+	// radius is the radius of the chart in pixels.
+	radius int
+	// holeR is the radius of the hole in pixels or zero if there is none.
+	holeR int
+	// arcs are the arcs of the drawn slices.
+	arcs []*arc
+	// legendAr is the area with the legend, one line per slice starting at
+	// the top of the area. Empty if the legend isn't displayed.
+	legendAr image.Rectangle
+}
+
+// Pie displays multiple values as contiguous slices of a circle, each slice
+// taking the part of the circle proportional to its value. The circle can
+// have a "hole" in the middle, turning the pie chart into a donut chart.
+//
+// A legend next to the chart lists the labels of the slices and their
+// percentages. Clicking on a slice or on its entry in the legend with the
+// left mouse button selects it, the percentage of the selected slice is
+// displayed inside the hole.
+//
+// Implements widgetapi.Widget. This object is thread-safe.
+type Pie struct {
+	// slices are the slices that will be drawn.
+	slices []*Slice
+	// total is the sum of the values of the slices.
+	total float64
+
+	// selected is the index of the selected slice or -1 if there is none.
+	selected int
+	// lastLayout is the layout from the last call to Draw.
+	lastLayout *layout
+
+	// mu protects the Pie.
+	mu sync.Mutex
+
+	// opts are the provided options.
+	opts *options
+}
+
+// New returns a new Pie.
+func New(opts ...Option) (*Pie, error) {
+	opt := newOptions()
+	for _, o := range opts {
+		o.set(opt)
+	}
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	return &Pie{
+		selected: -1,
+		opts:     opt,
+	}, nil
+}
+
+// Slices sets the slices displayed on the chart.
+// The values of the slices must be zero or positive numbers. Nothing is drawn
+// if the sum of the values is zero.
+// The selection of a slice is kept if the new slices contain a slice at the
+// same position.
+// Provided options override values set when New() was called.
+func (p *Pie) Slices(slices []*Slice, opts ...Option) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var total float64
+	for i, s := range slices {
+		if s == nil {
+			return fmt.Errorf("invalid slices[%d], cannot be nil", i)
+		}
+		if math.IsNaN(s.Value) || math.IsInf(s.Value, 0) || s.Value < 0 {
+			return fmt.Errorf("invalid slices[%d] value %v, must be a zero or positive number", i, s.Value)
+		}
+		total += s.Value
+	}
+
+	newOpts := *p.opts
+	for _, opt := range opts {
+		opt.set(&newOpts)
+	}
+	if err := newOpts.validate(); err != nil {
+		return err
+	}
+
+	p.slices = nil
+	for i, s := range slices {
+		// Copy to avoid external modifications. See #174.
+		c := *s
+		if c.Color == cell.ColorDefault {
+			c.Color = DefaultColors[i%len(DefaultColors)]
+		}
+		p.slices = append(p.slices, &c)
+	}
+	p.total = total
+	p.opts = &newOpts
+	// The layout is stale until the next draw.
+	p.lastLayout = nil
+	if p.selected >= len(p.slices) {
+		p.selected = -1
+	}
+	return nil
+}
+
+// percent returns the formatted percentage of the slice on the total.
+func (p *Pie) percent(s *Slice) string {
+	return p.opts.percentFormatter(s.Value / p.total * 100)
+}
+
+// holeRadius calculates the radius of the "hole" in the chart.
+// Returns zero if no hole should be drawn.
+func (p *Pie) holeRadius(radius int) int {
+	r := int(math.Round(float64(radius) / 100 * float64(p.opts.holePercent)))
+	if r < 2 { // Smallest possible circle radius.
+		return 0
+	}
+	return r
+}
+
+// legendEntry returns the text of the legend entry for the slice without the
+// marker.
+func (p *Pie) legendEntry(s *Slice) string {
+	if s.Label == "" {
+		return p.percent(s)
+	}
+	return fmt.Sprintf("%s %s", s.Label, p.percent(s))
+}
+
+// legendWidth returns the width of the legend including the marker.
+func (p *Pie) legendWidth() int {
+	var width int
+	for _, s := range p.slices {
+		if w := runewidth.StringWidth(p.legendEntry(s)); w > width {
+			width = w
+		}
+	}
+	return width + 2 // The marker and a space.
+}
+
+// minLegendWidth is the minimum width of the legend, narrower legend isn't
+// displayed.
+const minLegendWidth = 3
+
+// pieAndLegend splits the canvas area into the area for the chart on the
+// left and the legend on the right, separated by one cell. The legend is
+// narrowed if it wouldn't leave enough space for the chart and isn't
+// displayed at all if it cannot be narrowed any further.
+func (p *Pie) pieAndLegend(cvsAr image.Rectangle) (pieAr, legendAr image.Rectangle) {
+	if p.opts.hideLegend {
+		return cvsAr, image.ZR
+	}
+
+	width := p.legendWidth()
+	if avail := cvsAr.Dx() - minSize.X - 1; width > avail {
+		width = avail
+	}
+	if width < minLegendWidth {
+		return cvsAr, image.ZR
+	}
+
+	pieAr = image.Rect(cvsAr.Min.X, cvsAr.Min.Y, cvsAr.Max.X-width-1, cvsAr.Max.Y)
+	legendAr = image.Rect(pieAr.Max.X+1, cvsAr.Min.Y, cvsAr.Max.X, cvsAr.Max.Y)
+	if lines := len(p.slices); lines < legendAr.Dy() {
+		// Vertically center the legend.
+		legendAr.Min.Y += (legendAr.Dy() - lines) / 2
+		legendAr.Max.Y = legendAr.Min.Y + lines
+	}
+	return pieAr, legendAr
+}
+
+// drawText draws the percentage of the selected slice inside the hole.
+// The text is only drawn if the hole is large enough to accommodate it.
+func (p *Pie) drawText(cvs *canvas.Canvas, l *layout) error {
+	if p.selected < 0 || l.holeR == 0 {
+		return nil
+	}
+
+	cells, first := circle.AvailableCells(l.mid, l.holeR)
+	t := p.percent(p.slices[p.selected])
+	needCells := runewidth.StringWidth(t)
+	if cells < needCells {
+		return nil
+	}
+
+	// The mid point is relative to the braille canvas.
+	first = first.Add(l.pieAr.Min)
+	ar := image.Rect(first.X, first.Y, first.X+cells+2, first.Y+1)
+	start, err := alignfor.Text(ar, t, align.HorizontalCenter, align.VerticalMiddle)
+	if err != nil {
+		return fmt.Errorf("alignfor.Text => %v", err)
+	}
+	if err := draw.Text(cvs, t, start, draw.TextMaxX(start.X+needCells), draw.TextCellOpts(p.opts.textCellOpts...)); err != nil {
+		return fmt.Errorf("draw.Text => %v", err)
+	}
+	return nil
+}
+
+// drawLegend draws the legend, one line per slice. The entry of the selected
+// slice is inverted.
+func (p *Pie) drawLegend(cvs *canvas.Canvas, legendAr image.Rectangle) error {
+	for i, s := range p.slices {
+		y := legendAr.Min.Y + i
+		if y >= legendAr.Max.Y {
+			break
+		}
+
+		if _, err := cvs.SetCell(image.Point{legendAr.Min.X, y}, legendMarker, cell.FgColor(s.Color)); err != nil {
+			return err
+		}
+
+		opts := p.opts.legendCellOpts
+		if i == p.selected {
+			opts = append(append([]cell.Option(nil), opts...), cell.Inverse())
+		}
+		if err := draw.Text(cvs, p.legendEntry(s), image.Point{legendAr.Min.X + 2, y},
+			draw.TextMaxX(legendAr.Max.X),
+			draw.TextOverrunMode(draw.OverrunModeThreeDot),
+			draw.TextCellOpts(opts...),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Draw draws the Pie widget onto the canvas.
+// Implements widgetapi.Widget.Draw.
+func (p *Pie) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.lastLayout = nil
+	if p.total == 0 {
+		// Nothing to draw.
+		return nil
+	}
+
+	pieAr, legendAr := p.pieAndLegend(cvs.Area())
+	if pieAr.Dx() < minSize.X || pieAr.Dy() < minSize.Y {
+		return draw.ResizeNeeded(cvs)
+	}
+
+	bc, err := braille.New(pieAr)
+	if err != nil {
+		return fmt.Errorf("braille.New => %v", err)
+	}
+
+	var values []float64
+	for _, s := range p.slices {
+		values = append(values, s.Value)
+	}
+	mid, r := circle.MidAndRadius(bc.Area())
+	l := &layout{
+		pieAr:    pieAr,
+		mid:      mid,
+		radius:   r,
+		holeR:    p.holeRadius(r),
+		arcs:     newArcs(values),
+		legendAr: legendAr,
+	}
+
+	for i, a := range l.arcs {
+		if a.empty() {
+			continue
+		}
+		startA, endA := a.angles(p.opts.startAngle, p.opts.direction)
+		if err := draw.BrailleCircle(bc, mid, r,
+			draw.BrailleCircleFilled(),
+			draw.BrailleCircleArcOnly(startA, endA),
+			draw.BrailleCircleCellOpts(cell.FgColor(p.slices[i].Color)),
+		); err != nil {
+			return fmt.Errorf("failed to draw slice %d: %v", i, err)
+		}
+	}
+
+	if l.holeR != 0 {
+		if err := draw.BrailleCircle(bc, mid, l.holeR,
+			draw.BrailleCircleFilled(),
+			draw.BrailleCircleClearPixels(),
+		); err != nil {
+			return fmt.Errorf("failed to draw the hole: %v", err)
+		}
+	}
+	if err := bc.CopyTo(cvs); err != nil {
+		return err
+	}
+
+	if err := p.drawText(cvs, l); err != nil {
+		return err
+	}
+	if !legendAr.Empty() {
+		if err := p.drawLegend(cvs, legendAr); err != nil {
+			return err
+		}
+	}
+	p.lastLayout = l
+	return nil
+}
+
+// sliceAt returns the index of the slice drawn at the cell or -1 if there is
+// no slice at the cell.
+func (p *Pie) sliceAt(l *layout, c image.Point) int {
+	if c.In(l.legendAr) {
+		return c.Y - l.legendAr.Min.Y
+	}
+	if !c.In(l.pieAr) {
+		return -1
+	}
+
+	// Use the pixel in the middle of the cell.
+	rel := c.Sub(l.pieAr.Min)
+	px := image.Point{
+		rel.X*braille.ColMult + braille.ColMult/2,
+		rel.Y*braille.RowMult + braille.RowMult/2,
+	}
+	d := px.Sub(l.mid)
+	dist := math.Sqrt(float64(d.X*d.X + d.Y*d.Y))
+	if dist > float64(l.radius) || dist < float64(l.holeR) {
+		return -1
+	}
+
+	angle := trig.CircleAngleAtPoint(px, l.mid)
+	return arcAt(l.arcs, angleOffset(angle, p.opts.startAngle, p.opts.direction))
+}
+
+// Keyboard input isn't supported on the Pie widget.
+func (*Pie) Keyboard(k *terminalapi.Keyboard) error {
+	return errors.New("the Pie widget doesn't support keyboard events")
+}
+
+// Mouse selects the slice clicked on with the left mouse button.
+// Clicking outside of the slices clears the selection.
+// Implements widgetapi.Widget.Mouse.
+func (p *Pie) Mouse(m *terminalapi.Mouse) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	l := p.lastLayout
+	if m.Button != mouse.ButtonLeft || l == nil {
+		return nil
+	}
+	p.selected = p.sliceAt(l, m.Position)
+	if p.selected >= len(p.slices) {
+		p.selected = -1
+	}
+	return nil
+}
+
+// minSize is the smallest area we can draw the chart on, not including the
+// legend.
+var minSize = image.Point{3, 3}
+
+// Options implements widgetapi.Widget.Options.
+func (p *Pie) Options() widgetapi.Options {
+	return widgetapi.Options{
+		MinimumSize:  minSize,
+		WantKeyboard: widgetapi.KeyScopeNone,
+		WantMouse:    widgetapi.MouseScopeWidget,
+	}
+}
@@ -0,0 +1,643 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pie
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/canvas/braille/testbraille"
+	"github.com/mum4k/termdash/internal/canvas/testcanvas"
+	"github.com/mum4k/termdash/internal/circle"
+	"github.com/mum4k/termdash/internal/draw"
+	"github.com/mum4k/termdash/internal/draw/testdraw"
+	"github.com/mum4k/termdash/internal/faketerm"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// slice is one slice expected on the chart.
+type slice struct {
+	color      cell.Color
+	start, end int
+}
+
+// mustDrawPie draws the expected pie chart with the hole onto the canvas.
+func mustDrawPie(cvs *canvas.Canvas, ar image.Rectangle, holePercent int, slices []slice) {
+	bc := testbraille.MustNew(ar)
+	mid, r := circle.MidAndRadius(bc.Area())
+	for _, s := range slices {
+		testdraw.MustBrailleCircle(bc, mid, r,
+			draw.BrailleCircleFilled(),
+			draw.BrailleCircleArcOnly(s.start, s.end),
+			draw.BrailleCircleCellOpts(cell.FgColor(s.color)),
+		)
+	}
+	if holeR := int(math.Round(float64(r) / 100 * float64(holePercent))); holeR >= 2 {
+		testdraw.MustBrailleCircle(bc, mid, holeR,
+			draw.BrailleCircleFilled(),
+			draw.BrailleCircleClearPixels(),
+		)
+	}
+	testbraille.MustCopyTo(bc, cvs)
+}
+
+func TestPie(t *testing.T) {
+	tests := []struct {
+		desc          string
+		opts          []Option
+		update        func(*Pie) error // update gets called before drawing of the widget.
+		events        []*terminalapi.Mouse
+		canvas        image.Rectangle
+		want          func(size image.Point) *faketerm.Terminal
+		wantNewErr    bool
+		wantUpdateErr bool // whether to expect an error on a call to the update function
+		wantDrawErr   bool
+	}{
+		{
+			desc: "New fails on negative hole percent",
+			opts: []Option{
+				HolePercent(-1),
+			},
+			canvas:     image.Rect(0, 0, 3, 3),
+			wantNewErr: true,
+		},
+		{
+			desc: "New fails on too large hole percent",
+			opts: []Option{
+				HolePercent(101),
+			},
+			canvas:     image.Rect(0, 0, 3, 3),
+			wantNewErr: true,
+		},
+		{
+			desc: "New fails on too small start angle",
+			opts: []Option{
+				StartAngle(-1),
+			},
+			canvas:     image.Rect(0, 0, 3, 3),
+			wantNewErr: true,
+		},
+		{
+			desc: "New fails on too large start angle",
+			opts: []Option{
+				StartAngle(360),
+			},
+			canvas:     image.Rect(0, 0, 3, 3),
+			wantNewErr: true,
+		},
+		{
+			desc: "New fails on nil PercentFormatter",
+			opts: []Option{
+				PercentFormatter(nil),
+			},
+			canvas:     image.Rect(0, 0, 3, 3),
+			wantNewErr: true,
+		},
+		{
+			desc:   "Slices fails on invalid option",
+			canvas: image.Rect(0, 0, 3, 3),
+			update: func(p *Pie) error {
+				return p.Slices([]*Slice{{Value: 1}}, StartAngle(-1))
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc:   "Slices fails on nil slice",
+			canvas: image.Rect(0, 0, 3, 3),
+			update: func(p *Pie) error {
+				return p.Slices([]*Slice{{Value: 1}, nil})
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc:   "Slices fails on negative value",
+			canvas: image.Rect(0, 0, 3, 3),
+			update: func(p *Pie) error {
+				return p.Slices([]*Slice{{Value: -1}})
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc:   "Slices fails on NaN value",
+			canvas: image.Rect(0, 0, 3, 3),
+			update: func(p *Pie) error {
+				return p.Slices([]*Slice{{Value: math.NaN()}})
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc:   "Slices fails on infinite value",
+			canvas: image.Rect(0, 0, 3, 3),
+			update: func(p *Pie) error {
+				return p.Slices([]*Slice{{Value: math.Inf(1)}})
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc:   "draws empty without slices",
+			canvas: image.Rect(0, 0, 1, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+		},
+		{
+			desc:   "draws empty when all the values are zero",
+			canvas: image.Rect(0, 0, 1, 1),
+			update: func(p *Pie) error {
+				return p.Slices([]*Slice{{Value: 0}, {Value: 0}})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+		},
+		{
+			desc:   "draws resize needed character when canvas is smaller than minimum",
+			canvas: image.Rect(0, 0, 2, 2),
+			update: func(p *Pie) error {
+				return p.Slices([]*Slice{{Value: 1}})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustResizeNeeded(c)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "draws a single slice as a full circle",
+			opts: []Option{
+				HolePercent(0),
+				HideLegend(),
+			},
+			canvas: image.Rect(0, 0, 6, 3),
+			update: func(p *Pie) error {
+				return p.Slices([]*Slice{{Value: 1, Color: cell.ColorGreen}})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				mustDrawPie(c, ft.Area(), 0, []slice{
+					{cell.ColorGreen, 0, 360},
+				})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "draws two halves clockwise with default colors",
+			opts: []Option{
+				HolePercent(0),
+				HideLegend(),
+			},
+			canvas: image.Rect(0, 0, 6, 3),
+			update: func(p *Pie) error {
+				return p.Slices([]*Slice{{Value: 1}, {Value: 1}})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				mustDrawPie(c, ft.Area(), 0, []slice{
+					{cell.ColorBlue, 270, 90},
+					{cell.ColorRed, 90, 270},
+				})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "draws slices counter-clockwise from the start angle",
+			opts: []Option{
+				HolePercent(0),
+				HideLegend(),
+				StartAngle(0),
+				CounterClockwise(),
+			},
+			canvas: image.Rect(0, 0, 6, 3),
+			update: func(p *Pie) error {
+				return p.Slices([]*Slice{{Value: 1}, {Value: 3}})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				mustDrawPie(c, ft.Area(), 0, []slice{
+					{cell.ColorBlue, 0, 90},
+					{cell.ColorRed, 90, 360},
+				})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "skips slices with zero value",
+			opts: []Option{
+				HolePercent(0),
+				HideLegend(),
+			},
+			canvas: image.Rect(0, 0, 6, 3),
+			update: func(p *Pie) error {
+				return p.Slices([]*Slice{{Value: 1}, {Value: 0}, {Value: 1}})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				mustDrawPie(c, ft.Area(), 0, []slice{
+					{cell.ColorBlue, 270, 90},
+					{cell.ColorGreen, 90, 270},
+				})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "draws the legend and the hole",
+			canvas: image.Rect(0, 0, 20, 4),
+			update: func(p *Pie) error {
+				return p.Slices([]*Slice{
+					{Label: "A", Value: 1},
+					{Label: "B", Value: 3, Color: cell.ColorYellow},
+				})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				mustDrawPie(c, image.Rect(0, 0, 10, 4), DefaultHolePercent, []slice{
+					{cell.ColorBlue, 0, 90},
+					{cell.ColorYellow, 90, 360},
+				})
+				testcanvas.MustSetCell(c, image.Point{11, 1}, '█', cell.FgColor(cell.ColorBlue))
+				testdraw.MustText(c, "A 25.0%", image.Point{13, 1})
+				testcanvas.MustSetCell(c, image.Point{11, 2}, '█', cell.FgColor(cell.ColorYellow))
+				testdraw.MustText(c, "B 75.0%", image.Point{13, 2})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "legend uses custom formatter and cell options",
+			opts: []Option{
+				PercentFormatter(func(p float64) string {
+					return fmt.Sprintf("%.0f", p)
+				}),
+				LegendCellOpts(cell.FgColor(cell.ColorCyan)),
+			},
+			canvas: image.Rect(0, 0, 12, 3),
+			update: func(p *Pie) error {
+				return p.Slices([]*Slice{
+					{Label: "A", Value: 1},
+				})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				mustDrawPie(c, image.Rect(0, 0, 4, 3), DefaultHolePercent, []slice{
+					{cell.ColorBlue, 0, 360},
+				})
+				testcanvas.MustSetCell(c, image.Point{5, 1}, '█', cell.FgColor(cell.ColorBlue))
+				testdraw.MustText(c, "A 100", image.Point{7, 1}, draw.TextCellOpts(cell.FgColor(cell.ColorCyan)))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "trims the legend when it doesn't fit",
+			canvas: image.Rect(0, 0, 10, 3),
+			update: func(p *Pie) error {
+				return p.Slices([]*Slice{
+					{Label: "Long label", Value: 1},
+				})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				mustDrawPie(c, image.Rect(0, 0, 3, 3), DefaultHolePercent, []slice{
+					{cell.ColorBlue, 0, 360},
+				})
+				testcanvas.MustSetCell(c, image.Point{4, 1}, '█', cell.FgColor(cell.ColorBlue))
+				testdraw.MustText(c, "Lon…", image.Point{6, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "omits the legend when there isn't enough space",
+			opts:   []Option{HolePercent(0)},
+			canvas: image.Rect(0, 0, 6, 3),
+			update: func(p *Pie) error {
+				return p.Slices([]*Slice{
+					{Label: "A", Value: 1},
+				})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				mustDrawPie(c, ft.Area(), 0, []slice{
+					{cell.ColorBlue, 0, 360},
+				})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "selects a slice by clicking on its legend entry",
+			canvas: image.Rect(0, 0, 20, 4),
+			update: func(p *Pie) error {
+				return p.Slices([]*Slice{
+					{Label: "A", Value: 1},
+					{Label: "B", Value: 3},
+				})
+			},
+			events: []*terminalapi.Mouse{
+				{Position: image.Point{15, 2}, Button: mouse.ButtonLeft},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				mustDrawPie(c, image.Rect(0, 0, 10, 4), DefaultHolePercent, []slice{
+					{cell.ColorBlue, 0, 90},
+					{cell.ColorRed, 90, 360},
+				})
+				testcanvas.MustSetCell(c, image.Point{11, 1}, '█', cell.FgColor(cell.ColorBlue))
+				testdraw.MustText(c, "A 25.0%", image.Point{13, 1})
+				testcanvas.MustSetCell(c, image.Point{11, 2}, '█', cell.FgColor(cell.ColorRed))
+				testdraw.MustText(c, "B 75.0%", image.Point{13, 2}, draw.TextCellOpts(cell.Inverse()))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "selects a slice by clicking on the chart",
+			canvas: image.Rect(0, 0, 20, 4),
+			update: func(p *Pie) error {
+				return p.Slices([]*Slice{
+					{Label: "A", Value: 1},
+					{Label: "B", Value: 3},
+				})
+			},
+			events: []*terminalapi.Mouse{
+				{Position: image.Point{6, 1}, Button: mouse.ButtonLeft},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				mustDrawPie(c, image.Rect(0, 0, 10, 4), DefaultHolePercent, []slice{
+					{cell.ColorBlue, 0, 90},
+					{cell.ColorRed, 90, 360},
+				})
+				testcanvas.MustSetCell(c, image.Point{11, 1}, '█', cell.FgColor(cell.ColorBlue))
+				testdraw.MustText(c, "A 25.0%", image.Point{13, 1}, draw.TextCellOpts(cell.Inverse()))
+				testcanvas.MustSetCell(c, image.Point{11, 2}, '█', cell.FgColor(cell.ColorRed))
+				testdraw.MustText(c, "B 75.0%", image.Point{13, 2})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "clicking outside of the slices clears the selection",
+			canvas: image.Rect(0, 0, 20, 4),
+			update: func(p *Pie) error {
+				return p.Slices([]*Slice{
+					{Label: "A", Value: 1},
+					{Label: "B", Value: 3},
+				})
+			},
+			events: []*terminalapi.Mouse{
+				{Position: image.Point{15, 2}, Button: mouse.ButtonLeft},
+				{Position: image.Point{0, 0}, Button: mouse.ButtonLeft},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				mustDrawPie(c, image.Rect(0, 0, 10, 4), DefaultHolePercent, []slice{
+					{cell.ColorBlue, 0, 90},
+					{cell.ColorRed, 90, 360},
+				})
+				testcanvas.MustSetCell(c, image.Point{11, 1}, '█', cell.FgColor(cell.ColorBlue))
+				testdraw.MustText(c, "A 25.0%", image.Point{13, 1})
+				testcanvas.MustSetCell(c, image.Point{11, 2}, '█', cell.FgColor(cell.ColorRed))
+				testdraw.MustText(c, "B 75.0%", image.Point{13, 2})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "ignores buttons other than the left one",
+			canvas: image.Rect(0, 0, 20, 4),
+			update: func(p *Pie) error {
+				return p.Slices([]*Slice{
+					{Label: "A", Value: 1},
+					{Label: "B", Value: 3},
+				})
+			},
+			events: []*terminalapi.Mouse{
+				{Position: image.Point{15, 2}, Button: mouse.ButtonRight},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				mustDrawPie(c, image.Rect(0, 0, 10, 4), DefaultHolePercent, []slice{
+					{cell.ColorBlue, 0, 90},
+					{cell.ColorRed, 90, 360},
+				})
+				testcanvas.MustSetCell(c, image.Point{11, 1}, '█', cell.FgColor(cell.ColorBlue))
+				testdraw.MustText(c, "A 25.0%", image.Point{13, 1})
+				testcanvas.MustSetCell(c, image.Point{11, 2}, '█', cell.FgColor(cell.ColorRed))
+				testdraw.MustText(c, "B 75.0%", image.Point{13, 2})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "displays the percentage of the selected slice in the hole",
+			opts: []Option{
+				HolePercent(80),
+				HideLegend(),
+				TextCellOpts(cell.FgColor(cell.ColorMagenta)),
+			},
+			canvas: image.Rect(0, 0, 20, 10),
+			update: func(p *Pie) error {
+				return p.Slices([]*Slice{
+					{Value: 1},
+					{Value: 3},
+				})
+			},
+			events: []*terminalapi.Mouse{
+				{Position: image.Point{2, 5}, Button: mouse.ButtonLeft},
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				mustDrawPie(c, ft.Area(), 80, []slice{
+					{cell.ColorBlue, 0, 90},
+					{cell.ColorRed, 90, 360},
+				})
+				testdraw.MustText(c, "75.0%", image.Point{8, 5}, draw.TextCellOpts(cell.FgColor(cell.ColorMagenta)))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			p, err := New(tc.opts...)
+			if (err != nil) != tc.wantNewErr {
+				t.Errorf("New => unexpected error: %v, wantNewErr: %v", err, tc.wantNewErr)
+			}
+			if err != nil {
+				return
+			}
+
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+
+			if tc.update != nil {
+				err = tc.update(p)
+				if (err != nil) != tc.wantUpdateErr {
+					t.Errorf("update => unexpected error: %v, wantUpdateErr: %v", err, tc.wantUpdateErr)
+
+				}
+				if err != nil {
+					return
+				}
+			}
+
+			if len(tc.events) > 0 {
+				// Mouse events are mapped using the layout from the last
+				// draw.
+				if err := p.Draw(c, &widgetapi.Meta{}); err != nil {
+					t.Fatalf("Draw => unexpected error: %v", err)
+				}
+				for _, ev := range tc.events {
+					if err := p.Mouse(ev); err != nil {
+						t.Fatalf("Mouse => unexpected error: %v", err)
+					}
+				}
+				c, err = canvas.New(tc.canvas)
+				if err != nil {
+					t.Fatalf("canvas.New => unexpected error: %v", err)
+				}
+			}
+
+			err = p.Draw(c, &widgetapi.Meta{})
+			if (err != nil) != tc.wantDrawErr {
+				t.Errorf("Draw => unexpected error: %v, wantDrawErr: %v", err, tc.wantDrawErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+
+			var want *faketerm.Terminal
+			if tc.want != nil {
+				want = tc.want(c.Size())
+			} else {
+				want = faketerm.MustNew(c.Size())
+			}
+
+			if diff := faketerm.Diff(want, got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+		})
+	}
+}
+
+func TestKeyboard(t *testing.T) {
+	p, err := New()
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := p.Keyboard(&terminalapi.Keyboard{}); err == nil {
+		t.Errorf("Keyboard => got nil err, wanted one")
+	}
+}
+
+func TestMouseBeforeDraw(t *testing.T) {
+	p, err := New()
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := p.Mouse(&terminalapi.Mouse{Button: mouse.ButtonLeft}); err != nil {
+		t.Errorf("Mouse => unexpected error: %v", err)
+	}
+}
+
+func TestMouseAfterSlicesShrink(t *testing.T) {
+	p, err := New(HolePercent(50))
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	var slices []*Slice
+	for _, l := range []string{"A", "B", "C", "D", "E"} {
+		slices = append(slices, &Slice{Label: l, Value: 1})
+	}
+	if err := p.Slices(slices); err != nil {
+		t.Fatalf("Slices => unexpected error: %v", err)
+	}
+	ar := image.Rect(0, 0, 20, 7)
+	if err := p.Draw(testcanvas.MustNew(ar), &widgetapi.Meta{}); err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+	legendAr := p.lastLayout.legendAr
+
+	if err := p.Slices(slices[:2]); err != nil {
+		t.Fatalf("Slices => unexpected error: %v", err)
+	}
+	// The legend entry of the last of the previous slices.
+	last := image.Point{legendAr.Min.X, legendAr.Min.Y + 4}
+	if err := p.Mouse(&terminalapi.Mouse{Position: last, Button: mouse.ButtonLeft}); err != nil {
+		t.Fatalf("Mouse => unexpected error: %v", err)
+	}
+	if p.selected != -1 {
+		t.Errorf("Mouse => selected %d, want no selection", p.selected)
+	}
+	if err := p.Draw(testcanvas.MustNew(ar), &widgetapi.Meta{}); err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	p, err := New()
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+
+	got := p.Options()
+	want := widgetapi.Options{
+		MinimumSize:  image.Point{3, 3},
+		WantKeyboard: widgetapi.KeyScopeNone,
+		WantMouse:    widgetapi.MouseScopeWidget,
+	}
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary piedemo displays a couple of Pie widgets.
+// Exist when 'q' is pressed.
+package main
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/termbox"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgets/pie"
+)
+
+// playPie continuously changes the values of the slices once every delay.
+// Exits when the context expires.
+func playPie(ctx context.Context, p *pie.Pie, labels []string, delay time.Duration) {
+	ticker := time.NewTicker(delay)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			var slices []*pie.Slice
+			for _, l := range labels {
+				slices = append(slices, &pie.Slice{
+					Label: l,
+					Value: float64(rand.Intn(100) + 1),
+				})
+			}
+			if err := p.Slices(slices); err != nil {
+				panic(err)
+			}
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func main() {
+	t, err := termbox.New()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	donut, err := pie.New()
+	if err != nil {
+		panic(err)
+	}
+	if err := donut.Slices([]*pie.Slice{
+		{Label: "Go", Value: 45},
+		{Label: "Rust", Value: 25},
+		{Label: "Python", Value: 20},
+		{Label: "Other", Value: 10, Color: cell.ColorNumber(244)},
+	}); err != nil {
+		panic(err)
+	}
+
+	changing, err := pie.New(
+		pie.HolePercent(0),
+		pie.CounterClockwise(),
+		pie.StartAngle(0),
+	)
+	if err != nil {
+		panic(err)
+	}
+	go playPie(ctx, changing, []string{"CPU", "Memory", "Disk", "Network"}, 2*time.Second)
+
+	c, err := container.New(
+		t,
+		container.Border(linestyle.Light),
+		container.BorderTitle("PRESS Q TO QUIT, CLICK A SLICE TO SELECT IT"),
+		container.SplitVertical(
+			container.Left(
+				container.Border(linestyle.Light),
+				container.BorderTitle("Donut"),
+				container.PlaceWidget(donut),
+			),
+			container.Right(
+				container.Border(linestyle.Light),
+				container.BorderTitle("Pie"),
+				container.PlaceWidget(changing),
+			),
+		),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	quitter := func(k *terminalapi.Keyboard) {
+		if k.Key == 'q' || k.Key == 'Q' {
+			cancel()
+		}
+	}
+
+	if err := termdash.Run(ctx, t, c, termdash.KeyboardSubscriber(quitter), termdash.RedrawInterval(1*time.Second)); err != nil {
+		panic(err)
+	}
+}
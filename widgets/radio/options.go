@@ -0,0 +1,118 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package radio
+
+// options.go contains configurable options for Radio.
+
+import (
+	"fmt"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/runewidth"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// options holds the provided options.
+type options struct {
+	selected     int
+	selectedRune rune
+	horizontal   bool
+	markColor    cell.Color
+	textColor    cell.Color
+	onChange     CallbackFn
+}
+
+// validate validates the provided options.
+func (o *options) validate(count int) error {
+	if err := validateIndex(o.selected, count); err != nil {
+		return err
+	}
+	if got, want := runewidth.RuneWidth(o.selectedRune), 1; got != want {
+		return fmt.Errorf("invalid selected rune %q, must be %d cell wide, got %d", o.selectedRune, want, got)
+	}
+	return nil
+}
+
+// newOptions returns options with the default values set.
+func newOptions() *options {
+	return &options{
+		selectedRune: DefaultSelectedRune,
+		markColor:    cell.ColorDefault,
+		textColor:    cell.ColorDefault,
+	}
+}
+
+// Selected sets the index of the initially selected option.
+// Defaults to the first option.
+func Selected(i int) Option {
+	return option(func(opts *options) {
+		opts.selected = i
+	})
+}
+
+// DefaultSelectedRune is the default for the SelectedRune option.
+const DefaultSelectedRune = '*'
+
+// SelectedRune sets the rune displayed inside the mark of the selected
+// option. The rune must be one cell wide.
+// Defaults to DefaultSelectedRune.
+func SelectedRune(r rune) Option {
+	return option(func(opts *options) {
+		opts.selectedRune = r
+	})
+}
+
+// Horizontal lays out the options next to each other on a single line.
+// By default each option is on its own line.
+func Horizontal() Option {
+	return option(func(opts *options) {
+		opts.horizontal = true
+	})
+}
+
+// MarkColor sets the color of the marks.
+func MarkColor(c cell.Color) Option {
+	return option(func(opts *options) {
+		opts.markColor = c
+	})
+}
+
+// TextColor sets the color of the labels.
+func TextColor(c cell.Color) Option {
+	return option(func(opts *options) {
+		opts.textColor = c
+	})
+}
+
+// OnChange sets a function that is called each time the user selects a
+// different option.
+func OnChange(fn CallbackFn) Option {
+	return option(func(opts *options) {
+		opts.onChange = fn
+	})
+}
@@ -0,0 +1,289 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package radio implements an interactive group of options of which exactly
+// one is selected.
+package radio
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"sync"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/button"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/draw"
+	"github.com/mum4k/termdash/internal/runewidth"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// CallbackFn is the function called when the user selects an option. The
+// arguments are the index and the label of the selected option.
+//
+// The callback function must be thread-safe as the mouse or keyboard events
+// that change the selection are processed in a separate goroutine.
+//
+// If the function returns an error, the widget will forward it back to the
+// termdash infrastructure which causes a panic, unless the user provided a
+// termdash.ErrorHandler.
+type CallbackFn func(selected int, label string) error
+
+// Radio displays a group of labeled options, each with a mark that indicates
+// whether it is the selected one. The label of the selected option is
+// underlined while the container of the widget is focused.
+//
+// The user selects an option by a mouse click on it or by moving the
+// selection with the arrow keys while the container is focused.
+//
+// Implements widgetapi.Widget. This object is thread-safe.
+type Radio struct {
+	// labels are the labels of the options.
+	labels []string
+	// selected is the index of the selected option.
+	selected int
+
+	// mouseFSMs track left mouse clicks on each of the options.
+	mouseFSMs []*button.FSM
+
+	// mu protects the widget.
+	mu sync.Mutex
+
+	// opts are the provided options.
+	opts *options
+}
+
+// New returns a new Radio that displays options with the provided labels.
+// At least one label must be provided.
+func New(labels []string, opts ...Option) (*Radio, error) {
+	if len(labels) == 0 {
+		return nil, errors.New("at least one label must be provided")
+	}
+
+	opt := newOptions()
+	for _, o := range opts {
+		o.set(opt)
+	}
+	if err := opt.validate(len(labels)); err != nil {
+		return nil, err
+	}
+
+	var fsms []*button.FSM
+	for range labels {
+		fsms = append(fsms, button.NewFSM(mouse.ButtonLeft, image.ZR))
+	}
+	return &Radio{
+		labels:    labels,
+		selected:  opt.selected,
+		mouseFSMs: fsms,
+		opts:      opt,
+	}, nil
+}
+
+// Selected returns the index of the selected option.
+func (r *Radio) Selected() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.selected
+}
+
+// SetSelected selects the option at the provided index. Doesn't call the
+// OnChange callback.
+func (r *Radio) SetSelected(i int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := validateIndex(i, len(r.labels)); err != nil {
+		return err
+	}
+	r.selected = i
+	return nil
+}
+
+// validateIndex validates the index of an option.
+func validateIndex(i, count int) error {
+	if i < 0 || i >= count {
+		return fmt.Errorf("invalid option index %d, must be in range 0 <= i < %d", i, count)
+	}
+	return nil
+}
+
+// selectOption selects the option at the provided index and calls the
+// OnChange callback if the selection changed.
+// Caller must hold r.mu.
+func (r *Radio) selectOption(i int) error {
+	if i < 0 || i >= len(r.labels) || i == r.selected {
+		return nil
+	}
+	r.selected = i
+	if r.opts.onChange != nil {
+		return r.opts.onChange(r.selected, r.labels[r.selected])
+	}
+	return nil
+}
+
+const (
+	// markWidth is the width of the mark in cells, i.e. "(*)".
+	markWidth = 3
+
+	// horizontalGap is the number of cells between options laid out
+	// horizontally.
+	horizontalGap = 2
+)
+
+// itemWidth returns the width of the option with the provided label.
+func itemWidth(label string) int {
+	if label == "" {
+		return markWidth
+	}
+	return markWidth + 1 + runewidth.StringWidth(label)
+}
+
+// itemAreas returns the areas of the options within the canvas area. Areas
+// of options that don't fit are empty.
+func (r *Radio) itemAreas(cvsAr image.Rectangle) []image.Rectangle {
+	var areas []image.Rectangle
+	x, y := cvsAr.Min.X, cvsAr.Min.Y
+	for _, l := range r.labels {
+		var ar image.Rectangle
+		if r.opts.horizontal {
+			ar = image.Rect(x, y, x+itemWidth(l), y+1).Intersect(cvsAr)
+			x += itemWidth(l) + horizontalGap
+		} else {
+			ar = image.Rect(x, y, cvsAr.Max.X, y+1).Intersect(cvsAr)
+			y++
+		}
+		if ar.Dx() < markWidth {
+			ar = image.ZR
+		}
+		areas = append(areas, ar)
+	}
+	return areas
+}
+
+// mark returns the mark for the option at the provided index.
+func (r *Radio) mark(i int) string {
+	if i == r.selected {
+		return "(" + string(r.opts.selectedRune) + ")"
+	}
+	return "( )"
+}
+
+// Draw draws the Radio widget onto the canvas.
+// Implements widgetapi.Widget.Draw.
+func (r *Radio) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cvsAr := cvs.Area()
+	if cvsAr.Dx() < markWidth {
+		return draw.ResizeNeeded(cvs)
+	}
+
+	for i, ar := range r.itemAreas(cvsAr) {
+		r.mouseFSMs[i].UpdateArea(ar)
+		if ar.Empty() {
+			continue
+		}
+
+		if err := draw.Text(cvs, r.mark(i), ar.Min, draw.TextCellOpts(cell.FgColor(r.opts.markColor))); err != nil {
+			return err
+		}
+		if ar.Dx() <= markWidth+1 || r.labels[i] == "" {
+			continue
+		}
+
+		labelOpts := []cell.Option{cell.FgColor(r.opts.textColor)}
+		if meta.Focused && i == r.selected {
+			labelOpts = append(labelOpts, cell.Underline())
+		}
+		if err := draw.Text(cvs, r.labels[i], image.Point{ar.Min.X + markWidth + 1, ar.Min.Y},
+			draw.TextCellOpts(labelOpts...),
+			draw.TextMaxX(ar.Max.X),
+			draw.TextOverrunMode(draw.OverrunModeThreeDot),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Keyboard moves the selection on the arrow keys, the up and left arrows
+// select the previous option, the down and right arrows the next one.
+// Implements widgetapi.Widget.Keyboard.
+func (r *Radio) Keyboard(k *terminalapi.Keyboard) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch k.Key {
+	case keyboard.KeyArrowUp, keyboard.KeyArrowLeft:
+		return r.selectOption(r.selected - 1)
+	case keyboard.KeyArrowDown, keyboard.KeyArrowRight:
+		return r.selectOption(r.selected + 1)
+	}
+	return nil
+}
+
+// Mouse selects an option if both the press and the release of the left
+// mouse button happen inside it.
+// Implements widgetapi.Widget.Mouse.
+func (r *Radio) Mouse(m *terminalapi.Mouse) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clickedIdx := -1
+	for i, fsm := range r.mouseFSMs {
+		if clicked, _ := fsm.Event(m); clicked {
+			clickedIdx = i
+		}
+	}
+	return r.selectOption(clickedIdx)
+}
+
+// Options implements widgetapi.Widget.Options.
+func (r *Radio) Options() widgetapi.Options {
+	// No need to lock, as the labels are fixed when New is called.
+	var size image.Point
+	for i, l := range r.labels {
+		w := itemWidth(l)
+		if !r.opts.horizontal {
+			if w > size.X {
+				size.X = w
+			}
+			size.Y++
+			continue
+		}
+
+		if i > 0 {
+			size.X += horizontalGap
+		}
+		size.X += w
+		size.Y = 1
+	}
+
+	minSize := image.Point{markWidth, len(r.labels)}
+	if r.opts.horizontal {
+		minSize = image.Point{markWidth, 1}
+	}
+	return widgetapi.Options{
+		MinimumSize:  minSize,
+		MaximumSize:  size,
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		WantMouse:    widgetapi.MouseScopeWidget,
+	}
+}
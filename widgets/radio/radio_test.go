@@ -0,0 +1,409 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package radio
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"sync"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/canvas/testcanvas"
+	"github.com/mum4k/termdash/internal/draw"
+	"github.com/mum4k/termdash/internal/draw/testdraw"
+	"github.com/mum4k/termdash/internal/faketerm"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// callbackTracker tracks calls to the callback.
+type callbackTracker struct {
+	// wantErr when set to true, makes callback return an error.
+	wantErr bool
+
+	// calls are the values the callback was called with formatted as
+	// "index:label".
+	calls []string
+
+	// mu protects the tracker.
+	mu sync.Mutex
+}
+
+// callback is the callback function.
+func (ct *callbackTracker) callback(selected int, label string) error {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	if ct.wantErr {
+		return errors.New("ct.wantErr set to true")
+	}
+	ct.calls = append(ct.calls, fmt.Sprintf("%d:%s", selected, label))
+	return nil
+}
+
+// mustDrawOption draws an option with the provided mark and label starting
+// at the point.
+func mustDrawOption(cvs *canvas.Canvas, p image.Point, mark, label string, labelOpts ...cell.Option) {
+	testdraw.MustText(cvs, mark, p)
+	testdraw.MustText(cvs, label, image.Point{p.X + 4, p.Y}, draw.TextCellOpts(labelOpts...))
+}
+
+func TestRadio(t *testing.T) {
+	labels := []string{"low", "medium", "high"}
+
+	tests := []struct {
+		desc         string
+		labels       []string
+		opts         []Option
+		callback     *callbackTracker
+		setter       func(*Radio) error
+		events       []terminalapi.Event
+		canvas       image.Rectangle
+		meta         *widgetapi.Meta
+		want         func(size image.Point) *faketerm.Terminal
+		wantCalls    []string
+		wantSelected int
+		wantNewErr   bool
+		wantSetErr   bool
+		wantDrawErr  bool
+		wantEvErr    bool
+	}{
+		{
+			desc:       "New fails without labels",
+			canvas:     image.Rect(0, 0, 10, 3),
+			wantNewErr: true,
+		},
+		{
+			desc:       "New fails on selected index out of range",
+			labels:     labels,
+			opts:       []Option{Selected(3)},
+			canvas:     image.Rect(0, 0, 10, 3),
+			wantNewErr: true,
+		},
+		{
+			desc:       "New fails on wide selected rune",
+			labels:     labels,
+			opts:       []Option{SelectedRune('世')},
+			canvas:     image.Rect(0, 0, 10, 3),
+			wantNewErr: true,
+		},
+		{
+			desc:   "draws options vertically",
+			labels: labels,
+			canvas: image.Rect(0, 0, 10, 3),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustDrawOption(cvs, image.Point{0, 0}, "(*)", "low")
+				mustDrawOption(cvs, image.Point{0, 1}, "( )", "medium")
+				mustDrawOption(cvs, image.Point{0, 2}, "( )", "high")
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "draws options horizontally with custom selection, rune and colors",
+			labels: labels,
+			opts: []Option{
+				Horizontal(),
+				Selected(1),
+				SelectedRune('o'),
+				MarkColor(cell.ColorRed),
+				TextColor(cell.ColorBlue),
+			},
+			canvas: image.Rect(0, 0, 30, 1),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				markOpts := draw.TextCellOpts(cell.FgColor(cell.ColorRed))
+				testdraw.MustText(cvs, "( )", image.Point{0, 0}, markOpts)
+				testdraw.MustText(cvs, "low", image.Point{4, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorBlue)))
+				testdraw.MustText(cvs, "(o)", image.Point{9, 0}, markOpts)
+				testdraw.MustText(cvs, "medium", image.Point{13, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorBlue)))
+				testdraw.MustText(cvs, "( )", image.Point{21, 0}, markOpts)
+				testdraw.MustText(cvs, "high", image.Point{25, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorBlue)))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantSelected: 1,
+		},
+		{
+			desc:   "underlines the selected label when focused",
+			labels: labels,
+			canvas: image.Rect(0, 0, 10, 3),
+			meta:   &widgetapi.Meta{Focused: true},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustDrawOption(cvs, image.Point{0, 0}, "(*)", "low", cell.Underline())
+				mustDrawOption(cvs, image.Point{0, 1}, "( )", "medium")
+				mustDrawOption(cvs, image.Point{0, 2}, "( )", "high")
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "skips options that don't fit and trims labels",
+			labels: labels,
+			canvas: image.Rect(0, 0, 8, 2),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustDrawOption(cvs, image.Point{0, 0}, "(*)", "low")
+				mustDrawOption(cvs, image.Point{0, 1}, "( )", "med…")
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "draw fails on canvas too small",
+			labels: labels,
+			canvas: image.Rect(0, 0, 2, 3),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustResizeNeeded(cvs)
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:     "arrow keys move the selection within the options",
+			labels:   labels,
+			callback: &callbackTracker{},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowUp},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowRight},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowLeft},
+			},
+			canvas: image.Rect(0, 0, 10, 3),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustDrawOption(cvs, image.Point{0, 0}, "( )", "low")
+				mustDrawOption(cvs, image.Point{0, 1}, "(*)", "medium")
+				mustDrawOption(cvs, image.Point{0, 2}, "( )", "high")
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantCalls:    []string{"1:medium", "2:high", "1:medium"},
+			wantSelected: 1,
+		},
+		{
+			desc:     "mouse click selects an option",
+			labels:   labels,
+			callback: &callbackTracker{},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{5, 2}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{5, 2}, Button: mouse.ButtonRelease},
+				// Press and release on different options.
+				&terminalapi.Mouse{Position: image.Point{5, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{5, 1}, Button: mouse.ButtonRelease},
+			},
+			canvas:       image.Rect(0, 0, 10, 3),
+			meta:         &widgetapi.Meta{},
+			wantCalls:    []string{"2:high"},
+			wantSelected: 2,
+		},
+		{
+			desc:     "mouse click selects an option laid out horizontally",
+			labels:   labels,
+			opts:     []Option{Horizontal()},
+			callback: &callbackTracker{},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{10, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{10, 0}, Button: mouse.ButtonRelease},
+			},
+			canvas:       image.Rect(0, 0, 30, 1),
+			meta:         &widgetapi.Meta{},
+			wantCalls:    []string{"1:medium"},
+			wantSelected: 1,
+		},
+		{
+			desc:     "SetSelected doesn't call the callback",
+			labels:   labels,
+			callback: &callbackTracker{},
+			setter: func(r *Radio) error {
+				return r.SetSelected(2)
+			},
+			canvas:       image.Rect(0, 0, 10, 3),
+			meta:         &widgetapi.Meta{},
+			wantSelected: 2,
+		},
+		{
+			desc:   "SetSelected fails on index out of range",
+			labels: labels,
+			setter: func(r *Radio) error {
+				return r.SetSelected(-1)
+			},
+			canvas:     image.Rect(0, 0, 10, 3),
+			meta:       &widgetapi.Meta{},
+			wantSetErr: true,
+		},
+		{
+			desc:     "forwards errors from the callback",
+			labels:   labels,
+			callback: &callbackTracker{wantErr: true},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+			},
+			canvas:       image.Rect(0, 0, 10, 3),
+			meta:         &widgetapi.Meta{},
+			wantEvErr:    true,
+			wantSelected: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			opts := tc.opts
+			if tc.callback != nil {
+				opts = append(opts, OnChange(tc.callback.callback))
+			}
+			r, err := New(tc.labels, opts...)
+			if (err != nil) != tc.wantNewErr {
+				t.Errorf("New => unexpected error: %v, wantNewErr: %v", err, tc.wantNewErr)
+			}
+			if err != nil {
+				return
+			}
+
+			{
+				// Draw once which initializes the mouse state machines with the current canvas area.
+				c, err := canvas.New(tc.canvas)
+				if err != nil {
+					t.Fatalf("canvas.New => unexpected error: %v", err)
+				}
+				if err := r.Draw(c, tc.meta); err != nil && !tc.wantDrawErr {
+					t.Fatalf("Draw => unexpected error: %v", err)
+				}
+			}
+
+			if tc.setter != nil {
+				err := tc.setter(r)
+				if (err != nil) != tc.wantSetErr {
+					t.Errorf("setter => unexpected error: %v, wantSetErr: %v", err, tc.wantSetErr)
+				}
+			}
+			for _, ev := range tc.events {
+				var err error
+				switch e := ev.(type) {
+				case *terminalapi.Mouse:
+					err = r.Mouse(e)
+				case *terminalapi.Keyboard:
+					err = r.Keyboard(e)
+				default:
+					t.Fatalf("unsupported event type: %T", ev)
+				}
+				if (err != nil) != tc.wantEvErr {
+					t.Errorf("event %v => unexpected error: %v, wantEvErr: %v", ev, err, tc.wantEvErr)
+				}
+			}
+
+			if got := r.Selected(); got != tc.wantSelected {
+				t.Errorf("Selected => %v, want %v", got, tc.wantSelected)
+			}
+			if tc.callback != nil {
+				if diff := pretty.Compare(tc.wantCalls, tc.callback.calls); diff != "" {
+					t.Errorf("CallbackFn => unexpected diff (-want, +got):\n%s", diff)
+				}
+			}
+			if tc.want == nil {
+				return
+			}
+
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+			err = r.Draw(c, tc.meta)
+			if (err != nil) != tc.wantDrawErr {
+				t.Errorf("Draw => unexpected error: %v, wantDrawErr: %v", err, tc.wantDrawErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+		})
+	}
+}
+
+func TestOptions(t *testing.T) {
+	tests := []struct {
+		desc   string
+		labels []string
+		opts   []Option
+		want   widgetapi.Options
+	}{
+		{
+			desc:   "vertical size is based on the longest label",
+			labels: []string{"low", "medium", ""},
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{3, 3},
+				MaximumSize:  image.Point{10, 3},
+				WantKeyboard: widgetapi.KeyScopeFocused,
+				WantMouse:    widgetapi.MouseScopeWidget,
+			},
+		},
+		{
+			desc:   "horizontal size is based on all the labels",
+			labels: []string{"low", "medium", ""},
+			opts:   []Option{Horizontal()},
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{3, 1},
+				MaximumSize:  image.Point{24, 1},
+				WantKeyboard: widgetapi.KeyScopeFocused,
+				WantMouse:    widgetapi.MouseScopeWidget,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			r, err := New(tc.labels, tc.opts...)
+			if err != nil {
+				t.Fatalf("New => unexpected error: %v", err)
+			}
+
+			got := r.Options()
+			if diff := pretty.Compare(tc.want, got); diff != "" {
+				t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
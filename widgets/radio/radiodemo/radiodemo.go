@@ -0,0 +1,108 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary radiodemo shows the functionality of the radio widget.
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/termbox"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgets/radio"
+	"github.com/mum4k/termdash/widgets/text"
+)
+
+// newRadio returns a radio group that logs selection changes into the text
+// widget.
+func newRadio(name string, labels []string, log *text.Text, opts ...radio.Option) (*radio.Radio, error) {
+	opts = append(opts, radio.OnChange(func(selected int, label string) error {
+		return log.Write(fmt.Sprintf("%s: selected %q\n", name, label))
+	}))
+	return radio.New(labels, opts...)
+}
+
+func main() {
+	t, err := termbox.New()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	log, err := text.New(text.RollContent())
+	if err != nil {
+		panic(err)
+	}
+
+	interval, err := newRadio("Interval", []string{"1s", "5s", "30s", "1m"}, log,
+		radio.Selected(1),
+	)
+	if err != nil {
+		panic(err)
+	}
+	units, err := newRadio("Units", []string{"bytes", "bits"}, log,
+		radio.Horizontal(),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	c, err := container.New(
+		t,
+		container.Border(linestyle.Light),
+		container.BorderTitle("PRESS Q TO QUIT"),
+		container.SplitHorizontal(
+			container.Top(
+				container.SplitVertical(
+					container.Left(
+						container.Border(linestyle.Light),
+						container.BorderTitle("Interval"),
+						container.PlaceWidget(interval),
+					),
+					container.Right(
+						container.Border(linestyle.Light),
+						container.BorderTitle("Units"),
+						container.PlaceWidget(units),
+					),
+				),
+			),
+			container.Bottom(
+				container.Border(linestyle.Light),
+				container.BorderTitle("Click or use the arrows on a focused group"),
+				container.PlaceWidget(log),
+			),
+			container.SplitPercent(40),
+		),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	quitter := func(k *terminalapi.Keyboard) {
+		if k.Key == 'q' || k.Key == 'Q' {
+			cancel()
+		}
+	}
+
+	if err := termdash.Run(ctx, t, c, termdash.KeyboardSubscriber(quitter), termdash.RedrawInterval(100*time.Millisecond)); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scatter
+
+import (
+	"fmt"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/zoom"
+)
+
+// options.go contains configurable options for Scatter.
+
+// Option is used to provide options to New().
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// options stores the provided options.
+type options struct {
+	axesCellOpts       []cell.Option
+	xLabelCellOpts     []cell.Option
+	yLabelCellOpts     []cell.Option
+	zoomHighlightColor cell.Color
+	zoomStepPercent    int
+}
+
+// validate validates the provided options.
+func (o *options) validate() error {
+	if got, min, max := o.zoomStepPercent, 1, 100; got < min || got > max {
+		return fmt.Errorf("invalid ZoomStepPercent %d, must be in range %d <= value <= %d", got, min, max)
+	}
+	return nil
+}
+
+// newOptions returns a new options instance.
+func newOptions(opts ...Option) *options {
+	opt := &options{
+		zoomHighlightColor: cell.ColorNumber(235),
+		zoomStepPercent:    zoom.DefaultScrollStep,
+	}
+	for _, o := range opts {
+		o.set(opt)
+	}
+	return opt
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// AxesCellOpts set the cell options for the X and Y axes.
+func AxesCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.axesCellOpts = co
+	})
+}
+
+// XLabelCellOpts set the cell options for the labels on the X axis.
+func XLabelCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.xLabelCellOpts = co
+	})
+}
+
+// YLabelCellOpts set the cell options for the labels on the Y axis.
+func YLabelCellOpts(co ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.yLabelCellOpts = co
+	})
+}
+
+// ZoomHighlightColor sets the background color of the area that is selected
+// with mouse in order to zoom the scatter plot.
+// Defaults to color number 235.
+func ZoomHighlightColor(c cell.Color) Option {
+	return option(func(opts *options) {
+		opts.zoomHighlightColor = c
+	})
+}
+
+// ZoomStepPercent sets the zooming step on each mouse scroll event as the
+// percentage of the size of the X axis.
+// The value must be in range 0 < value <= 100.
+// Defaults to zoom.DefaultScrollStep.
+func ZoomStepPercent(perc int) Option {
+	return option(func(opts *options) {
+		opts.zoomStepPercent = perc
+	})
+}
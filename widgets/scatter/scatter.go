@@ -0,0 +1,526 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package scatter contains a widget that displays scatter plots.
+package scatter
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/area"
+	"github.com/mum4k/termdash/internal/axes"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/canvas/braille"
+	"github.com/mum4k/termdash/internal/draw"
+	"github.com/mum4k/termdash/internal/numbers"
+	"github.com/mum4k/termdash/internal/zoom"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+const (
+	// xResolution is the number of steps the range of the X values is split
+	// into. The X axis and the zoom tracker work with integer positions, each
+	// position represents one step.
+	xResolution = 1000
+
+	// nonZeroDecimals is the precision of the values in the labels of the X
+	// axis, see axes.NewValue.
+	nonZeroDecimals = 2
+)
+
+// Point is one point on the scatter plot.
+type Point struct {
+	// X is the value on the X axis.
+	X float64
+	// Y is the value on the Y axis.
+	Y float64
+}
+
+// MarkerStyle determines how the points of a series are drawn.
+type MarkerStyle int
+
+// String implements fmt.Stringer()
+func (ms MarkerStyle) String() string {
+	if n, ok := markerStyleNames[ms]; ok {
+		return n
+	}
+	return "MarkerStyleUnknown"
+}
+
+// markerStyleNames maps MarkerStyle values to human readable names.
+var markerStyleNames = map[MarkerStyle]string{
+	MarkerDot:    "MarkerDot",
+	MarkerPlus:   "MarkerPlus",
+	MarkerCross:  "MarkerCross",
+	MarkerSquare: "MarkerSquare",
+}
+
+const (
+	// MarkerDot draws each point as a single braille pixel.
+	MarkerDot MarkerStyle = iota
+
+	// MarkerPlus draws each point as a plus sign, three braille pixels wide
+	// and high.
+	MarkerPlus
+
+	// MarkerCross draws each point as a diagonal cross, three braille pixels
+	// wide and high.
+	MarkerCross
+
+	// MarkerSquare draws each point as a square of two by two braille pixels.
+	MarkerSquare
+)
+
+// markerPixels are the pixels set for each marker style, relative to the
+// pixel that represents the point.
+var markerPixels = map[MarkerStyle][]image.Point{
+	MarkerDot:    {{0, 0}},
+	MarkerPlus:   {{0, 0}, {-1, 0}, {1, 0}, {0, -1}, {0, 1}},
+	MarkerCross:  {{0, 0}, {-1, -1}, {1, -1}, {-1, 1}, {1, 1}},
+	MarkerSquare: {{0, 0}, {1, 0}, {0, 1}, {1, 1}},
+}
+
+// seriesValues represent values stored in the series.
+type seriesValues struct {
+	// points are the points in the series.
+	points []Point
+	// xMin and xMax are the smallest and the largest X values, NaN if the
+	// series has no valid points.
+	xMin, xMax float64
+	// yMin and yMax are the smallest and the largest Y values, NaN if the
+	// series has no valid points.
+	yMin, yMax float64
+
+	seriesCellOpts []cell.Option
+	marker         MarkerStyle
+}
+
+// newSeriesValues returns a new seriesValues instance.
+func newSeriesValues(points []Point) *seriesValues {
+	// Copy to avoid external modifications. See #174.
+	p := make([]Point, len(points))
+	copy(p, points)
+
+	var xs, ys []float64
+	for _, pt := range p {
+		if isMissing(pt) {
+			continue
+		}
+		xs = append(xs, pt.X)
+		ys = append(ys, pt.Y)
+	}
+	sv := &seriesValues{
+		points: p,
+		xMin:   math.NaN(),
+		xMax:   math.NaN(),
+		yMin:   math.NaN(),
+		yMax:   math.NaN(),
+	}
+	if len(xs) > 0 {
+		sv.xMin, sv.xMax = numbers.MinMax(xs)
+		sv.yMin, sv.yMax = numbers.MinMax(ys)
+	}
+	return sv
+}
+
+// isMissing asserts whether the point should be skipped, i.e. if any of its
+// coordinates is NaN.
+func isMissing(p Point) bool {
+	return math.IsNaN(p.X) || math.IsNaN(p.Y)
+}
+
+// Scatter draws scatter plots.
+//
+// Each scatter plot has an identifying label and a set of points that are
+// plotted. Unlike the LineChart, both the X and the Y values of the points
+// are arbitrary numbers and the points aren't connected.
+//
+// The X and the Y axes are scaled independently so that they can accommodate
+// the smallest and the largest value among all the series.
+//
+// Scatter supports mouse based zoom of the X axis, zooming is achieved by
+// either highlighting an area on the graph (left mouse clicking and dragging)
+// or by using the mouse scroll button.
+//
+// Implements widgetapi.Widget. This object is thread-safe.
+type Scatter struct {
+	// mu protects the Scatter widget.
+	mu sync.Mutex
+
+	// series are the series that will be plotted.
+	// Keyed by the name of the series and updated by calling Series.
+	series map[string]*seriesValues
+
+	// xMin and xMax are the boundary values of the X axis.
+	xMin, xMax float64
+	// yMin and yMax are the boundary values of the Y axis.
+	yMin, yMax float64
+
+	// xLabels are the labels of the X axis keyed by the integer position on
+	// the axis.
+	xLabels map[int]string
+
+	// opts are the provided options.
+	opts *options
+
+	// zoom tracks the zooming of the X axis.
+	zoom *zoom.Tracker
+}
+
+// New returns a new scatter plot widget.
+func New(opts ...Option) (*Scatter, error) {
+	opt := newOptions(opts...)
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	s := &Scatter{
+		series: map[string]*seriesValues{},
+		opts:   opt,
+	}
+	s.updateBounds()
+	return s, nil
+}
+
+// SeriesOption is used to provide options to Series.
+type SeriesOption interface {
+	// set sets the provided option.
+	set(*seriesValues)
+}
+
+// seriesOption implements SeriesOption.
+type seriesOption func(*seriesValues)
+
+// set implements SeriesOption.set.
+func (so seriesOption) set(sv *seriesValues) {
+	so(sv)
+}
+
+// SeriesCellOpts sets the cell options for this series.
+// Note that the braille canvas has resolution of 2x4 pixels per cell, but each
+// cell can only have one set of cell options set. Meaning that where series
+// share a cell, the last drawn series sets the cell options. Series are drawn
+// in alphabetical order based on their name.
+func SeriesCellOpts(co ...cell.Option) SeriesOption {
+	return seriesOption(func(opts *seriesValues) {
+		opts.seriesCellOpts = co
+	})
+}
+
+// SeriesMarker sets the style of the markers that represent the points of
+// this series. Defaults to MarkerDot.
+func SeriesMarker(ms MarkerStyle) SeriesOption {
+	return seriesOption(func(opts *seriesValues) {
+		opts.marker = ms
+	})
+}
+
+// Series sets the points that should be displayed as the scatter plot with
+// the provided label.
+// The points that should not be displayed should have math.NaN as either of
+// their coordinates. The coordinates cannot be infinite.
+// Subsequent calls with the same label replace any previously provided points.
+func (s *Scatter) Series(label string, points []Point, opts ...SeriesOption) error {
+	if label == "" {
+		return errors.New("the label cannot be empty")
+	}
+	for i, p := range points {
+		if math.IsInf(p.X, 0) || math.IsInf(p.Y, 0) {
+			return fmt.Errorf("invalid point %d -> %+v, the coordinates cannot be infinite", i, p)
+		}
+	}
+
+	series := newSeriesValues(points)
+	for _, opt := range opts {
+		opt.set(series)
+	}
+	if _, ok := markerPixels[series.marker]; !ok {
+		return fmt.Errorf("unsupported marker style %v(%d)", series.marker, series.marker)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.series[label] = series
+	s.updateBounds()
+	return nil
+}
+
+// updateBounds determines the boundary values of both axes and the labels of
+// the X axis.
+// s.mu must be held when calling this method.
+func (s *Scatter) updateBounds() {
+	var xs, ys []float64
+	for _, sv := range s.series {
+		xs = append(xs, sv.xMin, sv.xMax)
+		ys = append(ys, sv.yMin, sv.yMax)
+	}
+
+	xMin, xMax := minMax(xs)
+	if xMax <= xMin {
+		// The X axis needs a non-zero range.
+		xMax = xMin + 1
+	}
+	s.xMin, s.xMax = xMin, xMax
+	s.yMin, s.yMax = minMax(ys)
+
+	s.xLabels = make(map[int]string, xResolution+1)
+	for pos := 0; pos <= xResolution; pos++ {
+		s.xLabels[pos] = axes.NewValue(s.xValue(float64(pos)), nonZeroDecimals).Text()
+	}
+}
+
+// xValue returns the X value at the position on the X axis.
+func (s *Scatter) xValue(pos float64) float64 {
+	return s.xMin + pos*(s.xMax-s.xMin)/xResolution
+}
+
+// xPosition returns the position of the X value on the X axis.
+func (s *Scatter) xPosition(x float64) float64 {
+	return (x - s.xMin) / (s.xMax - s.xMin) * xResolution
+}
+
+// axesDetails determines the details about the X and Y axes.
+func (s *Scatter) axesDetails(cvs *canvas.Canvas) (*axes.XDetails, *axes.YDetails, error) {
+	yp := &axes.YProperties{
+		Min:        s.yMin,
+		Max:        s.yMax,
+		ReqXHeight: axes.RequiredHeight(xResolution, nil, axes.LabelOrientationHorizontal),
+		ScaleMode:  axes.YScaleModeAdaptive,
+	}
+	yd, err := axes.NewYDetails(cvs.Area(), yp)
+	if err != nil {
+		return nil, nil, fmt.Errorf("NewYDetails => %v", err)
+	}
+
+	xp := &axes.XProperties{
+		Min:          0,
+		Max:          xResolution,
+		ReqYWidth:    yd.Start.X,
+		CustomLabels: s.xLabels,
+		LO:           axes.LabelOrientationHorizontal,
+	}
+	xd, err := axes.NewXDetails(cvs.Area(), xp)
+	if err != nil {
+		return nil, nil, fmt.Errorf("NewXDetails => %v", err)
+	}
+	return xd, yd, nil
+}
+
+// Draw draws the points as scatter plots.
+// Implements widgetapi.Widget.Draw.
+func (s *Scatter) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	needAr, err := area.FromSize(s.minSize())
+	if err != nil {
+		return err
+	}
+	if !needAr.In(cvs.Area()) {
+		return draw.ResizeNeeded(cvs)
+	}
+
+	xd, yd, err := s.axesDetails(cvs)
+	if err != nil {
+		return err
+	}
+
+	zoomedXD, err := s.drawSeries(cvs, xd, yd)
+	if err != nil {
+		return err
+	}
+	return s.drawAxes(cvs, zoomedXD, yd)
+}
+
+// drawAxes draws the X,Y axes and their labels.
+func (s *Scatter) drawAxes(cvs *canvas.Canvas, xd *axes.XDetails, yd *axes.YDetails) error {
+	lines := []draw.HVLine{
+		{Start: yd.Start, End: yd.End},
+		{Start: xd.Start, End: xd.End},
+	}
+	if err := draw.HVLines(cvs, lines, draw.HVLineCellOpts(s.opts.axesCellOpts...)); err != nil {
+		return fmt.Errorf("failed to draw the axes: %v", err)
+	}
+
+	for _, l := range yd.Labels {
+		if err := draw.Text(cvs, l.Value.Text(), l.Pos,
+			draw.TextMaxX(yd.Start.X),
+			draw.TextOverrunMode(draw.OverrunModeThreeDot),
+			draw.TextCellOpts(s.opts.yLabelCellOpts...),
+		); err != nil {
+			return fmt.Errorf("failed to draw the Y labels: %v", err)
+		}
+	}
+
+	for _, l := range xd.Labels {
+		if err := draw.Text(cvs, l.Value.Text(), l.Pos, draw.TextCellOpts(s.opts.xLabelCellOpts...)); err != nil {
+			return fmt.Errorf("failed to draw the X labels: %v", err)
+		}
+	}
+	return nil
+}
+
+// drawSeries draws the points of the stored series.
+// Returns the X axis adjusted according to the current zoom.
+func (s *Scatter) drawSeries(cvs *canvas.Canvas, xd *axes.XDetails, yd *axes.YDetails) (*axes.XDetails, error) {
+	graphAr := image.Rect(yd.Start.X+1, yd.Start.Y, cvs.Area().Max.X, xd.End.Y)
+	bc, err := braille.New(graphAr)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.zoom == nil {
+		z, err := zoom.New(xd, cvs.Area(), graphAr, zoom.ScrollStep(s.opts.zoomStepPercent))
+		if err != nil {
+			return nil, err
+		}
+		s.zoom = z
+	} else {
+		if err := s.zoom.Update(xd, cvs.Area(), graphAr); err != nil {
+			return nil, err
+		}
+	}
+
+	zoomedXD := s.zoom.Zoom()
+	var names []string
+	for name := range s.series {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		sv := s.series[name]
+		for i, p := range sv.points {
+			if isMissing(p) {
+				continue
+			}
+
+			x, ok := s.xPixel(zoomedXD, p.X, bc.Area().Dx())
+			if !ok {
+				// Don't draw points outside of the current zoom.
+				continue
+			}
+			y, err := yd.Scale.ValueToPixel(p.Y)
+			if err != nil {
+				return nil, fmt.Errorf("failure for series %v[%d] on scale %v, yd.Scale.ValueToPixel(%v) => %v", name, i, yd.Scale, p.Y, err)
+			}
+
+			for _, mp := range markerPixels[sv.marker] {
+				px := image.Point{x + mp.X, y + mp.Y}
+				if !px.In(bc.Area()) {
+					continue
+				}
+				if err := bc.SetPixel(px, sv.seriesCellOpts...); err != nil {
+					return nil, fmt.Errorf("bc.SetPixel => %v", err)
+				}
+			}
+		}
+	}
+
+	if highlight, hRange := s.zoom.Highlight(); highlight {
+		cellAr := bc.CellArea()
+		ar := image.Rect(hRange.Start, cellAr.Min.Y, hRange.End, cellAr.Max.Y)
+		if err := bc.SetAreaCellOpts(ar, cell.BgColor(s.opts.zoomHighlightColor)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := bc.CopyTo(cvs); err != nil {
+		return nil, fmt.Errorf("bc.Apply => %v", err)
+	}
+	return zoomedXD, nil
+}
+
+// xPixel determines the X coordinate of the braille pixel that represents the
+// value on the provided X axis, which might be zoomed. The width is the width
+// of the braille canvas in pixels.
+// Returns false if the value falls outside of the axis.
+func (s *Scatter) xPixel(xd *axes.XDetails, v float64, width int) (int, bool) {
+	pos := s.xPosition(v)
+	min, max := xd.Scale.Min.Value, xd.Scale.Max.Value
+	if pos < min || pos > max {
+		return 0, false
+	}
+	if xd.Scale.Step.Rounded == 0 {
+		return 0, true
+	}
+
+	// The step is rounded, the value at the end of the axis might end up
+	// just past the last pixel.
+	x := int(math.Round((pos - min) / xd.Scale.Step.Rounded))
+	if x >= width {
+		x = width - 1
+	}
+	return x, true
+}
+
+// Keyboard implements widgetapi.Widget.Keyboard.
+func (s *Scatter) Keyboard(k *terminalapi.Keyboard) error {
+	return errors.New("the Scatter widget doesn't support keyboard events")
+}
+
+// Mouse implements widgetapi.Widget.Mouse.
+func (s *Scatter) Mouse(m *terminalapi.Mouse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.zoom == nil {
+		return nil
+	}
+	return s.zoom.Mouse(m)
+}
+
+// minSize determines the minimum required size to draw the scatter plot.
+func (s *Scatter) minSize() image.Point {
+	// At the very least we need:
+	// - n cells width for the Y axis and its labels as reported by it.
+	// - at least 1 cell width for the graph.
+	reqWidth := axes.RequiredWidth(s.yMin, s.yMax) + 1
+
+	// And for the height:
+	// - n cells width for the X axis and its labels as reported by it.
+	// - at least 2 cell height for the graph.
+	reqHeight := axes.RequiredHeight(xResolution, nil, axes.LabelOrientationHorizontal) + 2
+	return image.Point{reqWidth, reqHeight}
+}
+
+// Options implements widgetapi.Widget.Options.
+func (s *Scatter) Options() widgetapi.Options {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return widgetapi.Options{
+		MinimumSize: s.minSize(),
+		WantMouse:   widgetapi.MouseScopeGlobal,
+	}
+}
+
+// minMax is a wrapper around numbers.MinMax that returns zero values if
+// there are no valid values.
+func minMax(values []float64) (float64, float64) {
+	min, max := numbers.MinMax(values)
+	if math.IsNaN(min) {
+		min = 0
+	}
+	if math.IsNaN(max) {
+		max = 0
+	}
+	return min, max
+}
@@ -0,0 +1,511 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scatter
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/canvas/braille/testbraille"
+	"github.com/mum4k/termdash/internal/canvas/testcanvas"
+	"github.com/mum4k/termdash/internal/draw"
+	"github.com/mum4k/termdash/internal/draw/testdraw"
+	"github.com/mum4k/termdash/internal/faketerm"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// mustDrawAxes draws the axes and labels used in the tests that display the
+// three points provided by threePoints on a canvas of size 20x10.
+func mustDrawAxes(c *canvas.Canvas, xLabels ...string) {
+	lines := []draw.HVLine{
+		{Start: image.Point{4, 0}, End: image.Point{4, 8}},
+		{Start: image.Point{4, 8}, End: image.Point{19, 8}},
+	}
+	testdraw.MustHVLines(c, lines)
+
+	testdraw.MustText(c, "0", image.Point{3, 7})
+	testdraw.MustText(c, "5.28", image.Point{0, 3})
+	testdraw.MustText(c, xLabels[0], image.Point{5, 9})
+	testdraw.MustText(c, xLabels[1], image.Point{10, 9})
+}
+
+// threePoints are points used in the tests.
+var threePoints = []Point{{-1, 0}, {1, 10}, {0, 5}}
+
+func TestScatterDraws(t *testing.T) {
+	tests := []struct {
+		desc         string
+		canvas       image.Rectangle
+		opts         []Option
+		writes       func(*Scatter) error
+		want         func(size image.Point) *faketerm.Terminal
+		wantErr      bool
+		wantWriteErr bool
+	}{
+		{
+			desc:   "fails with scroll step too low",
+			canvas: image.Rect(0, 0, 3, 4),
+			opts: []Option{
+				ZoomStepPercent(0),
+			},
+			wantErr: true,
+		},
+		{
+			desc:   "fails with scroll step too high",
+			canvas: image.Rect(0, 0, 3, 4),
+			opts: []Option{
+				ZoomStepPercent(101),
+			},
+			wantErr: true,
+		},
+		{
+			desc:   "series fails without name for the series",
+			canvas: image.Rect(0, 0, 3, 4),
+			writes: func(s *Scatter) error {
+				return s.Series("", threePoints)
+			},
+			wantWriteErr: true,
+		},
+		{
+			desc:   "series fails on infinite coordinate",
+			canvas: image.Rect(0, 0, 3, 4),
+			writes: func(s *Scatter) error {
+				return s.Series("first", []Point{{0, 1}, {math.Inf(1), 2}})
+			},
+			wantWriteErr: true,
+		},
+		{
+			desc:   "series fails on unsupported marker style",
+			canvas: image.Rect(0, 0, 3, 4),
+			writes: func(s *Scatter) error {
+				return s.Series("first", threePoints, SeriesMarker(MarkerStyle(-1)))
+			},
+			wantWriteErr: true,
+		},
+		{
+			desc:   "draws resize needed character when canvas is smaller than requested",
+			canvas: image.Rect(0, 0, 1, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				testdraw.MustResizeNeeded(c)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "empty without series",
+			canvas: image.Rect(0, 0, 3, 4),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				// Y and X axis.
+				lines := []draw.HVLine{
+					{Start: image.Point{1, 0}, End: image.Point{1, 2}},
+					{Start: image.Point{1, 2}, End: image.Point{2, 2}},
+				}
+				testdraw.MustHVLines(c, lines)
+
+				// Zero value labels.
+				testdraw.MustText(c, "0", image.Point{0, 1})
+				testdraw.MustText(c, "0", image.Point{2, 3})
+
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "sets axes and label cell options",
+			canvas: image.Rect(0, 0, 3, 4),
+			opts: []Option{
+				AxesCellOpts(cell.FgColor(cell.ColorGreen)),
+				XLabelCellOpts(cell.FgColor(cell.ColorBlue)),
+				YLabelCellOpts(cell.FgColor(cell.ColorRed)),
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				// Y and X axis.
+				lines := []draw.HVLine{
+					{Start: image.Point{1, 0}, End: image.Point{1, 2}},
+					{Start: image.Point{1, 2}, End: image.Point{2, 2}},
+				}
+				testdraw.MustHVLines(c, lines, draw.HVLineCellOpts(cell.FgColor(cell.ColorGreen)))
+
+				// Zero value labels.
+				testdraw.MustText(c, "0", image.Point{0, 1}, draw.TextCellOpts(cell.FgColor(cell.ColorRed)))
+				testdraw.MustText(c, "0", image.Point{2, 3}, draw.TextCellOpts(cell.FgColor(cell.ColorBlue)))
+
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "draws points with negative X values",
+			canvas: image.Rect(0, 0, 20, 10),
+			writes: func(s *Scatter) error {
+				return s.Series("first", threePoints)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				mustDrawAxes(c, "-1", "-0.31")
+
+				bc := testbraille.MustNew(image.Rect(5, 0, 20, 8))
+				testbraille.MustSetPixel(bc, image.Point{0, 31})
+				testbraille.MustSetPixel(bc, image.Point{29, 1})
+				testbraille.MustSetPixel(bc, image.Point{14, 16})
+				testbraille.MustCopyTo(bc, c)
+
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "skips points with NaN coordinates",
+			canvas: image.Rect(0, 0, 20, 10),
+			writes: func(s *Scatter) error {
+				points := append([]Point{{math.NaN(), 100}, {-100, math.NaN()}}, threePoints...)
+				return s.Series("first", points)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				mustDrawAxes(c, "-1", "-0.31")
+
+				bc := testbraille.MustNew(image.Rect(5, 0, 20, 8))
+				testbraille.MustSetPixel(bc, image.Point{0, 31})
+				testbraille.MustSetPixel(bc, image.Point{29, 1})
+				testbraille.MustSetPixel(bc, image.Point{14, 16})
+				testbraille.MustCopyTo(bc, c)
+
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "draws multiple series with cell options and markers",
+			canvas: image.Rect(0, 0, 20, 10),
+			writes: func(s *Scatter) error {
+				if err := s.Series("first", threePoints[:2], SeriesCellOpts(cell.FgColor(cell.ColorBlue))); err != nil {
+					return err
+				}
+				return s.Series("second", threePoints[2:],
+					SeriesCellOpts(cell.FgColor(cell.ColorRed)),
+					SeriesMarker(MarkerPlus),
+				)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				mustDrawAxes(c, "-1", "-0.31")
+
+				bc := testbraille.MustNew(image.Rect(5, 0, 20, 8))
+				testbraille.MustSetPixel(bc, image.Point{0, 31}, cell.FgColor(cell.ColorBlue))
+				testbraille.MustSetPixel(bc, image.Point{29, 1}, cell.FgColor(cell.ColorBlue))
+				for _, p := range []image.Point{{14, 16}, {13, 16}, {15, 16}, {14, 15}, {14, 17}} {
+					testbraille.MustSetPixel(bc, p, cell.FgColor(cell.ColorRed))
+				}
+				testbraille.MustCopyTo(bc, c)
+
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "markers are clipped at the edges of the graph",
+			canvas: image.Rect(0, 0, 20, 10),
+			writes: func(s *Scatter) error {
+				return s.Series("first", threePoints, SeriesMarker(MarkerSquare))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				mustDrawAxes(c, "-1", "-0.31")
+
+				bc := testbraille.MustNew(image.Rect(5, 0, 20, 8))
+				for _, p := range []image.Point{
+					{0, 31}, {1, 31},
+					{29, 1}, {29, 2},
+					{14, 16}, {15, 16}, {14, 17}, {15, 17},
+				} {
+					testbraille.MustSetPixel(bc, p)
+				}
+				testbraille.MustCopyTo(bc, c)
+
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "highlights area for zoom",
+			canvas: image.Rect(0, 0, 20, 10),
+			writes: func(s *Scatter) error {
+				if err := s.Series("first", threePoints); err != nil {
+					return err
+				}
+				// Draw once so zoom tracker is initialized.
+				cvs := testcanvas.MustNew(image.Rect(0, 0, 20, 10))
+				if err := s.Draw(cvs, &widgetapi.Meta{}); err != nil {
+					return err
+				}
+				return s.Mouse(&terminalapi.Mouse{
+					Position: image.Point{6, 5},
+					Button:   mouse.ButtonLeft,
+				})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				mustDrawAxes(c, "-1", "-0.31")
+
+				bc := testbraille.MustNew(image.Rect(5, 0, 20, 8))
+				testbraille.MustSetPixel(bc, image.Point{0, 31})
+				testbraille.MustSetPixel(bc, image.Point{29, 1})
+				testbraille.MustSetPixel(bc, image.Point{14, 16})
+
+				// Highlighted area for zoom.
+				testbraille.MustSetAreaCellOpts(bc, image.Rect(1, 0, 2, 8), cell.BgColor(cell.ColorNumber(235)))
+
+				testbraille.MustCopyTo(bc, c)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "highlights area for zoom to a custom color",
+			canvas: image.Rect(0, 0, 20, 10),
+			opts: []Option{
+				ZoomHighlightColor(cell.ColorNumber(13)),
+			},
+			writes: func(s *Scatter) error {
+				if err := s.Series("first", threePoints); err != nil {
+					return err
+				}
+				// Draw once so zoom tracker is initialized.
+				cvs := testcanvas.MustNew(image.Rect(0, 0, 20, 10))
+				if err := s.Draw(cvs, &widgetapi.Meta{}); err != nil {
+					return err
+				}
+				return s.Mouse(&terminalapi.Mouse{
+					Position: image.Point{6, 5},
+					Button:   mouse.ButtonLeft,
+				})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				mustDrawAxes(c, "-1", "-0.31")
+
+				bc := testbraille.MustNew(image.Rect(5, 0, 20, 8))
+				testbraille.MustSetPixel(bc, image.Point{0, 31})
+				testbraille.MustSetPixel(bc, image.Point{29, 1})
+				testbraille.MustSetPixel(bc, image.Point{14, 16})
+
+				// Highlighted area for zoom.
+				testbraille.MustSetAreaCellOpts(bc, image.Rect(1, 0, 2, 8), cell.BgColor(cell.ColorNumber(13)))
+
+				testbraille.MustCopyTo(bc, c)
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "zooms in on scroll up and hides points outside of the zoom",
+			canvas: image.Rect(0, 0, 20, 10),
+			writes: func(s *Scatter) error {
+				if err := s.Series("first", threePoints); err != nil {
+					return err
+				}
+				// Draw once so zoom tracker is initialized.
+				cvs := testcanvas.MustNew(image.Rect(0, 0, 20, 10))
+				if err := s.Draw(cvs, &widgetapi.Meta{}); err != nil {
+					return err
+				}
+				return s.Mouse(&terminalapi.Mouse{
+					Position: image.Point{10, 5},
+					Button:   mouse.ButtonWheelUp,
+				})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				mustDrawAxes(c, "-1", "-0.38")
+
+				bc := testbraille.MustNew(image.Rect(5, 0, 20, 8))
+				testbraille.MustSetPixel(bc, image.Point{0, 31})
+				testbraille.MustSetPixel(bc, image.Point{16, 16})
+				testbraille.MustCopyTo(bc, c)
+
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "protects against external data mutation",
+			canvas: image.Rect(0, 0, 20, 10),
+			writes: func(s *Scatter) error {
+				points := append([]Point(nil), threePoints...)
+				if err := s.Series("first", points); err != nil {
+					return err
+				}
+				points[0] = Point{100, 100}
+				return nil
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+				mustDrawAxes(c, "-1", "-0.31")
+
+				bc := testbraille.MustNew(image.Rect(5, 0, 20, 8))
+				testbraille.MustSetPixel(bc, image.Point{0, 31})
+				testbraille.MustSetPixel(bc, image.Point{29, 1})
+				testbraille.MustSetPixel(bc, image.Point{14, 16})
+				testbraille.MustCopyTo(bc, c)
+
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+
+			widget, err := New(tc.opts...)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("New => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if tc.writes != nil {
+				err := tc.writes(widget)
+				if (err != nil) != tc.wantWriteErr {
+					t.Errorf("Series => unexpected error: %v, wantWriteErr: %v", err, tc.wantWriteErr)
+				}
+				if err != nil {
+					return
+				}
+			}
+
+			if err := widget.Draw(c, &widgetapi.Meta{}); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+		})
+	}
+}
+
+func TestKeyboard(t *testing.T) {
+	s, err := New()
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := s.Keyboard(&terminalapi.Keyboard{}); err == nil {
+		t.Errorf("Keyboard => got nil err, wanted one")
+	}
+}
+
+func TestMouseDoesNothingWithoutZoomTracker(t *testing.T) {
+	s, err := New()
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := s.Mouse(&terminalapi.Mouse{}); err != nil {
+		t.Errorf("Mouse => unexpected error: %v", err)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	tests := []struct {
+		desc string
+		// if not nil, executed before obtaining the options.
+		addSeries func(*Scatter) error
+		want      widgetapi.Options
+	}{
+		{
+			desc: "reserves space for axis without series",
+			want: widgetapi.Options{
+				MinimumSize: image.Point{3, 4},
+				WantMouse:   widgetapi.MouseScopeGlobal,
+			},
+		},
+		{
+			desc: "reserves space for longer Y labels",
+			addSeries: func(s *Scatter) error {
+				return s.Series("series", []Point{{0, 0}, {1, 100}})
+			},
+			want: widgetapi.Options{
+				MinimumSize: image.Point{5, 4},
+				WantMouse:   widgetapi.MouseScopeGlobal,
+			},
+		},
+		{
+			desc: "reserves space for negative Y labels",
+			addSeries: func(s *Scatter) error {
+				return s.Series("series", []Point{{0, -100}, {1, 100}})
+			},
+			want: widgetapi.Options{
+				MinimumSize: image.Point{6, 4},
+				WantMouse:   widgetapi.MouseScopeGlobal,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			s, err := New()
+			if err != nil {
+				t.Fatalf("New => unexpected error: %v", err)
+			}
+
+			if tc.addSeries != nil {
+				if err := tc.addSeries(s); err != nil {
+					t.Fatalf("tc.addSeries => %v", err)
+				}
+			}
+			got := s.Options()
+			if diff := pretty.Compare(tc.want, got); diff != "" {
+				t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
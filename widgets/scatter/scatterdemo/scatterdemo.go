@@ -0,0 +1,108 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary scatterdemo displays a scatter plot widget.
+// Exist when 'q' is pressed.
+package main
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/termbox"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgets/scatter"
+)
+
+// cluster generates n points normally distributed around the center.
+func cluster(n int, centerX, centerY, spread float64) []scatter.Point {
+	var res []scatter.Point
+	for i := 0; i < n; i++ {
+		res = append(res, scatter.Point{
+			X: centerX + rand.NormFloat64()*spread,
+			Y: centerY + rand.NormFloat64()*spread,
+		})
+	}
+	return res
+}
+
+// playScatter continuously generates new clusters of points, once every delay.
+// Exits when the context expires.
+func playScatter(ctx context.Context, s *scatter.Scatter, delay time.Duration) {
+	ticker := time.NewTicker(delay)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.Series("first", cluster(100, -5, 0, 2),
+				scatter.SeriesCellOpts(cell.FgColor(cell.ColorBlue)),
+			); err != nil {
+				panic(err)
+			}
+			if err := s.Series("second", cluster(50, 5, 10, 1.5),
+				scatter.SeriesCellOpts(cell.FgColor(cell.ColorRed)),
+				scatter.SeriesMarker(scatter.MarkerPlus),
+			); err != nil {
+				panic(err)
+			}
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func main() {
+	t, err := termbox.New()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	const redrawInterval = 250 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	s, err := scatter.New(
+		scatter.AxesCellOpts(cell.FgColor(cell.ColorRed)),
+		scatter.YLabelCellOpts(cell.FgColor(cell.ColorGreen)),
+		scatter.XLabelCellOpts(cell.FgColor(cell.ColorCyan)),
+	)
+	if err != nil {
+		panic(err)
+	}
+	go playScatter(ctx, s, 2*time.Second)
+	c, err := container.New(
+		t,
+		container.Border(linestyle.Light),
+		container.BorderTitle("PRESS Q TO QUIT"),
+		container.PlaceWidget(s),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	quitter := func(k *terminalapi.Keyboard) {
+		if k.Key == 'q' || k.Key == 'Q' {
+			cancel()
+		}
+	}
+
+	if err := termdash.Run(ctx, t, c, termdash.KeyboardSubscriber(quitter), termdash.RedrawInterval(redrawInterval)); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package segmentdisplay
+
+// font.go contains the fonts (display types) the SegmentDisplay can use.
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/segdisp/dotmatrix"
+	"github.com/mum4k/termdash/internal/segdisp/seven"
+	"github.com/mum4k/termdash/internal/segdisp/sixteen"
+)
+
+// Font determines the type of display used to draw each character.
+type Font int
+
+// String implements fmt.Stringer()
+func (f Font) String() string {
+	if n, ok := fontNames[f]; ok {
+		return n
+	}
+	return "FontUnknown"
+}
+
+// fontNames maps Font values to human readable names.
+var fontNames = map[Font]string{
+	FontSixteenSegment: "FontSixteenSegment",
+	FontSevenSegment:   "FontSevenSegment",
+	FontDotMatrix:      "FontDotMatrix",
+}
+
+const (
+	// FontSixteenSegment draws each character on a 16-segment display.
+	// Supports digits, upper-case letters and some punctuation.
+	FontSixteenSegment Font = iota
+
+	// FontSevenSegment draws each character on a 7-segment display.
+	// Supports digits, some letters and a few punctuation characters. Best
+	// suited for clocks and counters.
+	FontSevenSegment
+
+	// FontDotMatrix draws each character on a 5x7 dot-matrix display.
+	// Supports all printable ASCII characters including lower-case letters.
+	FontDotMatrix
+)
+
+// fontDisplay abstracts the display packages so that the widget can draw
+// with any of them.
+type fontDisplay struct {
+	// supportsChars and sanitize check and clean the text, see
+	// sixteen.SupportsChars and sixteen.Sanitize.
+	supportsChars func(string) (bool, []rune)
+	sanitize      func(string) string
+
+	// required returns the cell area required by a single display, see
+	// sixteen.Required.
+	required func(image.Rectangle) (image.Rectangle, error)
+
+	// minSize is the smallest cell area a single display can be drawn on.
+	minSize image.Point
+
+	// draw draws the character onto the canvas.
+	draw func(cvs *canvas.Canvas, c rune, cOpts ...cell.Option) error
+}
+
+// fontDisplays maps the fonts to their displays.
+var fontDisplays = map[Font]*fontDisplay{
+	FontSixteenSegment: {
+		supportsChars: sixteen.SupportsChars,
+		sanitize:      sixteen.Sanitize,
+		required:      sixteen.Required,
+		minSize:       image.Point{sixteen.MinCols, sixteen.MinRows},
+		draw: func(cvs *canvas.Canvas, c rune, cOpts ...cell.Option) error {
+			disp := sixteen.New()
+			if err := disp.SetCharacter(c); err != nil {
+				return fmt.Errorf("disp.SetCharacter => %v", err)
+			}
+			return disp.Draw(cvs, sixteen.CellOpts(cOpts...))
+		},
+	},
+	FontSevenSegment: {
+		supportsChars: seven.SupportsChars,
+		sanitize:      seven.Sanitize,
+		required:      seven.Required,
+		minSize:       image.Point{seven.MinCols, seven.MinRows},
+		draw: func(cvs *canvas.Canvas, c rune, cOpts ...cell.Option) error {
+			disp := seven.New()
+			if err := disp.SetCharacter(c); err != nil {
+				return fmt.Errorf("disp.SetCharacter => %v", err)
+			}
+			return disp.Draw(cvs, seven.CellOpts(cOpts...))
+		},
+	},
+	FontDotMatrix: {
+		supportsChars: dotmatrix.SupportsChars,
+		sanitize:      dotmatrix.Sanitize,
+		required:      dotmatrix.Required,
+		minSize:       image.Point{dotmatrix.MinCols, dotmatrix.MinRows},
+		draw: func(cvs *canvas.Canvas, c rune, cOpts ...cell.Option) error {
+			disp := dotmatrix.New()
+			if err := disp.SetCharacter(c); err != nil {
+				return fmt.Errorf("disp.SetCharacter => %v", err)
+			}
+			return disp.Draw(cvs, dotmatrix.CellOpts(cOpts...))
+		},
+	},
+}
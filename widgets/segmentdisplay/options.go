@@ -16,6 +16,7 @@ package segmentdisplay
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/mum4k/termdash/align"
 )
@@ -42,6 +43,11 @@ type options struct {
 	vAlign          align.Vertical
 	maximizeSegSize bool
 	gapPercent      int
+	font            Font
+
+	marquee             MarqueeMode
+	marqueeStep         time.Duration
+	marqueePauseOnHover bool
 }
 
 // validate validates the provided options.
@@ -49,15 +55,25 @@ func (o *options) validate() error {
 	if min, max := 0, 100; o.gapPercent < min || o.gapPercent > max {
 		return fmt.Errorf("invalid GapPercent %d, must be %d <= value <= %d", o.gapPercent, min, max)
 	}
+	if _, ok := fontDisplays[o.font]; !ok {
+		return fmt.Errorf("unsupported DisplayFont %v(%d)", o.font, o.font)
+	}
+	if _, ok := marqueeModeNames[o.marquee]; !ok {
+		return fmt.Errorf("unsupported Marquee mode %v(%d)", o.marquee, o.marquee)
+	}
+	if min := time.Duration(0); o.marqueeStep <= min {
+		return fmt.Errorf("invalid MarqueeStep %v, must be a positive duration", o.marqueeStep)
+	}
 	return nil
 }
 
 // newOptions returns options with the default values set.
 func newOptions() *options {
 	return &options{
-		hAlign:     align.HorizontalCenter,
-		vAlign:     align.VerticalMiddle,
-		gapPercent: DefaultGapPercent,
+		hAlign:      align.HorizontalCenter,
+		vAlign:      align.VerticalMiddle,
+		gapPercent:  DefaultGapPercent,
+		marqueeStep: DefaultMarqueeStep,
 	}
 }
 
@@ -110,3 +126,80 @@ func GapPercent(perc int) Option {
 		opts.gapPercent = perc
 	})
 }
+
+// DisplayFont sets the font, i.e. the type of display used to draw each
+// character. The font determines which characters are supported, see the
+// WriteSanitize option. Defaults to FontSixteenSegment.
+func DisplayFont(f Font) Option {
+	return option(func(opts *options) {
+		opts.font = f
+	})
+}
+
+// MarqueeMode determines how text that doesn't fit onto the canvas scrolls.
+type MarqueeMode int
+
+// String implements fmt.Stringer()
+func (mm MarqueeMode) String() string {
+	if n, ok := marqueeModeNames[mm]; ok {
+		return n
+	}
+	return "MarqueeUnknown"
+}
+
+// marqueeModeNames maps MarqueeMode values to human readable names.
+var marqueeModeNames = map[MarqueeMode]string{
+	MarqueeOff:    "MarqueeOff",
+	MarqueeLoop:   "MarqueeLoop",
+	MarqueeBounce: "MarqueeBounce",
+}
+
+const (
+	// MarqueeOff disables scrolling, text that doesn't fit is trimmed.
+	MarqueeOff MarqueeMode = iota
+
+	// MarqueeLoop scrolls the text from the right to the left. Once the end
+	// of the text leaves the display, the text starts over.
+	MarqueeLoop
+
+	// MarqueeBounce scrolls the text from the right to the left until its
+	// end is displayed and then back until its start is displayed.
+	MarqueeBounce
+)
+
+// Marquee enables automatic scrolling of text that is longer than the
+// number of characters the widget can display, i.e. its Capacity().
+// When enabled, the widget maximizes the height of the individual display
+// segments, see MaximizeSegmentHeight. Shorter text doesn't scroll.
+//
+// The text scrolls by one character every MarqueeStep. Since the widget only
+// scrolls when it is drawn, the step should be a multiple of the redraw
+// interval of termdash, see termdash.RedrawInterval.
+// Defaults to MarqueeOff.
+func Marquee(mode MarqueeMode) Option {
+	return option(func(opts *options) {
+		opts.marquee = mode
+	})
+}
+
+// DefaultMarqueeStep is the default value for the MarqueeStep option.
+const DefaultMarqueeStep = 500 * time.Millisecond
+
+// MarqueeStep sets the duration after which the text scrolls by one
+// character when the Marquee option is enabled. Must be a positive duration.
+func MarqueeStep(d time.Duration) Option {
+	return option(func(opts *options) {
+		opts.marqueeStep = d
+	})
+}
+
+// MarqueePauseOnHover pauses the scrolling while the mouse pointer is over
+// the widget when the Marquee option is enabled.
+// Terminals only report the position of the mouse when a button is pressed,
+// released or the wheel is scrolled, so the widget is considered hovered if
+// the last mouse event happened inside of it.
+func MarqueePauseOnHover() Option {
+	return option(func(opts *options) {
+		opts.marqueePauseOnHover = true
+	})
+}
@@ -20,8 +20,6 @@ package segmentdisplay
 import (
 	"fmt"
 	"image"
-
-	"github.com/mum4k/termdash/internal/segdisp/sixteen"
 )
 
 // segArea contains information about the area that will contain the segments.
@@ -49,10 +47,11 @@ func (sa *segArea) needArea() image.Rectangle {
 
 // newSegArea calculates the area for segments given available canvas area,
 // length of the text to be displayed and the size of gap between segments
-func newSegArea(cvsAr image.Rectangle, textLen, gapPercent int) (*segArea, error) {
-	segAr, err := sixteen.Required(cvsAr)
+// when drawing with the provided font.
+func newSegArea(font *fontDisplay, cvsAr image.Rectangle, textLen, gapPercent int) (*segArea, error) {
+	segAr, err := font.required(cvsAr)
 	if err != nil {
-		return nil, fmt.Errorf("sixteen.Required => %v", err)
+		return nil, fmt.Errorf("font.required => %v", err)
 	}
 	gapPixels := segAr.Dy() * gapPercent / 100
 
@@ -98,11 +97,11 @@ func newSegArea(cvsAr image.Rectangle, textLen, gapPercent int) (*segArea, error
 // maximizeFit finds the largest individual segment size that enables us to fit
 // the most characters onto a canvas with the provided area. Returns the area
 // required for a single segment and the number of segments we can fit.
-func maximizeFit(cvsAr image.Rectangle, textLen, gapPercent int) (*segArea, error) {
+func maximizeFit(font *fontDisplay, cvsAr image.Rectangle, textLen, gapPercent int) (*segArea, error) {
 	var bestSegAr *segArea
-	for height := cvsAr.Dy(); height >= sixteen.MinRows; height-- {
+	for height := cvsAr.Dy(); height >= font.minSize.Y; height-- {
 		cvsAr := image.Rect(cvsAr.Min.X, cvsAr.Min.Y, cvsAr.Max.X, cvsAr.Min.Y+height)
-		segAr, err := newSegArea(cvsAr, textLen, gapPercent)
+		segAr, err := newSegArea(font, cvsAr, textLen, gapPercent)
 		if err != nil {
 			return nil, err
 		}
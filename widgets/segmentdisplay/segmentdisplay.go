@@ -22,11 +22,11 @@ import (
 	"image"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/mum4k/termdash/internal/alignfor"
 	"github.com/mum4k/termdash/internal/attrrange"
 	"github.com/mum4k/termdash/internal/canvas"
-	"github.com/mum4k/termdash/internal/segdisp/sixteen"
 	"github.com/mum4k/termdash/terminal/terminalapi"
 	"github.com/mum4k/termdash/widgetapi"
 )
@@ -40,6 +40,9 @@ import (
 // Segment displays support only a subset of ASCII characters, provided options
 // determine the behavior when an unsupported character is encountered.
 //
+// Text that doesn't fit can optionally scroll across the display, see the
+// Marquee option.
+//
 // Implements widgetapi.Widget. This object is thread-safe.
 type SegmentDisplay struct {
 	// buff contains the text to be displayed.
@@ -54,6 +57,16 @@ type SegmentDisplay struct {
 	// time Draw was called.
 	lastCanFit int
 
+	// marqueeOffset is the number of characters the text scrolled by when
+	// the Marquee option is enabled.
+	marqueeOffset int
+	// lastMarqueeStep is the time when the text last scrolled.
+	lastMarqueeStep time.Time
+	// lastArea is the area of the canvas the last time Draw was called.
+	lastArea image.Rectangle
+	// hovered indicates if the last mouse event happened inside the widget.
+	hovered bool
+
 	// mu protects the widget.
 	mu sync.Mutex
 
@@ -106,26 +119,31 @@ func (sd *SegmentDisplay) Write(chunks []*TextChunk, opts ...Option) error {
 	sd.mu.Lock()
 	defer sd.mu.Unlock()
 
+	newOpts := *sd.opts
 	for _, o := range opts {
-		o.set(sd.opts)
+		o.set(&newOpts)
 	}
-	if err := sd.opts.validate(); err != nil {
+	if err := newOpts.validate(); err != nil {
 		return err
 	}
 
 	if len(chunks) == 0 {
 		return errors.New("at least one text chunk must be specified")
 	}
+	sd.opts = &newOpts
+	prevText := sd.buff.String()
+	prevOffset, prevStep := sd.marqueeOffset, sd.lastMarqueeStep
 	sd.reset()
 
+	font := fontDisplays[sd.opts.font]
 	for i, tc := range chunks {
 		if tc.text == "" {
 			return fmt.Errorf("text chunk[%d] is empty, all chunks must contains some text", i)
 		}
-		if ok, badRunes := sixteen.SupportsChars(tc.text); !ok && tc.wOpts.errOnUnsupported {
+		if ok, badRunes := font.supportsChars(tc.text); !ok && tc.wOpts.errOnUnsupported {
 			return fmt.Errorf("text chunk[%d] contains unsupported characters %v, clean the text or provide the WriteSanitize option", i, badRunes)
 		}
-		text := sixteen.Sanitize(tc.text)
+		text := font.sanitize(tc.text)
 
 		pos := sd.buff.Len()
 		sd.givenWOpts = append(sd.givenWOpts, tc.wOpts)
@@ -135,6 +153,12 @@ func (sd *SegmentDisplay) Write(chunks []*TextChunk, opts ...Option) error {
 		}
 		sd.buff.WriteString(text)
 	}
+
+	// Writing the same text again doesn't restart the scrolling, this allows
+	// periodic updates of the cell options.
+	if sd.buff.String() == prevText {
+		sd.marqueeOffset, sd.lastMarqueeStep = prevOffset, prevStep
+	}
 	return nil
 }
 
@@ -165,6 +189,68 @@ func (sd *SegmentDisplay) reset() {
 	sd.buff.Reset()
 	sd.givenWOpts = nil
 	sd.wOptsTracker = attrrange.NewTracker()
+	sd.marqueeOffset = 0
+	sd.lastMarqueeStep = time.Time{}
+}
+
+// marqueeEnabled asserts whether the text scrolls.
+// Caller must hold sd.mu.
+func (sd *SegmentDisplay) marqueeEnabled() bool {
+	return sd.opts.marquee != MarqueeOff
+}
+
+// Vars to be replaced from tests.
+var (
+	// timeNow is a function that returns the current time.
+	timeNow = time.Now
+)
+
+// marqueeAdvance scrolls the text by the number of steps that elapsed since
+// the text last scrolled.
+// Caller must hold sd.mu.
+func (sd *SegmentDisplay) marqueeAdvance() {
+	now := timeNow()
+	if sd.lastMarqueeStep.IsZero() || (sd.opts.marqueePauseOnHover && sd.hovered) {
+		sd.lastMarqueeStep = now
+		return
+	}
+
+	steps := int(now.Sub(sd.lastMarqueeStep) / sd.opts.marqueeStep)
+	if steps <= 0 {
+		return
+	}
+	sd.marqueeOffset += steps
+	sd.lastMarqueeStep = sd.lastMarqueeStep.Add(time.Duration(steps) * sd.opts.marqueeStep)
+}
+
+// textIndex returns the index of the character in the text that is displayed
+// in the specified slot of the display. Returns -1 if the slot should remain
+// empty.
+// Caller must hold sd.mu.
+func (sd *SegmentDisplay) textIndex(slot, canFit, textLen int) int {
+	if !sd.marqueeEnabled() || textLen <= canFit {
+		return slot
+	}
+
+	switch sd.opts.marquee {
+	case MarqueeBounce:
+		// The display window moves between the start and the end of the text
+		// and back.
+		maxStart := textLen - canFit
+		start := sd.marqueeOffset % (2 * maxStart)
+		if start > maxStart {
+			start = 2*maxStart - start
+		}
+		return start + slot
+
+	default: // MarqueeLoop.
+		// The text is followed by an empty display before it starts over.
+		idx := (sd.marqueeOffset + slot) % (textLen + canFit)
+		if idx >= textLen {
+			return -1
+		}
+		return idx
+	}
 }
 
 // preprocess determines the size of individual segments maximizing their
@@ -173,17 +259,18 @@ func (sd *SegmentDisplay) reset() {
 // size of gaps between segments in cells.
 func (sd *SegmentDisplay) preprocess(cvsAr image.Rectangle) (*segArea, error) {
 	textLen := sd.buff.Len() // We're guaranteed by Write to only have ASCII characters.
-	segAr, err := newSegArea(cvsAr, textLen, sd.opts.gapPercent)
+	font := fontDisplays[sd.opts.font]
+	segAr, err := newSegArea(font, cvsAr, textLen, sd.opts.gapPercent)
 	if err != nil {
 		return nil, err
 	}
 
 	need := sd.buff.Len()
-	if (need > 0 && need <= segAr.canFit) || sd.opts.maximizeSegSize {
+	if (need > 0 && need <= segAr.canFit) || sd.opts.maximizeSegSize || sd.marqueeEnabled() {
 		return segAr, nil
 	}
 
-	bestAr, err := maximizeFit(cvsAr, textLen, sd.opts.gapPercent)
+	bestAr, err := maximizeFit(font, cvsAr, textLen, sd.opts.gapPercent)
 	if err != nil {
 		return nil, err
 	}
@@ -202,6 +289,7 @@ func (sd *SegmentDisplay) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
 	}
 
 	sd.lastCanFit = segAr.canFit
+	sd.lastArea = cvs.Area()
 	if sd.buff.Len() == 0 {
 		return nil
 	}
@@ -212,23 +300,19 @@ func (sd *SegmentDisplay) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
 		return fmt.Errorf("alignfor.Rectangle => %v", err)
 	}
 
-	optRange, err := sd.wOptsTracker.ForPosition(0) // Text options for the current byte.
-	if err != nil {
-		return err
+	if sd.marqueeEnabled() {
+		sd.marqueeAdvance()
 	}
 
+	slots := len(text)
+	if slots > segAr.canFit {
+		slots = segAr.canFit
+	}
+
+	font := fontDisplays[sd.opts.font]
 	gaps := segAr.gaps
 	startX := aligned.Min.X
-	for i, c := range text {
-		if i >= segAr.canFit {
-			break
-		}
-
-		disp := sixteen.New()
-		if err := disp.SetCharacter(c); err != nil {
-			return fmt.Errorf("disp.SetCharacter => %v", err)
-		}
-
+	for slot := 0; slot < slots; slot++ {
 		endX := startX + segAr.segment.Dx()
 		ar := image.Rect(startX, aligned.Min.Y, endX, aligned.Max.Y)
 		startX = endX
@@ -237,22 +321,26 @@ func (sd *SegmentDisplay) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
 			gaps--
 		}
 
+		i := sd.textIndex(slot, segAr.canFit, len(text))
+		if i < 0 {
+			continue
+		}
+
 		dCvs, err := canvas.New(ar)
 		if err != nil {
 			return fmt.Errorf("canvas.New => %v", err)
 		}
 
-		if i >= optRange.High { // Get the next write options.
-			or, err := sd.wOptsTracker.ForPosition(i)
-			if err != nil {
-				return err
-			}
-			optRange = or
+		// We're guaranteed by Write to only have ASCII characters, so the
+		// index of a character is also the index of its byte.
+		optRange, err := sd.wOptsTracker.ForPosition(i)
+		if err != nil {
+			return err
 		}
 		wOpts := sd.givenWOpts[optRange.AttrIdx]
 
-		if err := disp.Draw(dCvs, sixteen.CellOpts(wOpts.cellOpts...)); err != nil {
-			return fmt.Errorf("disp.Draw => %v", err)
+		if err := font.draw(dCvs, rune(text[i]), wOpts.cellOpts...); err != nil {
+			return fmt.Errorf("font.draw => %v", err)
 		}
 
 		if err := dCvs.CopyTo(cvs); err != nil {
@@ -267,17 +355,35 @@ func (*SegmentDisplay) Keyboard(k *terminalapi.Keyboard) error {
 	return errors.New("the SegmentDisplay widget doesn't support keyboard events")
 }
 
-// Mouse input isn't supported on the SegmentDisplay widget.
-func (*SegmentDisplay) Mouse(m *terminalapi.Mouse) error {
-	return errors.New("the SegmentDisplay widget doesn't support mouse events")
+// Mouse is used to pause the scrolling text while the mouse is over the
+// widget, see MarqueePauseOnHover. Mouse input isn't supported otherwise.
+//
+// Implements widgetapi.Widget.Mouse.
+func (sd *SegmentDisplay) Mouse(m *terminalapi.Mouse) error {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	if !sd.opts.marqueePauseOnHover {
+		return errors.New("the SegmentDisplay widget doesn't support mouse events")
+	}
+	sd.hovered = m.Position.In(sd.lastArea)
+	return nil
 }
 
 // Options implements widgetapi.Widget.Options.
 func (sd *SegmentDisplay) Options() widgetapi.Options {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	wantMouse := widgetapi.MouseScopeNone
+	if sd.opts.marqueePauseOnHover {
+		// Global scope, so that the widget learns when the mouse leaves.
+		wantMouse = widgetapi.MouseScopeGlobal
+	}
 	return widgetapi.Options{
 		// The smallest supported size of a display segment.
-		MinimumSize:  image.Point{sixteen.MinCols, sixteen.MinRows},
+		MinimumSize:  fontDisplays[sd.opts.font].minSize,
 		WantKeyboard: widgetapi.KeyScopeNone,
-		WantMouse:    widgetapi.MouseScopeNone,
+		WantMouse:    wantMouse,
 	}
 }
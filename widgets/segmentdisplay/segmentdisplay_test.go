@@ -17,6 +17,7 @@ package segmentdisplay
 import (
 	"image"
 	"testing"
+	"time"
 
 	"github.com/kylelemons/godebug/pretty"
 	"github.com/mum4k/termdash/align"
@@ -24,8 +25,13 @@ import (
 	"github.com/mum4k/termdash/internal/canvas"
 	"github.com/mum4k/termdash/internal/canvas/testcanvas"
 	"github.com/mum4k/termdash/internal/faketerm"
+	"github.com/mum4k/termdash/internal/segdisp/dotmatrix"
+	"github.com/mum4k/termdash/internal/segdisp/dotmatrix/testdotmatrix"
+	"github.com/mum4k/termdash/internal/segdisp/seven"
+	"github.com/mum4k/termdash/internal/segdisp/seven/testseven"
 	"github.com/mum4k/termdash/internal/segdisp/sixteen"
 	"github.com/mum4k/termdash/internal/segdisp/sixteen/testsixteen"
+	"github.com/mum4k/termdash/mouse"
 	"github.com/mum4k/termdash/terminal/terminalapi"
 	"github.com/mum4k/termdash/widgetapi"
 )
@@ -39,6 +45,26 @@ func mustDrawChar(cvs *canvas.Canvas, char rune, ar image.Rectangle, opts ...six
 	testcanvas.MustCopyTo(c, cvs)
 }
 
+// mustDrawSevenChar draws the provided character in the area of the canvas
+// using a 7-segment display or panics.
+func mustDrawSevenChar(cvs *canvas.Canvas, char rune, ar image.Rectangle, opts ...seven.Option) {
+	d := seven.New()
+	testseven.MustSetCharacter(d, char)
+	c := testcanvas.MustNew(ar)
+	testseven.MustDraw(d, c, opts...)
+	testcanvas.MustCopyTo(c, cvs)
+}
+
+// mustDrawDotMatrixChar draws the provided character in the area of the
+// canvas using a dot-matrix display or panics.
+func mustDrawDotMatrixChar(cvs *canvas.Canvas, char rune, ar image.Rectangle, opts ...dotmatrix.Option) {
+	d := dotmatrix.New()
+	testdotmatrix.MustSetCharacter(d, char)
+	c := testcanvas.MustNew(ar)
+	testdotmatrix.MustDraw(d, c, opts...)
+	testcanvas.MustCopyTo(c, cvs)
+}
+
 func TestSegmentDisplay(t *testing.T) {
 	tests := []struct {
 		desc          string
@@ -849,6 +875,189 @@ func TestSegmentDisplay(t *testing.T) {
 			},
 			wantCapacity: 3,
 		},
+		{
+			desc: "New fails on unsupported Marquee mode",
+			opts: []Option{
+				Marquee(MarqueeMode(-1)),
+			},
+			canvas:     image.Rect(0, 0, sixteen.MinCols, sixteen.MinRows),
+			wantNewErr: true,
+		},
+		{
+			desc: "New fails on invalid MarqueeStep",
+			opts: []Option{
+				MarqueeStep(0),
+			},
+			canvas:     image.Rect(0, 0, sixteen.MinCols, sixteen.MinRows),
+			wantNewErr: true,
+		},
+		{
+			desc: "marquee doesn't scroll text that fits",
+			opts: []Option{
+				Marquee(MarqueeLoop),
+				GapPercent(0),
+			},
+			canvas: image.Rect(0, 0, sixteen.MinCols*2, sixteen.MinRows),
+			update: func(sd *SegmentDisplay) error {
+				return sd.Write([]*TextChunk{NewChunk("1")})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+
+				mustDrawChar(cvs, '1', image.Rect(0, 0, sixteen.MinCols, sixteen.MinRows))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantCapacity: 2,
+		},
+		{
+			desc: "marquee maximizes segment height instead of fitting the text",
+			opts: []Option{
+				Marquee(MarqueeLoop),
+				GapPercent(0),
+			},
+			canvas: image.Rect(0, 0, sixteen.MinCols*2, sixteen.MinRows*2),
+			update: func(sd *SegmentDisplay) error {
+				return sd.Write([]*TextChunk{NewChunk("12")})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+
+				ar, err := sixteen.Required(ft.Area())
+				if err != nil {
+					panic(err)
+				}
+				mustDrawChar(cvs, '1', ar)
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantCapacity: 1,
+		},
+		{
+			desc: "New fails on unsupported DisplayFont",
+			opts: []Option{
+				DisplayFont(Font(-1)),
+			},
+			canvas:     image.Rect(0, 0, sixteen.MinCols, sixteen.MinRows),
+			wantNewErr: true,
+		},
+		{
+			desc:   "write fails on unsupported DisplayFont",
+			canvas: image.Rect(0, 0, sixteen.MinCols, sixteen.MinRows),
+			update: func(sd *SegmentDisplay) error {
+				return sd.Write(
+					[]*TextChunk{NewChunk("1")},
+					DisplayFont(Font(-1)),
+				)
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc: "seven-segment font fails on characters it doesn't support",
+			opts: []Option{
+				DisplayFont(FontSevenSegment),
+			},
+			canvas: image.Rect(0, 0, seven.MinCols, seven.MinRows),
+			update: func(sd *SegmentDisplay) error {
+				return sd.Write([]*TextChunk{NewChunk("M", WriteErrOnUnsupported())})
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc: "draws text with the seven-segment font",
+			opts: []Option{
+				DisplayFont(FontSevenSegment),
+				GapPercent(0),
+			},
+			canvas: image.Rect(0, 0, seven.MinCols*3, seven.MinRows),
+			update: func(sd *SegmentDisplay) error {
+				return sd.Write([]*TextChunk{NewChunk("1b3")})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+
+				for _, tc := range []struct {
+					char rune
+					area image.Rectangle
+				}{
+					{'1', image.Rect(0, 0, seven.MinCols, seven.MinRows)},
+					{'b', image.Rect(seven.MinCols, 0, seven.MinCols*2, seven.MinRows)},
+					{'3', image.Rect(seven.MinCols*2, 0, seven.MinCols*3, seven.MinRows)},
+				} {
+					mustDrawSevenChar(cvs, tc.char, tc.area)
+				}
+
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantCapacity: 3,
+		},
+		{
+			desc: "seven-segment font sanitizes text",
+			opts: []Option{
+				DisplayFont(FontSevenSegment),
+				GapPercent(0),
+			},
+			canvas: image.Rect(0, 0, seven.MinCols*2, seven.MinRows),
+			update: func(sd *SegmentDisplay) error {
+				return sd.Write([]*TextChunk{NewChunk("M1")})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+
+				mustDrawSevenChar(cvs, '1', image.Rect(seven.MinCols, 0, seven.MinCols*2, seven.MinRows))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantCapacity: 2,
+		},
+		{
+			desc: "draws lower-case text with the dot-matrix font",
+			opts: []Option{
+				DisplayFont(FontDotMatrix),
+				GapPercent(0),
+			},
+			canvas: image.Rect(0, 0, dotmatrix.MinCols*2, dotmatrix.MinRows),
+			update: func(sd *SegmentDisplay) error {
+				return sd.Write([]*TextChunk{NewChunk("ab")})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+
+				mustDrawDotMatrixChar(cvs, 'a', image.Rect(0, 0, dotmatrix.MinCols, dotmatrix.MinRows))
+				mustDrawDotMatrixChar(cvs, 'b', image.Rect(dotmatrix.MinCols, 0, dotmatrix.MinCols*2, dotmatrix.MinRows))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantCapacity: 2,
+		},
+		{
+			desc: "font set on Write applies to the written text",
+			opts: []Option{
+				GapPercent(0),
+			},
+			canvas: image.Rect(0, 0, dotmatrix.MinCols, dotmatrix.MinRows),
+			update: func(sd *SegmentDisplay) error {
+				return sd.Write(
+					[]*TextChunk{NewChunk("z")},
+					DisplayFont(FontDotMatrix),
+				)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+
+				mustDrawDotMatrixChar(cvs, 'z', image.Rect(0, 0, dotmatrix.MinCols, dotmatrix.MinRows))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantCapacity: 1,
+		},
 	}
 
 	for _, tc := range tests {
@@ -911,6 +1120,249 @@ func TestSegmentDisplay(t *testing.T) {
 	}
 }
 
+func TestMarquee(t *testing.T) {
+	// step is one draw of the widget.
+	type step struct {
+		// elapsed is the time since the text was written.
+		elapsed time.Duration
+		// mouse if not nil is sent to the widget before drawing.
+		mouse *terminalapi.Mouse
+		// write if not empty is written to the widget before drawing.
+		write string
+		// want are the displayed characters, space for an empty display.
+		want string
+	}
+
+	tests := []struct {
+		desc  string
+		opts  []Option
+		text  string
+		steps []step
+	}{
+		{
+			desc: "loop mode scrolls the text followed by an empty display",
+			opts: []Option{
+				Marquee(MarqueeLoop),
+			},
+			text: "123",
+			steps: []step{
+				{elapsed: 0, want: "12"},
+				{elapsed: 400 * time.Millisecond, want: "12"},
+				{elapsed: 500 * time.Millisecond, want: "23"},
+				{elapsed: 1000 * time.Millisecond, want: "3 "},
+				{elapsed: 1500 * time.Millisecond, want: "  "},
+				{elapsed: 2000 * time.Millisecond, want: " 1"},
+				{elapsed: 2500 * time.Millisecond, want: "12"},
+			},
+		},
+		{
+			desc: "loop mode catches up with steps missed between draws",
+			opts: []Option{
+				Marquee(MarqueeLoop),
+			},
+			text: "123",
+			steps: []step{
+				{elapsed: 0, want: "12"},
+				{elapsed: 1100 * time.Millisecond, want: "3 "},
+			},
+		},
+		{
+			desc: "bounce mode scrolls the text back and forth",
+			opts: []Option{
+				Marquee(MarqueeBounce),
+				MarqueeStep(time.Second),
+			},
+			text: "1234",
+			steps: []step{
+				{elapsed: 0, want: "12"},
+				{elapsed: 1 * time.Second, want: "23"},
+				{elapsed: 2 * time.Second, want: "34"},
+				{elapsed: 3 * time.Second, want: "23"},
+				{elapsed: 4 * time.Second, want: "12"},
+				{elapsed: 5 * time.Second, want: "23"},
+			},
+		},
+		{
+			desc: "writing the same text doesn't restart the scrolling",
+			opts: []Option{
+				Marquee(MarqueeLoop),
+			},
+			text: "123",
+			steps: []step{
+				{elapsed: 0, want: "12"},
+				{elapsed: 500 * time.Millisecond, write: "123", want: "23"},
+			},
+		},
+		{
+			desc: "writing different text restarts the scrolling",
+			opts: []Option{
+				Marquee(MarqueeLoop),
+			},
+			text: "123",
+			steps: []step{
+				{elapsed: 0, want: "12"},
+				{elapsed: 500 * time.Millisecond, want: "23"},
+				{elapsed: 500 * time.Millisecond, write: "456", want: "45"},
+				{elapsed: 1000 * time.Millisecond, want: "56"},
+			},
+		},
+		{
+			desc: "pauses while hovered",
+			opts: []Option{
+				Marquee(MarqueeLoop),
+				MarqueePauseOnHover(),
+			},
+			text: "123",
+			steps: []step{
+				{elapsed: 0, want: "12"},
+				{
+					elapsed: 500 * time.Millisecond,
+					mouse:   &terminalapi.Mouse{Position: image.Point{0, 0}, Button: mouse.ButtonRelease},
+					want:    "12",
+				},
+				{elapsed: 1500 * time.Millisecond, want: "12"},
+				{
+					elapsed: 1700 * time.Millisecond,
+					mouse:   &terminalapi.Mouse{Position: image.Point{-1, -1}, Button: mouse.ButtonRelease},
+					want:    "12",
+				},
+				{elapsed: 2200 * time.Millisecond, want: "23"},
+			},
+		},
+		{
+			desc: "doesn't pause when hovered without the option",
+			opts: []Option{
+				Marquee(MarqueeLoop),
+			},
+			text: "123",
+			steps: []step{
+				{elapsed: 0, want: "12"},
+				{elapsed: 500 * time.Millisecond, want: "23"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			start := time.Date(2019, 5, 1, 10, 0, 0, 0, time.UTC)
+			var now time.Time
+			timeNow = func() time.Time {
+				return now
+			}
+			defer func() {
+				timeNow = time.Now
+			}()
+
+			opts := append([]Option{GapPercent(0)}, tc.opts...)
+			sd, err := New(opts...)
+			if err != nil {
+				t.Fatalf("New => unexpected error: %v", err)
+			}
+			if err := sd.Write([]*TextChunk{NewChunk(tc.text)}); err != nil {
+				t.Fatalf("Write => unexpected error: %v", err)
+			}
+
+			ar := image.Rect(0, 0, sixteen.MinCols*2, sixteen.MinRows)
+			for i, st := range tc.steps {
+				now = start.Add(st.elapsed)
+				if st.mouse != nil {
+					if err := sd.Mouse(st.mouse); err != nil {
+						t.Fatalf("step[%d]: Mouse => unexpected error: %v", i, err)
+					}
+				}
+				if st.write != "" {
+					if err := sd.Write([]*TextChunk{NewChunk(st.write)}); err != nil {
+						t.Fatalf("step[%d]: Write => unexpected error: %v", i, err)
+					}
+				}
+
+				c := testcanvas.MustNew(ar)
+				if err := sd.Draw(c, &widgetapi.Meta{}); err != nil {
+					t.Fatalf("step[%d]: Draw => unexpected error: %v", i, err)
+				}
+				got := faketerm.MustNew(c.Size())
+				testcanvas.MustApply(c, got)
+
+				want := faketerm.MustNew(c.Size())
+				wantCvs := testcanvas.MustNew(want.Area())
+				for slot, char := range st.want {
+					if char == ' ' {
+						continue
+					}
+					mustDrawChar(wantCvs, char, image.Rect(sixteen.MinCols*slot, 0, sixteen.MinCols*(slot+1), sixteen.MinRows))
+				}
+				testcanvas.MustApply(wantCvs, want)
+
+				if diff := faketerm.Diff(want, got); diff != "" {
+					t.Fatalf("step[%d]: Draw => %v", i, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteKeepsOptionsOnError(t *testing.T) {
+	sd, err := New()
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := sd.Write([]*TextChunk{NewChunk("1")}, DisplayFont(Font(-1))); err == nil {
+		t.Fatalf("Write => got nil err, wanted one")
+	}
+
+	want := widgetapi.Options{
+		MinimumSize:  image.Point{sixteen.MinCols, sixteen.MinRows},
+		WantKeyboard: widgetapi.KeyScopeNone,
+		WantMouse:    widgetapi.MouseScopeNone,
+	}
+	if diff := pretty.Compare(want, sd.Options()); diff != "" {
+		t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+	}
+}
+
+func TestMarqueeAfterRejectedWrite(t *testing.T) {
+	start := time.Date(2019, 5, 1, 10, 0, 0, 0, time.UTC)
+	now := start
+	timeNow = func() time.Time {
+		return now
+	}
+	defer func() {
+		timeNow = time.Now
+	}()
+
+	sd, err := New(GapPercent(0), Marquee(MarqueeLoop))
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := sd.Write([]*TextChunk{NewChunk("123")}); err != nil {
+		t.Fatalf("Write => unexpected error: %v", err)
+	}
+	ar := image.Rect(0, 0, sixteen.MinCols*2, sixteen.MinRows)
+	if err := sd.Draw(testcanvas.MustNew(ar), &widgetapi.Meta{}); err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+	if err := sd.Write([]*TextChunk{NewChunk("123")}, MarqueeStep(0)); err == nil {
+		t.Fatalf("Write => got nil err, wanted one")
+	}
+
+	now = start.Add(500 * time.Millisecond)
+	c := testcanvas.MustNew(ar)
+	if err := sd.Draw(c, &widgetapi.Meta{}); err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+	got := faketerm.MustNew(c.Size())
+	testcanvas.MustApply(c, got)
+
+	want := faketerm.MustNew(c.Size())
+	wantCvs := testcanvas.MustNew(want.Area())
+	mustDrawChar(wantCvs, '2', image.Rect(0, 0, sixteen.MinCols, sixteen.MinRows))
+	mustDrawChar(wantCvs, '3', image.Rect(sixteen.MinCols, 0, sixteen.MinCols*2, sixteen.MinRows))
+	testcanvas.MustApply(wantCvs, want)
+	if diff := faketerm.Diff(want, got); diff != "" {
+		t.Errorf("Draw => %v", diff)
+	}
+}
+
 func TestKeyboard(t *testing.T) {
 	sd, err := New()
 	if err != nil {
@@ -929,21 +1381,76 @@ func TestMouse(t *testing.T) {
 	if err := sd.Mouse(&terminalapi.Mouse{}); err == nil {
 		t.Errorf("Mouse => got nil err, wanted one")
 	}
-}
 
-func TestOptions(t *testing.T) {
-	sd, err := New()
+	hoverSD, err := New(Marquee(MarqueeLoop), MarqueePauseOnHover())
 	if err != nil {
 		t.Fatalf("New => unexpected error: %v", err)
 	}
-	got := sd.Options()
-	want := widgetapi.Options{
-		MinimumSize:  image.Point{sixteen.MinCols, sixteen.MinRows},
-		WantKeyboard: widgetapi.KeyScopeNone,
-		WantMouse:    widgetapi.MouseScopeNone,
+	if err := hoverSD.Mouse(&terminalapi.Mouse{}); err != nil {
+		t.Errorf("Mouse => unexpected error: %v", err)
 	}
-	if diff := pretty.Compare(want, got); diff != "" {
-		t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+}
+
+func TestOptions(t *testing.T) {
+	tests := []struct {
+		desc string
+		opts []Option
+		want widgetapi.Options
+	}{
+		{
+			desc: "sixteen-segment font by default",
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{sixteen.MinCols, sixteen.MinRows},
+				WantKeyboard: widgetapi.KeyScopeNone,
+				WantMouse:    widgetapi.MouseScopeNone,
+			},
+		},
+		{
+			desc: "seven-segment font",
+			opts: []Option{
+				DisplayFont(FontSevenSegment),
+			},
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{seven.MinCols, seven.MinRows},
+				WantKeyboard: widgetapi.KeyScopeNone,
+				WantMouse:    widgetapi.MouseScopeNone,
+			},
+		},
+		{
+			desc: "wants mouse events when pausing marquee on hover",
+			opts: []Option{
+				Marquee(MarqueeLoop),
+				MarqueePauseOnHover(),
+			},
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{sixteen.MinCols, sixteen.MinRows},
+				WantKeyboard: widgetapi.KeyScopeNone,
+				WantMouse:    widgetapi.MouseScopeGlobal,
+			},
+		},
+		{
+			desc: "dot-matrix font",
+			opts: []Option{
+				DisplayFont(FontDotMatrix),
+			},
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{dotmatrix.MinCols, dotmatrix.MinRows},
+				WantKeyboard: widgetapi.KeyScopeNone,
+				WantMouse:    widgetapi.MouseScopeNone,
+			},
+		},
 	}
 
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			sd, err := New(tc.opts...)
+			if err != nil {
+				t.Fatalf("New => unexpected error: %v", err)
+			}
+			got := sd.Options()
+			if diff := pretty.Compare(tc.want, got); diff != "" {
+				t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
 }
@@ -120,7 +120,7 @@ func main() {
 	defer t.Close()
 
 	ctx, cancel := context.WithCancel(context.Background())
-	clockSD, err := segmentdisplay.New()
+	clockSD, err := segmentdisplay.New(segmentdisplay.DisplayFont(segmentdisplay.FontSevenSegment))
 	if err != nil {
 		panic(err)
 	}
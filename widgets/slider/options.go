@@ -0,0 +1,218 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slider
+
+// options.go contains configurable options for Slider.
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mum4k/termdash/cell"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// options holds the provided options.
+type options struct {
+	min           int
+	max           int
+	step          int
+	value         int
+	valueSet      bool
+	isRange       bool
+	low           int
+	high          int
+	vertical      bool
+	hideValue     bool
+	trackColor    cell.Color
+	fillColor     cell.Color
+	handleColor   cell.Color
+	valueColor    cell.Color
+	onChange      CallbackFn
+	onRangeChange RangeCallbackFn
+}
+
+// validate validates the provided options.
+func (o *options) validate() error {
+	if o.min >= o.max {
+		return fmt.Errorf("invalid bounds Min:%d, Max:%d, must be Min < Max", o.min, o.max)
+	}
+	if o.step <= 0 || o.step > o.max-o.min {
+		return fmt.Errorf("invalid Step %d, must be 0 < Step <= Max - Min (%d)", o.step, o.max-o.min)
+	}
+	if o.isRange {
+		if err := validateRange(o.low, o.high, o.min, o.max); err != nil {
+			return err
+		}
+		if o.onChange != nil {
+			return errors.New("the OnChange option cannot be used with the Range option, use OnRangeChange instead")
+		}
+		return nil
+	}
+
+	if err := validateValue(o.value, o.min, o.max); err != nil {
+		return err
+	}
+	if o.onRangeChange != nil {
+		return errors.New("the OnRangeChange option can only be used with the Range option")
+	}
+	return nil
+}
+
+// newOptions returns options with the default values set.
+func newOptions() *options {
+	return &options{
+		min:         DefaultMin,
+		max:         DefaultMax,
+		step:        DefaultStep,
+		trackColor:  cell.ColorNumber(240),
+		fillColor:   cell.ColorGreen,
+		handleColor: cell.ColorWhite,
+		valueColor:  cell.ColorDefault,
+	}
+}
+
+const (
+	// DefaultMin is the default value for the Min option.
+	DefaultMin = 0
+	// DefaultMax is the default value for the Max option.
+	DefaultMax = 100
+	// DefaultStep is the default value for the Step option.
+	DefaultStep = 1
+)
+
+// Min sets the smallest value the slider can be set to.
+// Must be smaller than Max. Defaults to DefaultMin.
+func Min(v int) Option {
+	return option(func(opts *options) {
+		opts.min = v
+	})
+}
+
+// Max sets the largest value the slider can be set to.
+// Must be larger than Min. Defaults to DefaultMax.
+func Max(v int) Option {
+	return option(func(opts *options) {
+		opts.max = v
+	})
+}
+
+// Step sets the amount by which the arrow keys change the value. The mouse
+// sets values that are a multiple of the step away from Min.
+// Must be a positive integer not larger than Max - Min.
+// Defaults to DefaultStep.
+func Step(s int) Option {
+	return option(func(opts *options) {
+		opts.step = s
+	})
+}
+
+// Value sets the initial value of the slider.
+// Must be in range Min <= v <= Max. Defaults to Min.
+func Value(v int) Option {
+	return option(func(opts *options) {
+		opts.value = v
+		opts.valueSet = true
+	})
+}
+
+// Range makes the slider select a range of values using two handles and sets
+// the initial bounds of the range.
+// Must be in range Min <= low <= high <= Max.
+func Range(low, high int) Option {
+	return option(func(opts *options) {
+		opts.isRange = true
+		opts.low = low
+		opts.high = high
+	})
+}
+
+// Vertical makes the slider vertical with Min at the bottom.
+// Defaults to a horizontal slider with Min on the left.
+func Vertical() Option {
+	return option(func(opts *options) {
+		opts.vertical = true
+	})
+}
+
+// HideValue hides the numeric value that is by default displayed after the
+// end of the slider.
+func HideValue() Option {
+	return option(func(opts *options) {
+		opts.hideValue = true
+	})
+}
+
+// TrackColor sets the color of the part of the slider that is outside of the
+// selected values. Defaults to color number 240.
+func TrackColor(c cell.Color) Option {
+	return option(func(opts *options) {
+		opts.trackColor = c
+	})
+}
+
+// FillColor sets the color of the part of the slider that is within the
+// selected values. Defaults to green.
+func FillColor(c cell.Color) Option {
+	return option(func(opts *options) {
+		opts.fillColor = c
+	})
+}
+
+// HandleColor sets the color of the handles.
+// Defaults to white.
+func HandleColor(c cell.Color) Option {
+	return option(func(opts *options) {
+		opts.handleColor = c
+	})
+}
+
+// ValueColor sets the color of the displayed numeric value.
+func ValueColor(c cell.Color) Option {
+	return option(func(opts *options) {
+		opts.valueColor = c
+	})
+}
+
+// OnChange sets a function that is called each time the user changes the
+// value of the slider.
+// Cannot be used with the Range option.
+func OnChange(fn CallbackFn) Option {
+	return option(func(opts *options) {
+		opts.onChange = fn
+	})
+}
+
+// OnRangeChange sets a function that is called each time the user changes
+// the range selected by the slider.
+// Can only be used with the Range option.
+func OnRangeChange(fn RangeCallbackFn) Option {
+	return option(func(opts *options) {
+		opts.onRangeChange = fn
+	})
+}
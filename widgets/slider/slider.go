@@ -0,0 +1,488 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package slider implements a widget that sets a numeric value or a range of
+// values by moving handles along a track.
+package slider
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"strconv"
+	"sync"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/draw"
+	"github.com/mum4k/termdash/internal/runewidth"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// CallbackFn is the function called when the user changes the value of the
+// slider. The argument is the new value.
+//
+// The callback function must be thread-safe as the mouse or keyboard events
+// that change the value are processed in a separate goroutine.
+//
+// If the function returns an error, the widget will forward it back to the
+// termdash infrastructure which causes a panic, unless the user provided a
+// termdash.ErrorHandler.
+type CallbackFn func(value int) error
+
+// RangeCallbackFn is the function called when the user changes the range
+// selected by the slider. The arguments are the new bounds of the range.
+//
+// The same requirements as for CallbackFn apply.
+type RangeCallbackFn func(low, high int) error
+
+// Slider displays a track with a handle at the position of the current value
+// followed by the value itself. With the Range option, the slider has two
+// handles that select a range of values.
+//
+// The user moves the handle by clicking on or dragging it along the track
+// with the mouse. While its container is focused, the arrow keys move the
+// active handle by one step, the page up and page down keys by ten steps and
+// the home and end keys move it to the ends of the track. The space key
+// switches the active handle in range mode. The active handle is highlighted
+// while the container is focused.
+//
+// Implements widgetapi.Widget. This object is thread-safe.
+type Slider struct {
+	// values are the positions of the handles, one in the single value mode,
+	// the low and the high bounds in range mode.
+	values []int
+	// active is the index of the handle moved by the keyboard.
+	active int
+	// dragging indicates that the user pressed the left mouse button on the
+	// track and didn't release it yet.
+	dragging bool
+
+	// trackAr is the area of the track on the last call to Draw.
+	trackAr image.Rectangle
+
+	// mu protects the widget.
+	mu sync.Mutex
+
+	// opts are the provided options.
+	opts *options
+}
+
+// New returns a new Slider.
+func New(opts ...Option) (*Slider, error) {
+	opt := newOptions()
+	for _, o := range opts {
+		o.set(opt)
+	}
+	if !opt.valueSet {
+		opt.value = opt.min
+	}
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+
+	values := []int{opt.value}
+	if opt.isRange {
+		values = []int{opt.low, opt.high}
+	}
+	return &Slider{
+		values: values,
+		opts:   opt,
+	}, nil
+}
+
+// validateValue validates a value of the slider.
+func validateValue(v, min, max int) error {
+	if v < min || v > max {
+		return fmt.Errorf("invalid value %d, must be in range %d <= value <= %d", v, min, max)
+	}
+	return nil
+}
+
+// validateRange validates a range selected by the slider.
+func validateRange(low, high, min, max int) error {
+	if err := validateValue(low, min, max); err != nil {
+		return err
+	}
+	if err := validateValue(high, min, max); err != nil {
+		return err
+	}
+	if low > high {
+		return fmt.Errorf("invalid range low:%d, high:%d, must be low <= high", low, high)
+	}
+	return nil
+}
+
+// Value returns the current value of the slider. In range mode returns the
+// low bound of the range.
+func (s *Slider) Value() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.values[0]
+}
+
+// SetValue sets the value of the slider. Doesn't call the OnChange callback.
+// Cannot be used in range mode.
+func (s *Slider) SetValue(v int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.opts.isRange {
+		return errors.New("SetValue cannot be used in range mode, use SetRange instead")
+	}
+	if err := validateValue(v, s.opts.min, s.opts.max); err != nil {
+		return err
+	}
+	s.values[0] = v
+	return nil
+}
+
+// Range returns the range selected by the slider. In the single value mode
+// both bounds equal the value.
+func (s *Slider) Range() (low, high int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.values[0], s.values[len(s.values)-1]
+}
+
+// SetRange sets the range selected by the slider. Doesn't call the
+// OnRangeChange callback. Can only be used in range mode.
+func (s *Slider) SetRange(low, high int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.opts.isRange {
+		return errors.New("SetRange can only be used in range mode, use SetValue instead")
+	}
+	if err := validateRange(low, high, s.opts.min, s.opts.max); err != nil {
+		return err
+	}
+	s.values[0] = low
+	s.values[1] = high
+	return nil
+}
+
+// set moves the handle at the provided index to the value, keeping it within
+// the bounds and not moving it past the other handle. Calls the callback if
+// the value changed.
+// Caller must hold s.mu.
+func (s *Slider) set(handle, v int) error {
+	lower, upper := s.opts.min, s.opts.max
+	if s.opts.isRange {
+		if handle == 0 {
+			upper = s.values[1]
+		} else {
+			lower = s.values[0]
+		}
+	}
+	if v < lower {
+		v = lower
+	}
+	if v > upper {
+		v = upper
+	}
+	if s.values[handle] == v {
+		return nil
+	}
+	s.values[handle] = v
+
+	if s.opts.isRange {
+		if s.opts.onRangeChange != nil {
+			return s.opts.onRangeChange(s.values[0], s.values[1])
+		}
+		return nil
+	}
+	if s.opts.onChange != nil {
+		return s.opts.onChange(v)
+	}
+	return nil
+}
+
+// valueText returns the numeric value displayed next to the track.
+// Caller must hold s.mu.
+func (s *Slider) valueText() string {
+	if s.opts.isRange {
+		return fmt.Sprintf("%d..%d", s.values[0], s.values[1])
+	}
+	return strconv.Itoa(s.values[0])
+}
+
+// valueWidth returns the width of the space reserved for the displayed value,
+// so that the track doesn't change its length when the value changes.
+func (s *Slider) valueWidth() int {
+	if s.opts.hideValue {
+		return 0
+	}
+	w := runewidth.StringWidth(strconv.Itoa(s.opts.min))
+	if mw := runewidth.StringWidth(strconv.Itoa(s.opts.max)); mw > w {
+		w = mw
+	}
+	if s.opts.isRange {
+		return 2*w + 2
+	}
+	return w
+}
+
+// trackLen returns the length of the track in cells.
+func (s *Slider) trackLen() int {
+	if s.opts.vertical {
+		return s.trackAr.Dy()
+	}
+	return s.trackAr.Dx()
+}
+
+// position returns the position of the value on a track of the provided
+// length, zero being the position of Min.
+// Caller must hold s.mu.
+func (s *Slider) position(v, length int) int {
+	span := s.opts.max - s.opts.min
+	return ((v-s.opts.min)*(length-1) + span/2) / span
+}
+
+// valueAt returns the value at the provided position on a track of the
+// provided length. The value is a multiple of the step away from Min, except
+// for the last position which is always Max.
+// Caller must hold s.mu.
+func (s *Slider) valueAt(pos, length int) int {
+	if pos <= 0 {
+		return s.opts.min
+	}
+	if pos >= length-1 {
+		return s.opts.max
+	}
+	span := s.opts.max - s.opts.min
+	v := (pos*span + (length-1)/2) / (length - 1)
+	steps := (v + s.opts.step/2) / s.opts.step
+	if v = s.opts.min + steps*s.opts.step; v > s.opts.max {
+		v = s.opts.max
+	}
+	return v
+}
+
+// trackPoint returns the cell of the track at the position.
+// Caller must hold s.mu.
+func (s *Slider) trackPoint(pos int) image.Point {
+	if s.opts.vertical {
+		return image.Point{s.trackAr.Min.X, s.trackAr.Max.Y - 1 - pos}
+	}
+	return image.Point{s.trackAr.Min.X + pos, s.trackAr.Min.Y}
+}
+
+// Runes used to draw the slider.
+const (
+	hTrackRune  = '─'
+	hFillRune   = '━'
+	vTrackRune  = '│'
+	vFillRune   = '┃'
+	handleRune  = '●'
+	minTrackLen = 2
+)
+
+// Draw draws the Slider widget onto the canvas.
+// Implements widgetapi.Widget.Draw.
+func (s *Slider) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cvsAr := cvs.Area()
+	valueWidth := s.valueWidth()
+	var valueStart image.Point
+	if s.opts.vertical {
+		x := (cvsAr.Dx() - 1) / 2
+		s.trackAr = image.Rect(x, 0, x+1, cvsAr.Max.Y)
+		if valueWidth > 0 {
+			s.trackAr.Max.Y--
+			valueStart = image.Point{(cvsAr.Dx() - runewidth.StringWidth(s.valueText())) / 2, cvsAr.Max.Y - 1}
+		}
+	} else {
+		s.trackAr = image.Rect(0, 0, cvsAr.Max.X, 1)
+		if valueWidth > 0 {
+			s.trackAr.Max.X -= valueWidth + 1
+			valueStart = image.Point{cvsAr.Max.X - runewidth.StringWidth(s.valueText()), 0}
+		}
+	}
+	if s.trackLen() < minTrackLen || (s.opts.vertical && cvsAr.Dx() < valueWidth) {
+		s.trackAr = image.ZR
+		return draw.ResizeNeeded(cvs)
+	}
+
+	trackRune, fillRune := hTrackRune, hFillRune
+	if s.opts.vertical {
+		trackRune, fillRune = vTrackRune, vFillRune
+	}
+	length := s.trackLen()
+	fillFrom, fillTo := 0, s.position(s.values[0], length)
+	if s.opts.isRange {
+		fillFrom, fillTo = fillTo, s.position(s.values[1], length)
+	}
+	for pos := 0; pos < length; pos++ {
+		r, color := trackRune, s.opts.trackColor
+		if pos >= fillFrom && pos <= fillTo {
+			r, color = fillRune, s.opts.fillColor
+		}
+		if _, err := cvs.SetCell(s.trackPoint(pos), r, cell.FgColor(color)); err != nil {
+			return err
+		}
+	}
+
+	for i, v := range s.values {
+		handleOpts := []cell.Option{cell.FgColor(s.opts.handleColor)}
+		if meta.Focused && i == s.active {
+			handleOpts = append(handleOpts, cell.Inverse())
+		}
+		if _, err := cvs.SetCell(s.trackPoint(s.position(v, length)), handleRune, handleOpts...); err != nil {
+			return err
+		}
+	}
+
+	if valueWidth == 0 {
+		return nil
+	}
+	return draw.Text(cvs, s.valueText(), valueStart, draw.TextCellOpts(cell.FgColor(s.opts.valueColor)))
+}
+
+// pageSteps is the number of steps the page up and page down keys move the
+// handle by.
+const pageSteps = 10
+
+// Keyboard moves the active handle on the arrow, page up, page down, home
+// and end keys and switches the active handle on the space key.
+// Implements widgetapi.Widget.Keyboard.
+func (s *Slider) Keyboard(k *terminalapi.Keyboard) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v := s.values[s.active]
+	switch k.Key {
+	case keyboard.KeyArrowLeft, keyboard.KeyArrowDown:
+		return s.set(s.active, v-s.opts.step)
+	case keyboard.KeyArrowRight, keyboard.KeyArrowUp:
+		return s.set(s.active, v+s.opts.step)
+	case keyboard.KeyPgDn:
+		return s.set(s.active, v-pageSteps*s.opts.step)
+	case keyboard.KeyPgUp:
+		return s.set(s.active, v+pageSteps*s.opts.step)
+	case keyboard.KeyHome:
+		return s.set(s.active, s.opts.min)
+	case keyboard.KeyEnd:
+		return s.set(s.active, s.opts.max)
+	case keyboard.KeySpace:
+		s.active = (s.active + 1) % len(s.values)
+	}
+	return nil
+}
+
+// nearestHandle returns the index of the handle nearest to the value. Between
+// two handles at the same distance, prefers the one that can move towards the
+// value.
+// Caller must hold s.mu.
+func (s *Slider) nearestHandle(v int) int {
+	if len(s.values) == 1 {
+		return 0
+	}
+	low, high := v-s.values[0], s.values[1]-v
+	if low < 0 {
+		low = -low
+	}
+	if high < 0 {
+		high = -high
+	}
+	if high < low || (high == low && v > s.values[1]) {
+		return 1
+	}
+	return 0
+}
+
+// Mouse moves the handle nearest to a left mouse click on the track to the
+// clicked position and keeps moving it while the user drags it along the
+// track. Events outside of the widget are received so that a drag ends even
+// when the button is released outside of the widget.
+// Implements widgetapi.Widget.Mouse.
+func (s *Slider) Mouse(m *terminalapi.Mouse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch m.Button {
+	case mouse.ButtonRelease:
+		s.dragging = false
+		return nil
+
+	case mouse.ButtonLeft:
+		if m.Position == (image.Point{-1, -1}) {
+			// The user is dragging outside of the widget, the handle stays
+			// where it last was until the button is released.
+			return nil
+		}
+		if s.trackAr.Empty() {
+			return nil
+		}
+		if !s.dragging && !m.Position.In(s.trackAr) {
+			return nil
+		}
+
+		length := s.trackLen()
+		pos := m.Position.X - s.trackAr.Min.X
+		if s.opts.vertical {
+			pos = s.trackAr.Max.Y - 1 - m.Position.Y
+		}
+		if pos < 0 {
+			pos = 0
+		}
+		if pos >= length {
+			pos = length - 1
+		}
+		v := s.valueAt(pos, length)
+		if !s.dragging {
+			s.dragging = true
+			s.active = s.nearestHandle(v)
+		}
+		return s.set(s.active, v)
+	}
+	return nil
+}
+
+// Options implements widgetapi.Widget.Options.
+func (s *Slider) Options() widgetapi.Options {
+	// No need to lock, as the options are fixed when New is called.
+	valueWidth := s.valueWidth()
+	var min, max image.Point
+	if s.opts.vertical {
+		width := valueWidth
+		if width < 1 {
+			width = 1
+		}
+		height := minTrackLen
+		if valueWidth > 0 {
+			height++
+		}
+		min = image.Point{width, height}
+		max = image.Point{width, 0}
+	} else {
+		width := minTrackLen
+		if valueWidth > 0 {
+			width += valueWidth + 1
+		}
+		min = image.Point{width, 1}
+		max = image.Point{0, 1}
+	}
+	return widgetapi.Options{
+		MinimumSize:  min,
+		MaximumSize:  max,
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		WantMouse:    widgetapi.MouseScopeGlobal,
+	}
+}
@@ -0,0 +1,697 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slider
+
+import (
+	"errors"
+	"image"
+	"sync"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/canvas/testcanvas"
+	"github.com/mum4k/termdash/internal/draw/testdraw"
+	"github.com/mum4k/termdash/internal/faketerm"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// callbackTracker tracks calls to the callbacks.
+type callbackTracker struct {
+	// wantErr when set to true, makes callbacks return an error.
+	wantErr bool
+
+	// calls are the values the callbacks were called with.
+	calls [][]int
+
+	// mu protects the tracker.
+	mu sync.Mutex
+}
+
+// callback is the callback function for the single value mode.
+func (ct *callbackTracker) callback(value int) error {
+	return ct.record(value)
+}
+
+// rangeCallback is the callback function for the range mode.
+func (ct *callbackTracker) rangeCallback(low, high int) error {
+	return ct.record(low, high)
+}
+
+// record records a call to a callback.
+func (ct *callbackTracker) record(values ...int) error {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	if ct.wantErr {
+		return errors.New("ct.wantErr set to true")
+	}
+	ct.calls = append(ct.calls, values)
+	return nil
+}
+
+// mustHTrack draws a horizontal track of the provided length starting at the
+// left edge of the canvas with the fill between the positions.
+func mustHTrack(cvs *canvas.Canvas, length, fillFrom, fillTo int) {
+	for x := 0; x < length; x++ {
+		if x >= fillFrom && x <= fillTo {
+			testcanvas.MustSetCell(cvs, image.Point{x, 0}, hFillRune, cell.FgColor(cell.ColorGreen))
+		} else {
+			testcanvas.MustSetCell(cvs, image.Point{x, 0}, hTrackRune, cell.FgColor(cell.ColorNumber(240)))
+		}
+	}
+}
+
+// mustHandle draws a handle at the point.
+func mustHandle(cvs *canvas.Canvas, p image.Point, opts ...cell.Option) {
+	testcanvas.MustSetCell(cvs, p, handleRune, append([]cell.Option{cell.FgColor(cell.ColorWhite)}, opts...)...)
+}
+
+func TestSlider(t *testing.T) {
+	tests := []struct {
+		desc     string
+		opts     []Option
+		callback *callbackTracker
+		// rangeCallback is used with the Range option.
+		rangeCallback *callbackTracker
+		setter        func(*Slider) error
+		events        []terminalapi.Event
+		canvas        image.Rectangle
+		meta          *widgetapi.Meta
+		want          func(size image.Point) *faketerm.Terminal
+		// wantRange are the expected bounds returned by Range.
+		wantRange   []int
+		wantCalls   [][]int
+		wantNewErr  bool
+		wantSetErr  bool
+		wantDrawErr bool
+		wantEvErr   bool
+	}{
+		{
+			desc: "New fails when Min isn't smaller than Max",
+			opts: []Option{
+				Min(10),
+				Max(10),
+			},
+			canvas:     image.Rect(0, 0, 10, 1),
+			wantNewErr: true,
+		},
+		{
+			desc: "New fails on zero step",
+			opts: []Option{
+				Step(0),
+			},
+			canvas:     image.Rect(0, 0, 10, 1),
+			wantNewErr: true,
+		},
+		{
+			desc: "New fails on step larger than the bounds",
+			opts: []Option{
+				Step(101),
+			},
+			canvas:     image.Rect(0, 0, 10, 1),
+			wantNewErr: true,
+		},
+		{
+			desc: "New fails on value out of bounds",
+			opts: []Option{
+				Value(101),
+			},
+			canvas:     image.Rect(0, 0, 10, 1),
+			wantNewErr: true,
+		},
+		{
+			desc: "New fails on inverted range",
+			opts: []Option{
+				Range(50, 40),
+			},
+			canvas:     image.Rect(0, 0, 10, 1),
+			wantNewErr: true,
+		},
+		{
+			desc:       "New fails on OnChange in range mode",
+			opts:       []Option{Range(40, 50)},
+			callback:   &callbackTracker{},
+			canvas:     image.Rect(0, 0, 10, 1),
+			wantNewErr: true,
+		},
+		{
+			desc: "New fails on OnRangeChange in the single value mode",
+			opts: []Option{
+				OnRangeChange(func(int, int) error { return nil }),
+			},
+			canvas:     image.Rect(0, 0, 10, 1),
+			wantNewErr: true,
+		},
+		{
+			desc: "the value defaults to Min",
+			opts: []Option{
+				Min(10),
+				Max(20),
+			},
+			canvas:    image.Rect(0, 0, 10, 1),
+			meta:      &widgetapi.Meta{},
+			wantRange: []int{10, 10},
+		},
+		{
+			desc:   "draws slider at the default value",
+			canvas: image.Rect(0, 0, 10, 1),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustHTrack(cvs, 6, 0, 0)
+				mustHandle(cvs, image.Point{0, 0})
+				testdraw.MustText(cvs, "0", image.Point{9, 0})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantRange: []int{0, 0},
+		},
+		{
+			desc: "draws slider at the initial value with focus",
+			opts: []Option{
+				Value(50),
+				ValueColor(cell.ColorRed),
+			},
+			canvas: image.Rect(0, 0, 10, 1),
+			meta:   &widgetapi.Meta{Focused: true},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustHTrack(cvs, 6, 0, 3)
+				mustHandle(cvs, image.Point{3, 0}, cell.Inverse())
+				testcanvas.MustSetCell(cvs, image.Point{8, 0}, '5', cell.FgColor(cell.ColorRed))
+				testcanvas.MustSetCell(cvs, image.Point{9, 0}, '0', cell.FgColor(cell.ColorRed))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantRange: []int{50, 50},
+		},
+		{
+			desc: "draws slider without the value",
+			opts: []Option{
+				Value(100),
+				HideValue(),
+			},
+			canvas: image.Rect(0, 0, 5, 1),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustHTrack(cvs, 5, 0, 4)
+				mustHandle(cvs, image.Point{4, 0})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantRange: []int{100, 100},
+		},
+		{
+			desc: "draws slider in range mode",
+			opts: []Option{
+				Range(20, 80),
+			},
+			canvas: image.Rect(0, 0, 16, 1),
+			meta:   &widgetapi.Meta{Focused: true},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustHTrack(cvs, 7, 1, 5)
+				mustHandle(cvs, image.Point{1, 0}, cell.Inverse())
+				mustHandle(cvs, image.Point{5, 0})
+				testdraw.MustText(cvs, "20..80", image.Point{10, 0})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantRange: []int{20, 80},
+		},
+		{
+			desc: "draws vertical slider with custom colors",
+			opts: []Option{
+				Vertical(),
+				Value(50),
+				TrackColor(cell.ColorBlue),
+				FillColor(cell.ColorRed),
+				HandleColor(cell.ColorYellow),
+			},
+			canvas: image.Rect(0, 0, 3, 5),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testcanvas.MustSetCell(cvs, image.Point{1, 0}, vTrackRune, cell.FgColor(cell.ColorBlue))
+				testcanvas.MustSetCell(cvs, image.Point{1, 1}, vFillRune, cell.FgColor(cell.ColorRed))
+				testcanvas.MustSetCell(cvs, image.Point{1, 2}, vFillRune, cell.FgColor(cell.ColorRed))
+				testcanvas.MustSetCell(cvs, image.Point{1, 3}, vFillRune, cell.FgColor(cell.ColorRed))
+				testcanvas.MustSetCell(cvs, image.Point{1, 1}, handleRune, cell.FgColor(cell.ColorYellow))
+				testdraw.MustText(cvs, "50", image.Point{0, 4})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantRange: []int{50, 50},
+		},
+		{
+			desc:   "requests resize when the track doesn't fit",
+			canvas: image.Rect(0, 0, 5, 1),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustResizeNeeded(cvs)
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantRange: []int{0, 0},
+		},
+		{
+			desc:     "arrow keys change the value by the step",
+			opts:     []Option{Step(5)},
+			callback: &callbackTracker{},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowLeft},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowRight},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowUp},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowDown},
+			},
+			canvas:    image.Rect(0, 0, 10, 1),
+			meta:      &widgetapi.Meta{},
+			wantRange: []int{5, 5},
+			wantCalls: [][]int{{5}, {10}, {5}},
+		},
+		{
+			desc:     "page and home and end keys",
+			opts:     []Option{Value(50), Step(2)},
+			callback: &callbackTracker{},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyPgUp},
+				&terminalapi.Keyboard{Key: keyboard.KeyPgUp},
+				&terminalapi.Keyboard{Key: keyboard.KeyPgUp},
+				&terminalapi.Keyboard{Key: keyboard.KeyHome},
+				&terminalapi.Keyboard{Key: keyboard.KeyPgDn},
+				&terminalapi.Keyboard{Key: keyboard.KeyEnd},
+			},
+			canvas:    image.Rect(0, 0, 10, 1),
+			meta:      &widgetapi.Meta{},
+			wantRange: []int{100, 100},
+			wantCalls: [][]int{{70}, {90}, {100}, {0}, {100}},
+		},
+		{
+			desc:          "space switches the active handle in range mode",
+			opts:          []Option{Range(20, 80)},
+			rangeCallback: &callbackTracker{},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowRight},
+				&terminalapi.Keyboard{Key: keyboard.KeySpace},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowLeft},
+			},
+			canvas: image.Rect(0, 0, 16, 1),
+			meta:   &widgetapi.Meta{Focused: true},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustHTrack(cvs, 7, 1, 5)
+				mustHandle(cvs, image.Point{1, 0})
+				mustHandle(cvs, image.Point{5, 0}, cell.Inverse())
+				testdraw.MustText(cvs, "21..79", image.Point{10, 0})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantRange: []int{21, 79},
+			wantCalls: [][]int{{21, 80}, {21, 79}},
+		},
+		{
+			desc:          "handles cannot move past each other",
+			opts:          []Option{Range(50, 50)},
+			rangeCallback: &callbackTracker{},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowRight},
+				&terminalapi.Keyboard{Key: keyboard.KeySpace},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowLeft},
+			},
+			canvas:    image.Rect(0, 0, 16, 1),
+			meta:      &widgetapi.Meta{},
+			wantRange: []int{50, 50},
+		},
+		{
+			desc:     "mouse click sets the value at the position",
+			callback: &callbackTracker{},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{3, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{3, 0}, Button: mouse.ButtonRelease},
+				&terminalapi.Mouse{Position: image.Point{9, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{9, 0}, Button: mouse.ButtonRelease},
+			},
+			canvas:    image.Rect(0, 0, 14, 1),
+			meta:      &widgetapi.Meta{},
+			wantRange: []int{100, 100},
+			wantCalls: [][]int{{33}, {100}},
+		},
+		{
+			desc:     "mouse sets values that are a multiple of the step",
+			opts:     []Option{Step(10)},
+			callback: &callbackTracker{},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{3, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{3, 0}, Button: mouse.ButtonRelease},
+			},
+			canvas:    image.Rect(0, 0, 14, 1),
+			meta:      &widgetapi.Meta{},
+			wantRange: []int{30, 30},
+			wantCalls: [][]int{{30}},
+		},
+		{
+			desc:     "dragging moves the handle even past the end of the track",
+			callback: &callbackTracker{},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{0, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{3, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{12, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{12, 0}, Button: mouse.ButtonRelease},
+			},
+			canvas:    image.Rect(0, 0, 14, 1),
+			meta:      &widgetapi.Meta{},
+			wantRange: []int{100, 100},
+			wantCalls: [][]int{{33}, {100}},
+		},
+		{
+			desc:     "drag ends when the button is released outside of the widget",
+			callback: &callbackTracker{},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{0, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{3, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{-1, -1}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{-1, -1}, Button: mouse.ButtonRelease},
+				&terminalapi.Mouse{Position: image.Point{12, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{12, 0}, Button: mouse.ButtonRelease},
+			},
+			canvas:    image.Rect(0, 0, 14, 1),
+			meta:      &widgetapi.Meta{},
+			wantRange: []int{33, 33},
+			wantCalls: [][]int{{33}},
+		},
+		{
+			desc:     "mouse click outside of the track is ignored",
+			callback: &callbackTracker{},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{12, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{12, 0}, Button: mouse.ButtonRelease},
+			},
+			canvas:    image.Rect(0, 0, 14, 1),
+			meta:      &widgetapi.Meta{},
+			wantRange: []int{0, 0},
+		},
+		{
+			desc:          "mouse click moves the nearest handle in range mode",
+			opts:          []Option{Range(20, 80)},
+			rangeCallback: &callbackTracker{},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{9, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{9, 0}, Button: mouse.ButtonRelease},
+				&terminalapi.Mouse{Position: image.Point{0, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{0, 0}, Button: mouse.ButtonRelease},
+			},
+			canvas:    image.Rect(0, 0, 19, 1),
+			meta:      &widgetapi.Meta{},
+			wantRange: []int{0, 100},
+			wantCalls: [][]int{{20, 100}, {0, 100}},
+		},
+		{
+			desc:     "mouse click on vertical slider",
+			opts:     []Option{Vertical()},
+			callback: &callbackTracker{},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{1, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{1, 0}, Button: mouse.ButtonRelease},
+			},
+			canvas:    image.Rect(0, 0, 3, 5),
+			meta:      &widgetapi.Meta{},
+			wantRange: []int{100, 100},
+			wantCalls: [][]int{{100}},
+		},
+		{
+			desc:     "SetValue doesn't call the callback",
+			callback: &callbackTracker{},
+			setter: func(s *Slider) error {
+				return s.SetValue(42)
+			},
+			canvas:    image.Rect(0, 0, 10, 1),
+			meta:      &widgetapi.Meta{},
+			wantRange: []int{42, 42},
+		},
+		{
+			desc: "SetValue fails on value out of bounds",
+			setter: func(s *Slider) error {
+				return s.SetValue(-1)
+			},
+			canvas:     image.Rect(0, 0, 10, 1),
+			meta:       &widgetapi.Meta{},
+			wantRange:  []int{0, 0},
+			wantSetErr: true,
+		},
+		{
+			desc: "SetValue fails in range mode",
+			opts: []Option{Range(20, 80)},
+			setter: func(s *Slider) error {
+				return s.SetValue(50)
+			},
+			canvas:     image.Rect(0, 0, 10, 1),
+			meta:       &widgetapi.Meta{},
+			wantRange:  []int{20, 80},
+			wantSetErr: true,
+		},
+		{
+			desc: "SetRange sets the range",
+			opts: []Option{Range(20, 80)},
+			setter: func(s *Slider) error {
+				return s.SetRange(30, 40)
+			},
+			canvas:    image.Rect(0, 0, 10, 1),
+			meta:      &widgetapi.Meta{},
+			wantRange: []int{30, 40},
+		},
+		{
+			desc: "SetRange fails on inverted range",
+			opts: []Option{Range(20, 80)},
+			setter: func(s *Slider) error {
+				return s.SetRange(40, 30)
+			},
+			canvas:     image.Rect(0, 0, 10, 1),
+			meta:       &widgetapi.Meta{},
+			wantRange:  []int{20, 80},
+			wantSetErr: true,
+		},
+		{
+			desc: "SetRange fails in the single value mode",
+			setter: func(s *Slider) error {
+				return s.SetRange(30, 40)
+			},
+			canvas:     image.Rect(0, 0, 10, 1),
+			meta:       &widgetapi.Meta{},
+			wantRange:  []int{0, 0},
+			wantSetErr: true,
+		},
+		{
+			desc:     "forwards errors from the callback",
+			callback: &callbackTracker{wantErr: true},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowRight},
+			},
+			canvas:    image.Rect(0, 0, 10, 1),
+			meta:      &widgetapi.Meta{},
+			wantRange: []int{1, 1},
+			wantEvErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			opts := tc.opts
+			if tc.callback != nil {
+				opts = append(opts, OnChange(tc.callback.callback))
+			}
+			if tc.rangeCallback != nil {
+				opts = append(opts, OnRangeChange(tc.rangeCallback.rangeCallback))
+			}
+			s, err := New(opts...)
+			if (err != nil) != tc.wantNewErr {
+				t.Errorf("New => unexpected error: %v, wantNewErr: %v", err, tc.wantNewErr)
+			}
+			if err != nil {
+				return
+			}
+
+			{
+				// Draw once which determines the area of the track.
+				c, err := canvas.New(tc.canvas)
+				if err != nil {
+					t.Fatalf("canvas.New => unexpected error: %v", err)
+				}
+				if err := s.Draw(c, tc.meta); err != nil && !tc.wantDrawErr {
+					t.Fatalf("Draw => unexpected error: %v", err)
+				}
+			}
+
+			if tc.setter != nil {
+				err := tc.setter(s)
+				if (err != nil) != tc.wantSetErr {
+					t.Errorf("setter => unexpected error: %v, wantSetErr: %v", err, tc.wantSetErr)
+				}
+			}
+			for _, ev := range tc.events {
+				var err error
+				switch e := ev.(type) {
+				case *terminalapi.Mouse:
+					err = s.Mouse(e)
+				case *terminalapi.Keyboard:
+					err = s.Keyboard(e)
+				default:
+					t.Fatalf("unsupported event type: %T", ev)
+				}
+				if (err != nil) != tc.wantEvErr {
+					t.Errorf("event %v => unexpected error: %v, wantEvErr: %v", ev, err, tc.wantEvErr)
+				}
+			}
+
+			low, high := s.Range()
+			if diff := pretty.Compare(tc.wantRange, []int{low, high}); diff != "" {
+				t.Errorf("Range => unexpected diff (-want, +got):\n%s", diff)
+			}
+			if got := s.Value(); got != low {
+				t.Errorf("Value => %d, want %d", got, low)
+			}
+			for _, ct := range []*callbackTracker{tc.callback, tc.rangeCallback} {
+				if ct == nil {
+					continue
+				}
+				if diff := pretty.Compare(tc.wantCalls, ct.calls); diff != "" {
+					t.Errorf("CallbackFn => unexpected diff (-want, +got):\n%s", diff)
+				}
+			}
+			if tc.want == nil {
+				return
+			}
+
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+			err = s.Draw(c, tc.meta)
+			if (err != nil) != tc.wantDrawErr {
+				t.Errorf("Draw => unexpected error: %v, wantDrawErr: %v", err, tc.wantDrawErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+		})
+	}
+}
+
+func TestOptions(t *testing.T) {
+	tests := []struct {
+		desc string
+		opts []Option
+		want widgetapi.Options
+	}{
+		{
+			desc: "horizontal slider reserves space for the value",
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{6, 1},
+				MaximumSize:  image.Point{0, 1},
+				WantKeyboard: widgetapi.KeyScopeFocused,
+				WantMouse:    widgetapi.MouseScopeGlobal,
+			},
+		},
+		{
+			desc: "horizontal slider in range mode",
+			opts: []Option{
+				Min(-5),
+				Max(5),
+				Range(0, 1),
+			},
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{9, 1},
+				MaximumSize:  image.Point{0, 1},
+				WantKeyboard: widgetapi.KeyScopeFocused,
+				WantMouse:    widgetapi.MouseScopeGlobal,
+			},
+		},
+		{
+			desc: "horizontal slider without the value",
+			opts: []Option{
+				HideValue(),
+			},
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{2, 1},
+				MaximumSize:  image.Point{0, 1},
+				WantKeyboard: widgetapi.KeyScopeFocused,
+				WantMouse:    widgetapi.MouseScopeGlobal,
+			},
+		},
+		{
+			desc: "vertical slider",
+			opts: []Option{
+				Vertical(),
+			},
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{3, 3},
+				MaximumSize:  image.Point{3, 0},
+				WantKeyboard: widgetapi.KeyScopeFocused,
+				WantMouse:    widgetapi.MouseScopeGlobal,
+			},
+		},
+		{
+			desc: "vertical slider without the value",
+			opts: []Option{
+				Vertical(),
+				HideValue(),
+			},
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{1, 2},
+				MaximumSize:  image.Point{1, 0},
+				WantKeyboard: widgetapi.KeyScopeFocused,
+				WantMouse:    widgetapi.MouseScopeGlobal,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			s, err := New(tc.opts...)
+			if err != nil {
+				t.Fatalf("New => unexpected error: %v", err)
+			}
+
+			got := s.Options()
+			if diff := pretty.Compare(tc.want, got); diff != "" {
+				t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
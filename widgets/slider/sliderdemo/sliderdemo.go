@@ -0,0 +1,131 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary sliderdemo shows the functionality of the slider widget.
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/termbox"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgets/slider"
+	"github.com/mum4k/termdash/widgets/text"
+)
+
+func main() {
+	t, err := termbox.New()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	log, err := text.New(text.RollContent())
+	if err != nil {
+		panic(err)
+	}
+
+	refresh, err := slider.New(
+		slider.Min(100),
+		slider.Max(5000),
+		slider.Step(100),
+		slider.Value(1000),
+		slider.OnChange(func(value int) error {
+			return log.Write(fmt.Sprintf("Refresh: %dms\n", value))
+		}),
+	)
+	if err != nil {
+		panic(err)
+	}
+	thresholds, err := slider.New(
+		slider.Range(20, 80),
+		slider.Step(5),
+		slider.OnRangeChange(func(low, high int) error {
+			return log.Write(fmt.Sprintf("Thresholds: %d%% to %d%%\n", low, high))
+		}),
+	)
+	if err != nil {
+		panic(err)
+	}
+	volume, err := slider.New(
+		slider.Vertical(),
+		slider.Value(50),
+		slider.FillColor(cell.ColorCyan),
+		slider.OnChange(func(value int) error {
+			return log.Write(fmt.Sprintf("Volume: %d\n", value))
+		}),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	c, err := container.New(
+		t,
+		container.Border(linestyle.Light),
+		container.BorderTitle("PRESS Q TO QUIT"),
+		container.SplitVertical(
+			container.Left(
+				container.SplitHorizontal(
+					container.Top(
+						container.SplitHorizontal(
+							container.Top(
+								container.Border(linestyle.Light),
+								container.BorderTitle("Refresh rate"),
+								container.PlaceWidget(refresh),
+							),
+							container.Bottom(
+								container.Border(linestyle.Light),
+								container.BorderTitle("Thresholds"),
+								container.PlaceWidget(thresholds),
+							),
+						),
+					),
+					container.Bottom(
+						container.Border(linestyle.Light),
+						container.BorderTitle("Click, drag or use the arrows on a focused slider"),
+						container.PlaceWidget(log),
+					),
+					container.SplitPercent(30),
+				),
+			),
+			container.Right(
+				container.Border(linestyle.Light),
+				container.BorderTitle("Volume"),
+				container.PlaceWidget(volume),
+			),
+			container.SplitPercent(85),
+		),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	quitter := func(k *terminalapi.Keyboard) {
+		if k.Key == 'q' || k.Key == 'Q' {
+			cancel()
+		}
+	}
+
+	if err := termdash.Run(ctx, t, c, termdash.KeyboardSubscriber(quitter), termdash.RedrawInterval(100*time.Millisecond)); err != nil {
+		panic(err)
+	}
+}
@@ -17,7 +17,10 @@ package sparkline
 // options.go contains configurable options for SparkLine.
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"strconv"
 
 	"github.com/mum4k/termdash/cell"
 )
@@ -42,12 +45,20 @@ type options struct {
 	labelCellOpts []cell.Option
 	height        int
 	color         cell.Color
+	// thresholds maps values to the colors of bars at or above them.
+	thresholds map[float64]cell.Color
+
+	showMin        bool
+	showMax        bool
+	showLast       bool
+	valueFormatter func(float64) string
 }
 
 // newOptions returns options with the default values set.
 func newOptions() *options {
 	return &options{
-		color: DefaultColor,
+		color:          DefaultColor,
+		valueFormatter: defaultValueFormatter,
 	}
 }
 
@@ -56,6 +67,14 @@ func (o *options) validate() error {
 	if got, min := o.height, 0; got < min {
 		return fmt.Errorf("invalid Height %d, must be %d <= Height", got, min)
 	}
+	for v := range o.thresholds {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("invalid Threshold %v, must be a valid number", v)
+		}
+	}
+	if o.valueFormatter == nil {
+		return errors.New("the ValueFormatter cannot be nil")
+	}
 	return nil
 }
 
@@ -81,8 +100,71 @@ const DefaultColor = cell.ColorGreen
 
 // Color sets the color of the SparkLine.
 // Defaults to DefaultColor if not set.
+// If thresholds are provided, this is the color of bars with values below the
+// lowest threshold.
 func Color(c cell.Color) Option {
 	return option(func(opts *options) {
 		opts.color = c
 	})
 }
+
+// Threshold sets the color of bars with values that reach or exceed the
+// specified value. Can be provided multiple times to define multiple color
+// bands, each bar is drawn with the color of the highest threshold it
+// reached. Providing the same value again replaces its color.
+func Threshold(value float64, c cell.Color) Option {
+	return option(func(opts *options) {
+		thresholds := map[float64]cell.Color{}
+		for v, c := range opts.thresholds {
+			thresholds[v] = c
+		}
+		thresholds[value] = c
+		opts.thresholds = thresholds
+	})
+}
+
+// ClearThresholds removes all the thresholds set by previous options, all the
+// bars are then drawn with the color set by the Color option.
+func ClearThresholds() Option {
+	return option(func(opts *options) {
+		opts.thresholds = nil
+	})
+}
+
+// ShowMin displays the minimum of the visible values on the line with the
+// label, aligned to the right.
+func ShowMin() Option {
+	return option(func(opts *options) {
+		opts.showMin = true
+	})
+}
+
+// ShowMax displays the maximum of the visible values on the line with the
+// label, aligned to the right.
+func ShowMax() Option {
+	return option(func(opts *options) {
+		opts.showMax = true
+	})
+}
+
+// ShowLast displays the last added value on the line with the label, aligned
+// to the right.
+func ShowLast() Option {
+	return option(func(opts *options) {
+		opts.showLast = true
+	})
+}
+
+// defaultValueFormatter is the default formatter of the displayed values.
+func defaultValueFormatter(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+// ValueFormatter sets the function that formats the values displayed by the
+// ShowMin, ShowMax and ShowLast options. Defaults to at most two decimal
+// places, e.g. "-1.25".
+func ValueFormatter(f func(float64) string) Option {
+	return option(func(opts *options) {
+		opts.valueFormatter = f
+	})
+}
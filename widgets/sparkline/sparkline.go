@@ -19,12 +19,15 @@ import (
 	"errors"
 	"fmt"
 	"image"
+	"math"
+	"strings"
 	"sync"
 
 	"github.com/mum4k/termdash/cell"
 	"github.com/mum4k/termdash/internal/area"
 	"github.com/mum4k/termdash/internal/canvas"
 	"github.com/mum4k/termdash/internal/draw"
+	"github.com/mum4k/termdash/internal/runewidth"
 	"github.com/mum4k/termdash/terminal/terminalapi"
 	"github.com/mum4k/termdash/widgetapi"
 )
@@ -32,12 +35,13 @@ import (
 // SparkLine draws a graph showing a series of values as vertical bars.
 //
 // Bars can have sub-cell height. The graphs scale adjusts dynamically based on
-// the largest visible value.
+// the largest visible value. Bars of positive values grow up from the zero
+// baseline, bars of negative values grow down from it.
 //
 // Implements widgetapi.Widget. This object is thread-safe.
 type SparkLine struct {
 	// data are the data points the SparkLine displays.
-	data []int
+	data []float64
 
 	// lastWidth is the width of the canvas as of the last time when Draw was called.
 	lastWidth int
@@ -81,6 +85,12 @@ func (sl *SparkLine) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
 
 	ar := sl.area(cvs)
 	visible, max := visibleMax(sl.data, ar.Dx())
+	min := minOf(visible)
+	up, down := splitCells(min, max, ar.Dy())
+	perCell := cellValue(min, max, up, down)
+	// baseY is the first line under the zero baseline.
+	baseY := ar.Min.Y + up
+
 	var curX int
 	if len(visible) < ar.Dx() {
 		curX = ar.Max.X - len(visible)
@@ -89,46 +99,140 @@ func (sl *SparkLine) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
 	}
 
 	for _, v := range visible {
-		blocks := toBlocks(v, max, ar.Dy())
-		curY := ar.Max.Y - 1
-		for i := 0; i < blocks.full; i++ {
-			if _, err := cvs.SetCell(
-				image.Point{curX, curY},
-				sparks[len(sparks)-1], // Last spark represents full cell.
-				cell.FgColor(sl.opts.color),
-			); err != nil {
+		color := cell.FgColor(sl.barColor(v))
+		if v >= 0 {
+			blocks := toBlocks(v, perCell*float64(up), up)
+			if err := drawBar(cvs, image.Point{curX, baseY - 1}, -1, blocks, color); err != nil {
+				return err
+			}
+		} else {
+			blocks := toBlocks(-v, perCell*float64(down), down)
+			if err := drawBar(cvs, image.Point{curX, baseY}, 1, blocks, color); err != nil {
 				return err
 			}
+		}
+		curX++
+	}
+	return sl.drawLabelLine(cvs, ar, visible)
+}
 
-			curY--
+// drawBar draws one bar from the start point in the vertical direction
+// indicated by dir, i.e. up for a negative dir and down for a positive one.
+func drawBar(cvs *canvas.Canvas, start image.Point, dir int, b blocks, opts ...cell.Option) error {
+	cur := start
+	for i := 0; i < b.full; i++ {
+		if _, err := cvs.SetCell(
+			cur,
+			sparks[len(sparks)-1], // Last spark represents full cell.
+			opts...,
+		); err != nil {
+			return err
 		}
+		cur.Y += dir
+	}
 
-		if blocks.partSpark != 0 {
-			if _, err := cvs.SetCell(
-				image.Point{curX, curY},
-				blocks.partSpark,
-				cell.FgColor(sl.opts.color),
-			); err != nil {
-				return err
-			}
+	if b.partSpark == 0 {
+		return nil
+	}
+	r := b.partSpark
+	if dir > 0 {
+		// Sparks fill the bottom of the cell, the part of a bar growing down
+		// fills the top.
+		r = invertedSpark(r)
+		opts = append(opts, cell.Inverse())
+	}
+	_, err := cvs.SetCell(cur, r, opts...)
+	return err
+}
+
+// barColor returns the color of the bar representing the value, i.e. the
+// color of the highest threshold the value reached or the color set by the
+// Color option if it didn't reach any.
+func (sl *SparkLine) barColor(v float64) cell.Color {
+	color := sl.opts.color
+	highest := math.Inf(-1)
+	for t, c := range sl.opts.thresholds {
+		if t > highest && v >= t {
+			color = c
+			highest = t
 		}
+	}
+	return color
+}
 
-		curX++
+// annotations returns the text that displays the minimum, maximum and last
+// values as requested by the options. Returns an empty string if no such
+// option was provided or there are no visible values.
+func (sl *SparkLine) annotations(visible []float64) string {
+	if len(visible) == 0 {
+		return ""
+	}
+
+	min, max := visible[0], visible[0]
+	for _, v := range visible {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+
+	var parts []string
+	if sl.opts.showMin {
+		parts = append(parts, fmt.Sprintf("min:%s", sl.opts.valueFormatter(min)))
+	}
+	if sl.opts.showMax {
+		parts = append(parts, fmt.Sprintf("max:%s", sl.opts.valueFormatter(max)))
+	}
+	if sl.opts.showLast {
+		parts = append(parts, fmt.Sprintf("last:%s", sl.opts.valueFormatter(visible[len(visible)-1])))
+	}
+	return strings.Join(parts, " ")
+}
+
+// drawLabelLine draws the label and the annotations on the line immediately
+// above the SparkLine. The annotations are aligned to the right, the label is
+// trimmed if it doesn't fit next to them.
+func (sl *SparkLine) drawLabelLine(cvs *canvas.Canvas, ar image.Rectangle, visible []float64) error {
+	if !sl.hasLabelLine() {
+		return nil
 	}
 
-	if sl.opts.label != "" {
-		// Label is placed immediately above the SparkLine.
-		lStart := image.Point{ar.Min.X, ar.Min.Y - 1}
-		if err := draw.Text(cvs, sl.opts.label, lStart,
+	y := ar.Min.Y - 1
+	labelMaxX := ar.Max.X
+	if ann := sl.annotations(visible); ann != "" {
+		start := image.Point{ar.Max.X - runewidth.StringWidth(ann), y}
+		if start.X < ar.Min.X {
+			start.X = ar.Min.X
+		}
+		if err := draw.Text(cvs, ann, start,
 			draw.TextCellOpts(sl.opts.labelCellOpts...),
 			draw.TextOverrunMode(draw.OverrunModeThreeDot),
 		); err != nil {
 			return err
 		}
+		labelMaxX = start.X - 1 // One space between the label and the annotations.
+	}
+
+	if sl.opts.label != "" && labelMaxX > ar.Min.X {
+		if err := draw.Text(cvs, sl.opts.label, image.Point{ar.Min.X, y},
+			draw.TextCellOpts(sl.opts.labelCellOpts...),
+			draw.TextMaxX(labelMaxX),
+			draw.TextOverrunMode(draw.OverrunModeThreeDot),
+		); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// hasLabelLine determines if the SparkLine needs a line for the label or the
+// annotations.
+func (sl *SparkLine) hasLabelLine() bool {
+	return sl.opts.label != "" || sl.opts.showMin || sl.opts.showMax || sl.opts.showLast
+}
+
 // ValueCapacity returns the number of values that can fit into the canvas.
 // This is essentially the number of available cells on the canvas as observed
 // on the last call to draw. Returns zero if draw wasn't called.
@@ -147,8 +251,8 @@ func (sl *SparkLine) ValueCapacity() int {
 // points are valid and are represented by an empty space on the SparkLine
 // (i.e. a missing bar).
 //
-// At least one data point must be provided. All data points must be positive
-// integers.
+// At least one data point must be provided. Bars of negative data points grow
+// down from the zero baseline. Use AddFloats to add fractional data points.
 //
 // The last added data point will be the one displayed all the way on the right
 // of the SparkLine. If there are more data points than we can fit bars to the
@@ -160,19 +264,50 @@ func (sl *SparkLine) Add(data []int, opts ...Option) error {
 	sl.mu.Lock()
 	defer sl.mu.Unlock()
 
-	for _, opt := range opts {
-		opt.set(sl.opts)
+	if err := sl.setOptions(opts...); err != nil {
+		return err
+	}
+	for _, d := range data {
+		sl.data = append(sl.data, float64(d))
 	}
+	return nil
+}
+
+// AddFloats adds data points to the SparkLine.
+// Works the same way as Add, except the data points can be any valid numbers,
+// i.e. not NaN or infinity.
+//
+// Provided options override values set when New() was called.
+func (sl *SparkLine) AddFloats(data []float64, opts ...Option) error {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
 
 	for i, d := range data {
-		if d < 0 {
-			return fmt.Errorf("data point[%d]: %v must be a positive integer", i, d)
+		if math.IsNaN(d) || math.IsInf(d, 0) {
+			return fmt.Errorf("data point[%d]: %v must be a valid number", i, d)
 		}
 	}
+	if err := sl.setOptions(opts...); err != nil {
+		return err
+	}
 	sl.data = append(sl.data, data...)
 	return nil
 }
 
+// setOptions validates the provided options and applies them if they are
+// valid.
+func (sl *SparkLine) setOptions(opts ...Option) error {
+	newOpts := *sl.opts
+	for _, opt := range opts {
+		opt.set(&newOpts)
+	}
+	if err := newOpts.validate(); err != nil {
+		return err
+	}
+	sl.opts = &newOpts
+	return nil
+}
+
 // Clear removes all the data points in the SparkLine, effectively returning to
 // an empty graph.
 func (sl *SparkLine) Clear() {
@@ -204,7 +339,7 @@ func (sl *SparkLine) area(cvs *canvas.Canvas) image.Rectangle {
 	} else {
 		minY = cvsAr.Min.Y
 
-		if sl.opts.label != "" {
+		if sl.hasLabelLine() {
 			minY++ // Reserve one line for the label.
 		}
 	}
@@ -227,7 +362,7 @@ func (sl *SparkLine) minSize() image.Point {
 		minHeight = 1 // At least one line of characters.
 	}
 
-	if sl.opts.label != "" {
+	if sl.hasLabelLine() {
 		minHeight++ // One line for the text label.
 	}
 	return image.Point{minWidth, minHeight}
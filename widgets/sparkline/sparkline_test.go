@@ -15,7 +15,10 @@
 package sparkline
 
 import (
+	"errors"
+	"fmt"
 	"image"
+	"math"
 	"testing"
 
 	"github.com/kylelemons/godebug/pretty"
@@ -66,17 +69,6 @@ func TestSparkLine(t *testing.T) {
 			},
 			wantCapacity: 1,
 		},
-		{
-			desc: "fails on negative data points",
-			update: func(sl *SparkLine) error {
-				return sl.Add([]int{0, 3, -1, 2})
-			},
-			canvas: image.Rect(0, 0, 1, 1),
-			want: func(size image.Point) *faketerm.Terminal {
-				return faketerm.MustNew(size)
-			},
-			wantUpdateErr: true,
-		},
 		{
 			desc: "single height sparkline",
 			update: func(sl *SparkLine) error {
@@ -451,6 +443,283 @@ func TestSparkLine(t *testing.T) {
 			},
 			wantCapacity: 9,
 		},
+		{
+			desc: "fails on nil ValueFormatter",
+			opts: []Option{
+				ValueFormatter(nil),
+			},
+			update: func(sl *SparkLine) error {
+				return nil
+			},
+			canvas: image.Rect(0, 0, 1, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantErr: true,
+		},
+		{
+			desc: "fails on NaN threshold",
+			opts: []Option{
+				Threshold(math.NaN(), cell.ColorRed),
+			},
+			update: func(sl *SparkLine) error {
+				return nil
+			},
+			canvas: image.Rect(0, 0, 1, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantErr: true,
+		},
+		{
+			desc: "Add fails on invalid option",
+			update: func(sl *SparkLine) error {
+				return sl.Add([]int{1}, Height(-1))
+			},
+			canvas: image.Rect(0, 0, 1, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc: "AddFloats fails on NaN data points",
+			update: func(sl *SparkLine) error {
+				return sl.AddFloats([]float64{1, math.NaN()})
+			},
+			canvas: image.Rect(0, 0, 1, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc: "AddFloats fails on infinite data points",
+			update: func(sl *SparkLine) error {
+				return sl.AddFloats([]float64{math.Inf(-1)})
+			},
+			canvas: image.Rect(0, 0, 1, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantUpdateErr: true,
+		},
+		{
+			desc: "draws fractional data points",
+			update: func(sl *SparkLine) error {
+				return sl.AddFloats([]float64{0.5, 1})
+			},
+			canvas: image.Rect(0, 0, 2, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "▄█", image.Point{0, 0}, draw.TextCellOpts(
+					cell.FgColor(DefaultColor),
+				))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantCapacity: 2,
+		},
+		{
+			desc: "negative data points grow down from the baseline",
+			update: func(sl *SparkLine) error {
+				return sl.AddFloats([]float64{4, -4, 2, -2})
+			},
+			canvas: image.Rect(0, 0, 4, 2),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				opt := cell.FgColor(DefaultColor)
+				testcanvas.MustSetCell(c, image.Point{0, 0}, '█', opt)
+				testcanvas.MustSetCell(c, image.Point{1, 1}, '█', opt)
+				testcanvas.MustSetCell(c, image.Point{2, 0}, '▄', opt)
+				testcanvas.MustSetCell(c, image.Point{3, 1}, '▄', opt, cell.Inverse())
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantCapacity: 4,
+		},
+		{
+			desc: "negative integer data points grow down from the baseline",
+			update: func(sl *SparkLine) error {
+				return sl.Add([]int{4, -4, 2, -2})
+			},
+			canvas: image.Rect(0, 0, 4, 2),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				opt := cell.FgColor(DefaultColor)
+				testcanvas.MustSetCell(c, image.Point{0, 0}, '█', opt)
+				testcanvas.MustSetCell(c, image.Point{1, 1}, '█', opt)
+				testcanvas.MustSetCell(c, image.Point{2, 0}, '▄', opt)
+				testcanvas.MustSetCell(c, image.Point{3, 1}, '▄', opt, cell.Inverse())
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantCapacity: 4,
+		},
+		{
+			desc: "all negative data points grow down from the top",
+			update: func(sl *SparkLine) error {
+				return sl.AddFloats([]float64{-2, -1, -0.5})
+			},
+			canvas: image.Rect(0, 0, 3, 2),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				opt := cell.FgColor(DefaultColor)
+				testcanvas.MustSetCell(c, image.Point{0, 0}, '█', opt)
+				testcanvas.MustSetCell(c, image.Point{0, 1}, '█', opt)
+				testcanvas.MustSetCell(c, image.Point{1, 0}, '█', opt)
+				testcanvas.MustSetCell(c, image.Point{2, 0}, '▄', opt, cell.Inverse())
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantCapacity: 3,
+		},
+		{
+			desc: "colors bars by thresholds",
+			opts: []Option{
+				Threshold(9, cell.ColorRed),
+				Threshold(5, cell.ColorYellow),
+			},
+			update: func(sl *SparkLine) error {
+				return sl.Add([]int{1, 5, 9})
+			},
+			canvas: image.Rect(0, 0, 3, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testcanvas.MustSetCell(c, image.Point{0, 0}, '▁', cell.FgColor(DefaultColor))
+				testcanvas.MustSetCell(c, image.Point{1, 0}, '▄', cell.FgColor(cell.ColorYellow))
+				testcanvas.MustSetCell(c, image.Point{2, 0}, '█', cell.FgColor(cell.ColorRed))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantCapacity: 3,
+		},
+		{
+			desc: "thresholds can be cleared on a call to Add",
+			opts: []Option{
+				Threshold(5, cell.ColorYellow),
+			},
+			update: func(sl *SparkLine) error {
+				return sl.Add([]int{5}, ClearThresholds())
+			},
+			canvas: image.Rect(0, 0, 1, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testcanvas.MustSetCell(c, image.Point{0, 0}, '█', cell.FgColor(DefaultColor))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantCapacity: 1,
+		},
+		{
+			desc: "displays annotations next to the label",
+			opts: []Option{
+				Label("cpu", cell.FgColor(cell.ColorBlue)),
+				ShowMax(),
+				ShowLast(),
+			},
+			update: func(sl *SparkLine) error {
+				return sl.AddFloats([]float64{1.5, -0.25, 3})
+			},
+			canvas: image.Rect(0, 0, 20, 2),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "cpu", image.Point{0, 0}, draw.TextCellOpts(
+					cell.FgColor(cell.ColorBlue),
+				))
+				testdraw.MustText(c, "max:3 last:3", image.Point{8, 0}, draw.TextCellOpts(
+					cell.FgColor(cell.ColorBlue),
+				))
+				testcanvas.MustSetCell(c, image.Point{17, 1}, '▄', cell.FgColor(DefaultColor))
+				testcanvas.MustSetCell(c, image.Point{19, 1}, '█', cell.FgColor(DefaultColor))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantCapacity: 20,
+		},
+		{
+			desc: "displays annotations without label using custom formatter",
+			opts: []Option{
+				ShowMin(),
+				ValueFormatter(func(v float64) string {
+					return fmt.Sprintf("%.1f", v)
+				}),
+			},
+			update: func(sl *SparkLine) error {
+				return sl.AddFloats([]float64{2, 4})
+			},
+			canvas: image.Rect(0, 0, 8, 2),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "min:2.0", image.Point{1, 0})
+				testcanvas.MustSetCell(c, image.Point{6, 1}, '▄', cell.FgColor(DefaultColor))
+				testcanvas.MustSetCell(c, image.Point{7, 1}, '█', cell.FgColor(DefaultColor))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantCapacity: 8,
+		},
+		{
+			desc: "keeps the options when adding data rejects them",
+			opts: []Option{
+				ShowLast(),
+			},
+			update: func(sl *SparkLine) error {
+				if err := sl.AddFloats([]float64{3}, ValueFormatter(nil)); err == nil {
+					return errors.New("AddFloats => got nil error, want one")
+				}
+				return sl.Add([]int{1})
+			},
+			canvas: image.Rect(0, 0, 10, 2),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "last:1", image.Point{4, 0})
+				testcanvas.MustSetCell(c, image.Point{9, 1}, '█', cell.FgColor(DefaultColor))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantCapacity: 10,
+		},
+		{
+			desc: "trims the label to fit next to the annotations",
+			opts: []Option{
+				Label("long label"),
+				ShowLast(),
+			},
+			update: func(sl *SparkLine) error {
+				return sl.Add([]int{1})
+			},
+			canvas: image.Rect(0, 0, 10, 2),
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "lo…", image.Point{0, 0})
+				testdraw.MustText(c, "last:1", image.Point{4, 0})
+				testcanvas.MustSetCell(c, image.Point{9, 1}, '█', cell.FgColor(DefaultColor))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+			wantCapacity: 10,
+		},
 	}
 
 	for _, tc := range tests {
@@ -541,6 +810,17 @@ func TestOptions(t *testing.T) {
 				WantMouse:    widgetapi.MouseScopeNone,
 			},
 		},
+		{
+			desc: "annotations without label and no fixed height",
+			opts: []Option{
+				ShowLast(),
+			},
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{1, 2},
+				WantKeyboard: widgetapi.KeyScopeNone,
+				WantMouse:    widgetapi.MouseScopeNone,
+			},
+		},
 		{
 			desc: "label and fixed height",
 			opts: []Option{
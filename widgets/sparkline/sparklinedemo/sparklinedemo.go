@@ -18,6 +18,7 @@ package main
 
 import (
 	"context"
+	"math"
 	"math/rand"
 	"time"
 
@@ -75,6 +76,28 @@ func fillSparkLine(ctx context.Context, sl *sparkline.SparkLine, delay time.Dura
 	}
 }
 
+// playWave continuously adds values of a noisy sine wave that goes below zero
+// to the SparkLine, once every delay. Exits when the context expires.
+func playWave(ctx context.Context, sl *sparkline.SparkLine, delay time.Duration) {
+	var step float64
+
+	ticker := time.NewTicker(delay)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			v := math.Sin(step)*10 + rand.Float64()*4 - 2
+			if err := sl.AddFloats([]float64{v}); err != nil {
+				panic(err)
+			}
+			step += 0.2
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 func main() {
 	t, err := termbox.New()
 	if err != nil {
@@ -107,6 +130,18 @@ func main() {
 		panic(err)
 	}
 	go fillSparkLine(ctx, yellow, 1*time.Second)
+	wave, err := sparkline.New(
+		sparkline.Label("Wave", cell.FgColor(cell.ColorBlue)),
+		sparkline.Color(cell.ColorCyan),
+		sparkline.Threshold(8, cell.ColorRed),
+		sparkline.ShowMin(),
+		sparkline.ShowMax(),
+		sparkline.ShowLast(),
+	)
+	if err != nil {
+		panic(err)
+	}
+	go playWave(ctx, wave, 250*time.Millisecond)
 
 	c, err := container.New(
 		t,
@@ -115,7 +150,11 @@ func main() {
 		container.SplitVertical(
 			container.Left(
 				container.SplitHorizontal(
-					container.Top(),
+					container.Top(
+						container.Border(linestyle.Light),
+						container.BorderTitle("Negative values"),
+						container.PlaceWidget(wave),
+					),
 					container.Bottom(
 						container.Border(linestyle.Light),
 						container.BorderTitle("SparkLine group"),
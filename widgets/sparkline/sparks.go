@@ -29,8 +29,8 @@ var sparks = []rune{'▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
 
 // visibleMax determines the maximum visible data point given the canvas width.
 // Returns a slice that contains only visible data points and the maximum value
-// among them.
-func visibleMax(data []int, width int) ([]int, int) {
+// among them or zero if all of them are negative.
+func visibleMax(data []float64, width int) ([]float64, float64) {
 	if width <= 0 || len(data) == 0 {
 		return nil, 0
 	}
@@ -39,7 +39,7 @@ func visibleMax(data []int, width int) ([]int, int) {
 		data = data[len(data)-width:]
 	}
 
-	var max int
+	var max float64
 	for _, v := range data {
 		if v > max {
 			max = v
@@ -48,6 +48,61 @@ func visibleMax(data []int, width int) ([]int, int) {
 	return data, max
 }
 
+// minOf returns the minimum value among the data points or zero if all of them
+// are positive.
+func minOf(data []float64) float64 {
+	var min float64
+	for _, v := range data {
+		if v < min {
+			min = v
+		}
+	}
+	return min
+}
+
+// splitCells splits the vertical cells between the bars of positive values
+// that grow up from the zero baseline and the bars of negative values that
+// grow down from it, proportionally to the maximum and the minimum value.
+// The max must be zero or positive and the min zero or negative.
+// Returns the number of cells above and below the baseline.
+func splitCells(min, max float64, vertCells int) (up, down int) {
+	switch {
+	case min >= 0:
+		return vertCells, 0
+	case max <= 0:
+		return 0, vertCells
+	case vertCells == 1:
+		if max >= -min {
+			return 1, 0
+		}
+		return 0, 1
+	}
+
+	up = int(math.Round(float64(vertCells) * max / (max - min)))
+	if up < 1 {
+		up = 1
+	}
+	if up > vertCells-1 {
+		up = vertCells - 1
+	}
+	return up, vertCells - up
+}
+
+// cellValue determines the value represented by one full vertical cell so that
+// bars above and below the baseline use the same scale.
+func cellValue(min, max float64, up, down int) float64 {
+	var res float64
+	if up > 0 {
+		res = max / float64(up)
+	}
+	if down > 0 {
+		if v := -min / float64(down); v > res {
+			res = v
+		}
+	}
+	return res
+}
+
 // blocks represents the building blocks that display one value on a SparkLine.
 // I.e. one vertical bar.
 type blocks struct {
@@ -62,7 +117,7 @@ type blocks struct {
 // toBlocks determines the number of full and partial vertical blocks required
 // to represent the provided value given the specified max visible value and
 // number of vertical cells available to the SparkLine.
-func toBlocks(value, max, vertCells int) blocks {
+func toBlocks(value, max float64, vertCells int) blocks {
 	if value <= 0 || max <= 0 || vertCells <= 0 {
 		return blocks{}
 	}
@@ -72,10 +127,10 @@ func toBlocks(value, max, vertCells int) blocks {
 
 	// Scale is how much of the max does one smallest spark element represent,
 	// given the vertical cells that will be used to represent the value.
-	scale := float64(cellSparks) * float64(vertCells) / float64(max)
+	scale := float64(cellSparks) * float64(vertCells) / max
 
 	// How many smallest spark elements are needed to represent the value.
-	elements := int(math.Round(float64(value) * scale))
+	elements := int(math.Round(value * scale))
 
 	b := blocks{
 		full: elements / cellSparks,
@@ -88,6 +143,18 @@ func toBlocks(value, max, vertCells int) blocks {
 	return b
 }
 
+// invertedSpark returns the spark that has to be drawn with inverted colors
+// in order to fill the same part of a cell as the provided spark but from the
+// top of the cell. Used for bars that grow down from the baseline.
+func invertedSpark(partSpark rune) rune {
+	for i, s := range sparks {
+		if s == partSpark {
+			return sparks[len(sparks)-2-i]
+		}
+	}
+	return 0
+}
+
 // init ensures that all spark characters are half-width runes.
 // The SparkLine widget assumes that each value can be represented in a column
 // that has a width of one cell.
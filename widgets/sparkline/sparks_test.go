@@ -23,10 +23,10 @@ import (
 func TestVisibleMax(t *testing.T) {
 	tests := []struct {
 		desc     string
-		data     []int
+		data     []float64
 		width    int
-		wantData []int
-		wantMax  int
+		wantData []float64
+		wantMax  float64
 	}{
 		{
 			desc:     "zero for no data",
@@ -36,51 +36,51 @@ func TestVisibleMax(t *testing.T) {
 		},
 		{
 			desc:     "zero for zero width",
-			data:     []int{0, 1},
+			data:     []float64{0, 1},
 			width:    0,
 			wantData: nil,
 			wantMax:  0,
 		},
 		{
 			desc:     "zero for negative width",
-			data:     []int{0, 1},
+			data:     []float64{0, 1},
 			width:    -1,
 			wantData: nil,
 			wantMax:  0,
 		},
 		{
 			desc:     "all values are zero",
-			data:     []int{0, 0, 0},
+			data:     []float64{0, 0, 0},
 			width:    3,
-			wantData: []int{0, 0, 0},
+			wantData: []float64{0, 0, 0},
 			wantMax:  0,
 		},
 		{
 			desc:     "all values are visible",
-			data:     []int{8, 0, 1},
+			data:     []float64{8, 0, 1},
 			width:    3,
-			wantData: []int{8, 0, 1},
+			wantData: []float64{8, 0, 1},
 			wantMax:  8,
 		},
 		{
 			desc:     "width greater than number of values",
-			data:     []int{8, 0, 1},
+			data:     []float64{8, 0, 1},
 			width:    10,
-			wantData: []int{8, 0, 1},
+			wantData: []float64{8, 0, 1},
 			wantMax:  8,
 		},
 		{
 			desc:     "only some values are visible",
-			data:     []int{8, 2, 1},
+			data:     []float64{8, 2, 1},
 			width:    2,
-			wantData: []int{2, 1},
+			wantData: []float64{2, 1},
 			wantMax:  2,
 		},
 		{
 			desc:     "only one value is visible",
-			data:     []int{8, 2, 1},
+			data:     []float64{8, 2, 1},
 			width:    1,
-			wantData: []int{1},
+			wantData: []float64{1},
 			wantMax:  1,
 		},
 	}
@@ -101,8 +101,8 @@ func TestVisibleMax(t *testing.T) {
 func TestToBlocks(t *testing.T) {
 	tests := []struct {
 		desc      string
-		value     int
-		max       int
+		value     float64
+		max       float64
 		vertCells int
 		want      blocks
 	}{
@@ -268,3 +268,173 @@ func findRune(target rune, runes []rune) int {
 	}
 	return -1
 }
+
+func TestMinOf(t *testing.T) {
+	tests := []struct {
+		desc string
+		data []float64
+		want float64
+	}{
+		{
+			desc: "zero for no data",
+			want: 0,
+		},
+		{
+			desc: "zero when all values are positive",
+			data: []float64{1, 2},
+			want: 0,
+		},
+		{
+			desc: "the smallest negative value",
+			data: []float64{1, -2.5, -1},
+			want: -2.5,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := minOf(tc.data); got != tc.want {
+				t.Errorf("minOf => %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSplitCells(t *testing.T) {
+	tests := []struct {
+		desc      string
+		min       float64
+		max       float64
+		vertCells int
+		wantUp    int
+		wantDown  int
+	}{
+		{
+			desc:      "all cells up without negative values",
+			min:       0,
+			max:       10,
+			vertCells: 3,
+			wantUp:    3,
+		},
+		{
+			desc:      "all cells up when all values are zero",
+			vertCells: 3,
+			wantUp:    3,
+		},
+		{
+			desc:      "all cells down without positive values",
+			min:       -10,
+			max:       0,
+			vertCells: 3,
+			wantDown:  3,
+		},
+		{
+			desc:      "splits proportionally",
+			min:       -10,
+			max:       30,
+			vertCells: 4,
+			wantUp:    3,
+			wantDown:  1,
+		},
+		{
+			desc:      "keeps at least one cell up",
+			min:       -100,
+			max:       1,
+			vertCells: 4,
+			wantUp:    1,
+			wantDown:  3,
+		},
+		{
+			desc:      "keeps at least one cell down",
+			min:       -1,
+			max:       100,
+			vertCells: 4,
+			wantUp:    3,
+			wantDown:  1,
+		},
+		{
+			desc:      "single cell goes to the larger positive value",
+			min:       -1,
+			max:       2,
+			vertCells: 1,
+			wantUp:    1,
+		},
+		{
+			desc:      "single cell goes to the larger negative value",
+			min:       -2,
+			max:       1,
+			vertCells: 1,
+			wantDown:  1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			gotUp, gotDown := splitCells(tc.min, tc.max, tc.vertCells)
+			if gotUp != tc.wantUp || gotDown != tc.wantDown {
+				t.Errorf("splitCells => %d, %d, want %d, %d", gotUp, gotDown, tc.wantUp, tc.wantDown)
+			}
+		})
+	}
+}
+
+func TestCellValue(t *testing.T) {
+	tests := []struct {
+		desc string
+		min  float64
+		max  float64
+		up   int
+		down int
+		want float64
+	}{
+		{
+			desc: "only positive values",
+			max:  10,
+			up:   2,
+			want: 5,
+		},
+		{
+			desc: "only negative values",
+			min:  -10,
+			down: 5,
+			want: 2,
+		},
+		{
+			desc: "uses the larger value per cell",
+			min:  -10,
+			max:  30,
+			up:   2,
+			down: 2,
+			want: 15,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := cellValue(tc.min, tc.max, tc.up, tc.down); got != tc.want {
+				t.Errorf("cellValue => %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestInvertedSpark(t *testing.T) {
+	tests := []struct {
+		spark rune
+		want  rune
+	}{
+		{'▁', '▇'},
+		{'▂', '▆'},
+		{'▄', '▄'},
+		{'▇', '▁'},
+		{'x', 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(string(tc.spark), func(t *testing.T) {
+			if got := invertedSpark(tc.spark); got != tc.want {
+				t.Errorf("invertedSpark(%q) => %q, want %q", tc.spark, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+// options.go contains configurable options for Spinner.
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"unicode"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/runewidth"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// options holds the provided options.
+type options struct {
+	frameSet   FrameSet
+	frames     []string
+	interval   time.Duration
+	label      string
+	stopped    bool
+	color      cell.Color
+	labelColor cell.Color
+	redraw     func() error
+}
+
+// validate validates the provided options.
+func (o *options) validate() error {
+	if o.frames == nil {
+		if _, ok := frameSets[o.frameSet]; !ok {
+			return fmt.Errorf("unsupported FrameSet(%d)", o.frameSet)
+		}
+	} else if len(o.frames) == 0 {
+		return errors.New("at least one custom frame must be provided")
+	}
+	for _, f := range o.frames {
+		if f == "" {
+			return errors.New("custom frames cannot be empty")
+		}
+		if err := validText(f); err != nil {
+			return fmt.Errorf("invalid custom frame: %v", err)
+		}
+	}
+	if o.interval <= 0 {
+		return fmt.Errorf("invalid Interval %v, must be a positive duration", o.interval)
+	}
+	if err := validateLabel(o.label); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validateLabel validates the label.
+func validateLabel(label string) error {
+	if label == "" {
+		return nil
+	}
+	if err := validText(label); err != nil {
+		return fmt.Errorf("invalid label: %v", err)
+	}
+	return nil
+}
+
+// validText validates text displayed on a single line.
+// The text must not contain any control or space characters other than ' '.
+func validText(text string) error {
+	for _, c := range text {
+		if c == ' ' {
+			continue
+		}
+		if unicode.IsControl(c) {
+			return fmt.Errorf("the provided text %q cannot contain control characters, found: %q", text, c)
+		}
+		if unicode.IsSpace(c) {
+			return fmt.Errorf("the provided text %q cannot contain space character %q", text, c)
+		}
+	}
+	return nil
+}
+
+// newOptions returns options with the default values set.
+func newOptions() *options {
+	return &options{
+		frameSet:   FrameSetBraille,
+		interval:   DefaultInterval,
+		color:      cell.ColorDefault,
+		labelColor: cell.ColorDefault,
+	}
+}
+
+// FrameSet is a set of frames built into the spinner.
+type FrameSet int
+
+// String implements fmt.Stringer()
+func (fs FrameSet) String() string {
+	if n, ok := frameSetNames[fs]; ok {
+		return n
+	}
+	return "FrameSetUnknown"
+}
+
+// frameSetNames maps FrameSet values to human readable names.
+var frameSetNames = map[FrameSet]string{
+	FrameSetBraille: "FrameSetBraille",
+	FrameSetLine:    "FrameSetLine",
+	FrameSetArc:     "FrameSetArc",
+	FrameSetCircle:  "FrameSetCircle",
+}
+
+const (
+	// FrameSetBraille is a dot circling in a braille character.
+	FrameSetBraille FrameSet = iota
+	// FrameSetLine is a rotating line drawn with ASCII characters.
+	FrameSetLine
+	// FrameSetArc is an arc rotating along a circle.
+	FrameSetArc
+	// FrameSetCircle is a quarter of a circle that rotates.
+	FrameSetCircle
+)
+
+// frameSets are the frames of the built-in frame sets.
+var frameSets = map[FrameSet][]string{
+	FrameSetBraille: {"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"},
+	FrameSetLine:    {"-", "\\", "|", "/"},
+	FrameSetArc:     {"◜", "◠", "◝", "◞", "◡", "◟"},
+	FrameSetCircle:  {"◴", "◷", "◶", "◵"},
+}
+
+// Frames sets the built-in frame set the spinner displays.
+// Defaults to FrameSetBraille.
+func Frames(fs FrameSet) Option {
+	return option(func(opts *options) {
+		opts.frameSet = fs
+	})
+}
+
+// CustomFrames sets custom frames the spinner displays in a loop instead of
+// a built-in frame set. At least one non-empty frame must be provided. The
+// frames can have different widths, the spinner reserves space for the
+// widest one.
+func CustomFrames(frames ...string) Option {
+	return option(func(opts *options) {
+		opts.frames = frames
+		if opts.frames == nil {
+			opts.frames = []string{}
+		}
+	})
+}
+
+// DefaultInterval is the default value for the Interval option.
+const DefaultInterval = 100 * time.Millisecond
+
+// Interval sets the time each frame is displayed for. The displayed frame is
+// determined by the time elapsed since the spinner started, but it only
+// appears on the screen when termdash redraws it. Provide the Redraw option to
+// animate the spinner at this interval regardless of the redraw interval of
+// termdash.
+// Must be a positive duration. Defaults to DefaultInterval.
+func Interval(d time.Duration) Option {
+	return option(func(opts *options) {
+		opts.interval = d
+	})
+}
+
+// Label sets a text displayed after the spinner.
+func Label(text string) Option {
+	return option(func(opts *options) {
+		opts.label = text
+	})
+}
+
+// Stopped creates the spinner stopped, it displays no frames until Start is
+// called. By default the spinner is running after it is created.
+func Stopped() Option {
+	return option(func(opts *options) {
+		opts.stopped = true
+	})
+}
+
+// Redraw sets a function the spinner calls every Interval while it is
+// running, e.g. one that calls Redraw on a termdash.Controller. This animates
+// the spinner at its own frame interval independently of the RedrawInterval
+// of termdash, which also allows the spinner to animate under a Controller
+// that doesn't redraw periodically. The function is called once more after
+// the spinner is stopped, so the frames disappear from the screen.
+//
+// The function is called from a goroutine owned by the spinner without
+// holding the spinner's lock, so it can draw the spinner. If the function
+// returns an error, the spinner stops calling it until it is stopped and
+// started again.
+func Redraw(fn func() error) Option {
+	return option(func(opts *options) {
+		opts.redraw = fn
+	})
+}
+
+// Color sets the color of the frames.
+func Color(c cell.Color) Option {
+	return option(func(opts *options) {
+		opts.color = c
+	})
+}
+
+// LabelColor sets the color of the label.
+func LabelColor(c cell.Color) Option {
+	return option(func(opts *options) {
+		opts.labelColor = c
+	})
+}
+
+// framesWidth returns the width of the widest frame.
+func framesWidth(frames []string) int {
+	var w int
+	for _, f := range frames {
+		if fw := runewidth.StringWidth(f); fw > w {
+			w = fw
+		}
+	}
+	return w
+}
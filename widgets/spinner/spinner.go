@@ -0,0 +1,234 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package spinner implements a widget that indicates ongoing activity.
+package spinner
+
+import (
+	"errors"
+	"image"
+	"sync"
+	"time"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/draw"
+	"github.com/mum4k/termdash/internal/runewidth"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// Spinner displays an animation that loops through frames while an activity
+// is in progress, optionally followed by a label.
+//
+// The displayed frame is determined by the time elapsed since the spinner
+// started. Provide the Redraw option so the spinner triggers redraws at its
+// own frame interval, otherwise the animation only advances as often as
+// termdash redraws the screen.
+//
+// Implements widgetapi.Widget. This object is thread-safe.
+type Spinner struct {
+	// frames are the frames of the animation.
+	frames []string
+	// width is the width of the widest frame.
+	width int
+	// label is the text displayed after the frames.
+	label string
+
+	// running indicates whether the spinner is animating.
+	running bool
+	// started is the time the spinner was last started.
+	started time.Time
+	// stopCh is closed to stop the goroutine triggering redraws, nil if it
+	// isn't running.
+	stopCh chan struct{}
+
+	// mu protects the widget.
+	mu sync.Mutex
+
+	// opts are the provided options.
+	opts *options
+}
+
+// Vars to be replaced from tests.
+var (
+	// timeNow is a function that returns the current time.
+	timeNow = time.Now
+)
+
+// New returns a new Spinner, which is running unless the Stopped option is
+// provided.
+func New(opts ...Option) (*Spinner, error) {
+	opt := newOptions()
+	for _, o := range opts {
+		o.set(opt)
+	}
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+
+	frames := opt.frames
+	if frames == nil {
+		frames = frameSets[opt.frameSet]
+	}
+	s := &Spinner{
+		frames: frames,
+		width:  framesWidth(frames),
+		label:  opt.label,
+		opts:   opt,
+	}
+	if !opt.stopped {
+		s.start()
+	}
+	return s, nil
+}
+
+// Start starts the animation from the first frame. Has no effect if the
+// spinner is already running.
+func (s *Spinner) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.start()
+}
+
+// start is the implementation of Start.
+// Caller must hold s.mu.
+func (s *Spinner) start() {
+	if s.running {
+		return
+	}
+	s.running = true
+	s.started = timeNow()
+	if s.opts.redraw != nil {
+		s.stopCh = make(chan struct{})
+		go animate(s.opts.redraw, s.opts.interval, s.stopCh)
+	}
+}
+
+// animate calls the redraw function every interval until the stop channel is
+// closed or the function returns an error. Calls the function once more after
+// the stop channel is closed.
+func animate(redraw func() error, interval time.Duration, stopCh <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := redraw(); err != nil {
+				return
+			}
+
+		case <-stopCh:
+			redraw()
+			return
+		}
+	}
+}
+
+// Stop stops the animation. A stopped spinner displays only its label.
+func (s *Spinner) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running = false
+	if s.stopCh != nil {
+		close(s.stopCh)
+		s.stopCh = nil
+	}
+}
+
+// Running asserts whether the spinner is running.
+func (s *Spinner) Running() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running
+}
+
+// SetLabel sets the text displayed after the spinner.
+func (s *Spinner) SetLabel(text string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := validateLabel(text); err != nil {
+		return err
+	}
+	s.label = text
+	return nil
+}
+
+// frame returns the index of the frame to display.
+// Caller must hold s.mu.
+func (s *Spinner) frame() int {
+	elapsed := timeNow().Sub(s.started)
+	if elapsed < 0 {
+		return 0
+	}
+	return int(elapsed/s.opts.interval) % len(s.frames)
+}
+
+// Draw draws the current frame and the label onto the canvas.
+// Implements widgetapi.Widget.Draw.
+func (s *Spinner) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cvsAr := cvs.Area()
+	if cvsAr.Dx() < s.width {
+		return draw.ResizeNeeded(cvs)
+	}
+
+	if s.running {
+		if err := draw.Text(cvs, s.frames[s.frame()], image.Point{0, 0},
+			draw.TextCellOpts(cell.FgColor(s.opts.color)),
+		); err != nil {
+			return err
+		}
+	}
+
+	labelStart := image.Point{s.width + 1, 0}
+	if s.label == "" || labelStart.X >= cvsAr.Max.X {
+		return nil
+	}
+	return draw.Text(cvs, s.label, labelStart,
+		draw.TextCellOpts(cell.FgColor(s.opts.labelColor)),
+		draw.TextMaxX(cvsAr.Max.X),
+		draw.TextOverrunMode(draw.OverrunModeThreeDot),
+	)
+}
+
+// Keyboard input isn't supported on the Spinner widget.
+func (*Spinner) Keyboard(k *terminalapi.Keyboard) error {
+	return errors.New("the Spinner widget doesn't support keyboard events")
+}
+
+// Mouse input isn't supported on the Spinner widget.
+func (*Spinner) Mouse(m *terminalapi.Mouse) error {
+	return errors.New("the Spinner widget doesn't support mouse events")
+}
+
+// Options implements widgetapi.Widget.Options.
+func (s *Spinner) Options() widgetapi.Options {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	maxWidth := s.width
+	if s.label != "" {
+		maxWidth += 1 + runewidth.StringWidth(s.label)
+	}
+	return widgetapi.Options{
+		MinimumSize:  image.Point{s.width, 1},
+		MaximumSize:  image.Point{maxWidth, 1},
+		WantKeyboard: widgetapi.KeyScopeNone,
+		WantMouse:    widgetapi.MouseScopeNone,
+	}
+}
@@ -0,0 +1,432 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import (
+	"errors"
+	"image"
+	"testing"
+	"time"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/canvas/testcanvas"
+	"github.com/mum4k/termdash/internal/draw"
+	"github.com/mum4k/termdash/internal/draw/testdraw"
+	"github.com/mum4k/termdash/internal/faketerm"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// step is a single draw of the spinner.
+type step struct {
+	// elapsed is the time elapsed since the spinner was created.
+	elapsed time.Duration
+	// action is executed before the draw if not nil.
+	action func(*Spinner) error
+	// want is the expected content of the canvas.
+	want func(size image.Point) *faketerm.Terminal
+}
+
+// mustDraw returns a function that draws the frame and label as the spinner
+// with the default colors would.
+func mustDraw(frame, label string, labelX int) func(size image.Point) *faketerm.Terminal {
+	return func(size image.Point) *faketerm.Terminal {
+		ft := faketerm.MustNew(size)
+		cvs := testcanvas.MustNew(ft.Area())
+		if frame != "" {
+			testdraw.MustText(cvs, frame, image.Point{0, 0})
+		}
+		if label != "" {
+			testdraw.MustText(cvs, label, image.Point{labelX, 0})
+		}
+		testcanvas.MustApply(cvs, ft)
+		return ft
+	}
+}
+
+func TestSpinner(t *testing.T) {
+	tests := []struct {
+		desc       string
+		opts       []Option
+		canvas     image.Rectangle
+		steps      []step
+		wantNewErr bool
+	}{
+		{
+			desc: "New fails on unsupported frame set",
+			opts: []Option{
+				Frames(FrameSet(-1)),
+			},
+			wantNewErr: true,
+		},
+		{
+			desc: "New fails without custom frames",
+			opts: []Option{
+				CustomFrames(),
+			},
+			wantNewErr: true,
+		},
+		{
+			desc: "New fails on empty custom frame",
+			opts: []Option{
+				CustomFrames("a", ""),
+			},
+			wantNewErr: true,
+		},
+		{
+			desc: "New fails on custom frame with control characters",
+			opts: []Option{
+				CustomFrames("a\n"),
+			},
+			wantNewErr: true,
+		},
+		{
+			desc: "New fails on zero interval",
+			opts: []Option{
+				Interval(0),
+			},
+			wantNewErr: true,
+		},
+		{
+			desc: "New fails on invalid label",
+			opts: []Option{
+				Label("a\nb"),
+			},
+			wantNewErr: true,
+		},
+		{
+			desc:   "loops through the default frames at the default interval",
+			canvas: image.Rect(0, 0, 3, 1),
+			steps: []step{
+				{elapsed: 0, want: mustDraw("⠋", "", 0)},
+				{elapsed: 50 * time.Millisecond, want: mustDraw("⠋", "", 0)},
+				{elapsed: 100 * time.Millisecond, want: mustDraw("⠙", "", 0)},
+				{elapsed: 950 * time.Millisecond, want: mustDraw("⠏", "", 0)},
+				{elapsed: time.Second, want: mustDraw("⠋", "", 0)},
+			},
+		},
+		{
+			desc: "skips frames when drawn less often than the interval",
+			opts: []Option{
+				Frames(FrameSetLine),
+				Interval(10 * time.Millisecond),
+			},
+			canvas: image.Rect(0, 0, 3, 1),
+			steps: []step{
+				{elapsed: 0, want: mustDraw("-", "", 0)},
+				{elapsed: 30 * time.Millisecond, want: mustDraw("/", "", 0)},
+				{elapsed: 50 * time.Millisecond, want: mustDraw("\\", "", 0)},
+			},
+		},
+		{
+			desc: "draws the arc and circle frame sets",
+			opts: []Option{
+				Frames(FrameSetArc),
+			},
+			canvas: image.Rect(0, 0, 3, 1),
+			steps: []step{
+				{elapsed: 0, want: mustDraw("◜", "", 0)},
+				{elapsed: 500 * time.Millisecond, want: mustDraw("◟", "", 0)},
+			},
+		},
+		{
+			desc: "draws custom frames of different widths and the label",
+			opts: []Option{
+				CustomFrames("[=  ]", "[ =]"),
+				Label("loading"),
+			},
+			canvas: image.Rect(0, 0, 15, 1),
+			steps: []step{
+				{elapsed: 0, want: mustDraw("[=  ]", "loading", 6)},
+				{elapsed: 100 * time.Millisecond, want: mustDraw("[ =]", "loading", 6)},
+			},
+		},
+		{
+			desc: "draws frames and the label in custom colors",
+			opts: []Option{
+				Label("hi"),
+				Color(cell.ColorRed),
+				LabelColor(cell.ColorBlue),
+			},
+			canvas: image.Rect(0, 0, 5, 1),
+			steps: []step{
+				{
+					elapsed: 0,
+					want: func(size image.Point) *faketerm.Terminal {
+						ft := faketerm.MustNew(size)
+						cvs := testcanvas.MustNew(ft.Area())
+						testdraw.MustText(cvs, "⠋", image.Point{0, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorRed)))
+						testdraw.MustText(cvs, "hi", image.Point{2, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorBlue)))
+						testcanvas.MustApply(cvs, ft)
+						return ft
+					},
+				},
+			},
+		},
+		{
+			desc: "trims the label that doesn't fit",
+			opts: []Option{
+				Label("loading"),
+			},
+			canvas: image.Rect(0, 0, 5, 1),
+			steps: []step{
+				{elapsed: 0, want: mustDraw("⠋", "lo…", 2)},
+			},
+		},
+		{
+			desc: "requests resize when the frames don't fit",
+			opts: []Option{
+				CustomFrames("[==]"),
+			},
+			canvas: image.Rect(0, 0, 3, 1),
+			steps: []step{
+				{
+					elapsed: 0,
+					want: func(size image.Point) *faketerm.Terminal {
+						ft := faketerm.MustNew(size)
+						cvs := testcanvas.MustNew(ft.Area())
+						testdraw.MustResizeNeeded(cvs)
+						testcanvas.MustApply(cvs, ft)
+						return ft
+					},
+				},
+			},
+		},
+		{
+			desc: "stopped spinner displays only the label until started",
+			opts: []Option{
+				Stopped(),
+				Label("idle"),
+			},
+			canvas: image.Rect(0, 0, 8, 1),
+			steps: []step{
+				{elapsed: 0, want: mustDraw("", "idle", 2)},
+				{
+					elapsed: 250 * time.Millisecond,
+					action: func(s *Spinner) error {
+						s.Start()
+						return s.SetLabel("busy")
+					},
+					want: mustDraw("⠋", "busy", 2),
+				},
+				{elapsed: 350 * time.Millisecond, want: mustDraw("⠙", "busy", 2)},
+				{
+					elapsed: 450 * time.Millisecond,
+					action: func(s *Spinner) error {
+						s.Stop()
+						return nil
+					},
+					want: mustDraw("", "busy", 2),
+				},
+			},
+		},
+		{
+			desc:   "Start has no effect on a running spinner",
+			canvas: image.Rect(0, 0, 3, 1),
+			steps: []step{
+				{
+					elapsed: 100 * time.Millisecond,
+					action: func(s *Spinner) error {
+						s.Start()
+						return nil
+					},
+					want: mustDraw("⠙", "", 0),
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			start := time.Date(2019, 5, 1, 10, 0, 0, 0, time.UTC)
+			now := start
+			timeNow = func() time.Time {
+				return now
+			}
+			defer func() {
+				timeNow = time.Now
+			}()
+
+			s, err := New(tc.opts...)
+			if (err != nil) != tc.wantNewErr {
+				t.Errorf("New => unexpected error: %v, wantNewErr: %v", err, tc.wantNewErr)
+			}
+			if err != nil {
+				return
+			}
+
+			for i, st := range tc.steps {
+				now = start.Add(st.elapsed)
+				if st.action != nil {
+					if err := st.action(s); err != nil {
+						t.Fatalf("step %d: action => unexpected error: %v", i, err)
+					}
+				}
+
+				c, err := canvas.New(tc.canvas)
+				if err != nil {
+					t.Fatalf("canvas.New => unexpected error: %v", err)
+				}
+				if err := s.Draw(c, &widgetapi.Meta{}); err != nil {
+					t.Fatalf("step %d: Draw => unexpected error: %v", i, err)
+				}
+
+				got, err := faketerm.New(c.Size())
+				if err != nil {
+					t.Fatalf("faketerm.New => unexpected error: %v", err)
+				}
+				if err := c.Apply(got); err != nil {
+					t.Fatalf("Apply => unexpected error: %v", err)
+				}
+				if diff := faketerm.Diff(st.want(c.Size()), got); diff != "" {
+					t.Errorf("step %d: Draw => %v", i, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestRunning(t *testing.T) {
+	s, err := New(Stopped())
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if s.Running() {
+		t.Errorf("Running => true, want false after New with Stopped")
+	}
+	s.Start()
+	if !s.Running() {
+		t.Errorf("Running => false, want true after Start")
+	}
+	s.Stop()
+	if s.Running() {
+		t.Errorf("Running => true, want false after Stop")
+	}
+}
+
+func TestRedraw(t *testing.T) {
+	redrawCh := make(chan struct{})
+	s, err := New(
+		Interval(time.Millisecond),
+		Redraw(func() error {
+			redrawCh <- struct{}{}
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-redrawCh:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for redraw %d while the spinner is running", i)
+		}
+	}
+
+	s.Stop()
+	// The goroutine calls the function once more after the spinner stopped,
+	// possibly preceded by a tick that raced with Stop.
+	for i := 0; i < 2; i++ {
+		select {
+		case <-redrawCh:
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	select {
+	case <-redrawCh:
+		t.Errorf("Redraw function called after the spinner stopped")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestRedrawStopsOnError(t *testing.T) {
+	redrawCh := make(chan struct{}, 10)
+	s, err := New(
+		Interval(time.Millisecond),
+		Redraw(func() error {
+			redrawCh <- struct{}{}
+			return errors.New("redraw failed")
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	defer s.Stop()
+
+	select {
+	case <-redrawCh:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for the first redraw")
+	}
+	select {
+	case <-redrawCh:
+		t.Errorf("Redraw function called after it returned an error")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestSetLabel(t *testing.T) {
+	s, err := New(Label("a"))
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	if err := s.SetLabel("a\tb\n"); err == nil {
+		t.Errorf("SetLabel => got nil error, want an error on a label with control characters")
+	}
+}
+
+func TestOptions(t *testing.T) {
+	tests := []struct {
+		desc string
+		opts []Option
+		want widgetapi.Options
+	}{
+		{
+			desc: "only the frames",
+			want: widgetapi.Options{
+				MinimumSize: image.Point{1, 1},
+				MaximumSize: image.Point{1, 1},
+			},
+		},
+		{
+			desc: "widest custom frame and the label",
+			opts: []Option{
+				CustomFrames("[=  ]", "[ =]"),
+				Label("世界"),
+			},
+			want: widgetapi.Options{
+				MinimumSize: image.Point{5, 1},
+				MaximumSize: image.Point{10, 1},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			s, err := New(tc.opts...)
+			if err != nil {
+				t.Fatalf("New => unexpected error: %v", err)
+			}
+
+			got := s.Options()
+			if diff := pretty.Compare(tc.want, got); diff != "" {
+				t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
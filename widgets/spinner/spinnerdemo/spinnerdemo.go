@@ -0,0 +1,185 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary spinnerdemo shows the functionality of the spinner widget.
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/termbox"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgets/spinner"
+)
+
+// fetch periodically simulates a long running fetch of data, running the
+// spinner while the fetch is in progress.
+func fetch(ctx context.Context, s *spinner.Spinner) {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+	for i := 1; ; i++ {
+		select {
+		case <-ticker.C:
+			s.Start()
+			if err := s.SetLabel(fmt.Sprintf("Fetching batch %d...", i)); err != nil {
+				panic(err)
+			}
+			select {
+			case <-time.After(3 * time.Second):
+			case <-ctx.Done():
+				return
+			}
+			s.Stop()
+			if err := s.SetLabel(fmt.Sprintf("Fetched batch %d", i)); err != nil {
+				panic(err)
+			}
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// redrawer triggers redraws of the terminal, once the controller is created
+// and until it is closed. Spinners are created before the controller.
+type redrawer struct {
+	mu   sync.Mutex
+	ctrl *termdash.Controller
+}
+
+// setController sets the controller that redraws the terminal, nil stops the
+// redraws.
+func (r *redrawer) setController(ctrl *termdash.Controller) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ctrl = ctrl
+}
+
+// redraw redraws the terminal if a controller is set.
+func (r *redrawer) redraw() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.ctrl == nil {
+		return nil
+	}
+	return r.ctrl.Redraw()
+}
+
+func main() {
+	t, err := termbox.New()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &redrawer{}
+
+	braille, err := spinner.New(
+		spinner.Redraw(r.redraw),
+		spinner.Label("Braille dots"),
+	)
+	if err != nil {
+		panic(err)
+	}
+	line, err := spinner.New(
+		spinner.Redraw(r.redraw),
+		spinner.Frames(spinner.FrameSetLine),
+		spinner.Interval(200*time.Millisecond),
+		spinner.Label("Line"),
+	)
+	if err != nil {
+		panic(err)
+	}
+	arc, err := spinner.New(
+		spinner.Redraw(r.redraw),
+		spinner.Frames(spinner.FrameSetArc),
+		spinner.Color(cell.ColorCyan),
+		spinner.Label("Arc"),
+	)
+	if err != nil {
+		panic(err)
+	}
+	custom, err := spinner.New(
+		spinner.Redraw(r.redraw),
+		spinner.CustomFrames("[=   ]", "[ =  ]", "[  = ]", "[   =]", "[  = ]", "[ =  ]"),
+		spinner.Color(cell.ColorGreen),
+		spinner.Label("Custom frames"),
+	)
+	if err != nil {
+		panic(err)
+	}
+	fetcher, err := spinner.New(
+		spinner.Redraw(r.redraw),
+		spinner.Stopped(),
+		spinner.Color(cell.ColorYellow),
+		spinner.Label("Waiting for the first fetch"),
+	)
+	if err != nil {
+		panic(err)
+	}
+	go fetch(ctx, fetcher)
+
+	c, err := container.New(
+		t,
+		container.Border(linestyle.Light),
+		container.BorderTitle("PRESS Q TO QUIT"),
+		container.SplitHorizontal(
+			container.Top(
+				container.SplitHorizontal(
+					container.Top(container.PlaceWidget(braille)),
+					container.Bottom(container.PlaceWidget(line)),
+				),
+			),
+			container.Bottom(
+				container.SplitHorizontal(
+					container.Top(
+						container.SplitHorizontal(
+							container.Top(container.PlaceWidget(arc)),
+							container.Bottom(container.PlaceWidget(custom)),
+						),
+					),
+					container.Bottom(container.PlaceWidget(fetcher)),
+					container.SplitPercent(66),
+				),
+			),
+			container.SplitPercent(40),
+		),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	quitter := func(k *terminalapi.Keyboard) {
+		if k.Key == 'q' || k.Key == 'Q' {
+			cancel()
+		}
+	}
+
+	ctrl, err := termdash.NewController(t, c, termdash.KeyboardSubscriber(quitter))
+	if err != nil {
+		panic(err)
+	}
+	r.setController(ctrl)
+	<-ctx.Done()
+	r.setController(nil)
+	ctrl.Close()
+}
@@ -17,6 +17,7 @@ package text
 import (
 	"fmt"
 
+	"github.com/mum4k/termdash/cell"
 	"github.com/mum4k/termdash/internal/wrap"
 	"github.com/mum4k/termdash/keyboard"
 	"github.com/mum4k/termdash/mouse"
@@ -33,7 +34,10 @@ type Option interface {
 // options stores the provided options.
 type options struct {
 	wrapMode         wrap.Mode
+	tabWidth         int
 	rollContent      bool
+	followContent    bool
+	keyEnd           keyboard.Key
 	disableScrolling bool
 	mouseUpButton    mouse.Button
 	mouseDownButton  mouse.Button
@@ -41,17 +45,45 @@ type options struct {
 	keyDown          keyboard.Key
 	keyPgUp          keyboard.Key
 	keyPgDown        keyboard.Key
+
+	scrollHorizontally bool
+	keyLeft            keyboard.Key
+	keyRight           keyboard.Key
+	mouseLeftButton    mouse.Button
+	mouseRightButton   mouse.Button
+
+	searchKeys            bool
+	keySearch             keyboard.Key
+	keySearchNext         keyboard.Key
+	keySearchPrev         keyboard.Key
+	searchCellOpts        []cell.Option
+	searchCurrentCellOpts []cell.Option
 }
 
 // newOptions returns a new options instance.
 func newOptions(opts ...Option) *options {
 	opt := &options{
+		tabWidth:        DefaultTabWidth,
 		mouseUpButton:   DefaultScrollMouseButtonUp,
 		mouseDownButton: DefaultScrollMouseButtonDown,
 		keyUp:           DefaultScrollKeyUp,
 		keyDown:         DefaultScrollKeyDown,
 		keyPgUp:         DefaultScrollKeyPageUp,
 		keyPgDown:       DefaultScrollKeyPageDown,
+		keyEnd:          DefaultFollowKeyEnd,
+		keyLeft:         DefaultScrollKeyLeft,
+		keyRight:        DefaultScrollKeyRight,
+		keySearch:       DefaultSearchKey,
+		keySearchNext:   DefaultSearchKeyNext,
+		keySearchPrev:   DefaultSearchKeyPrev,
+		searchCellOpts: []cell.Option{
+			cell.FgColor(cell.ColorBlack),
+			cell.BgColor(cell.ColorYellow),
+		},
+		searchCurrentCellOpts: []cell.Option{
+			cell.FgColor(cell.ColorBlack),
+			cell.BgColor(cell.ColorCyan),
+		},
 	}
 	for _, o := range opts {
 		o.set(opt)
@@ -61,6 +93,9 @@ func newOptions(opts ...Option) *options {
 
 // validate validates the provided options.
 func (o *options) validate() error {
+	if o.tabWidth <= 0 {
+		return fmt.Errorf("invalid TabWidth(%d), must be a positive number", o.tabWidth)
+	}
 	keys := map[keyboard.Key]bool{
 		o.keyUp:     true,
 		o.keyDown:   true,
@@ -70,6 +105,42 @@ func (o *options) validate() error {
 	if len(keys) != 4 {
 		return fmt.Errorf("invalid ScrollKeys(up:%v, down:%v, pageUp:%v, pageDown:%v), the keys must be unique", o.keyUp, o.keyDown, o.keyPgUp, o.keyPgDown)
 	}
+	if o.scrollHorizontally {
+		if o.wrapMode != wrap.Never {
+			return fmt.Errorf("ScrollHorizontally cannot be combined with line wrapping, found wrap mode %v", o.wrapMode)
+		}
+		keys[o.keyLeft] = true
+		keys[o.keyRight] = true
+		if len(keys) != 6 {
+			return fmt.Errorf("invalid ScrollKeysHorizontal(left:%v, right:%v), the keys must be unique and must not be used as ScrollKeys", o.keyLeft, o.keyRight)
+		}
+		if o.mouseLeftButton != 0 && o.mouseLeftButton == o.mouseRightButton {
+			return fmt.Errorf("invalid ScrollMouseButtonsHorizontal(left:%v, right:%v), the buttons must be unique", o.mouseLeftButton, o.mouseRightButton)
+		}
+		buttons := map[mouse.Button]bool{
+			o.mouseUpButton:   true,
+			o.mouseDownButton: true,
+		}
+		if buttons[o.mouseLeftButton] || buttons[o.mouseRightButton] {
+			return fmt.Errorf("invalid ScrollMouseButtonsHorizontal(left:%v, right:%v), the buttons must not be used as ScrollMouseButtons", o.mouseLeftButton, o.mouseRightButton)
+		}
+	}
+	if o.followContent {
+		want := len(keys) + 1
+		keys[o.keyEnd] = true
+		if len(keys) != want {
+			return fmt.Errorf("invalid FollowKeyEnd(%v), the key must not be used to scroll", o.keyEnd)
+		}
+	}
+	if o.searchKeys {
+		want := len(keys) + 3
+		keys[o.keySearch] = true
+		keys[o.keySearchNext] = true
+		keys[o.keySearchPrev] = true
+		if len(keys) != want {
+			return fmt.Errorf("invalid SearchKeys(search:%v, next:%v, prev:%v), the keys must be unique and must not be used to scroll", o.keySearch, o.keySearchNext, o.keySearchPrev)
+		}
+	}
 	if o.mouseUpButton == o.mouseDownButton {
 		return fmt.Errorf("invalid ScrollMouseButtons(up:%v, down:%v), the buttons must be unique", o.mouseUpButton, o.mouseDownButton)
 	}
@@ -102,6 +173,19 @@ func WrapAtRunes() Option {
 	})
 }
 
+// DefaultTabWidth is the default distance between tab stops in cells.
+const DefaultTabWidth = wrap.DefaultTabWidth
+
+// TabWidth sets the distance between tab stops in cells. Any tab characters
+// in the text are expanded into spaces up to the next tab stop. The tab stops
+// are relative to the start of each displayed line, i.e. wrapped lines have
+// their own tab stops. Must be a positive number.
+func TabWidth(cells int) Option {
+	return option(func(opts *options) {
+		opts.tabWidth = cells
+	})
+}
+
 // RollContent configures the text widget so that it rolls the text content up
 // if more text than the size of the container is added. If not provided, the
 // content is trimmed instead.
@@ -111,6 +195,46 @@ func RollContent() Option {
 	})
 }
 
+// FollowContent configures the text widget so that it follows the text
+// content like RollContent, i.e. rolls it up as more text is added, but only
+// while the last line is visible. When the user scrolls up, following is
+// paused and a marker with the number of new lines written since then is
+// displayed on the last line of the widget, e.g. "3 new lines ↓". Following
+// resumes when the user scrolls back to the last line, presses the end key
+// or clicks on the marker.
+// Implies RollContent.
+func FollowContent() Option {
+	return option(func(opts *options) {
+		opts.rollContent = true
+		opts.followContent = true
+	})
+}
+
+// DefaultFollowKeyEnd is the default key that resumes following of the
+// content.
+const DefaultFollowKeyEnd = keyboard.KeyEnd
+
+// FollowKeyEnd configures the keyboard key that scrolls to the last line and
+// resumes following of the content when FollowContent is provided.
+// The provided key must not be used as any of the scrolling keys.
+func FollowKeyEnd(key keyboard.Key) Option {
+	return option(func(opts *options) {
+		opts.keyEnd = key
+	})
+}
+
+// ScrollHorizontally configures the text widget so that lines longer than the
+// width of the widget are trimmed at the edge of the canvas and the content
+// can be scrolled horizontally using the keyboard or mouse. A continuation
+// marker ('…') is displayed at each edge of a line that has more content
+// beyond it. Vertical scrolling isn't affected.
+// Cannot be combined with WrapAtWords or WrapAtRunes.
+func ScrollHorizontally() Option {
+	return option(func(opts *options) {
+		opts.scrollHorizontally = true
+	})
+}
+
 // DisableScrolling disables the scrolling of the content using keyboard and
 // mouse.
 func DisableScrolling() Option {
@@ -154,3 +278,82 @@ func ScrollKeys(up, down, pageUp, pageDown keyboard.Key) Option {
 		opts.keyPgDown = pageDown
 	})
 }
+
+// The default keys for horizontal content scrolling.
+const (
+	DefaultScrollKeyLeft  = keyboard.KeyArrowLeft
+	DefaultScrollKeyRight = keyboard.KeyArrowRight
+)
+
+// ScrollKeysHorizontal configures the keyboard keys that scroll the content
+// horizontally when ScrollHorizontally is provided.
+// The provided keys must be unique and must not be used as ScrollKeys.
+func ScrollKeysHorizontal(left, right keyboard.Key) Option {
+	return option(func(opts *options) {
+		opts.keyLeft = left
+		opts.keyRight = right
+	})
+}
+
+// ScrollMouseButtonsHorizontal configures the mouse buttons that scroll the
+// content horizontally when ScrollHorizontally is provided.
+// The provided buttons must be unique and must not be used as
+// ScrollMouseButtons.
+//
+// Horizontal scrolling with the mouse is disabled by default, since the
+// terminal doesn't report modifier keys like shift on mouse events and so the
+// mouse wheel can't be shared with vertical scrolling.
+func ScrollMouseButtonsHorizontal(left, right mouse.Button) Option {
+	return option(func(opts *options) {
+		opts.mouseLeftButton = left
+		opts.mouseRightButton = right
+	})
+}
+
+// The default keys for searching the content.
+const (
+	DefaultSearchKey     = keyboard.Key('/')
+	DefaultSearchKeyNext = keyboard.Key('n')
+	DefaultSearchKeyPrev = keyboard.Key('N')
+)
+
+// EnableSearchKeys enables searching of the content using the keyboard.
+// When the search key is pressed, the widget displays a prompt on its last
+// line where the user types the searched substring. Pressing Enter starts the
+// search, Esc cancels it. The next and prev keys then select the next or the
+// previous match.
+// Uses the default search keys unless SearchKeys is also provided.
+func EnableSearchKeys() Option {
+	return option(func(opts *options) {
+		opts.searchKeys = true
+	})
+}
+
+// SearchKeys configures the keyboard keys that search the content and
+// enables searching as described for EnableSearchKeys.
+// The provided keys must be unique and must not be used as ScrollKeys.
+func SearchKeys(search, next, prev keyboard.Key) Option {
+	return option(func(opts *options) {
+		opts.searchKeys = true
+		opts.keySearch = search
+		opts.keySearchNext = next
+		opts.keySearchPrev = prev
+	})
+}
+
+// SearchCellOpts sets the cell options used to highlight all the matches of
+// the active search. Defaults to black text on yellow background.
+func SearchCellOpts(cellOpts ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.searchCellOpts = cellOpts
+	})
+}
+
+// SearchCurrentCellOpts sets the cell options used to highlight the
+// currently selected match of the active search. Defaults to black text on
+// cyan background.
+func SearchCurrentCellOpts(cellOpts ...cell.Option) Option {
+	return option(func(opts *options) {
+		opts.searchCurrentCellOpts = cellOpts
+	})
+}
@@ -16,8 +16,6 @@ package text
 
 // scroll.go contains code that tracks the current scrolling position.
 
-import "math"
-
 // scrollTracker tracks the current scrolling position for the Text widget.
 //
 // The text widget displays the contained text buffer as lines of text that fit
@@ -47,11 +45,19 @@ type scrollTracker struct {
 	// means down by two pages.
 	scrollPage int
 
+	// toLast stores a user request to scroll to the last line. Requests in
+	// scroll and scrollPage are applied relative to the last line if set.
+	toLast bool
+
 	// first tracks the first line that will be printed.
 	first int
 
 	// state is the state of the scrolling FSM.
 	state rollState
+
+	// paused indicates that the content rolling is paused, because the user
+	// scrolled up.
+	paused bool
 }
 
 // newScrollTracker returns a new scroll tracker.
@@ -82,12 +88,46 @@ func (st *scrollTracker) downOnePage() {
 	st.scrollPage++
 }
 
+// toEnd processes a user request to scroll to the last line.
+// Any other outstanding scroll requests are discarded.
+func (st *scrollTracker) toEnd() {
+	st.toLast = true
+	st.scroll = 0
+	st.scrollPage = 0
+}
+
+// showLine processes a request to scroll so that the specified line is
+// visible on a canvas of the provided height. Does nothing if the line is
+// already visible, otherwise scrolls so that the line is in the middle of the
+// canvas. Any other outstanding scroll requests are discarded.
+func (st *scrollTracker) showLine(line, height int) {
+	first, last := st.first, st.first+height-1
+	if height >= minLinesForMarkers {
+		// The first and the last line might be covered by the scroll markers.
+		if first > 0 {
+			first++
+		}
+		last--
+	}
+	if line >= first && line <= last {
+		return
+	}
+	st.scroll = line - height/2 - st.first
+	st.scrollPage = 0
+	st.toLast = false
+}
+
 // doScroll processes any outstanding scroll requests and calculates the
 // resulting first line.
 func (st *scrollTracker) doScroll(lines, height int) int {
-	first := st.first + st.scroll + st.scrollPage*height
+	first := st.first
+	if st.toLast {
+		first = normalizeScroll(lines, lines, height)
+	}
+	first += st.scroll + st.scrollPage*height
 	st.scroll = 0
 	st.scrollPage = 0
+	st.toLast = false
 	return normalizeScroll(first, lines, height)
 }
 
@@ -115,8 +155,8 @@ func rollingDisabled(st *scrollTracker, lines, height int) rollState {
 func rollToEnd(st *scrollTracker, lines, height int) rollState {
 	// If the user didn't scroll, just roll the content so that the last line
 	// is visible.
-	if st.scroll == 0 && st.scrollPage == 0 {
-		st.first = normalizeScroll(math.MaxUint32, lines, height)
+	if !st.toLast && st.scroll == 0 && st.scrollPage == 0 {
+		st.first = normalizeScroll(lines, lines, height)
 		return rollToEnd
 	}
 
@@ -124,6 +164,7 @@ func rollToEnd(st *scrollTracker, lines, height int) rollState {
 	if lastLineVisible(st.first, lines, height) {
 		return rollToEnd
 	}
+	st.paused = true
 	return rollingPaused
 }
 
@@ -132,6 +173,7 @@ func rollToEnd(st *scrollTracker, lines, height int) rollState {
 func rollingPaused(st *scrollTracker, lines, height int) rollState {
 	st.first = st.doScroll(lines, height)
 	if lastLineVisible(st.first, lines, height) {
+		st.paused = false
 		return rollToEnd
 	}
 	return rollingPaused
@@ -163,3 +205,58 @@ func normalizeScroll(first, lines, height int) int {
 	}
 	return first
 }
+
+// hScrollTracker tracks the horizontal scrolling position for the Text widget
+// when lines aren't wrapped.
+//
+// Similarly to scrollTracker, this object keeps track of all the scrolling
+// events that happened since the last redraw and consumes them when
+// calculating which is the first drawn cell on each line.
+//
+// This is not thread safe.
+type hScrollTracker struct {
+	// scroll stores user requests to scroll left (negative) or right
+	// (positive). E.g. -1 means left by one cell and 2 means right by two
+	// cells.
+	scroll int
+
+	// first tracks the first cell on each line that will be printed.
+	first int
+}
+
+// leftOneCell processes a user request to scroll left by one cell.
+func (hst *hScrollTracker) leftOneCell() {
+	hst.scroll--
+}
+
+// rightOneCell processes a user request to scroll right by one cell.
+func (hst *hScrollTracker) rightOneCell() {
+	hst.scroll++
+}
+
+// showCell processes a request to scroll so that the specified cell is
+// visible on a canvas of the provided width. Does nothing if the cell is
+// already visible, otherwise scrolls so that the cell is in the middle of the
+// canvas. Any other outstanding scroll requests are discarded.
+func (hst *hScrollTracker) showCell(cell, width int) {
+	first, last := hst.first, hst.first+width-1
+	// The first and the last cell might be covered by the continuation
+	// markers.
+	if first > 0 {
+		first++
+	}
+	last--
+	if cell >= first && cell <= last {
+		return
+	}
+	hst.scroll = cell - width/2 - hst.first
+}
+
+// firstCell returns the index of the first cell that should be drawn on each
+// line when the longest line has the specified width and the canvas has the
+// specified width.
+func (hst *hScrollTracker) firstCell(lineWidth, width int) int {
+	hst.first = normalizeScroll(hst.first+hst.scroll, lineWidth, width)
+	hst.scroll = 0
+	return hst.first
+}
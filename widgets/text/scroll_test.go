@@ -145,6 +145,26 @@ func TestScrollTrackerNoContentRolling(t *testing.T) {
 			},
 			want: 3,
 		},
+		{
+			desc:   "user can scroll to the last line",
+			lines:  8,
+			height: 2,
+			events: func(st *scrollTracker) {
+				st.downOnePage()
+				st.toEnd()
+			},
+			want: 6,
+		},
+		{
+			desc:   "scrolling after scrolling to the last line is relative to it",
+			lines:  8,
+			height: 2,
+			events: func(st *scrollTracker) {
+				st.toEnd()
+				st.upOneLine()
+			},
+			want: 5,
+		},
 	}
 
 	for _, tc := range tests {
@@ -165,11 +185,12 @@ func TestScrollTrackerContentRolling(t *testing.T) {
 	st := newScrollTracker(&options{rollContent: true})
 	// All of these test cases act on the same instance of the scroll tracker.
 	tests := []struct {
-		desc   string
-		lines  int
-		height int
-		events func()
-		want   int
+		desc       string
+		lines      int
+		height     int
+		events     func()
+		want       int
+		wantPaused bool
 	}{
 		{
 			desc:   "all content fits, draws from the first line",
@@ -202,13 +223,15 @@ func TestScrollTrackerContentRolling(t *testing.T) {
 			events: func() {
 				st.upOneLine()
 			},
-			want: 1,
+			want:       1,
+			wantPaused: true,
 		},
 		{
-			desc:   "keeps scrolled to position when new content arrives",
-			lines:  5,
-			height: 2,
-			want:   1,
+			desc:       "keeps scrolled to position when new content arrives",
+			lines:      5,
+			height:     2,
+			want:       1,
+			wantPaused: true,
 		},
 		{
 			desc:   "scrolling down to the last line displays the latest line",
@@ -234,13 +257,15 @@ func TestScrollTrackerContentRolling(t *testing.T) {
 			events: func() {
 				st.upOneLine()
 			},
-			want: 3,
+			want:       3,
+			wantPaused: true,
 		},
 		{
-			desc:   "keeps scrolled to position when new content arrives again",
-			lines:  7,
-			height: 2,
-			want:   3,
+			desc:       "keeps scrolled to position when new content arrives again",
+			lines:      7,
+			height:     2,
+			want:       3,
+			wantPaused: true,
 		},
 		{
 			desc:   "resize so that the last line becomes visible",
@@ -254,6 +279,26 @@ func TestScrollTrackerContentRolling(t *testing.T) {
 			height: 7,
 			want:   1,
 		},
+		{
+			desc:   "scroll up pauses the rolling",
+			lines:  8,
+			height: 7,
+			events: func() {
+				st.upOneLine()
+			},
+			want:       0,
+			wantPaused: true,
+		},
+		{
+			desc:   "scrolling to the end resumes the rolling",
+			lines:  9,
+			height: 7,
+			events: func() {
+				st.upOnePage()
+				st.toEnd()
+			},
+			want: 2,
+		},
 	}
 
 	for _, tc := range tests {
@@ -265,6 +310,9 @@ func TestScrollTrackerContentRolling(t *testing.T) {
 			if got != tc.want {
 				t.Errorf("firstLine => got %d, want %d", got, tc.want)
 			}
+			if st.paused != tc.wantPaused {
+				t.Errorf("paused => got %v, want %v", st.paused, tc.wantPaused)
+			}
 		})
 	}
 }
@@ -330,3 +378,91 @@ func TestNormalizeScroll(t *testing.T) {
 		})
 	}
 }
+
+func TestHScrollTracker(t *testing.T) {
+	tests := []struct {
+		desc      string
+		lineWidth int
+		width     int
+		events    func(*hScrollTracker)
+		want      int
+	}{
+		{
+			desc:      "starts from the first cell",
+			lineWidth: 10,
+			width:     5,
+			want:      0,
+		},
+		{
+			desc:      "user can scroll right by a cell",
+			lineWidth: 10,
+			width:     5,
+			events: func(hst *hScrollTracker) {
+				hst.rightOneCell()
+			},
+			want: 1,
+		},
+		{
+			desc:      "scroll right capped so that the end of the longest line is visible",
+			lineWidth: 10,
+			width:     5,
+			events: func(hst *hScrollTracker) {
+				for i := 0; i < 10; i++ {
+					hst.rightOneCell()
+				}
+			},
+			want: 5,
+		},
+		{
+			desc:      "scroll left capped at the first cell",
+			lineWidth: 10,
+			width:     5,
+			events: func(hst *hScrollTracker) {
+				hst.rightOneCell()
+				hst.leftOneCell()
+				hst.leftOneCell()
+			},
+			want: 0,
+		},
+		{
+			desc:      "no scrolling when the lines fit",
+			lineWidth: 5,
+			width:     5,
+			events: func(hst *hScrollTracker) {
+				hst.rightOneCell()
+			},
+			want: 0,
+		},
+		{
+			desc:      "shows a cell that is outside of the canvas",
+			lineWidth: 20,
+			width:     5,
+			events: func(hst *hScrollTracker) {
+				hst.showCell(12, 5)
+			},
+			want: 10,
+		},
+		{
+			desc:      "doesn't scroll to a cell that is already visible",
+			lineWidth: 20,
+			width:     5,
+			events: func(hst *hScrollTracker) {
+				hst.showCell(2, 5)
+			},
+			want: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			hst := &hScrollTracker{}
+			if tc.events != nil {
+				tc.events(hst)
+			}
+			got := hst.firstCell(tc.lineWidth, tc.width)
+			if got != tc.want {
+				t.Errorf("firstCell => got %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
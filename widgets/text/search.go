@@ -0,0 +1,174 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+// search.go contains code that finds and tracks matches of a search within the
+// text content.
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mum4k/termdash/internal/attrrange"
+	"github.com/mum4k/termdash/internal/canvas/buffer"
+)
+
+// matcher finds ranges of matching runes in a string.
+// Returns pairs of byte offsets like regexp.Regexp.FindAllStringIndex.
+type matcher func(s string) [][]int
+
+// substrMatcher returns a matcher that finds all non-overlapping occurrences
+// of the substring.
+func substrMatcher(substr string) matcher {
+	return func(s string) [][]int {
+		var res [][]int
+		for offset := 0; offset < len(s); {
+			idx := strings.Index(s[offset:], substr)
+			if idx < 0 {
+				break
+			}
+			low := offset + idx
+			high := low + len(substr)
+			res = append(res, []int{low, high})
+			offset = high
+		}
+		return res
+	}
+}
+
+// regexpMatcher returns a matcher that finds all matches of the regular
+// expression.
+func regexpMatcher(re *regexp.Regexp) matcher {
+	return func(s string) [][]int {
+		return re.FindAllStringIndex(s, -1)
+	}
+}
+
+// match is a single match of the search within the content.
+type match struct {
+	// low is the index of the first content cell that is part of the match.
+	low int
+	// high is the index of the content cell just after the match.
+	high int
+}
+
+// searchTracker tracks the matches of the active search.
+// This object is not thread-safe.
+type searchTracker struct {
+	// find finds the matches in the content.
+	find matcher
+
+	// matches are all the matches in the order in which they appear in the
+	// content.
+	matches []*match
+
+	// current is the index of the currently selected match.
+	current int
+
+	// ranges map the content cell indexes to the index of the match they
+	// belong to.
+	ranges *attrrange.Tracker
+
+	// jump indicates that the view should scroll to the current match on the
+	// next call to Draw.
+	jump bool
+}
+
+// newSearchTracker returns a new tracker that uses the provided matcher.
+func newSearchTracker(find matcher) *searchTracker {
+	return &searchTracker{
+		find:    find,
+		ranges:  attrrange.NewTracker(),
+		current: -1,
+	}
+}
+
+// update finds all the matches in the provided content.
+// Preserves the currently selected match if it still exists.
+func (st *searchTracker) update(content []*buffer.Cell) error {
+	var b strings.Builder
+	// runeIdx maps byte offsets in the string to the indexes of content cells.
+	runeIdx := make([]int, 0, len(content))
+	for i, c := range content {
+		for j := 0; j < utf8.RuneLen(c.Rune); j++ {
+			runeIdx = append(runeIdx, i)
+		}
+		b.WriteRune(c.Rune)
+	}
+	// Allows mapping of the high offset of a match that ends on the last byte.
+	runeIdx = append(runeIdx, len(content))
+
+	st.matches = nil
+	st.ranges = attrrange.NewTracker()
+	for _, found := range st.find(b.String()) {
+		low, high := runeIdx[found[0]], runeIdx[found[1]]
+		if low == high {
+			continue // Skip empty matches, there is nothing to highlight.
+		}
+		if err := st.ranges.Add(low, high, len(st.matches)); err != nil {
+			return err
+		}
+		st.matches = append(st.matches, &match{low: low, high: high})
+	}
+
+	switch {
+	case len(st.matches) == 0:
+		st.current = -1
+	case st.current < 0:
+		st.current = 0
+		st.jump = true
+	case st.current >= len(st.matches):
+		st.current = len(st.matches) - 1
+	}
+	return nil
+}
+
+// next selects the next match, wrapping around after the last one.
+func (st *searchTracker) next() {
+	if len(st.matches) == 0 {
+		return
+	}
+	st.current = (st.current + 1) % len(st.matches)
+	st.jump = true
+}
+
+// prev selects the previous match, wrapping around before the first one.
+func (st *searchTracker) prev() {
+	if len(st.matches) == 0 {
+		return
+	}
+	st.current = (st.current - 1 + len(st.matches)) % len(st.matches)
+	st.jump = true
+}
+
+// currentMatch returns the currently selected match or nil if there are no
+// matches.
+func (st *searchTracker) currentMatch() *match {
+	if st.current < 0 {
+		return nil
+	}
+	return st.matches[st.current]
+}
+
+// matchAt returns the index of the match that contains the content cell at
+// the specified index. Returns false if the cell isn't part of any match.
+func (st *searchTracker) matchAt(cellIdx int) (int, bool) {
+	ar, err := st.ranges.ForPosition(cellIdx)
+	if err != nil {
+		return 0, false
+	}
+	return ar.AttrIdx, true
+}
@@ -0,0 +1,166 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/internal/canvas/buffer"
+)
+
+func TestSearchTracker(t *testing.T) {
+	tests := []struct {
+		desc        string
+		find        matcher
+		content     string
+		actions     func(*searchTracker)
+		wantMatches []*match
+		wantCurrent int
+	}{
+		{
+			desc:        "no matches",
+			find:        substrMatcher("xyz"),
+			content:     "hello world",
+			wantCurrent: -1,
+		},
+		{
+			desc:    "single substring match",
+			find:    substrMatcher("world"),
+			content: "hello world",
+			wantMatches: []*match{
+				{low: 6, high: 11},
+			},
+			wantCurrent: 0,
+		},
+		{
+			desc:    "multiple non-overlapping substring matches",
+			find:    substrMatcher("aa"),
+			content: "aaaaa",
+			wantMatches: []*match{
+				{low: 0, high: 2},
+				{low: 2, high: 4},
+			},
+			wantCurrent: 0,
+		},
+		{
+			desc:    "matches are indexes of runes not bytes",
+			find:    substrMatcher("世界"),
+			content: "你好世界 世界",
+			wantMatches: []*match{
+				{low: 2, high: 4},
+				{low: 5, high: 7},
+			},
+			wantCurrent: 0,
+		},
+		{
+			desc:    "regexp matches",
+			find:    regexpMatcher(regexp.MustCompile(`[0-9]+`)),
+			content: "a1 b22 c333",
+			wantMatches: []*match{
+				{low: 1, high: 2},
+				{low: 4, high: 6},
+				{low: 8, high: 11},
+			},
+			wantCurrent: 0,
+		},
+		{
+			desc:        "skips empty regexp matches",
+			find:        regexpMatcher(regexp.MustCompile(`x*`)),
+			content:     "abc",
+			wantCurrent: -1,
+		},
+		{
+			desc:    "next selects the next match",
+			find:    substrMatcher("a"),
+			content: "aaa",
+			actions: func(st *searchTracker) {
+				st.next()
+			},
+			wantMatches: []*match{
+				{low: 0, high: 1},
+				{low: 1, high: 2},
+				{low: 2, high: 3},
+			},
+			wantCurrent: 1,
+		},
+		{
+			desc:    "next wraps around after the last match",
+			find:    substrMatcher("a"),
+			content: "aa",
+			actions: func(st *searchTracker) {
+				st.next()
+				st.next()
+			},
+			wantMatches: []*match{
+				{low: 0, high: 1},
+				{low: 1, high: 2},
+			},
+			wantCurrent: 0,
+		},
+		{
+			desc:    "prev wraps around before the first match",
+			find:    substrMatcher("a"),
+			content: "aa",
+			actions: func(st *searchTracker) {
+				st.prev()
+			},
+			wantMatches: []*match{
+				{low: 0, high: 1},
+				{low: 1, high: 2},
+			},
+			wantCurrent: 1,
+		},
+		{
+			desc:    "next and prev do nothing without matches",
+			find:    substrMatcher("b"),
+			content: "aa",
+			actions: func(st *searchTracker) {
+				st.prev()
+				st.next()
+			},
+			wantCurrent: -1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			st := newSearchTracker(tc.find)
+			if err := st.update(buffer.NewCells(tc.content)); err != nil {
+				t.Fatalf("update => unexpected error: %v", err)
+			}
+			if tc.actions != nil {
+				tc.actions(st)
+			}
+
+			if diff := pretty.Compare(tc.wantMatches, st.matches); diff != "" {
+				t.Errorf("update => unexpected matches, diff (-want, +got):\n%s", diff)
+			}
+			if st.current != tc.wantCurrent {
+				t.Errorf("current => %d, want %d", st.current, tc.wantCurrent)
+			}
+
+			for i, m := range st.matches {
+				for pos := m.low; pos < m.high; pos++ {
+					got, ok := st.matchAt(pos)
+					if !ok || got != i {
+						t.Errorf("matchAt(%d) => %d, %v, want %d, true", pos, got, ok, i)
+					}
+				}
+			}
+		})
+	}
+}
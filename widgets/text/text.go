@@ -16,13 +16,24 @@
 package text
 
 import (
+	"errors"
 	"fmt"
 	"image"
+	"regexp"
+	"strings"
 	"sync"
+	"unicode"
 
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/attrrange"
 	"github.com/mum4k/termdash/internal/canvas"
 	"github.com/mum4k/termdash/internal/canvas/buffer"
+	"github.com/mum4k/termdash/internal/draw"
+	"github.com/mum4k/termdash/internal/markup"
+	"github.com/mum4k/termdash/internal/runewidth"
 	"github.com/mum4k/termdash/internal/wrap"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
 	"github.com/mum4k/termdash/terminal/terminalapi"
 	"github.com/mum4k/termdash/widgetapi"
 )
@@ -36,6 +47,12 @@ import (
 // By default the widget supports scrolling of content with either the keyboard
 // or mouse. See the options for the default keys and mouse buttons.
 //
+// The content can be searched, all the matches are highlighted and the view
+// scrolls to the selected match. See the Search methods and the SearchKeys
+// option.
+//
+// Parts of the text can be made clickable, see the WriteClickable option.
+//
 // Implements widgetapi.Widget. This object is thread-safe.
 type Text struct {
 	// content is the text content that will be displayed in the widget as
@@ -46,6 +63,17 @@ type Text struct {
 
 	// scroll tracks scrolling the position.
 	scroll *scrollTracker
+	// hScroll tracks the horizontal scrolling position.
+	hScroll *hScrollTracker
+	// maxLineWidth is the width of the longest line in wrapped.
+	maxLineWidth int
+
+	// newLines counts the lines written while following of the content was
+	// paused.
+	newLines int
+	// markerLine is the line on the canvas with the new lines marker or -1
+	// if the marker isn't displayed.
+	markerLine int
 
 	// lastWidth stores the width of the last canvas the widget drew on.
 	// Used to determine if the previous line wrapping was invalidated.
@@ -55,6 +83,31 @@ type Text struct {
 	// invalidated.
 	contentChanged bool
 
+	// search tracks matches of the active search, nil if there isn't one.
+	search *searchTracker
+	// cellIdx maps the cells in wrapped to their index in content.
+	// Only populated while a search is active or there are clickable spans.
+	cellIdx map[*buffer.Cell]int
+	// tabCells maps the space cells in wrapped that replace tab characters
+	// to the tab cells in content.
+	tabCells map[*buffer.Cell]*buffer.Cell
+	// searchInput indicates that the user is typing a search query.
+	searchInput bool
+	// query is the search query typed by the user.
+	query []rune
+
+	// spans are the clickable spans in the order they were written.
+	spans []*span
+	// spanRanges map the content cell indexes to the index of the span they
+	// belong to.
+	spanRanges *attrrange.Tracker
+	// clickable maps points on the canvas to the index of the span drawn
+	// there. Populated on each call to Draw.
+	clickable map[image.Point]int
+	// pressed is the index of the span on which the left mouse button was
+	// pressed or -1 if it wasn't pressed over a span.
+	pressed int
+
 	// mu protects the Text widget.
 	mu sync.Mutex
 
@@ -69,8 +122,12 @@ func New(opts ...Option) (*Text, error) {
 		return nil, err
 	}
 	return &Text{
-		scroll: newScrollTracker(opt),
-		opts:   opt,
+		scroll:     newScrollTracker(opt),
+		hScroll:    &hScrollTracker{},
+		spanRanges: attrrange.NewTracker(),
+		pressed:    -1,
+		markerLine: -1,
+		opts:       opt,
 	}, nil
 }
 
@@ -79,13 +136,23 @@ func (t *Text) Reset() {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 	t.reset()
+	t.clearSearch()
 }
 
 // reset implements Reset, caller must hold t.mu.
 func (t *Text) reset() {
 	t.content = nil
 	t.wrapped = nil
+	t.cellIdx = nil
+	t.spans = nil
+	t.spanRanges = attrrange.NewTracker()
+	t.clickable = nil
+	t.pressed = -1
+	t.newLines = 0
+	t.markerLine = -1
 	t.scroll = newScrollTracker(t.opts)
+	t.hScroll = &hScrollTracker{}
+	t.maxLineWidth = 0
 	t.lastWidth = 0
 	t.contentChanged = true
 }
@@ -93,28 +160,139 @@ func (t *Text) reset() {
 // Write writes text for the widget to display. Multiple calls append
 // additional text. The text contain cannot control characters
 // (unicode.IsControl) or space character (unicode.IsSpace) other than:
-//   ' ', '\n'
+//   ' ', '\n', '\t'
 // Any newline ('\n') characters are interpreted as newlines when displaying
-// the text.
+// the text. Any tab ('\t') characters are expanded to the next tab stop, see
+// the TabWidth option.
 func (t *Text) Write(text string, wOpts ...WriteOption) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
-	if err := wrap.ValidText(text); err != nil {
+	opts := newWriteOptions(wOpts...)
+	if opts.clickable && opts.spanFn == nil {
+		return fmt.Errorf("the function of the clickable span %q cannot be nil", opts.spanID)
+	}
+	chunks := markup.Plain(text)
+	if opts.markup {
+		c, err := markup.Parse(text)
+		if err != nil {
+			return err
+		}
+		chunks = c
+	}
+
+	if err := wrap.ValidText(markup.Text(chunks)); err != nil {
 		return err
 	}
 
-	opts := newWriteOptions(wOpts...)
 	if opts.replace {
 		t.reset()
 	}
-	for _, r := range text {
-		t.content = append(t.content, buffer.NewCell(r, opts.cellOpts))
+	low := len(t.content)
+	for _, ch := range chunks {
+		cellOpts := opts.cellOpts
+		if len(ch.Opts) > 0 {
+			cellOpts = cell.NewOptions(append([]cell.Option{opts.cellOpts}, ch.Opts...)...)
+		}
+		for _, r := range ch.Text {
+			t.content = append(t.content, buffer.NewCell(r, cellOpts))
+		}
+	}
+	if t.opts.followContent && t.scroll.paused {
+		t.newLines += strings.Count(markup.Text(chunks), "\n")
+	}
+	if high := len(t.content); opts.clickable && high > low {
+		if err := t.spanRanges.Add(low, high, len(t.spans)); err != nil {
+			return err
+		}
+		t.spans = append(t.spans, &span{id: opts.spanID, fn: opts.spanFn})
 	}
 	t.contentChanged = true
+	if t.search != nil {
+		// The new content can contain new matches.
+		return t.search.update(t.content)
+	}
 	return nil
 }
 
+// Search finds all occurrences of the provided substring in the content of
+// the widget, highlights them and scrolls to the first one. Replaces any
+// previous search. The matches are kept up to date as more text is written
+// to the widget.
+// Returns the number of matches found.
+func (t *Text) Search(substr string) (int, error) {
+	if substr == "" {
+		return 0, errors.New("the searched substring cannot be empty")
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.startSearch(substrMatcher(substr))
+}
+
+// SearchRegexp is like Search, but finds all the matches of the provided
+// regular expression. Matches of the regular expression that are empty are
+// ignored.
+func (t *Text) SearchRegexp(re *regexp.Regexp) (int, error) {
+	if re == nil {
+		return 0, errors.New("the searched regular expression cannot be nil")
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.startSearch(regexpMatcher(re))
+}
+
+// startSearch starts a new search using the provided matcher.
+// Caller must hold t.mu.
+func (t *Text) startSearch(find matcher) (int, error) {
+	st := newSearchTracker(find)
+	if err := st.update(t.content); err != nil {
+		return 0, err
+	}
+	t.search = st
+	t.cellIdx = nil
+	return len(st.matches), nil
+}
+
+// NextMatch selects the next match of the active search and scrolls to it.
+// Wraps around to the first match after the last one. Does nothing if there
+// is no active search.
+func (t *Text) NextMatch() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.search != nil {
+		t.search.next()
+	}
+}
+
+// PrevMatch selects the previous match of the active search and scrolls to
+// it. Wraps around to the last match before the first one. Does nothing if
+// there is no active search.
+func (t *Text) PrevMatch() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.search != nil {
+		t.search.prev()
+	}
+}
+
+// ClearSearch stops the active search and removes the highlighting of its
+// matches.
+func (t *Text) ClearSearch() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.clearSearch()
+}
+
+// clearSearch implements ClearSearch, caller must hold t.mu.
+func (t *Text) clearSearch() {
+	t.search = nil
+	t.cellIdx = nil
+	t.searchInput = false
+	t.query = nil
+}
+
 // minLinesForMarkers are the minimum amount of lines required on the canvas in
 // order to draw the scroll markers ('⇧' and '⇩').
 const minLinesForMarkers = 3
@@ -156,11 +334,38 @@ func (t *Text) drawScrollDown(cvs *canvas.Canvas, cur image.Point, fromLine int)
 	return false, nil
 }
 
+// drawScrollLeft draws the continuation marker at the start of the specified
+// line. Used when some of the line's cells were scrolled out on the left.
+func drawScrollLeft(cvs *canvas.Canvas, line int) error {
+	cells, err := cvs.SetCell(image.Point{0, line}, '…')
+	if err != nil {
+		return err
+	}
+	if cells != 1 {
+		panic(fmt.Errorf("invalid continuation marker, it occupies %d cells, the implementation only supports markers that occupy exactly one cell", cells))
+	}
+	return nil
+}
+
+// lineWidth returns the width of the line in cells when printed on the
+// terminal.
+func lineWidth(line []*buffer.Cell) int {
+	var w int
+	for _, c := range line {
+		w += runewidth.RuneWidth(c.Rune)
+	}
+	return w
+}
+
 // draw draws the text context on the canvas starting at the specified line.
 func (t *Text) draw(cvs *canvas.Canvas) error {
 	var cur image.Point // Tracks the current drawing position on the canvas.
 	height := cvs.Area().Dy()
 	fromLine := t.scroll.firstLine(len(t.wrapped), height)
+	fromCell := 0
+	if t.opts.scrollHorizontally {
+		fromCell = t.hScroll.firstCell(t.maxLineWidth, cvs.Area().Dx())
+	}
 
 	for _, line := range t.wrapped[fromLine:] {
 		// Scroll up marker.
@@ -183,7 +388,21 @@ func (t *Text) draw(cvs *canvas.Canvas) error {
 			break // Skip all lines falling after (under) the canvas.
 		}
 
+		var skipped bool // Any cells skipped due to horizontal scrolling.
+		col := 0         // The horizontal position of the cell within the line.
 		for _, cell := range line {
+			rw := runewidth.RuneWidth(cell.Rune)
+			if col < fromCell {
+				col += rw
+				skipped = true
+				continue // Skip cells scrolled out on the left.
+			}
+			if x := col - fromCell; cur.X < x {
+				// A full-width rune was partially scrolled out on the left.
+				cur = image.Point{x, cur.Y}
+			}
+			col += rw
+
 			tr, err := lineTrim(cvs, cur, cell.Rune, t.opts)
 			if err != nil {
 				return err
@@ -193,17 +412,151 @@ func (t *Text) draw(cvs *canvas.Canvas) error {
 				break // Skip over any characters trimmed on the current line.
 			}
 
-			cells, err := cvs.SetCell(cur, cell.Rune, cell.Opts)
+			cells, err := cvs.SetCell(cur, cell.Rune, t.cellOpts(cell)...)
 			if err != nil {
 				return err
 			}
+			if s, ok := t.spanAt(cell); ok {
+				for i := 0; i < cells; i++ {
+					t.clickable[image.Point{cur.X + i, cur.Y}] = s
+				}
+			}
 			cur = image.Point{cur.X + cells, cur.Y} // Move within the same line.
 		}
+		if skipped {
+			if err := drawScrollLeft(cvs, cur.Y); err != nil {
+				return err
+			}
+		}
 		cur = image.Point{0, cur.Y + 1} // Move to the next line.
 	}
 	return nil
 }
 
+// cellOpts returns the options the cell should be drawn with.
+// Cells that are part of a search match are highlighted.
+func (t *Text) cellOpts(c *buffer.Cell) []cell.Option {
+	opts := []cell.Option{c.Opts}
+	if t.search == nil {
+		return opts
+	}
+	idx, ok := t.cellIdx[c]
+	if !ok {
+		return opts
+	}
+	m, ok := t.search.matchAt(idx)
+	switch {
+	case !ok:
+		return opts
+	case m == t.search.current:
+		return append(opts, t.opts.searchCurrentCellOpts...)
+	default:
+		return append(opts, t.opts.searchCellOpts...)
+	}
+}
+
+// spanAt returns the index of the clickable span the cell belongs to.
+// Returns false if the cell isn't part of any span.
+func (t *Text) spanAt(c *buffer.Cell) (int, bool) {
+	if len(t.spans) == 0 {
+		return 0, false
+	}
+	idx, ok := t.cellIdx[c]
+	if !ok {
+		return 0, false
+	}
+	ar, err := t.spanRanges.ForPosition(idx)
+	if err != nil {
+		return 0, false
+	}
+	return ar.AttrIdx, true
+}
+
+// indexCells maps the wrapped cells to their indexes in the content.
+func (t *Text) indexCells() {
+	t.cellIdx = make(map[*buffer.Cell]int, len(t.content))
+	for i, c := range t.content {
+		t.cellIdx[c] = i
+	}
+	// The spaces that replace a tab have the index of the tab.
+	for space, tab := range t.tabCells {
+		if i, ok := t.cellIdx[tab]; ok {
+			t.cellIdx[space] = i
+		}
+	}
+}
+
+// matchPosition returns the index of the wrapped line that contains the start
+// of the match and the horizontal position of the match within the line.
+func (t *Text) matchPosition(m *match) (line, col int) {
+	for i, l := range t.wrapped {
+		col := 0
+		for _, c := range l {
+			// The wrapping might have dropped the first cell of the match if
+			// it was a space.
+			if idx, ok := t.cellIdx[c]; ok && idx >= m.low {
+				return i, col
+			}
+			col += runewidth.RuneWidth(c.Rune)
+		}
+	}
+	return len(t.wrapped) - 1, 0
+}
+
+// drawNewLinesMarker draws the marker with the number of new lines on the
+// last line of the canvas if following of the content is paused and new
+// lines were written since.
+func (t *Text) drawNewLinesMarker(cvs *canvas.Canvas) error {
+	t.markerLine = -1
+	if !t.scroll.paused {
+		t.newLines = 0
+		return nil
+	}
+	ar := cvs.Area()
+	if t.newLines == 0 || ar.Dy() < minLinesForMarkers {
+		return nil
+	}
+
+	line := image.Rect(ar.Min.X, ar.Max.Y-1, ar.Max.X, ar.Max.Y)
+	if err := cvs.SetAreaCells(line, 0); err != nil {
+		return err
+	}
+	for x := line.Min.X; x < line.Max.X; x++ {
+		// The spans on this line are covered by the marker.
+		delete(t.clickable, image.Point{x, line.Min.Y})
+	}
+	text := fmt.Sprintf("%d new lines ↓", t.newLines)
+	if t.newLines == 1 {
+		text = "1 new line ↓"
+	}
+	t.markerLine = line.Min.Y
+	return draw.Text(
+		cvs, text, line.Min,
+		draw.TextMaxX(line.Max.X),
+		draw.TextOverrunMode(draw.OverrunModeThreeDot),
+	)
+}
+
+// drawSearchInput draws the search query the user is typing on the last line
+// of the canvas.
+func (t *Text) drawSearchInput(cvs *canvas.Canvas) error {
+	ar := cvs.Area()
+	line := image.Rect(ar.Min.X, ar.Max.Y-1, ar.Max.X, ar.Max.Y)
+	if err := cvs.SetAreaCells(line, 0); err != nil {
+		return err
+	}
+	for x := line.Min.X; x < line.Max.X; x++ {
+		// The spans on this line are covered by the search input.
+		delete(t.clickable, image.Point{x, line.Min.Y})
+	}
+	t.markerLine = -1 // The new lines marker is covered too.
+	return draw.Text(
+		cvs, "/"+string(t.query), line.Min,
+		draw.TextMaxX(line.Max.X),
+		draw.TextOverrunMode(draw.OverrunModeThreeDot),
+	)
+}
+
 // Draw draws the text onto the canvas.
 // Implements widgetapi.Widget.Draw.
 func (t *Text) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
@@ -214,20 +567,52 @@ func (t *Text) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
 	if len(t.content) > 0 && (t.contentChanged || t.lastWidth != width) {
 		// The previous text preprocessing (line wrapping) is invalidated when
 		// new text is added or the width of the canvas changed.
-		wr, err := wrap.Cells(t.content, width, t.opts.wrapMode)
+		tabCells := map[*buffer.Cell]*buffer.Cell{}
+		wr, err := wrap.Cells(t.content, width, t.opts.wrapMode, wrap.TabWidth(t.opts.tabWidth), wrap.TabCells(tabCells))
 		if err != nil {
 			return err
 		}
 		t.wrapped = wr
+		t.tabCells = tabCells
+		t.cellIdx = nil
+		t.maxLineWidth = 0
+		for _, line := range wr {
+			if w := lineWidth(line); w > t.maxLineWidth {
+				t.maxLineWidth = w
+			}
+		}
 	}
 	t.lastWidth = width
 
-	if len(t.wrapped) == 0 {
-		return nil // Nothing to draw if there's no text.
+	if (t.search != nil || len(t.spans) > 0) && t.cellIdx == nil {
+		t.indexCells()
+	}
+	if t.search != nil && t.search.jump {
+		if m := t.search.currentMatch(); m != nil && len(t.wrapped) > 0 {
+			line, col := t.matchPosition(m)
+			t.scroll.showLine(line, cvs.Area().Dy())
+			if t.opts.scrollHorizontally {
+				t.hScroll.showCell(col, width)
+			}
+		}
+		t.search.jump = false
 	}
 
-	if err := t.draw(cvs); err != nil {
-		return err
+	t.clickable = map[image.Point]int{}
+	if len(t.wrapped) > 0 {
+		if err := t.draw(cvs); err != nil {
+			return err
+		}
+	}
+	if t.opts.followContent {
+		if err := t.drawNewLinesMarker(cvs); err != nil {
+			return err
+		}
+	}
+	if t.searchInput {
+		if err := t.drawSearchInput(cvs); err != nil {
+			return err
+		}
 	}
 	t.contentChanged = false
 	return nil
@@ -238,7 +623,36 @@ func (t *Text) Keyboard(k *terminalapi.Keyboard) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
+	if t.searchInput {
+		return t.searchKeyboard(k)
+	}
+
+	switch {
+	case t.opts.searchKeys && k.Key == t.opts.keySearch:
+		t.searchInput = true
+		t.query = nil
+		return nil
+	case t.opts.searchKeys && k.Key == t.opts.keySearchNext:
+		if t.search != nil {
+			t.search.next()
+		}
+		return nil
+	case t.opts.searchKeys && k.Key == t.opts.keySearchPrev:
+		if t.search != nil {
+			t.search.prev()
+		}
+		return nil
+	case t.opts.disableScrolling:
+		return nil
+	}
+
 	switch {
+	case t.opts.followContent && k.Key == t.opts.keyEnd:
+		t.scroll.toEnd()
+	case t.opts.scrollHorizontally && k.Key == t.opts.keyLeft:
+		t.hScroll.leftOneCell()
+	case t.opts.scrollHorizontally && k.Key == t.opts.keyRight:
+		t.hScroll.rightOneCell()
 	case k.Key == t.opts.keyUp:
 		t.scroll.upOneLine()
 	case k.Key == t.opts.keyDown:
@@ -251,22 +665,91 @@ func (t *Text) Keyboard(k *terminalapi.Keyboard) error {
 	return nil
 }
 
+// searchKeyboard processes keyboard events while the user is typing a search
+// query. Caller must hold t.mu.
+func (t *Text) searchKeyboard(k *terminalapi.Keyboard) error {
+	switch {
+	case k.Key == keyboard.KeyEsc:
+		t.searchInput = false
+		t.query = nil
+
+	case k.Key == keyboard.KeyEnter:
+		t.searchInput = false
+		if len(t.query) == 0 {
+			t.clearSearch()
+			return nil
+		}
+		_, err := t.startSearch(substrMatcher(string(t.query)))
+		return err
+
+	case k.Key == keyboard.KeyBackspace || k.Key == keyboard.KeyBackspace2:
+		if len(t.query) > 0 {
+			t.query = t.query[:len(t.query)-1]
+		}
+
+	case k.Key == keyboard.KeySpace || k.Key > 0 && unicode.IsPrint(rune(k.Key)):
+		t.query = append(t.query, rune(k.Key))
+	}
+	return nil
+}
+
 // Mouse implements widgetapi.Widget.Mouse.
 func (t *Text) Mouse(m *terminalapi.Mouse) error {
+	s := t.mouse(m)
+	if s == nil {
+		return nil
+	}
+	// Called without holding t.mu so that the function can use the widget.
+	return s.fn(s.id)
+}
+
+// mouse processes the mouse event and returns the clickable span that was
+// clicked or nil if no span was clicked.
+func (t *Text) mouse(m *terminalapi.Mouse) *span {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
+	if m.Button == mouse.ButtonLeft && t.markerLine >= 0 && m.Position.Y == t.markerLine {
+		t.scroll.toEnd()
+		return nil
+	}
+
+	s, onSpan := t.clickable[m.Position]
+	switch {
+	case m.Button == mouse.ButtonLeft && onSpan:
+		t.pressed = s
+		return nil
+	case m.Button == mouse.ButtonRelease:
+		pressed := t.pressed
+		t.pressed = -1
+		if onSpan && s == pressed {
+			return t.spans[s]
+		}
+		return nil
+	}
+	t.pressed = -1
+	if t.opts.disableScrolling {
+		return nil
+	}
+
 	switch b := m.Button; {
 	case b == t.opts.mouseUpButton:
 		t.scroll.upOneLine()
 	case b == t.opts.mouseDownButton:
 		t.scroll.downOneLine()
+	case t.opts.scrollHorizontally && b != 0 && b == t.opts.mouseLeftButton:
+		t.hScroll.leftOneCell()
+	case t.opts.scrollHorizontally && b != 0 && b == t.opts.mouseRightButton:
+		t.hScroll.rightOneCell()
 	}
 	return nil
 }
 
 // Options of the widget
 func (t *Text) Options() widgetapi.Options {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	var ks widgetapi.KeyScope
 	var ms widgetapi.MouseScope
 	if t.opts.disableScrolling {
@@ -276,6 +759,12 @@ func (t *Text) Options() widgetapi.Options {
 		ks = widgetapi.KeyScopeFocused
 		ms = widgetapi.MouseScopeWidget
 	}
+	if t.opts.searchKeys {
+		ks = widgetapi.KeyScopeFocused
+	}
+	if len(t.spans) > 0 {
+		ms = widgetapi.MouseScopeWidget
+	}
 
 	return widgetapi.Options{
 		// At least one line with at least one full-width rune.
@@ -284,3 +773,11 @@ func (t *Text) Options() widgetapi.Options {
 		WantKeyboard: ks,
 	}
 }
+
+// span is a clickable span of text.
+type span struct {
+	// id identifies the span.
+	id string
+	// fn is called when the span is clicked.
+	fn SpanClickFn
+}
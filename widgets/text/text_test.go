@@ -16,6 +16,7 @@ package text
 
 import (
 	"image"
+	"regexp"
 	"testing"
 
 	"github.com/kylelemons/godebug/pretty"
@@ -32,6 +33,23 @@ import (
 )
 
 func TestTextDraws(t *testing.T) {
+	// The widget draws lines 0-4 rolled to the end, the user scrolls up and
+	// two more lines are written.
+	writes := func(widget *Text) error {
+		if err := widget.Write("0\n1\n2\n3\n4"); err != nil {
+			return err
+		}
+		c := testcanvas.MustNew(image.Rect(0, 0, 15, 3))
+		if err := widget.Draw(c, nil); err != nil {
+			return err
+		}
+		widget.Keyboard(&terminalapi.Keyboard{Key: keyboard.KeyArrowUp})
+		if err := widget.Draw(c, nil); err != nil {
+			return err
+		}
+		return widget.Write("\n5\n6")
+	}
+
 	tests := []struct {
 		desc         string
 		canvas       image.Rectangle
@@ -76,7 +94,7 @@ func TestTextDraws(t *testing.T) {
 			desc:   "write fails for invalid text",
 			canvas: image.Rect(0, 0, 1, 1),
 			writes: func(widget *Text) error {
-				return widget.Write("\thello")
+				return widget.Write("\rhello")
 			},
 			want: func(size image.Point) *faketerm.Terminal {
 				return faketerm.MustNew(size)
@@ -768,90 +786,1055 @@ func TestTextDraws(t *testing.T) {
 				return ft
 			},
 		},
-	}
-
-	for _, tc := range tests {
-		t.Run(tc.desc, func(t *testing.T) {
-			c, err := canvas.New(tc.canvas)
-			if err != nil {
-				t.Fatalf("canvas.New => unexpected error: %v", err)
-			}
+		{
+			desc:   "fails when search keys aren't unique",
+			canvas: image.Rect(0, 0, 1, 1),
+			opts: []Option{
+				SearchKeys('/', 'n', 'n'),
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantErr: true,
+		},
+		{
+			desc:   "fails when search keys collide with scroll keys",
+			canvas: image.Rect(0, 0, 1, 1),
+			opts: []Option{
+				SearchKeys(keyboard.KeyArrowUp, 'n', 'N'),
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantErr: true,
+		},
+		{
+			desc:   "search fails on empty substring",
+			canvas: image.Rect(0, 0, 10, 1),
+			writes: func(widget *Text) error {
+				if err := widget.Write("hello"); err != nil {
+					return err
+				}
+				_, err := widget.Search("")
+				return err
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantWriteErr: true,
+		},
+		{
+			desc:   "search highlights all matches",
+			canvas: image.Rect(0, 0, 10, 1),
+			writes: func(widget *Text) error {
+				if err := widget.Write("ab ab ab"); err != nil {
+					return err
+				}
+				_, err := widget.Search("ab")
+				return err
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
 
-			widget, err := New(tc.opts...)
-			if (err != nil) != tc.wantErr {
-				t.Errorf("New => unexpected error: %v, wantErr: %v", err, tc.wantErr)
-			}
-			if err != nil {
-				return
-			}
+				testdraw.MustText(c, "ab", image.Point{0, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorBlack), cell.BgColor(cell.ColorCyan)))
+				testdraw.MustText(c, " ", image.Point{2, 0})
+				testdraw.MustText(c, "ab", image.Point{3, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorBlack), cell.BgColor(cell.ColorYellow)))
+				testdraw.MustText(c, " ", image.Point{5, 0})
+				testdraw.MustText(c, "ab", image.Point{6, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorBlack), cell.BgColor(cell.ColorYellow)))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "search highlights the cells of tabs in matches",
+			canvas: image.Rect(0, 0, 10, 1),
+			opts: []Option{
+				TabWidth(4),
+			},
+			writes: func(widget *Text) error {
+				if err := widget.Write("a\tb c"); err != nil {
+					return err
+				}
+				_, err := widget.Search("a\tb")
+				return err
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
 
-			if tc.writes != nil {
-				err := tc.writes(widget)
-				if (err != nil) != tc.wantWriteErr {
-					t.Errorf("Write => unexpected error: %v, wantWriteErr: %v", err, tc.wantWriteErr)
+				testdraw.MustText(c, "a   b", image.Point{0, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorBlack), cell.BgColor(cell.ColorCyan)))
+				testdraw.MustText(c, " c", image.Point{5, 0})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "regexp search with custom highlight cell options",
+			canvas: image.Rect(0, 0, 10, 1),
+			opts: []Option{
+				SearchCellOpts(cell.FgColor(cell.ColorRed)),
+				SearchCurrentCellOpts(cell.FgColor(cell.ColorBlue)),
+			},
+			writes: func(widget *Text) error {
+				if err := widget.Write("a1 b2"); err != nil {
+					return err
 				}
-				if err != nil {
-					return
+				_, err := widget.SearchRegexp(regexp.MustCompile(`[0-9]`))
+				return err
+			},
+			events: func(widget *Text) {
+				widget.NextMatch()
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "a", image.Point{0, 0})
+				testdraw.MustText(c, "1", image.Point{1, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorRed)))
+				testdraw.MustText(c, " b", image.Point{2, 0})
+				testdraw.MustText(c, "2", image.Point{4, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorBlue)))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "clearing the search removes highlighting",
+			canvas: image.Rect(0, 0, 10, 1),
+			writes: func(widget *Text) error {
+				if err := widget.Write("hello"); err != nil {
+					return err
 				}
-			}
+				_, err := widget.Search("ll")
+				return err
+			},
+			events: func(widget *Text) {
+				widget.ClearSearch()
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
 
-			if tc.events != nil {
-				tc.events(widget)
-			}
+				testdraw.MustText(c, "hello", image.Point{0, 0})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "search scrolls to the selected match",
+			canvas: image.Rect(0, 0, 10, 3),
+			writes: func(widget *Text) error {
+				if err := widget.Write("line0\nline1\nline2\nfound\nline4\nline5\nline6"); err != nil {
+					return err
+				}
+				_, err := widget.Search("found")
+				return err
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
 
-			if err := widget.Draw(c, tc.meta); err != nil {
-				t.Fatalf("Draw => unexpected error: %v", err)
-			}
+				testdraw.MustText(c, "⇧", image.Point{0, 0})
+				testdraw.MustText(c, "found", image.Point{0, 1}, draw.TextCellOpts(cell.FgColor(cell.ColorBlack), cell.BgColor(cell.ColorCyan)))
+				testdraw.MustText(c, "⇩", image.Point{0, 2})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "search keys type the query on the last line",
+			canvas: image.Rect(0, 0, 10, 2),
+			opts: []Option{
+				EnableSearchKeys(),
+			},
+			writes: func(widget *Text) error {
+				return widget.Write("hello\nworld")
+			},
+			events: func(widget *Text) {
+				for _, k := range []keyboard.Key{'/', 'w', 'x', keyboard.KeyBackspace2, 'o'} {
+					widget.Keyboard(&terminalapi.Keyboard{Key: k})
+				}
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
 
-			got, err := faketerm.New(c.Size())
-			if err != nil {
-				t.Fatalf("faketerm.New => unexpected error: %v", err)
-			}
+				testdraw.MustText(c, "hello", image.Point{0, 0})
+				testdraw.MustText(c, "/wo", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "search keys search and select matches",
+			canvas: image.Rect(0, 0, 10, 2),
+			opts: []Option{
+				EnableSearchKeys(),
+			},
+			writes: func(widget *Text) error {
+				return widget.Write("ab\nab")
+			},
+			events: func(widget *Text) {
+				for _, k := range []keyboard.Key{'/', 'a', 'b', keyboard.KeyEnter, 'n', 'n', 'N'} {
+					widget.Keyboard(&terminalapi.Keyboard{Key: k})
+				}
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
 
-			if err := c.Apply(got); err != nil {
-				t.Fatalf("Apply => unexpected error: %v", err)
-			}
+				testdraw.MustText(c, "ab", image.Point{0, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorBlack), cell.BgColor(cell.ColorYellow)))
+				testdraw.MustText(c, "ab", image.Point{0, 1}, draw.TextCellOpts(cell.FgColor(cell.ColorBlack), cell.BgColor(cell.ColorCyan)))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "escape cancels the search input",
+			canvas: image.Rect(0, 0, 10, 2),
+			opts: []Option{
+				EnableSearchKeys(),
+			},
+			writes: func(widget *Text) error {
+				return widget.Write("ab\nab")
+			},
+			events: func(widget *Text) {
+				for _, k := range []keyboard.Key{'/', 'a', keyboard.KeyEsc} {
+					widget.Keyboard(&terminalapi.Keyboard{Key: k})
+				}
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
 
-			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
-				t.Errorf("Draw => %v", diff)
-			}
-		})
-	}
-}
+				testdraw.MustText(c, "ab", image.Point{0, 0})
+				testdraw.MustText(c, "ab", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "search keys are ignored when not enabled",
+			canvas: image.Rect(0, 0, 10, 2),
+			writes: func(widget *Text) error {
+				return widget.Write("ab\nab")
+			},
+			events: func(widget *Text) {
+				for _, k := range []keyboard.Key{'/', 'a'} {
+					widget.Keyboard(&terminalapi.Keyboard{Key: k})
+				}
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
 
-func TestOptions(t *testing.T) {
-	tests := []struct {
-		desc string
-		opts []Option
-		want widgetapi.Options
-	}{
+				testdraw.MustText(c, "ab", image.Point{0, 0})
+				testdraw.MustText(c, "ab", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
 		{
-			desc: "minimum size for one character",
-			want: widgetapi.Options{
-				MinimumSize:  image.Point{1, 1},
-				WantKeyboard: widgetapi.KeyScopeFocused,
-				WantMouse:    widgetapi.MouseScopeWidget,
+			desc:   "fails when horizontal scrolling is combined with wrapping",
+			canvas: image.Rect(0, 0, 1, 1),
+			opts: []Option{
+				ScrollHorizontally(),
+				WrapAtWords(),
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
 			},
+			wantErr: true,
 		},
 		{
-			desc: "disabling scrolling removes keyboard and mouse",
+			desc:   "fails when horizontal scroll keys collide with scroll keys",
+			canvas: image.Rect(0, 0, 1, 1),
 			opts: []Option{
-				DisableScrolling(),
+				ScrollHorizontally(),
+				ScrollKeysHorizontal(keyboard.KeyArrowUp, keyboard.KeyArrowRight),
 			},
-			want: widgetapi.Options{
-				MinimumSize:  image.Point{1, 1},
-				WantKeyboard: widgetapi.KeyScopeNone,
-				WantMouse:    widgetapi.MouseScopeNone,
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
 			},
+			wantErr: true,
 		},
-	}
+		{
+			desc:   "fails when horizontal scroll mouse buttons collide with scroll buttons",
+			canvas: image.Rect(0, 0, 1, 1),
+			opts: []Option{
+				ScrollHorizontally(),
+				ScrollMouseButtonsHorizontal(mouse.ButtonWheelUp, mouse.ButtonRight),
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantErr: true,
+		},
+		{
+			desc:   "horizontal scrolling trims long lines without scrolling",
+			canvas: image.Rect(0, 0, 5, 2),
+			opts: []Option{
+				ScrollHorizontally(),
+			},
+			writes: func(widget *Text) error {
+				return widget.Write("0123456789\nab")
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
 
-	for _, tc := range tests {
+				testdraw.MustText(c, "0123…", image.Point{0, 0})
+				testdraw.MustText(c, "ab", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "scrolls right using the keyboard",
+			canvas: image.Rect(0, 0, 5, 2),
+			opts: []Option{
+				ScrollHorizontally(),
+			},
+			writes: func(widget *Text) error {
+				return widget.Write("0123456789\nab")
+			},
+			events: func(widget *Text) {
+				widget.Keyboard(&terminalapi.Keyboard{Key: keyboard.KeyArrowRight})
+				widget.Keyboard(&terminalapi.Keyboard{Key: keyboard.KeyArrowRight})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "…345…", image.Point{0, 0})
+				testdraw.MustText(c, "…", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "scrolls back left using custom keys",
+			canvas: image.Rect(0, 0, 5, 1),
+			opts: []Option{
+				ScrollHorizontally(),
+				ScrollKeysHorizontal('h', 'l'),
+			},
+			writes: func(widget *Text) error {
+				return widget.Write("0123456789")
+			},
+			events: func(widget *Text) {
+				widget.Keyboard(&terminalapi.Keyboard{Key: 'l'})
+				widget.Keyboard(&terminalapi.Keyboard{Key: 'l'})
+				widget.Keyboard(&terminalapi.Keyboard{Key: 'h'})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "…234…", image.Point{0, 0})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "horizontal scrolling stops at the end of the longest line",
+			canvas: image.Rect(0, 0, 5, 1),
+			opts: []Option{
+				ScrollHorizontally(),
+			},
+			writes: func(widget *Text) error {
+				return widget.Write("0123456789")
+			},
+			events: func(widget *Text) {
+				for i := 0; i < 20; i++ {
+					widget.Keyboard(&terminalapi.Keyboard{Key: keyboard.KeyArrowRight})
+				}
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "…6789", image.Point{0, 0})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "scrolls horizontally using configured mouse buttons",
+			canvas: image.Rect(0, 0, 5, 1),
+			opts: []Option{
+				ScrollHorizontally(),
+				ScrollMouseButtonsHorizontal(mouse.ButtonLeft, mouse.ButtonRight),
+			},
+			writes: func(widget *Text) error {
+				return widget.Write("0123456789")
+			},
+			events: func(widget *Text) {
+				widget.Mouse(&terminalapi.Mouse{Button: mouse.ButtonRight})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "…234…", image.Point{0, 0})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "horizontal scrolling over partially hidden full-width rune",
+			canvas: image.Rect(0, 0, 6, 1),
+			opts: []Option{
+				ScrollHorizontally(),
+			},
+			writes: func(widget *Text) error {
+				return widget.Write("你好世界")
+			},
+			events: func(widget *Text) {
+				widget.Keyboard(&terminalapi.Keyboard{Key: keyboard.KeyArrowRight})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "…好世…", image.Point{0, 0})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "search scrolls horizontally to the selected match",
+			canvas: image.Rect(0, 0, 5, 1),
+			opts: []Option{
+				ScrollHorizontally(),
+				SearchCurrentCellOpts(cell.FgColor(cell.ColorRed)),
+			},
+			writes: func(widget *Text) error {
+				if err := widget.Write("0123456789x"); err != nil {
+					return err
+				}
+				_, err := widget.Search("8")
+				return err
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "…7", image.Point{0, 0})
+				testdraw.MustText(c, "8", image.Point{2, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorRed)))
+				testdraw.MustText(c, "9x", image.Point{3, 0})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "write fails for invalid markup",
+			canvas: image.Rect(0, 0, 10, 1),
+			writes: func(widget *Text) error {
+				return widget.Write("[fg=purple]hello", WriteMarkup())
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantWriteErr: true,
+		},
+		{
+			desc:   "write with markup applies its options on top of the cell options",
+			canvas: image.Rect(0, 0, 15, 1),
+			writes: func(widget *Text) error {
+				return widget.Write(
+					"[fg=red]ERROR[/] [[disk]",
+					WriteMarkup(),
+					WriteCellOpts(cell.BgColor(cell.ColorBlue)),
+				)
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "ERROR", image.Point{0, 0}, draw.TextCellOpts(
+					cell.FgColor(cell.ColorRed),
+					cell.BgColor(cell.ColorBlue),
+				))
+				testdraw.MustText(c, " [disk]", image.Point{5, 0}, draw.TextCellOpts(
+					cell.BgColor(cell.ColorBlue),
+				))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "fails when the follow end key is used to scroll",
+			opts: []Option{
+				FollowContent(),
+				FollowKeyEnd(keyboard.KeyArrowUp),
+			},
+			canvas: image.Rect(0, 0, 1, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantErr: true,
+		},
+		{
+			desc: "follows the content",
+			opts: []Option{
+				FollowContent(),
+			},
+			canvas: image.Rect(0, 0, 15, 3),
+			writes: func(widget *Text) error {
+				return widget.Write("0\n1\n2\n3\n4\n5\n6")
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "⇧", image.Point{0, 0})
+				testdraw.MustText(c, "5", image.Point{0, 1})
+				testdraw.MustText(c, "6", image.Point{0, 2})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "displays the number of new lines when following is paused",
+			opts: []Option{
+				FollowContent(),
+			},
+			canvas: image.Rect(0, 0, 15, 3),
+			writes: writes,
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "⇧", image.Point{0, 0})
+				testdraw.MustText(c, "2", image.Point{0, 1})
+				testdraw.MustText(c, "2 new lines ↓", image.Point{0, 2})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "counts all the lines written while paused",
+			opts: []Option{
+				FollowContent(),
+			},
+			canvas: image.Rect(0, 0, 15, 3),
+			writes: func(widget *Text) error {
+				if err := writes(widget); err != nil {
+					return err
+				}
+				return widget.Write("\n")
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "⇧", image.Point{0, 0})
+				testdraw.MustText(c, "2", image.Point{0, 1})
+				testdraw.MustText(c, "3 new lines ↓", image.Point{0, 2})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "the end key resumes following",
+			opts: []Option{
+				FollowContent(),
+			},
+			canvas: image.Rect(0, 0, 15, 3),
+			writes: writes,
+			events: func(widget *Text) {
+				widget.Keyboard(&terminalapi.Keyboard{Key: keyboard.KeyEnd})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "⇧", image.Point{0, 0})
+				testdraw.MustText(c, "5", image.Point{0, 1})
+				testdraw.MustText(c, "6", image.Point{0, 2})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "a custom end key resumes following",
+			opts: []Option{
+				FollowContent(),
+				FollowKeyEnd('e'),
+			},
+			canvas: image.Rect(0, 0, 15, 3),
+			writes: writes,
+			events: func(widget *Text) {
+				widget.Keyboard(&terminalapi.Keyboard{Key: 'e'})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "⇧", image.Point{0, 0})
+				testdraw.MustText(c, "5", image.Point{0, 1})
+				testdraw.MustText(c, "6", image.Point{0, 2})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "clicking on the marker resumes following",
+			opts: []Option{
+				FollowContent(),
+			},
+			canvas: image.Rect(0, 0, 15, 3),
+			writes: func(widget *Text) error {
+				if err := writes(widget); err != nil {
+					return err
+				}
+				return widget.Draw(testcanvas.MustNew(image.Rect(0, 0, 15, 3)), nil)
+			},
+			events: func(widget *Text) {
+				widget.Mouse(&terminalapi.Mouse{Position: image.Point{3, 2}, Button: mouse.ButtonLeft})
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "⇧", image.Point{0, 0})
+				testdraw.MustText(c, "5", image.Point{0, 1})
+				testdraw.MustText(c, "6", image.Point{0, 2})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "scrolling back down resumes following and clears the marker",
+			opts: []Option{
+				FollowContent(),
+			},
+			canvas: image.Rect(0, 0, 15, 3),
+			writes: func(widget *Text) error {
+				if err := writes(widget); err != nil {
+					return err
+				}
+				for i := 0; i < 3; i++ {
+					widget.Keyboard(&terminalapi.Keyboard{Key: keyboard.KeyArrowDown})
+				}
+				if err := widget.Draw(testcanvas.MustNew(image.Rect(0, 0, 15, 3)), nil); err != nil {
+					return err
+				}
+				widget.Keyboard(&terminalapi.Keyboard{Key: keyboard.KeyArrowUp})
+				if err := widget.Draw(testcanvas.MustNew(image.Rect(0, 0, 15, 3)), nil); err != nil {
+					return err
+				}
+				return widget.Write("\n7")
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "⇧", image.Point{0, 0})
+				testdraw.MustText(c, "4", image.Point{0, 1})
+				testdraw.MustText(c, "1 new line ↓", image.Point{0, 2})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "fails on invalid tab width",
+			opts: []Option{
+				TabWidth(0),
+			},
+			canvas: image.Rect(0, 0, 1, 1),
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+			wantErr: true,
+		},
+		{
+			desc:   "expands tabs to the default tab stops",
+			canvas: image.Rect(0, 0, 10, 2),
+			writes: func(widget *Text) error {
+				return widget.Write("a\tb\n\tc")
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "a       b", image.Point{0, 0})
+				testdraw.MustText(c, "        c", image.Point{0, 1})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc: "expands tabs to the configured tab stops on wrapped lines",
+			opts: []Option{
+				TabWidth(4),
+				WrapAtRunes(),
+			},
+			canvas: image.Rect(0, 0, 6, 2),
+			writes: func(widget *Text) error {
+				return widget.Write("ab\tcd\tef", WriteCellOpts(cell.FgColor(cell.ColorRed)))
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "ab  cd", image.Point{0, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorRed)))
+				testdraw.MustText(c, "    ef", image.Point{0, 1}, draw.TextCellOpts(cell.FgColor(cell.ColorRed)))
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "markup isn't interpreted without the option",
+			canvas: image.Rect(0, 0, 15, 1),
+			writes: func(widget *Text) error {
+				return widget.Write("[bold]x[/]")
+			},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				c := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(c, "[bold]x[/]", image.Point{0, 0})
+				testcanvas.MustApply(c, ft)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+
+			widget, err := New(tc.opts...)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("New => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if tc.writes != nil {
+				err := tc.writes(widget)
+				if (err != nil) != tc.wantWriteErr {
+					t.Errorf("Write => unexpected error: %v, wantWriteErr: %v", err, tc.wantWriteErr)
+				}
+				if err != nil {
+					return
+				}
+			}
+
+			if tc.events != nil {
+				tc.events(widget)
+			}
+
+			if err := widget.Draw(c, tc.meta); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+		})
+	}
+}
+
+func TestClickableSpans(t *testing.T) {
+	tests := []struct {
+		desc    string
+		canvas  image.Rectangle
+		opts    []Option
+		writes  func(*Text, SpanClickFn) error
+		events  []*terminalapi.Mouse
+		want    []string // IDs of the clicked spans.
+		wantErr bool
+	}{
+		{
+			desc:   "click on a span",
+			canvas: image.Rect(0, 0, 10, 1),
+			writes: func(widget *Text, fn SpanClickFn) error {
+				if err := widget.Write("id="); err != nil {
+					return err
+				}
+				return widget.Write("abc", WriteClickable("abc", fn))
+			},
+			events: []*terminalapi.Mouse{
+				{Position: image.Point{4, 0}, Button: mouse.ButtonLeft},
+				{Position: image.Point{5, 0}, Button: mouse.ButtonRelease},
+			},
+			want: []string{"abc"},
+		},
+		{
+			desc:   "click on a tab within a span",
+			canvas: image.Rect(0, 0, 10, 1),
+			opts: []Option{
+				TabWidth(4),
+			},
+			writes: func(widget *Text, fn SpanClickFn) error {
+				return widget.Write("x\ty", WriteClickable("xy", fn))
+			},
+			events: []*terminalapi.Mouse{
+				{Position: image.Point{2, 0}, Button: mouse.ButtonLeft},
+				{Position: image.Point{3, 0}, Button: mouse.ButtonRelease},
+			},
+			want: []string{"xy"},
+		},
+		{
+			desc:   "no click outside of spans",
+			canvas: image.Rect(0, 0, 10, 1),
+			writes: func(widget *Text, fn SpanClickFn) error {
+				if err := widget.Write("id="); err != nil {
+					return err
+				}
+				return widget.Write("abc", WriteClickable("abc", fn))
+			},
+			events: []*terminalapi.Mouse{
+				{Position: image.Point{1, 0}, Button: mouse.ButtonLeft},
+				{Position: image.Point{1, 0}, Button: mouse.ButtonRelease},
+				{Position: image.Point{8, 0}, Button: mouse.ButtonLeft},
+				{Position: image.Point{8, 0}, Button: mouse.ButtonRelease},
+			},
+		},
+		{
+			desc:   "no click when released over a different span",
+			canvas: image.Rect(0, 0, 10, 1),
+			writes: func(widget *Text, fn SpanClickFn) error {
+				if err := widget.Write("ab", WriteClickable("ab", fn)); err != nil {
+					return err
+				}
+				return widget.Write("cd", WriteClickable("cd", fn))
+			},
+			events: []*terminalapi.Mouse{
+				{Position: image.Point{0, 0}, Button: mouse.ButtonLeft},
+				{Position: image.Point{2, 0}, Button: mouse.ButtonRelease},
+				{Position: image.Point{3, 0}, Button: mouse.ButtonLeft},
+				{Position: image.Point{3, 0}, Button: mouse.ButtonRelease},
+			},
+			want: []string{"cd"},
+		},
+		{
+			desc:   "no click on release without press",
+			canvas: image.Rect(0, 0, 10, 1),
+			writes: func(widget *Text, fn SpanClickFn) error {
+				return widget.Write("ab", WriteClickable("ab", fn))
+			},
+			events: []*terminalapi.Mouse{
+				{Position: image.Point{0, 0}, Button: mouse.ButtonRelease},
+			},
+		},
+		{
+			desc:   "click on a span wrapped onto the next line",
+			canvas: image.Rect(0, 0, 4, 2),
+			opts: []Option{
+				WrapAtRunes(),
+			},
+			writes: func(widget *Text, fn SpanClickFn) error {
+				if err := widget.Write("id="); err != nil {
+					return err
+				}
+				return widget.Write("abc", WriteClickable("abc", fn))
+			},
+			events: []*terminalapi.Mouse{
+				{Position: image.Point{1, 1}, Button: mouse.ButtonLeft},
+				{Position: image.Point{1, 1}, Button: mouse.ButtonRelease},
+			},
+			want: []string{"abc"},
+		},
+		{
+			desc:   "click on the second cell of a full-width rune",
+			canvas: image.Rect(0, 0, 10, 1),
+			writes: func(widget *Text, fn SpanClickFn) error {
+				return widget.Write("你好", WriteClickable("hello", fn))
+			},
+			events: []*terminalapi.Mouse{
+				{Position: image.Point{3, 0}, Button: mouse.ButtonLeft},
+				{Position: image.Point{3, 0}, Button: mouse.ButtonRelease},
+			},
+			want: []string{"hello"},
+		},
+		{
+			desc:   "click on a span scrolled horizontally",
+			canvas: image.Rect(0, 0, 4, 1),
+			opts: []Option{
+				ScrollHorizontally(),
+			},
+			writes: func(widget *Text, fn SpanClickFn) error {
+				if err := widget.Write("0123"); err != nil {
+					return err
+				}
+				if err := widget.Write("ab", WriteClickable("ab", fn)); err != nil {
+					return err
+				}
+				widget.Keyboard(&terminalapi.Keyboard{Key: keyboard.KeyArrowRight})
+				widget.Keyboard(&terminalapi.Keyboard{Key: keyboard.KeyArrowRight})
+				return nil
+			},
+			events: []*terminalapi.Mouse{
+				{Position: image.Point{3, 0}, Button: mouse.ButtonLeft},
+				{Position: image.Point{3, 0}, Button: mouse.ButtonRelease},
+			},
+			want: []string{"ab"},
+		},
+		{
+			desc:   "clicks work when scrolling is disabled",
+			canvas: image.Rect(0, 0, 10, 1),
+			opts: []Option{
+				DisableScrolling(),
+			},
+			writes: func(widget *Text, fn SpanClickFn) error {
+				return widget.Write("ab", WriteClickable("ab", fn))
+			},
+			events: []*terminalapi.Mouse{
+				{Position: image.Point{0, 0}, Button: mouse.ButtonLeft},
+				{Position: image.Point{0, 0}, Button: mouse.ButtonRelease},
+			},
+			want: []string{"ab"},
+		},
+		{
+			desc:   "reset removes the spans",
+			canvas: image.Rect(0, 0, 10, 1),
+			writes: func(widget *Text, fn SpanClickFn) error {
+				if err := widget.Write("ab", WriteClickable("ab", fn)); err != nil {
+					return err
+				}
+				return widget.Write("ab", WriteReplace())
+			},
+			events: []*terminalapi.Mouse{
+				{Position: image.Point{0, 0}, Button: mouse.ButtonLeft},
+				{Position: image.Point{0, 0}, Button: mouse.ButtonRelease},
+			},
+		},
+		{
+			desc:   "write fails on nil function",
+			canvas: image.Rect(0, 0, 10, 1),
+			writes: func(widget *Text, fn SpanClickFn) error {
+				return widget.Write("ab", WriteClickable("ab", nil))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+
+			widget, err := New(tc.opts...)
+			if err != nil {
+				t.Fatalf("New => unexpected error: %v", err)
+			}
+
+			var got []string
+			fn := func(id string) error {
+				got = append(got, id)
+				// The function can use the widget.
+				return widget.Write("x")
+			}
+			{
+				err := tc.writes(widget, fn)
+				if (err != nil) != tc.wantErr {
+					t.Errorf("Write => unexpected error: %v, wantErr: %v", err, tc.wantErr)
+				}
+				if err != nil {
+					return
+				}
+			}
+
+			if err := widget.Draw(c, nil); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+			for _, ev := range tc.events {
+				if err := widget.Mouse(ev); err != nil {
+					t.Fatalf("Mouse => unexpected error: %v", err)
+				}
+			}
+
+			if diff := pretty.Compare(tc.want, got); diff != "" {
+				t.Errorf("clicked spans => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOptions(t *testing.T) {
+	tests := []struct {
+		desc   string
+		opts   []Option
+		writes func(*Text) error
+		want   widgetapi.Options
+	}{
+		{
+			desc: "minimum size for one character",
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{1, 1},
+				WantKeyboard: widgetapi.KeyScopeFocused,
+				WantMouse:    widgetapi.MouseScopeWidget,
+			},
+		},
+		{
+			desc: "search keys require keyboard even when scrolling is disabled",
+			opts: []Option{
+				DisableScrolling(),
+				EnableSearchKeys(),
+			},
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{1, 1},
+				WantKeyboard: widgetapi.KeyScopeFocused,
+				WantMouse:    widgetapi.MouseScopeNone,
+			},
+		},
+		{
+			desc: "clickable spans require mouse even when scrolling is disabled",
+			opts: []Option{
+				DisableScrolling(),
+			},
+			writes: func(widget *Text) error {
+				return widget.Write("ab", WriteClickable("ab", func(string) error { return nil }))
+			},
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{1, 1},
+				WantKeyboard: widgetapi.KeyScopeNone,
+				WantMouse:    widgetapi.MouseScopeWidget,
+			},
+		},
+		{
+			desc: "disabling scrolling removes keyboard and mouse",
+			opts: []Option{
+				DisableScrolling(),
+			},
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{1, 1},
+				WantKeyboard: widgetapi.KeyScopeNone,
+				WantMouse:    widgetapi.MouseScopeNone,
+			},
+		},
+	}
+
+	for _, tc := range tests {
 		t.Run(tc.desc, func(t *testing.T) {
 			text, err := New(tc.opts...)
 			if err != nil {
 				t.Fatalf("New => unexpected error: %v", err)
 			}
+			if tc.writes != nil {
+				if err := tc.writes(text); err != nil {
+					t.Fatalf("Write => unexpected error: %v", err)
+				}
+			}
 
 			got := text.Options()
 			if diff := pretty.Compare(tc.want, got); diff != "" {
@@ -30,6 +30,14 @@ type WriteOption interface {
 type writeOptions struct {
 	cellOpts *cell.Options
 	replace  bool
+	markup   bool
+
+	// clickable indicates that the written text is a clickable span.
+	clickable bool
+	// spanID identifies the clickable span.
+	spanID string
+	// spanFn is called when the clickable span is clicked.
+	spanFn SpanClickFn
 }
 
 // newWriteOptions returns new writeOptions instance.
@@ -65,3 +73,38 @@ func WriteReplace() WriteOption {
 		wOpts.replace = true
 	})
 }
+
+// WriteMarkup instructs the text widget to interpret the text as markup that
+// sets the cell options of parts of the text, e.g.:
+//   "[fg=red,bold]ERROR[/] disk full"
+// The cell options set by the markup are applied on top of the ones provided
+// via WriteCellOpts. A literal '[' must be written as "[[".
+// See the documentation of the internal/markup package for the full syntax.
+// Write returns an error if the markup is invalid.
+func WriteMarkup() WriteOption {
+	return writeOption(func(wOpts *writeOptions) {
+		wOpts.markup = true
+	})
+}
+
+// SpanClickFn is called when the user clicks on a clickable span of text.
+// The id is the identifier of the span provided to WriteClickable.
+//
+// The function is called synchronously while the widget processes the mouse
+// event, so it shouldn't block. It can safely call methods of the Text
+// widget. Any error returned by the function is reported as an error from
+// the widget's Mouse method.
+type SpanClickFn func(id string) error
+
+// WriteClickable makes the text written by this call a clickable span, the
+// provided function is called with the id when the user clicks on any part
+// of the span. A click is a press and release of the left mouse button over
+// the same span.
+// Write returns an error if the function is nil.
+func WriteClickable(id string, fn SpanClickFn) WriteOption {
+	return writeOption(func(wOpts *writeOptions) {
+		wOpts.clickable = true
+		wOpts.spanID = id
+		wOpts.spanFn = fn
+	})
+}
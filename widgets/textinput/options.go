@@ -173,9 +173,11 @@ func MaxWidthCells(cells int) Option {
 }
 
 // Label adds a text label to the left of the input field.
+// Any tab characters in the label are expanded to tab stops placed every eight
+// cells.
 func Label(label string, cOpts ...cell.Option) Option {
 	return option(func(opts *options) {
-		opts.label = label
+		opts.label = wrap.ExpandTabs(label, wrap.DefaultTabWidth)
 		opts.labelCellOpts = cOpts
 	})
 }
@@ -375,6 +375,28 @@ func TestTextInput(t *testing.T) {
 				return ft
 			},
 		},
+		{
+			desc: "expands tabs in the label",
+			opts: []Option{
+				Label("a\t:"),
+			},
+			canvas: image.Rect(0, 0, 15, 1),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+
+				testdraw.MustText(cvs, "a       :", image.Point{0, 0})
+				testcanvas.MustSetAreaCells(
+					cvs,
+					image.Rect(9, 0, 15, 1),
+					textFieldRune,
+					cell.BgColor(cell.ColorNumber(DefaultFillColorNumber)),
+				)
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
 		{
 			desc: "has label and border, not enough remaining height",
 			opts: []Option{
@@ -0,0 +1,101 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toggle
+
+// options.go contains configurable options for Toggle.
+
+import (
+	"github.com/mum4k/termdash/cell"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// options holds the provided options.
+type options struct {
+	on        bool
+	onColor   cell.Color
+	offColor  cell.Color
+	knobColor cell.Color
+	textColor cell.Color
+	onChange  CallbackFn
+}
+
+// newOptions returns options with the default values set.
+func newOptions() *options {
+	return &options{
+		onColor:   cell.ColorGreen,
+		offColor:  cell.ColorNumber(240),
+		knobColor: cell.ColorWhite,
+		textColor: cell.ColorDefault,
+	}
+}
+
+// On makes the switch initially turned on.
+func On() Option {
+	return option(func(opts *options) {
+		opts.on = true
+	})
+}
+
+// OnColor sets the color of the switch when it is on.
+// Defaults to green.
+func OnColor(c cell.Color) Option {
+	return option(func(opts *options) {
+		opts.onColor = c
+	})
+}
+
+// OffColor sets the color of the switch when it is off.
+// Defaults to color number 240.
+func OffColor(c cell.Color) Option {
+	return option(func(opts *options) {
+		opts.offColor = c
+	})
+}
+
+// KnobColor sets the color of the knob of the switch.
+// Defaults to white.
+func KnobColor(c cell.Color) Option {
+	return option(func(opts *options) {
+		opts.knobColor = c
+	})
+}
+
+// TextColor sets the color of the label.
+func TextColor(c cell.Color) Option {
+	return option(func(opts *options) {
+		opts.textColor = c
+	})
+}
+
+// OnChange sets a function that is called each time the user turns the
+// switch on or off.
+func OnChange(fn CallbackFn) Option {
+	return option(func(opts *options) {
+		opts.onChange = fn
+	})
+}
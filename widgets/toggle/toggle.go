@@ -0,0 +1,210 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package toggle implements an interactive switch that can be turned on and
+// off.
+package toggle
+
+import (
+	"image"
+	"sync"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/button"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/draw"
+	"github.com/mum4k/termdash/internal/runewidth"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// CallbackFn is the function called when the user turns the switch on or
+// off. The argument is the new state of the switch.
+//
+// The callback function must be thread-safe as the mouse or keyboard events
+// that change the state are processed in a separate goroutine.
+//
+// If the function returns an error, the widget will forward it back to the
+// termdash infrastructure which causes a panic, unless the user provided a
+// termdash.ErrorHandler.
+type CallbackFn func(on bool) error
+
+// Toggle displays a switch with a knob on the left when off and on the right
+// when on, followed by a label. The label is underlined while the container
+// of the widget is focused.
+//
+// The user flips the switch by a mouse click or by the space key while its
+// container is focused. The left and right arrow keys turn the switch off and
+// on respectively.
+//
+// Implements widgetapi.Widget. This object is thread-safe.
+type Toggle struct {
+	// label is the text displayed next to the switch.
+	label string
+	// on is the current state of the switch.
+	on bool
+
+	// mouseFSM tracks left mouse clicks.
+	mouseFSM *button.FSM
+
+	// mu protects the widget.
+	mu sync.Mutex
+
+	// opts are the provided options.
+	opts *options
+}
+
+// New returns a new Toggle that displays the provided label.
+func New(label string, opts ...Option) (*Toggle, error) {
+	opt := newOptions()
+	for _, o := range opts {
+		o.set(opt)
+	}
+	return &Toggle{
+		label:    label,
+		on:       opt.on,
+		mouseFSM: button.NewFSM(mouse.ButtonLeft, image.ZR),
+		opts:     opt,
+	}, nil
+}
+
+// On returns the current state of the switch.
+func (t *Toggle) On() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.on
+}
+
+// SetOn sets the state of the switch. Doesn't call the OnChange callback.
+func (t *Toggle) SetOn(on bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.on = on
+}
+
+// set sets the state of the switch and calls the OnChange callback if the
+// state changed.
+// Caller must hold t.mu.
+func (t *Toggle) set(on bool) error {
+	if t.on == on {
+		return nil
+	}
+	t.on = on
+	if t.opts.onChange != nil {
+		return t.opts.onChange(t.on)
+	}
+	return nil
+}
+
+const (
+	// trackWidth is the width of the switch in cells.
+	trackWidth = 4
+	// knobWidth is the width of the knob in cells.
+	knobWidth = 2
+)
+
+// Vars to be replaced from tests.
+var (
+	// Runes to use in cells of the switch.
+	// Changed from tests to provide readable test failures.
+	trackRune = ' '
+	knobRune  = ' '
+)
+
+// Draw draws the Toggle widget onto the canvas.
+// Implements widgetapi.Widget.Draw.
+func (t *Toggle) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cvsAr := cvs.Area()
+	t.mouseFSM.UpdateArea(cvsAr)
+	if cvsAr.Dx() < trackWidth {
+		return draw.ResizeNeeded(cvs)
+	}
+
+	trackColor := t.opts.offColor
+	knobAr := image.Rect(0, 0, knobWidth, 1)
+	if t.on {
+		trackColor = t.opts.onColor
+		knobAr = image.Rect(trackWidth-knobWidth, 0, trackWidth, 1)
+	}
+	if err := cvs.SetAreaCells(image.Rect(0, 0, trackWidth, 1), trackRune, cell.BgColor(trackColor)); err != nil {
+		return err
+	}
+	if err := cvs.SetAreaCells(knobAr, knobRune, cell.BgColor(t.opts.knobColor)); err != nil {
+		return err
+	}
+
+	if cvsAr.Dx() <= trackWidth+1 || t.label == "" {
+		return nil
+	}
+	labelOpts := []cell.Option{cell.FgColor(t.opts.textColor)}
+	if meta.Focused {
+		labelOpts = append(labelOpts, cell.Underline())
+	}
+	return draw.Text(cvs, t.label, image.Point{trackWidth + 1, 0},
+		draw.TextCellOpts(labelOpts...),
+		draw.TextMaxX(cvsAr.Max.X),
+		draw.TextOverrunMode(draw.OverrunModeThreeDot),
+	)
+}
+
+// Keyboard flips the switch on the space key and turns it off or on on the
+// left and right arrow keys.
+// Implements widgetapi.Widget.Keyboard.
+func (t *Toggle) Keyboard(k *terminalapi.Keyboard) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch k.Key {
+	case keyboard.KeySpace:
+		return t.set(!t.on)
+	case keyboard.KeyArrowLeft:
+		return t.set(false)
+	case keyboard.KeyArrowRight:
+		return t.set(true)
+	}
+	return nil
+}
+
+// Mouse flips the switch if both the press and the release of the left mouse
+// button happen inside the widget.
+// Implements widgetapi.Widget.Mouse.
+func (t *Toggle) Mouse(m *terminalapi.Mouse) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if clicked, _ := t.mouseFSM.Event(m); clicked {
+		return t.set(!t.on)
+	}
+	return nil
+}
+
+// Options implements widgetapi.Widget.Options.
+func (t *Toggle) Options() widgetapi.Options {
+	// No need to lock, as the label is fixed when New is called.
+	width := trackWidth
+	if t.label != "" {
+		width += 1 + runewidth.StringWidth(t.label)
+	}
+	return widgetapi.Options{
+		MinimumSize:  image.Point{trackWidth, 1},
+		MaximumSize:  image.Point{width, 1},
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		WantMouse:    widgetapi.MouseScopeWidget,
+	}
+}
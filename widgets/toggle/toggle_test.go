@@ -0,0 +1,345 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toggle
+
+import (
+	"errors"
+	"image"
+	"sync"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/canvas/testcanvas"
+	"github.com/mum4k/termdash/internal/draw"
+	"github.com/mum4k/termdash/internal/draw/testdraw"
+	"github.com/mum4k/termdash/internal/faketerm"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// callbackTracker tracks calls to the callback.
+type callbackTracker struct {
+	// wantErr when set to true, makes callback return an error.
+	wantErr bool
+
+	// calls are the values the callback was called with.
+	calls []bool
+
+	// mu protects the tracker.
+	mu sync.Mutex
+}
+
+// callback is the callback function.
+func (ct *callbackTracker) callback(on bool) error {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	if ct.wantErr {
+		return errors.New("ct.wantErr set to true")
+	}
+	ct.calls = append(ct.calls, on)
+	return nil
+}
+
+// mustDrawSwitch draws the switch in the provided state and colors.
+func mustDrawSwitch(cvs *canvas.Canvas, on bool, trackColor, knobColor cell.Color) {
+	testcanvas.MustSetAreaCells(cvs, image.Rect(0, 0, 4, 1), 't', cell.BgColor(trackColor))
+	knobAr := image.Rect(0, 0, 2, 1)
+	if on {
+		knobAr = image.Rect(2, 0, 4, 1)
+	}
+	testcanvas.MustSetAreaCells(cvs, knobAr, 'k', cell.BgColor(knobColor))
+}
+
+func TestToggle(t *testing.T) {
+	offColor := cell.ColorNumber(240)
+
+	tests := []struct {
+		desc        string
+		label       string
+		opts        []Option
+		callback    *callbackTracker
+		setter      func(*Toggle)
+		events      []terminalapi.Event
+		canvas      image.Rectangle
+		meta        *widgetapi.Meta
+		want        func(size image.Point) *faketerm.Terminal
+		wantCalls   []bool
+		wantOn      bool
+		wantDrawErr bool
+		wantEvErr   bool
+	}{
+		{
+			desc:   "draws switch that is off",
+			label:  "wifi",
+			canvas: image.Rect(0, 0, 10, 1),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustDrawSwitch(cvs, false, offColor, cell.ColorWhite)
+				testdraw.MustText(cvs, "wifi", image.Point{5, 0})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:  "draws switch that is initially on with custom colors",
+			label: "wifi",
+			opts: []Option{
+				On(),
+				OnColor(cell.ColorBlue),
+				KnobColor(cell.ColorRed),
+				TextColor(cell.ColorYellow),
+			},
+			canvas: image.Rect(0, 0, 10, 1),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustDrawSwitch(cvs, true, cell.ColorBlue, cell.ColorRed)
+				testdraw.MustText(cvs, "wifi", image.Point{5, 0}, draw.TextCellOpts(cell.FgColor(cell.ColorYellow)))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantOn: true,
+		},
+		{
+			desc:   "underlines the label when focused",
+			label:  "wifi",
+			opts:   []Option{OffColor(cell.ColorRed)},
+			canvas: image.Rect(0, 0, 10, 1),
+			meta:   &widgetapi.Meta{Focused: true},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustDrawSwitch(cvs, false, cell.ColorRed, cell.ColorWhite)
+				testdraw.MustText(cvs, "wifi", image.Point{5, 0}, draw.TextCellOpts(cell.Underline()))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "draw fails on canvas too small",
+			label:  "wifi",
+			canvas: image.Rect(0, 0, 3, 1),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testdraw.MustResizeNeeded(cvs)
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:     "space key flips the switch",
+			label:    "wifi",
+			callback: &callbackTracker{},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeySpace},
+				&terminalapi.Keyboard{Key: keyboard.KeySpace},
+				&terminalapi.Keyboard{Key: keyboard.KeySpace},
+			},
+			canvas: image.Rect(0, 0, 10, 1),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				mustDrawSwitch(cvs, true, cell.ColorGreen, cell.ColorWhite)
+				testdraw.MustText(cvs, "wifi", image.Point{5, 0})
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+			wantCalls: []bool{true, false, true},
+			wantOn:    true,
+		},
+		{
+			desc:     "arrow keys turn the switch on and off only on change",
+			label:    "wifi",
+			callback: &callbackTracker{},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowLeft},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowRight},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowRight},
+				&terminalapi.Keyboard{Key: keyboard.KeyArrowLeft},
+			},
+			canvas:    image.Rect(0, 0, 10, 1),
+			meta:      &widgetapi.Meta{},
+			wantCalls: []bool{true, false},
+		},
+		{
+			desc:     "mouse click flips the switch",
+			label:    "wifi",
+			callback: &callbackTracker{},
+			events: []terminalapi.Event{
+				&terminalapi.Mouse{Position: image.Point{1, 0}, Button: mouse.ButtonLeft},
+				&terminalapi.Mouse{Position: image.Point{6, 0}, Button: mouse.ButtonRelease},
+			},
+			canvas:    image.Rect(0, 0, 10, 1),
+			meta:      &widgetapi.Meta{},
+			wantCalls: []bool{true},
+			wantOn:    true,
+		},
+		{
+			desc:     "SetOn doesn't call the callback",
+			label:    "wifi",
+			callback: &callbackTracker{},
+			setter: func(tg *Toggle) {
+				tg.SetOn(true)
+			},
+			canvas: image.Rect(0, 0, 10, 1),
+			meta:   &widgetapi.Meta{},
+			wantOn: true,
+		},
+		{
+			desc:     "forwards errors from the callback",
+			label:    "wifi",
+			callback: &callbackTracker{wantErr: true},
+			events: []terminalapi.Event{
+				&terminalapi.Keyboard{Key: keyboard.KeySpace},
+			},
+			canvas:    image.Rect(0, 0, 10, 1),
+			meta:      &widgetapi.Meta{},
+			wantEvErr: true,
+			wantOn:    true,
+		},
+	}
+
+	trackRune = 't'
+	knobRune = 'k'
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			opts := tc.opts
+			if tc.callback != nil {
+				opts = append(opts, OnChange(tc.callback.callback))
+			}
+			tg, err := New(tc.label, opts...)
+			if err != nil {
+				t.Fatalf("New => unexpected error: %v", err)
+			}
+
+			{
+				// Draw once which initializes the mouse state machine with the current canvas area.
+				c, err := canvas.New(tc.canvas)
+				if err != nil {
+					t.Fatalf("canvas.New => unexpected error: %v", err)
+				}
+				if err := tg.Draw(c, tc.meta); err != nil && !tc.wantDrawErr {
+					t.Fatalf("Draw => unexpected error: %v", err)
+				}
+			}
+
+			if tc.setter != nil {
+				tc.setter(tg)
+			}
+			for _, ev := range tc.events {
+				var err error
+				switch e := ev.(type) {
+				case *terminalapi.Mouse:
+					err = tg.Mouse(e)
+				case *terminalapi.Keyboard:
+					err = tg.Keyboard(e)
+				default:
+					t.Fatalf("unsupported event type: %T", ev)
+				}
+				if (err != nil) != tc.wantEvErr {
+					t.Errorf("event %v => unexpected error: %v, wantEvErr: %v", ev, err, tc.wantEvErr)
+				}
+			}
+
+			if got := tg.On(); got != tc.wantOn {
+				t.Errorf("On => %v, want %v", got, tc.wantOn)
+			}
+			if tc.callback != nil {
+				if diff := pretty.Compare(tc.wantCalls, tc.callback.calls); diff != "" {
+					t.Errorf("CallbackFn => unexpected diff (-want, +got):\n%s", diff)
+				}
+			}
+			if tc.want == nil {
+				return
+			}
+
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+			err = tg.Draw(c, tc.meta)
+			if (err != nil) != tc.wantDrawErr {
+				t.Errorf("Draw => unexpected error: %v, wantDrawErr: %v", err, tc.wantDrawErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+		})
+	}
+}
+
+func TestOptions(t *testing.T) {
+	tests := []struct {
+		desc  string
+		label string
+		want  widgetapi.Options
+	}{
+		{
+			desc:  "width is based on the label",
+			label: "wifi",
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{4, 1},
+				MaximumSize:  image.Point{9, 1},
+				WantKeyboard: widgetapi.KeyScopeFocused,
+				WantMouse:    widgetapi.MouseScopeWidget,
+			},
+		},
+		{
+			desc: "only the switch without a label",
+			want: widgetapi.Options{
+				MinimumSize:  image.Point{4, 1},
+				MaximumSize:  image.Point{4, 1},
+				WantKeyboard: widgetapi.KeyScopeFocused,
+				WantMouse:    widgetapi.MouseScopeWidget,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			tg, err := New(tc.label)
+			if err != nil {
+				t.Fatalf("New => unexpected error: %v", err)
+			}
+
+			got := tg.Options()
+			if diff := pretty.Compare(tc.want, got); diff != "" {
+				t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
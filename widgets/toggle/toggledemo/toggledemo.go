@@ -0,0 +1,104 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary toggledemo shows the functionality of the toggle widget.
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/termbox"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgets/text"
+	"github.com/mum4k/termdash/widgets/toggle"
+)
+
+// newToggle returns a switch that logs its state changes into the text
+// widget.
+func newToggle(label string, log *text.Text, opts ...toggle.Option) (*toggle.Toggle, error) {
+	opts = append(opts, toggle.OnChange(func(on bool) error {
+		return log.Write(fmt.Sprintf("%q on: %v\n", label, on))
+	}))
+	return toggle.New(label, opts...)
+}
+
+func main() {
+	t, err := termbox.New()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	log, err := text.New(text.RollContent())
+	if err != nil {
+		panic(err)
+	}
+
+	maintenance, err := newToggle("Maintenance mode", log)
+	if err != nil {
+		panic(err)
+	}
+	debug, err := newToggle("Debug logging", log,
+		toggle.On(),
+		toggle.OnColor(cell.ColorBlue),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	c, err := container.New(
+		t,
+		container.Border(linestyle.Light),
+		container.BorderTitle("PRESS Q TO QUIT"),
+		container.SplitHorizontal(
+			container.Top(
+				container.SplitHorizontal(
+					container.Top(
+						container.PlaceWidget(maintenance),
+					),
+					container.Bottom(
+						container.PlaceWidget(debug),
+					),
+				),
+			),
+			container.Bottom(
+				container.Border(linestyle.Light),
+				container.BorderTitle("Click or use Space and arrows on a focused switch"),
+				container.PlaceWidget(log),
+			),
+			container.SplitPercent(30),
+		),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	quitter := func(k *terminalapi.Keyboard) {
+		if k.Key == 'q' || k.Key == 'Q' {
+			cancel()
+		}
+	}
+
+	if err := termdash.Run(ctx, t, c, termdash.KeyboardSubscriber(quitter), termdash.RedrawInterval(100*time.Millisecond)); err != nil {
+		panic(err)
+	}
+}
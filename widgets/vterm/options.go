@@ -0,0 +1,88 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vterm
+
+// options.go contains configurable options for VTerm.
+
+import (
+	"errors"
+	"strings"
+)
+
+// Option is used to provide options.
+type Option interface {
+	// set sets the provided option.
+	set(*options)
+}
+
+// option implements Option.
+type option func(*options)
+
+// set implements Option.set.
+func (o option) set(opts *options) {
+	o(opts)
+}
+
+// options holds the provided options.
+type options struct {
+	term   string
+	onExit ExitFn
+}
+
+// validate validates the provided options.
+func (o *options) validate() error {
+	if o.term == "" {
+		return errors.New("the Term option cannot be empty")
+	}
+	if strings.ContainsAny(o.term, "=\x00") {
+		return errors.New("the Term option cannot contain '=' or NUL characters")
+	}
+	return nil
+}
+
+// newOptions returns options with the default values set.
+func newOptions() *options {
+	return &options{
+		term: DefaultTerm,
+	}
+}
+
+// DefaultTerm is the default value of the TERM environment variable of the
+// command.
+const DefaultTerm = "xterm-256color"
+
+// Term sets the value of the TERM environment variable of the command.
+// Only applies if the environment of the provided command is nil, i.e. when
+// the command inherits the environment of the current process.
+// Defaults to DefaultTerm.
+func Term(term string) Option {
+	return option(func(opts *options) {
+		opts.term = term
+	})
+}
+
+// ExitFn is called when the command exits.
+// The argument is the error returned by exec.Cmd.Wait, nil if the command
+// exited successfully.
+type ExitFn func(error)
+
+// OnExit sets a function that is called when the command exits.
+// The function is called from a goroutine of the widget and must be
+// thread-safe.
+func OnExit(fn ExitFn) Option {
+	return option(func(opts *options) {
+		opts.onExit = fn
+	})
+}
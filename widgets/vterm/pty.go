@@ -0,0 +1,47 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+// +build !windows
+
+package vterm
+
+// pty.go starts commands in a pseudo-terminal.
+
+import (
+	"image"
+	"os"
+	"os/exec"
+
+	"github.com/creack/pty"
+)
+
+// startPty starts the command in a pseudo-terminal of the size.
+// Returns the controlling side of the pseudo-terminal.
+func startPty(cmd *exec.Cmd, size image.Point) (*os.File, error) {
+	return pty.StartWithSize(cmd, winsize(size))
+}
+
+// setPtySize changes the size of the pseudo-terminal.
+func setPtySize(f *os.File, size image.Point) error {
+	return pty.Setsize(f, winsize(size))
+}
+
+// winsize converts the size to the window size of the pseudo-terminal.
+func winsize(size image.Point) *pty.Winsize {
+	return &pty.Winsize{
+		Cols: uint16(size.X),
+		Rows: uint16(size.Y),
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+// +build !windows
+
+package vterm
+
+import (
+	"image"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+func TestNewRunsCommand(t *testing.T) {
+	done := make(chan error, 1)
+	vt, err := New(exec.Command("sh", "-c", "printf 'hello\\n'; stty size"), OnExit(func(err error) {
+		done <- err
+	}))
+	if err != nil {
+		t.Fatalf("New => unexpected error: %v", err)
+	}
+	defer vt.Close()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("OnExit => unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the command didn't exit")
+	}
+
+	c, err := canvas.New(image.Rect(0, 0, 80, 24))
+	if err != nil {
+		t.Fatalf("canvas.New => unexpected error: %v", err)
+	}
+	if err := vt.Draw(c, &widgetapi.Meta{}); err != nil {
+		t.Fatalf("Draw => unexpected error: %v", err)
+	}
+
+	vt.mu.Lock()
+	got := vt.screen.String()
+	vt.mu.Unlock()
+	if want := "hello\n24 80\n"; !strings.HasPrefix(got, want) {
+		t.Errorf("screen => %q, want prefix %q", got, want)
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vterm
+
+// pty_windows.go contains stubs for Windows which doesn't support the
+// pseudo-terminals this widget uses.
+
+import (
+	"errors"
+	"image"
+	"os"
+	"os/exec"
+)
+
+// errUnsupported is returned on platforms without pseudo-terminals.
+var errUnsupported = errors.New("the VTerm widget isn't supported on Windows")
+
+// startPty starts the command in a pseudo-terminal of the size.
+func startPty(cmd *exec.Cmd, size image.Point) (*os.File, error) {
+	return nil, errUnsupported
+}
+
+// setPtySize changes the size of the pseudo-terminal.
+func setPtySize(f *os.File, size image.Point) error {
+	return errUnsupported
+}
@@ -0,0 +1,776 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vterm
+
+// screen.go contains a terminal emulator that interprets the output of
+// programs written for VT100 and xterm compatible terminals.
+
+import (
+	"fmt"
+	"image"
+	"unicode/utf8"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/runewidth"
+)
+
+// attrs are the attributes of the text written to the screen.
+type attrs struct {
+	fg        cell.Color
+	bg        cell.Color
+	bold      bool
+	underline bool
+	inverse   bool
+}
+
+// cellOpts returns the cell options that display the attributes.
+func (a attrs) cellOpts() []cell.Option {
+	opts := []cell.Option{cell.FgColor(a.fg), cell.BgColor(a.bg)}
+	if a.bold {
+		opts = append(opts, cell.Bold())
+	}
+	if a.underline {
+		opts = append(opts, cell.Underline())
+	}
+	if a.inverse {
+		opts = append(opts, cell.Inverse())
+	}
+	return opts
+}
+
+// glyph is a single cell on the screen.
+type glyph struct {
+	// r is the displayed rune, zero for an empty cell.
+	r rune
+	// wideCont indicates that the cell is covered by a full-width rune in
+	// the cell on its left.
+	wideCont bool
+	// attrs are the attributes of the cell.
+	attrs attrs
+}
+
+// parserState is the state of the parser of control sequences.
+type parserState int
+
+const (
+	// stateGround is the state while printing text.
+	stateGround parserState = iota
+	// stateEscape is the state after the escape character.
+	stateEscape
+	// stateEscapeCharset is the state after an escape sequence that selects
+	// a character set and expects one more character.
+	stateEscapeCharset
+	// stateCSI is the state within a control sequence introduced by ESC [.
+	stateCSI
+	// stateOSC is the state within an operating system command introduced
+	// by ESC ].
+	stateOSC
+	// stateOSCEscape is the state after an escape character within an
+	// operating system command.
+	stateOSCEscape
+)
+
+// tabWidth is the distance between tab stops.
+const tabWidth = 8
+
+// cursor is the position of the cursor and the attributes saved with it.
+type cursor struct {
+	pos   image.Point
+	attrs attrs
+}
+
+// screen is a grid of cells that is updated by the output of a program.
+// The screen isn't thread-safe.
+type screen struct {
+	// size is the number of columns and rows.
+	size image.Point
+	// lines are the rows of cells.
+	lines [][]glyph
+
+	// cur is the position of the cursor.
+	cur image.Point
+	// pendingWrap indicates that a rune was written to the last column and
+	// the next rune wraps to the next line.
+	pendingWrap bool
+	// attrs are the attributes of newly written text.
+	attrs attrs
+	// saved is the cursor saved by the DECSC sequence.
+	saved cursor
+
+	// top and bottom are the rows of the scrolling region, bottom is
+	// exclusive.
+	top, bottom int
+
+	// cursorVisible indicates whether the cursor should be displayed.
+	cursorVisible bool
+	// appCursor indicates that the cursor keys send application sequences.
+	appCursor bool
+	// autowrap indicates that text wraps at the end of the line.
+	autowrap bool
+
+	// mainLines are the lines of the main screen while the alternate screen
+	// is active, nil otherwise.
+	mainLines [][]glyph
+
+	// state is the state of the parser.
+	state parserState
+	// params are the numeric parameters of the current control sequence.
+	params []int
+	// private is the private marker of the current control sequence.
+	private byte
+	// partial is an incomplete UTF-8 encoded rune.
+	partial []byte
+
+	// responses are bytes the terminal sends back to the program in reply
+	// to queries.
+	responses []byte
+}
+
+// newScreen returns a new screen of the provided size.
+func newScreen(size image.Point) *screen {
+	s := &screen{}
+	s.reset(size)
+	return s
+}
+
+// reset sets the screen to its initial state.
+func (s *screen) reset(size image.Point) {
+	*s = screen{
+		size:          size,
+		lines:         blankLines(size, attrs{}),
+		bottom:        size.Y,
+		cursorVisible: true,
+		autowrap:      true,
+	}
+}
+
+// blankLines returns empty lines of the size.
+func blankLines(size image.Point, a attrs) [][]glyph {
+	lines := make([][]glyph, size.Y)
+	for i := range lines {
+		lines[i] = blankLine(size.X, a)
+	}
+	return lines
+}
+
+// blankLine returns an empty line of the width.
+func blankLine(width int, a attrs) []glyph {
+	line := make([]glyph, width)
+	for i := range line {
+		line[i] = glyph{attrs: a}
+	}
+	return line
+}
+
+// blank returns the attributes of erased cells. Erased cells keep the
+// background color.
+func (s *screen) blank() attrs {
+	return attrs{bg: s.attrs.bg}
+}
+
+// takeResponses returns and forgets the responses to queries.
+func (s *screen) takeResponses() []byte {
+	r := s.responses
+	s.responses = nil
+	return r
+}
+
+// resize changes the size of the screen. Content that doesn't fit is trimmed
+// keeping the row with the cursor visible.
+func (s *screen) resize(size image.Point) {
+	if size == s.size {
+		return
+	}
+	drop := 0
+	if s.cur.Y >= size.Y {
+		drop = s.cur.Y - size.Y + 1
+	}
+	s.lines = resizeLines(s.lines, size, drop)
+	if s.mainLines != nil {
+		s.mainLines = resizeLines(s.mainLines, size, drop)
+	}
+	s.size = size
+	s.cur.Y -= drop
+	s.top, s.bottom = 0, size.Y
+	s.pendingWrap = false
+	s.cur = s.clamp(s.cur)
+	s.saved.pos = s.clamp(s.saved.pos)
+}
+
+// resizeLines returns the lines resized to the size after dropping the
+// provided number of lines from the top.
+func resizeLines(lines [][]glyph, size image.Point, drop int) [][]glyph {
+	lines = lines[drop:]
+	res := make([][]glyph, size.Y)
+	for y := range res {
+		line := blankLine(size.X, attrs{})
+		if y < len(lines) {
+			copy(line, lines[y])
+		}
+		res[y] = line
+	}
+	return res
+}
+
+// clamp returns the point moved within the screen.
+func (s *screen) clamp(p image.Point) image.Point {
+	if p.X >= s.size.X {
+		p.X = s.size.X - 1
+	}
+	if p.Y >= s.size.Y {
+		p.Y = s.size.Y - 1
+	}
+	if p.X < 0 {
+		p.X = 0
+	}
+	if p.Y < 0 {
+		p.Y = 0
+	}
+	return p
+}
+
+// moveTo moves the cursor to the point within the screen.
+func (s *screen) moveTo(p image.Point) {
+	s.cur = s.clamp(p)
+	s.pendingWrap = false
+}
+
+// write interprets the output of the program.
+func (s *screen) write(data []byte) {
+	for _, b := range data {
+		if len(s.partial) > 0 || (s.state == stateGround && b >= utf8.RuneSelf) {
+			s.partial = append(s.partial, b)
+			if !utf8.FullRune(s.partial) {
+				continue
+			}
+			r, _ := utf8.DecodeRune(s.partial)
+			s.partial = s.partial[:0]
+			s.print(r)
+			continue
+		}
+		s.parse(b)
+	}
+}
+
+// parse advances the parser by one byte.
+func (s *screen) parse(b byte) {
+	switch s.state {
+	case stateGround:
+		switch {
+		case b == 0x1b:
+			s.state = stateEscape
+		case b < 0x20 || b == 0x7f:
+			s.control(b)
+		default:
+			s.print(rune(b))
+		}
+
+	case stateEscape:
+		s.escape(b)
+
+	case stateEscapeCharset:
+		s.state = stateGround
+
+	case stateCSI:
+		switch {
+		case b >= '0' && b <= '9':
+			if len(s.params) == 0 {
+				s.params = append(s.params, 0)
+			}
+			last := len(s.params) - 1
+			if s.params[last] < 1e5 {
+				s.params[last] = s.params[last]*10 + int(b-'0')
+			}
+		case b == ';':
+			if len(s.params) == 0 {
+				s.params = append(s.params, 0)
+			}
+			s.params = append(s.params, 0)
+		case b == '?' || b == '>' || b == '=' || b == '<':
+			s.private = b
+		case b >= 0x40 && b <= 0x7e:
+			s.state = stateGround
+			s.csi(b)
+		case b == 0x1b:
+			s.state = stateEscape
+		case b < 0x20:
+			s.control(b)
+		}
+		// Intermediate bytes are ignored.
+
+	case stateOSC:
+		switch b {
+		case 0x07:
+			s.state = stateGround
+		case 0x1b:
+			s.state = stateOSCEscape
+		}
+		// The commands, e.g. setting the window title, are ignored.
+
+	case stateOSCEscape:
+		s.state = stateGround
+	}
+}
+
+// control executes a control character.
+func (s *screen) control(b byte) {
+	switch b {
+	case '\r':
+		s.moveTo(image.Point{0, s.cur.Y})
+	case '\n', 0x0b, 0x0c:
+		s.lineFeed()
+	case '\b':
+		s.moveTo(image.Point{s.cur.X - 1, s.cur.Y})
+	case '\t':
+		s.moveTo(image.Point{(s.cur.X/tabWidth + 1) * tabWidth, s.cur.Y})
+	}
+	// Other control characters like the bell are ignored.
+}
+
+// escape executes an escape sequence.
+func (s *screen) escape(b byte) {
+	s.state = stateGround
+	switch b {
+	case '[':
+		s.state = stateCSI
+		s.params = s.params[:0]
+		s.private = 0
+	case ']':
+		s.state = stateOSC
+	case '(', ')', '*', '+', '#', '%':
+		s.state = stateEscapeCharset
+	case '7':
+		s.saveCursor()
+	case '8':
+		s.restoreCursor()
+	case 'D':
+		s.lineFeed()
+	case 'E':
+		s.lineFeed()
+		s.moveTo(image.Point{0, s.cur.Y})
+	case 'M':
+		s.reverseIndex()
+	case 'c':
+		s.reset(s.size)
+	}
+	// Other sequences like keypad modes are ignored.
+}
+
+// param returns the numeric parameter at the index or the default value if
+// the parameter wasn't provided or is zero.
+func (s *screen) param(i, def int) int {
+	if i >= len(s.params) || s.params[i] == 0 {
+		return def
+	}
+	return s.params[i]
+}
+
+// csi executes a control sequence with the final byte.
+func (s *screen) csi(final byte) {
+	if s.private == '?' {
+		switch final {
+		case 'h':
+			s.setModes(true)
+		case 'l':
+			s.setModes(false)
+		}
+		return
+	}
+	if s.private != 0 {
+		// Secondary queries aren't supported.
+		return
+	}
+
+	n := s.param(0, 1)
+	switch final {
+	case 'A':
+		s.moveTo(image.Point{s.cur.X, s.cur.Y - n})
+	case 'B', 'e':
+		s.moveTo(image.Point{s.cur.X, s.cur.Y + n})
+	case 'C', 'a':
+		s.moveTo(image.Point{s.cur.X + n, s.cur.Y})
+	case 'D':
+		s.moveTo(image.Point{s.cur.X - n, s.cur.Y})
+	case 'E':
+		s.moveTo(image.Point{0, s.cur.Y + n})
+	case 'F':
+		s.moveTo(image.Point{0, s.cur.Y - n})
+	case 'G', '`':
+		s.moveTo(image.Point{n - 1, s.cur.Y})
+	case 'H', 'f':
+		s.moveTo(image.Point{s.param(1, 1) - 1, n - 1})
+	case 'd':
+		s.moveTo(image.Point{s.cur.X, n - 1})
+	case 'J':
+		s.eraseDisplay(s.param(0, 0))
+	case 'K':
+		s.eraseLine(s.param(0, 0))
+	case 'L':
+		if s.cur.Y >= s.top && s.cur.Y < s.bottom {
+			s.scrollDown(s.cur.Y, s.bottom, n)
+			s.moveTo(image.Point{0, s.cur.Y})
+		}
+	case 'M':
+		if s.cur.Y >= s.top && s.cur.Y < s.bottom {
+			s.scrollUp(s.cur.Y, s.bottom, n)
+			s.moveTo(image.Point{0, s.cur.Y})
+		}
+	case '@':
+		s.insertBlanks(n)
+	case 'P':
+		s.deleteChars(n)
+	case 'X':
+		s.erase(s.cur.Y, s.cur.X, s.cur.X+n)
+	case 'S':
+		s.scrollUp(s.top, s.bottom, n)
+	case 'T':
+		s.scrollDown(s.top, s.bottom, n)
+	case 'r':
+		top, bottom := s.param(0, 1)-1, s.param(1, s.size.Y)
+		if bottom > s.size.Y {
+			bottom = s.size.Y
+		}
+		if top < bottom-1 {
+			s.top, s.bottom = top, bottom
+			s.moveTo(image.Point{0, 0})
+		}
+	case 'm':
+		s.sgr()
+	case 's':
+		s.saveCursor()
+	case 'u':
+		s.restoreCursor()
+	case 'n':
+		switch s.param(0, 0) {
+		case 5:
+			s.responses = append(s.responses, "\x1b[0n"...)
+		case 6:
+			s.responses = append(s.responses, fmt.Sprintf("\x1b[%d;%dR", s.cur.Y+1, s.cur.X+1)...)
+		}
+	case 'c':
+		if s.param(0, 0) == 0 {
+			// Identifies as a VT100 with advanced video option.
+			s.responses = append(s.responses, "\x1b[?1;2c"...)
+		}
+	}
+}
+
+// setModes sets or resets the private modes in the parameters.
+func (s *screen) setModes(set bool) {
+	for _, mode := range s.params {
+		switch mode {
+		case 1:
+			s.appCursor = set
+		case 7:
+			s.autowrap = set
+		case 25:
+			s.cursorVisible = set
+		case 47, 1047:
+			s.altScreen(set)
+		case 1049:
+			if set {
+				s.saveCursor()
+				s.altScreen(true)
+			} else {
+				s.altScreen(false)
+				s.restoreCursor()
+			}
+		}
+	}
+}
+
+// altScreen switches to or from the alternate screen, which starts empty and
+// is discarded when the main screen is restored.
+func (s *screen) altScreen(enter bool) {
+	if enter == (s.mainLines != nil) {
+		return
+	}
+	if enter {
+		s.mainLines = s.lines
+		s.lines = blankLines(s.size, attrs{})
+		return
+	}
+	s.lines = s.mainLines
+	s.mainLines = nil
+}
+
+// sgr sets the attributes of text based on the parameters of the Select
+// Graphic Rendition sequence.
+func (s *screen) sgr() {
+	if len(s.params) == 0 {
+		s.attrs = attrs{}
+		return
+	}
+	for i := 0; i < len(s.params); i++ {
+		p := s.params[i]
+		switch {
+		case p == 0:
+			s.attrs = attrs{}
+		case p == 1:
+			s.attrs.bold = true
+		case p == 4:
+			s.attrs.underline = true
+		case p == 7:
+			s.attrs.inverse = true
+		case p == 22:
+			s.attrs.bold = false
+		case p == 24:
+			s.attrs.underline = false
+		case p == 27:
+			s.attrs.inverse = false
+		case p >= 30 && p <= 37:
+			s.attrs.fg = cell.ColorNumber(p - 30)
+		case p == 38:
+			c, consumed := s.extendedColor(i + 1)
+			s.attrs.fg = c
+			i += consumed
+		case p == 39:
+			s.attrs.fg = cell.ColorDefault
+		case p >= 40 && p <= 47:
+			s.attrs.bg = cell.ColorNumber(p - 40)
+		case p == 48:
+			c, consumed := s.extendedColor(i + 1)
+			s.attrs.bg = c
+			i += consumed
+		case p == 49:
+			s.attrs.bg = cell.ColorDefault
+		case p >= 90 && p <= 97:
+			s.attrs.fg = cell.ColorNumber(p - 90 + 8)
+		case p >= 100 && p <= 107:
+			s.attrs.bg = cell.ColorNumber(p - 100 + 8)
+		}
+		// Other attributes like italic or blinking are ignored.
+	}
+}
+
+// extendedColor parses the 256 color or the 24 bit color starting at the
+// index of the parameters. Returns the color and the number of consumed
+// parameters.
+func (s *screen) extendedColor(i int) (cell.Color, int) {
+	if i >= len(s.params) {
+		return cell.ColorDefault, 0
+	}
+	switch s.params[i] {
+	case 5:
+		if i+1 < len(s.params) {
+			return cell.ColorNumber(s.params[i+1]), 2
+		}
+	case 2:
+		if i+3 < len(s.params) {
+			return cell.ColorRGB24(s.params[i+1], s.params[i+2], s.params[i+3]), 4
+		}
+	}
+	return cell.ColorDefault, len(s.params) - i
+}
+
+// saveCursor saves the position of the cursor and the attributes.
+func (s *screen) saveCursor() {
+	s.saved = cursor{pos: s.cur, attrs: s.attrs}
+}
+
+// restoreCursor restores the position of the cursor and the attributes.
+func (s *screen) restoreCursor() {
+	s.moveTo(s.saved.pos)
+	s.attrs = s.saved.attrs
+}
+
+// lineFeed moves the cursor down, scrolling the scrolling region if the
+// cursor is on its last row.
+func (s *screen) lineFeed() {
+	s.pendingWrap = false
+	switch {
+	case s.cur.Y == s.bottom-1:
+		s.scrollUp(s.top, s.bottom, 1)
+	case s.cur.Y < s.size.Y-1:
+		s.cur.Y++
+	}
+}
+
+// reverseIndex moves the cursor up, scrolling the scrolling region down if
+// the cursor is on its first row.
+func (s *screen) reverseIndex() {
+	s.pendingWrap = false
+	switch {
+	case s.cur.Y == s.top:
+		s.scrollDown(s.top, s.bottom, 1)
+	case s.cur.Y > 0:
+		s.cur.Y--
+	}
+}
+
+// scrollUp moves the rows in range top <= y < bottom up by n rows, adding
+// blank rows at the bottom.
+func (s *screen) scrollUp(top, bottom, n int) {
+	if n > bottom-top {
+		n = bottom - top
+	}
+	copy(s.lines[top:bottom], s.lines[top+n:bottom])
+	for y := bottom - n; y < bottom; y++ {
+		s.lines[y] = blankLine(s.size.X, s.blank())
+	}
+}
+
+// scrollDown moves the rows in range top <= y < bottom down by n rows,
+// adding blank rows at the top.
+func (s *screen) scrollDown(top, bottom, n int) {
+	if n > bottom-top {
+		n = bottom - top
+	}
+	copy(s.lines[top+n:bottom], s.lines[top:bottom-n])
+	for y := top; y < top+n; y++ {
+		s.lines[y] = blankLine(s.size.X, s.blank())
+	}
+}
+
+// erase blanks the cells of the row in range from <= x < to.
+func (s *screen) erase(y, from, to int) {
+	if from < 0 {
+		from = 0
+	}
+	if to > s.size.X {
+		to = s.size.X
+	}
+	for x := from; x < to; x++ {
+		s.lines[y][x] = glyph{attrs: s.blank()}
+	}
+}
+
+// eraseDisplay executes the Erase in Display sequence in the mode.
+func (s *screen) eraseDisplay(mode int) {
+	switch mode {
+	case 0:
+		s.erase(s.cur.Y, s.cur.X, s.size.X)
+		for y := s.cur.Y + 1; y < s.size.Y; y++ {
+			s.erase(y, 0, s.size.X)
+		}
+	case 1:
+		for y := 0; y < s.cur.Y; y++ {
+			s.erase(y, 0, s.size.X)
+		}
+		s.erase(s.cur.Y, 0, s.cur.X+1)
+	case 2, 3:
+		for y := 0; y < s.size.Y; y++ {
+			s.erase(y, 0, s.size.X)
+		}
+	}
+}
+
+// eraseLine executes the Erase in Line sequence in the mode.
+func (s *screen) eraseLine(mode int) {
+	switch mode {
+	case 0:
+		s.erase(s.cur.Y, s.cur.X, s.size.X)
+	case 1:
+		s.erase(s.cur.Y, 0, s.cur.X+1)
+	case 2:
+		s.erase(s.cur.Y, 0, s.size.X)
+	}
+}
+
+// insertBlanks inserts n blank cells at the cursor, moving the rest of the
+// row to the right.
+func (s *screen) insertBlanks(n int) {
+	line := s.lines[s.cur.Y]
+	if n > s.size.X-s.cur.X {
+		n = s.size.X - s.cur.X
+	}
+	copy(line[s.cur.X+n:], line[s.cur.X:])
+	s.erase(s.cur.Y, s.cur.X, s.cur.X+n)
+}
+
+// deleteChars deletes n cells at the cursor, moving the rest of the row to
+// the left.
+func (s *screen) deleteChars(n int) {
+	line := s.lines[s.cur.Y]
+	if n > s.size.X-s.cur.X {
+		n = s.size.X - s.cur.X
+	}
+	copy(line[s.cur.X:], line[s.cur.X+n:])
+	s.erase(s.cur.Y, s.size.X-n, s.size.X)
+}
+
+// print writes the rune at the cursor and advances the cursor.
+func (s *screen) print(r rune) {
+	width := runewidth.RuneWidth(r)
+	if width == 0 {
+		// Combining characters aren't supported.
+		return
+	}
+	if width > s.size.X {
+		return
+	}
+
+	if s.pendingWrap || s.cur.X+width > s.size.X {
+		if !s.autowrap {
+			s.cur.X = s.size.X - width
+		} else {
+			s.lineFeed()
+			s.cur.X = 0
+		}
+		s.pendingWrap = false
+	}
+
+	s.set(s.cur, glyph{r: r, attrs: s.attrs})
+	if width == 2 {
+		s.set(image.Point{s.cur.X + 1, s.cur.Y}, glyph{wideCont: true, attrs: s.attrs})
+	}
+
+	if s.cur.X+width >= s.size.X {
+		s.cur.X = s.size.X - 1
+		s.pendingWrap = s.autowrap
+		return
+	}
+	s.cur.X += width
+}
+
+// set sets the cell at the point, blanking any full-width rune it partially
+// overwrites.
+func (s *screen) set(p image.Point, g glyph) {
+	line := s.lines[p.Y]
+	if line[p.X].wideCont && p.X > 0 {
+		line[p.X-1] = glyph{attrs: line[p.X-1].attrs}
+	}
+	if !g.wideCont && p.X+1 < s.size.X && line[p.X+1].wideCont {
+		line[p.X+1] = glyph{attrs: line[p.X+1].attrs}
+	}
+	line[p.X] = g
+}
+
+// String returns the runes on the screen, one line per row with trailing
+// empty cells removed. Used for testing and debugging.
+func (s *screen) String() string {
+	var b []rune
+	for y, line := range s.lines {
+		if y > 0 {
+			b = append(b, '\n')
+		}
+		var row []rune
+		for _, g := range line {
+			switch {
+			case g.wideCont:
+			case g.r == 0:
+				row = append(row, ' ')
+			default:
+				row = append(row, g.r)
+			}
+		}
+		end := len(row)
+		for end > 0 && row[end-1] == ' ' {
+			end--
+		}
+		b = append(b, row[:end]...)
+	}
+	return string(b)
+}
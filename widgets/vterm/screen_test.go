@@ -0,0 +1,433 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vterm
+
+import (
+	"image"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+)
+
+func TestScreenText(t *testing.T) {
+	tests := []struct {
+		desc       string
+		size       image.Point
+		input      string
+		want       string
+		wantCursor image.Point
+	}{
+		{
+			desc:       "prints text",
+			size:       image.Point{5, 2},
+			input:      "abc",
+			want:       "abc\n",
+			wantCursor: image.Point{3, 0},
+		},
+		{
+			desc:       "carriage return and line feed",
+			size:       image.Point{5, 3},
+			input:      "ab\r\ncd\nef",
+			want:       "ab\ncd\n  ef",
+			wantCursor: image.Point{4, 2},
+		},
+		{
+			desc:       "wraps at the end of the line",
+			size:       image.Point{3, 3},
+			input:      "abcdef",
+			want:       "abc\ndef\n",
+			wantCursor: image.Point{2, 1},
+		},
+		{
+			desc:       "carriage return cancels the pending wrap",
+			size:       image.Point{3, 2},
+			input:      "abc\rd",
+			want:       "dbc\n",
+			wantCursor: image.Point{1, 0},
+		},
+		{
+			desc:       "doesn't wrap when autowrap is disabled",
+			size:       image.Point{3, 2},
+			input:      "\x1b[?7labcdef",
+			want:       "abf\n",
+			wantCursor: image.Point{2, 0},
+		},
+		{
+			desc:       "scrolls at the bottom",
+			size:       image.Point{3, 2},
+			input:      "a\r\nb\r\nc",
+			want:       "b\nc",
+			wantCursor: image.Point{1, 1},
+		},
+		{
+			desc:       "backspace and tab",
+			size:       image.Point{12, 1},
+			input:      "ab\bc\td",
+			want:       "ac      d",
+			wantCursor: image.Point{9, 0},
+		},
+		{
+			desc:       "full-width runes",
+			size:       image.Point{5, 2},
+			input:      "a世界",
+			want:       "a世界\n",
+			wantCursor: image.Point{4, 0},
+		},
+		{
+			desc:       "full-width rune wraps if it doesn't fit",
+			size:       image.Point{4, 2},
+			input:      "abc世",
+			want:       "abc\n世",
+			wantCursor: image.Point{2, 1},
+		},
+		{
+			desc:       "overwriting half of a full-width rune erases it",
+			size:       image.Point{4, 1},
+			input:      "世\x1b[2Ga",
+			want:       " a",
+			wantCursor: image.Point{2, 0},
+		},
+		{
+			desc:       "UTF-8 split across writes",
+			size:       image.Point{4, 1},
+			input:      "\xe4\xb8\x96",
+			want:       "世",
+			wantCursor: image.Point{2, 0},
+		},
+		{
+			desc:       "cursor movement",
+			size:       image.Point{5, 5},
+			input:      "\x1b[3;4Ha\x1b[2Ab\x1b[3Dc\x1b[Bd\x1b[5Ge",
+			want:       " c  b\n  d e\n   a\n\n",
+			wantCursor: image.Point{4, 1},
+		},
+		{
+			desc:       "cursor movement is limited to the screen",
+			size:       image.Point{3, 3},
+			input:      "\x1b[10;10Ha\x1b[20Ab\x1b[20Dc",
+			want:       "c b\n\n  a",
+			wantCursor: image.Point{1, 0},
+		},
+		{
+			desc:       "next and previous line, vertical position",
+			size:       image.Point{3, 4},
+			input:      "ab\x1b[2Ec\x1b[Fd\x1b[4de",
+			want:       "ab\nd\nc\n e",
+			wantCursor: image.Point{2, 3},
+		},
+		{
+			desc:       "erase in display below",
+			size:       image.Point{3, 3},
+			input:      "abc\r\ndef\r\nghi\x1b[2;2H\x1b[J",
+			want:       "abc\nd\n",
+			wantCursor: image.Point{1, 1},
+		},
+		{
+			desc:       "erase in display above",
+			size:       image.Point{3, 3},
+			input:      "abc\r\ndef\r\nghi\x1b[2;2H\x1b[1J",
+			want:       "\n  f\nghi",
+			wantCursor: image.Point{1, 1},
+		},
+		{
+			desc:       "erase in display all",
+			size:       image.Point{3, 3},
+			input:      "abc\r\ndef\x1b[2J",
+			want:       "\n\n",
+			wantCursor: image.Point{2, 1},
+		},
+		{
+			desc:       "erase in line",
+			size:       image.Point{5, 3},
+			input:      "abcde\r\nabcde\r\nabcde\x1b[1;3H\x1b[K\x1b[2;3H\x1b[1K\x1b[3;3H\x1b[2K",
+			want:       "ab\n   de\n",
+			wantCursor: image.Point{2, 2},
+		},
+		{
+			desc:       "insert and delete characters",
+			size:       image.Point{5, 2},
+			input:      "abcde\x1b[1;2H\x1b[2@\r\nabcde\x1b[2;2H\x1b[2P",
+			want:       "a  bc\nade",
+			wantCursor: image.Point{1, 1},
+		},
+		{
+			desc:       "erase characters",
+			size:       image.Point{5, 1},
+			input:      "abcde\x1b[2G\x1b[3X",
+			want:       "a   e",
+			wantCursor: image.Point{1, 0},
+		},
+		{
+			desc:       "insert and delete lines",
+			size:       image.Point{2, 4},
+			input:      "a\r\nb\r\nc\r\nd\x1b[2H\x1b[L\x1b[4H\x1b[M",
+			want:       "a\n\nb\n",
+			wantCursor: image.Point{0, 3},
+		},
+		{
+			desc:       "scroll up and down",
+			size:       image.Point{2, 3},
+			input:      "a\r\nb\r\nc\x1b[S\x1b[2T",
+			want:       "\n\nb",
+			wantCursor: image.Point{1, 2},
+		},
+		{
+			desc:       "scrolling region",
+			size:       image.Point{2, 4},
+			input:      "a\r\nb\r\nc\r\nd\x1b[2;3r\x1b[3H\ne\n",
+			want:       "a\ne\n\nd",
+			wantCursor: image.Point{1, 2},
+		},
+		{
+			desc:       "reverse index scrolls the region down",
+			size:       image.Point{2, 4},
+			input:      "a\r\nb\r\nc\r\nd\x1b[2;3r\x1b[2H\x1bMe",
+			want:       "a\ne\nb\nd",
+			wantCursor: image.Point{1, 1},
+		},
+		{
+			desc:       "invalid scrolling region is ignored",
+			size:       image.Point{2, 3},
+			input:      "\x1b[3;3r\x1b[3Ha\n",
+			want:       "\na\n",
+			wantCursor: image.Point{1, 2},
+		},
+		{
+			desc:       "saves and restores the cursor",
+			size:       image.Point{4, 2},
+			input:      "a\x1b7\r\nb\x1b8c\x1b[2;3H\x1b[sd\x1b[u\x1b[De",
+			want:       "ac\nbed",
+			wantCursor: image.Point{2, 1},
+		},
+		{
+			desc:       "alternate screen",
+			size:       image.Point{4, 2},
+			input:      "ab\x1b[?1049hcd\x1b[?1049l",
+			want:       "ab\n",
+			wantCursor: image.Point{2, 0},
+		},
+		{
+			desc:       "draws on the alternate screen",
+			size:       image.Point{4, 2},
+			input:      "ab\x1b[?1049h\x1b[Hcd",
+			want:       "cd\n",
+			wantCursor: image.Point{2, 0},
+		},
+		{
+			desc:       "ignores operating system commands",
+			size:       image.Point{4, 1},
+			input:      "\x1b]0;title\x07a\x1b]2;title\x1b\\b",
+			want:       "ab",
+			wantCursor: image.Point{2, 0},
+		},
+		{
+			desc:       "ignores character set selection",
+			size:       image.Point{4, 1},
+			input:      "\x1b(Ba\x1b)0b",
+			want:       "ab",
+			wantCursor: image.Point{2, 0},
+		},
+		{
+			desc:       "reset",
+			size:       image.Point{4, 1},
+			input:      "ab\x1bc",
+			want:       "",
+			wantCursor: image.Point{0, 0},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			s := newScreen(tc.size)
+			for i := 0; i < len(tc.input); i++ {
+				// Write byte by byte to exercise sequences split across
+				// writes.
+				s.write([]byte{tc.input[i]})
+			}
+			if got := s.String(); got != tc.want {
+				t.Errorf("String => %q, want %q", got, tc.want)
+			}
+			if s.cur != tc.wantCursor {
+				t.Errorf("cursor => %v, want %v", s.cur, tc.wantCursor)
+			}
+		})
+	}
+}
+
+func TestScreenAttrs(t *testing.T) {
+	tests := []struct {
+		desc  string
+		input string
+		want  attrs
+	}{
+		{
+			desc:  "default attributes",
+			input: "a",
+			want:  attrs{},
+		},
+		{
+			desc:  "bold, underline and inverse",
+			input: "\x1b[1;4;7ma",
+			want:  attrs{bold: true, underline: true, inverse: true},
+		},
+		{
+			desc:  "resets individual attributes",
+			input: "\x1b[1;4;7m\x1b[22;24;27ma",
+			want:  attrs{},
+		},
+		{
+			desc:  "resets all attributes",
+			input: "\x1b[1;31;42m\x1b[ma",
+			want:  attrs{},
+		},
+		{
+			desc:  "basic colors",
+			input: "\x1b[31;42ma",
+			want:  attrs{fg: cell.ColorNumber(1), bg: cell.ColorNumber(2)},
+		},
+		{
+			desc:  "bright colors",
+			input: "\x1b[91;102ma",
+			want:  attrs{fg: cell.ColorNumber(9), bg: cell.ColorNumber(10)},
+		},
+		{
+			desc:  "256 colors",
+			input: "\x1b[38;5;200;48;5;17ma",
+			want:  attrs{fg: cell.ColorNumber(200), bg: cell.ColorNumber(17)},
+		},
+		{
+			desc:  "24 bit colors",
+			input: "\x1b[38;2;255;0;0;1ma",
+			want:  attrs{fg: cell.ColorRGB24(255, 0, 0), bold: true},
+		},
+		{
+			desc:  "default colors",
+			input: "\x1b[31;42m\x1b[39;49ma",
+			want:  attrs{},
+		},
+		{
+			desc:  "erasing keeps the background color",
+			input: "a\x1b[44m\x1b[1D\x1b[K",
+			want:  attrs{bg: cell.ColorNumber(4)},
+		},
+		{
+			desc:  "restoring the cursor restores the attributes",
+			input: "\x1b[1m\x1b7\x1b[0m\x1b8a",
+			want:  attrs{bold: true},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			s := newScreen(image.Point{3, 1})
+			s.write([]byte(tc.input))
+			if diff := pretty.Compare(tc.want, s.lines[0][0].attrs); diff != "" {
+				t.Errorf("attrs => unexpected diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestScreenModes(t *testing.T) {
+	s := newScreen(image.Point{3, 1})
+	if !s.cursorVisible || s.appCursor {
+		t.Fatalf("initial modes => cursorVisible:%v appCursor:%v, want true, false", s.cursorVisible, s.appCursor)
+	}
+
+	s.write([]byte("\x1b[?25l\x1b[?1h"))
+	if s.cursorVisible || !s.appCursor {
+		t.Errorf("after set => cursorVisible:%v appCursor:%v, want false, true", s.cursorVisible, s.appCursor)
+	}
+
+	s.write([]byte("\x1b[?25;1h\x1b[?1l"))
+	if !s.cursorVisible || s.appCursor {
+		t.Errorf("after reset => cursorVisible:%v appCursor:%v, want true, false", s.cursorVisible, s.appCursor)
+	}
+}
+
+func TestScreenResponses(t *testing.T) {
+	s := newScreen(image.Point{5, 5})
+	s.write([]byte("\x1b[3;2H\x1b[6n\x1b[5n\x1b[c\x1b[>c"))
+
+	want := "\x1b[3;2R\x1b[0n\x1b[?1;2c"
+	if got := string(s.takeResponses()); got != want {
+		t.Errorf("takeResponses => %q, want %q", got, want)
+	}
+	if got := s.takeResponses(); len(got) != 0 {
+		t.Errorf("takeResponses => %q, want no responses after they were taken", got)
+	}
+}
+
+func TestScreenResize(t *testing.T) {
+	tests := []struct {
+		desc       string
+		size       image.Point
+		input      string
+		resize     image.Point
+		want       string
+		wantCursor image.Point
+	}{
+		{
+			desc:       "grows",
+			size:       image.Point{2, 2},
+			input:      "ab\r\nc",
+			resize:     image.Point{3, 3},
+			want:       "ab\nc\n",
+			wantCursor: image.Point{1, 1},
+		},
+		{
+			desc:       "shrinks keeping the cursor row visible",
+			size:       image.Point{3, 3},
+			input:      "abc\r\nde\r\nf",
+			resize:     image.Point{2, 2},
+			want:       "de\nf",
+			wantCursor: image.Point{1, 1},
+		},
+		{
+			desc:       "shrinks from the bottom when the cursor is above",
+			size:       image.Point{3, 3},
+			input:      "abc\r\nde\r\nf\x1b[H",
+			resize:     image.Point{2, 2},
+			want:       "ab\nde",
+			wantCursor: image.Point{0, 0},
+		},
+		{
+			desc:       "resets the scrolling region",
+			size:       image.Point{2, 4},
+			input:      "\x1b[2;3r",
+			resize:     image.Point{2, 3},
+			want:       "\n\n",
+			wantCursor: image.Point{0, 0},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			s := newScreen(tc.size)
+			s.write([]byte(tc.input))
+			s.resize(tc.resize)
+			if got := s.String(); got != tc.want {
+				t.Errorf("String => %q, want %q", got, tc.want)
+			}
+			if s.cur != tc.wantCursor {
+				t.Errorf("cursor => %v, want %v", s.cur, tc.wantCursor)
+			}
+			if s.top != 0 || s.bottom != tc.resize.Y {
+				t.Errorf("scrolling region => [%d, %d), want [0, %d)", s.top, s.bottom, tc.resize.Y)
+			}
+		})
+	}
+}
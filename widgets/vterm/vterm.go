@@ -0,0 +1,308 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package vterm implements a widget that runs a command in an embedded
+// terminal.
+package vterm
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// defaultSize is the size of the terminal before the widget is first drawn.
+var defaultSize = image.Point{80, 24}
+
+// VTerm runs a command in a pseudo-terminal and displays its output.
+//
+// The output is interpreted as the output of a VT100 or xterm compatible
+// terminal, supporting cursor movement, erasing, scrolling regions, the
+// alternate screen and 256 or 24 bit colors. Keyboard events are forwarded
+// to the command while the widget is focused and the pseudo-terminal is
+// resized to match the canvas of the widget.
+//
+// Implements widgetapi.Widget. This object is thread-safe.
+type VTerm struct {
+	// rw reads the output of the command and writes its input.
+	rw io.ReadWriter
+	// setSize changes the size of the terminal of the command.
+	setSize func(image.Point) error
+	// close terminates the command.
+	close func() error
+
+	// screen interprets the output of the command.
+	screen *screen
+	// exited indicates that the command exited.
+	exited bool
+	// exitErr is the error the command exited with.
+	exitErr error
+
+	// mu protects the widget.
+	mu sync.Mutex
+
+	// opts are the provided options.
+	opts *options
+}
+
+// New starts the command in a pseudo-terminal and returns a widget that
+// displays it.
+// The command must not be started, its standard input, output and error are
+// connected to the pseudo-terminal. The caller should call Close when the
+// widget is no longer needed.
+func New(cmd *exec.Cmd, opts ...Option) (*VTerm, error) {
+	opt := newOptions()
+	for _, o := range opts {
+		o.set(opt)
+	}
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	if cmd == nil {
+		return nil, errors.New("the command cannot be nil")
+	}
+
+	if cmd.Env == nil {
+		cmd.Env = append(os.Environ(), "TERM="+opt.term)
+	}
+	f, err := startPty(cmd, defaultSize)
+	if err != nil {
+		return nil, fmt.Errorf("unable to start the command: %v", err)
+	}
+
+	vt := newVTerm(f, func(size image.Point) error {
+		return setPtySize(f, size)
+	}, opt)
+	vt.close = func() error {
+		if exited, _ := vt.Exited(); !exited {
+			if err := cmd.Process.Kill(); err != nil {
+				return err
+			}
+		}
+		return f.Close()
+	}
+	go vt.readLoop(cmd.Wait)
+	return vt, nil
+}
+
+// newVTerm returns a widget that communicates with a command over rw.
+func newVTerm(rw io.ReadWriter, setSize func(image.Point) error, opts *options) *VTerm {
+	return &VTerm{
+		rw:      rw,
+		setSize: setSize,
+		screen:  newScreen(defaultSize),
+		opts:    opts,
+	}
+}
+
+// readLoop interprets the output of the command until there is none left,
+// then waits for the command to exit.
+func (vt *VTerm) readLoop(wait func() error) {
+	buf := make([]byte, 4096)
+	for {
+		n, err := vt.rw.Read(buf)
+		if n > 0 {
+			vt.mu.Lock()
+			vt.screen.write(buf[:n])
+			resp := vt.screen.takeResponses()
+			vt.mu.Unlock()
+
+			if len(resp) > 0 {
+				if _, err := vt.rw.Write(resp); err != nil {
+					break
+				}
+			}
+		}
+		if err != nil {
+			// Reading from a pseudo-terminal returns an error other than
+			// io.EOF once the command exits, so any error ends the loop.
+			break
+		}
+	}
+
+	err := wait()
+	vt.mu.Lock()
+	vt.exited = true
+	vt.exitErr = err
+	vt.mu.Unlock()
+
+	if vt.opts.onExit != nil {
+		vt.opts.onExit(err)
+	}
+}
+
+// Exited returns true if the command exited and the error returned by
+// exec.Cmd.Wait.
+func (vt *VTerm) Exited() (bool, error) {
+	vt.mu.Lock()
+	defer vt.mu.Unlock()
+	return vt.exited, vt.exitErr
+}
+
+// Close kills the command if it is still running and closes the
+// pseudo-terminal.
+func (vt *VTerm) Close() error {
+	if vt.close == nil {
+		return nil
+	}
+	return vt.close()
+}
+
+// Draw draws the VTerm widget onto the canvas.
+// Implements widgetapi.Widget.Draw.
+func (vt *VTerm) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	vt.mu.Lock()
+	defer vt.mu.Unlock()
+
+	size := cvs.Area().Size()
+	if size != vt.screen.size {
+		vt.screen.resize(size)
+		if !vt.exited {
+			if err := vt.setSize(size); err != nil {
+				return fmt.Errorf("unable to resize the terminal: %v", err)
+			}
+		}
+	}
+
+	s := vt.screen
+	for y, line := range s.lines {
+		for x, g := range line {
+			if g.wideCont || (g.r == 0 && g.attrs == attrs{}) {
+				continue
+			}
+			r := g.r
+			if r == 0 {
+				r = ' '
+			}
+			if _, err := cvs.SetCell(image.Point{x, y}, r, g.attrs.cellOpts()...); err != nil {
+				return err
+			}
+		}
+	}
+
+	if meta.Focused && s.cursorVisible && !vt.exited {
+		g := s.lines[s.cur.Y][s.cur.X]
+		r := g.r
+		if r == 0 || g.wideCont {
+			r = ' '
+		}
+		a := g.attrs
+		a.inverse = !a.inverse
+		if _, err := cvs.SetCell(s.cur, r, a.cellOpts()...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Keyboard forwards the pressed key to the command.
+// Implements widgetapi.Widget.Keyboard.
+func (vt *VTerm) Keyboard(k *terminalapi.Keyboard) error {
+	vt.mu.Lock()
+	exited := vt.exited
+	input := keyInput(k.Key, vt.screen.appCursor)
+	vt.mu.Unlock()
+
+	if exited || len(input) == 0 {
+		return nil
+	}
+	if _, err := vt.rw.Write(input); err != nil {
+		return fmt.Errorf("unable to write to the terminal: %v", err)
+	}
+	return nil
+}
+
+// Mouse input isn't supported on the VTerm widget.
+// Implements widgetapi.Widget.Mouse.
+func (*VTerm) Mouse(m *terminalapi.Mouse) error {
+	return errors.New("the VTerm widget doesn't support mouse events")
+}
+
+// Options implements widgetapi.Widget.Options.
+func (*VTerm) Options() widgetapi.Options {
+	return widgetapi.Options{
+		MinimumSize:  image.Point{1, 1},
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		WantMouse:    widgetapi.MouseScopeNone,
+	}
+}
+
+// keySequences are the sequences terminals send for keys that don't have
+// application cursor variants.
+var keySequences = map[keyboard.Key]string{
+	keyboard.KeyInsert:     "\x1b[2~",
+	keyboard.KeyDelete:     "\x1b[3~",
+	keyboard.KeyPgUp:       "\x1b[5~",
+	keyboard.KeyPgDn:       "\x1b[6~",
+	keyboard.KeyF1:         "\x1bOP",
+	keyboard.KeyF2:         "\x1bOQ",
+	keyboard.KeyF3:         "\x1bOR",
+	keyboard.KeyF4:         "\x1bOS",
+	keyboard.KeyF5:         "\x1b[15~",
+	keyboard.KeyF6:         "\x1b[17~",
+	keyboard.KeyF7:         "\x1b[18~",
+	keyboard.KeyF8:         "\x1b[19~",
+	keyboard.KeyF9:         "\x1b[20~",
+	keyboard.KeyF10:        "\x1b[21~",
+	keyboard.KeyF11:        "\x1b[23~",
+	keyboard.KeyF12:        "\x1b[24~",
+	keyboard.KeyBackspace2: "\x7f",
+}
+
+// cursorKeys are the final characters of the sequences of cursor keys.
+var cursorKeys = map[keyboard.Key]byte{
+	keyboard.KeyArrowUp:    'A',
+	keyboard.KeyArrowDown:  'B',
+	keyboard.KeyArrowRight: 'C',
+	keyboard.KeyArrowLeft:  'D',
+	keyboard.KeyHome:       'H',
+	keyboard.KeyEnd:        'F',
+}
+
+// keyInput returns the bytes a terminal sends to the command when the key is
+// pressed. The appCursor argument indicates if the command enabled the
+// application cursor keys mode. Returns nil for unknown keys.
+func keyInput(k keyboard.Key, appCursor bool) []byte {
+	if k >= 0 {
+		if !utf8.ValidRune(rune(k)) {
+			return nil
+		}
+		return []byte(string(rune(k)))
+	}
+	if k <= keyboard.KeyCtrlTilde && k >= keyboard.KeyCtrl7 {
+		// The control keys are declared in the order of the control
+		// characters they produce, starting with NUL.
+		return []byte{byte(keyboard.KeyCtrlTilde - k)}
+	}
+	if final, ok := cursorKeys[k]; ok {
+		if appCursor {
+			return []byte{0x1b, 'O', final}
+		}
+		return []byte{0x1b, '[', final}
+	}
+	if seq, ok := keySequences[k]; ok {
+		return []byte(seq)
+	}
+	return nil
+}
@@ -0,0 +1,396 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vterm
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/internal/canvas"
+	"github.com/mum4k/termdash/internal/canvas/testcanvas"
+	"github.com/mum4k/termdash/internal/faketerm"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+// fakeIO is a fake pseudo-terminal that returns the output from the reader
+// and records the input.
+type fakeIO struct {
+	r io.Reader
+	w bytes.Buffer
+}
+
+// Read implements io.Reader.Read.
+func (f *fakeIO) Read(p []byte) (int, error) {
+	return f.r.Read(p)
+}
+
+// Write implements io.Writer.Write.
+func (f *fakeIO) Write(p []byte) (int, error) {
+	return f.w.Write(p)
+}
+
+// newTestVTerm returns a VTerm with a running command that produced the
+// output.
+func newTestVTerm(output string, opts ...Option) (*VTerm, *fakeIO) {
+	opt := newOptions()
+	for _, o := range opts {
+		o.set(opt)
+	}
+	f := &fakeIO{r: strings.NewReader("")}
+	vt := newVTerm(f, func(image.Point) error { return nil }, opt)
+	vt.screen.write([]byte(output))
+	return vt, f
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		desc string
+		cmd  *exec.Cmd
+		opts []Option
+	}{
+		{
+			desc: "fails on nil command",
+		},
+		{
+			desc: "fails on empty Term",
+			cmd:  exec.Command("true"),
+			opts: []Option{Term("")},
+		},
+		{
+			desc: "fails on Term with an equal sign",
+			cmd:  exec.Command("true"),
+			opts: []Option{Term("a=b")},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			if _, err := New(tc.cmd, tc.opts...); err == nil {
+				t.Errorf("New => got nil error, want an error")
+			}
+		})
+	}
+}
+
+func TestDraw(t *testing.T) {
+	tests := []struct {
+		desc    string
+		canvas  image.Rectangle
+		output  string
+		exitErr error
+		meta    *widgetapi.Meta
+		want    func(size image.Point) *faketerm.Terminal
+	}{
+		{
+			desc:   "draws nothing without output",
+			canvas: image.Rect(0, 0, 4, 2),
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				return faketerm.MustNew(size)
+			},
+		},
+		{
+			desc:   "draws text with attributes",
+			canvas: image.Rect(0, 0, 4, 2),
+			output: "a\x1b[1;31;42mb\x1b[0m\r\n\x1b[4mc",
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testcanvas.MustSetCell(cvs, image.Point{0, 0}, 'a')
+				testcanvas.MustSetCell(cvs, image.Point{1, 0}, 'b',
+					cell.FgColor(cell.ColorNumber(1)),
+					cell.BgColor(cell.ColorNumber(2)),
+					cell.Bold(),
+				)
+				testcanvas.MustSetCell(cvs, image.Point{0, 1}, 'c', cell.Underline())
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "draws erased cells with a background color",
+			canvas: image.Rect(0, 0, 3, 2),
+			output: "a\x1b[44m\x1b[K",
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testcanvas.MustSetCell(cvs, image.Point{0, 0}, 'a')
+				testcanvas.MustSetCell(cvs, image.Point{1, 0}, ' ', cell.BgColor(cell.ColorNumber(4)))
+				testcanvas.MustSetCell(cvs, image.Point{2, 0}, ' ', cell.BgColor(cell.ColorNumber(4)))
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "draws full-width runes",
+			canvas: image.Rect(0, 0, 4, 1),
+			output: "世a",
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testcanvas.MustSetCell(cvs, image.Point{0, 0}, '世')
+				testcanvas.MustSetCell(cvs, image.Point{2, 0}, 'a')
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "draws the cursor when focused",
+			canvas: image.Rect(0, 0, 4, 2),
+			output: "ab\x1b[D",
+			meta:   &widgetapi.Meta{Focused: true},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testcanvas.MustSetCell(cvs, image.Point{0, 0}, 'a')
+				testcanvas.MustSetCell(cvs, image.Point{1, 0}, 'b', cell.Inverse())
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "draws the cursor on an empty cell",
+			canvas: image.Rect(0, 0, 4, 2),
+			output: "ab",
+			meta:   &widgetapi.Meta{Focused: true},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testcanvas.MustSetCell(cvs, image.Point{0, 0}, 'a')
+				testcanvas.MustSetCell(cvs, image.Point{1, 0}, 'b')
+				testcanvas.MustSetCell(cvs, image.Point{2, 0}, ' ', cell.Inverse())
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "doesn't draw a hidden cursor",
+			canvas: image.Rect(0, 0, 4, 2),
+			output: "ab\x1b[?25l",
+			meta:   &widgetapi.Meta{Focused: true},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testcanvas.MustSetCell(cvs, image.Point{0, 0}, 'a')
+				testcanvas.MustSetCell(cvs, image.Point{1, 0}, 'b')
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:    "doesn't draw the cursor after the command exited",
+			canvas:  image.Rect(0, 0, 4, 2),
+			output:  "ab",
+			exitErr: errors.New("exit status 1"),
+			meta:    &widgetapi.Meta{Focused: true},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testcanvas.MustSetCell(cvs, image.Point{0, 0}, 'a')
+				testcanvas.MustSetCell(cvs, image.Point{1, 0}, 'b')
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+		{
+			desc:   "output beyond the canvas scrolls",
+			canvas: image.Rect(0, 0, 2, 2),
+			output: "\x1b[24;1Ha\r\nb",
+			meta:   &widgetapi.Meta{},
+			want: func(size image.Point) *faketerm.Terminal {
+				ft := faketerm.MustNew(size)
+				cvs := testcanvas.MustNew(ft.Area())
+				testcanvas.MustSetCell(cvs, image.Point{0, 0}, 'a')
+				testcanvas.MustSetCell(cvs, image.Point{0, 1}, 'b')
+				testcanvas.MustApply(cvs, ft)
+				return ft
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			vt, _ := newTestVTerm(tc.output)
+			if tc.exitErr != nil {
+				vt.exited, vt.exitErr = true, tc.exitErr
+			}
+
+			c, err := canvas.New(tc.canvas)
+			if err != nil {
+				t.Fatalf("canvas.New => unexpected error: %v", err)
+			}
+			if err := vt.Draw(c, tc.meta); err != nil {
+				t.Fatalf("Draw => unexpected error: %v", err)
+			}
+
+			got, err := faketerm.New(c.Size())
+			if err != nil {
+				t.Fatalf("faketerm.New => unexpected error: %v", err)
+			}
+			if err := c.Apply(got); err != nil {
+				t.Fatalf("Apply => unexpected error: %v", err)
+			}
+			if diff := faketerm.Diff(tc.want(c.Size()), got); diff != "" {
+				t.Errorf("Draw => %v", diff)
+			}
+		})
+	}
+}
+
+func TestDrawResizes(t *testing.T) {
+	var sizes []image.Point
+	resizeErr := error(nil)
+	vt := newVTerm(&fakeIO{r: strings.NewReader("")}, func(size image.Point) error {
+		sizes = append(sizes, size)
+		return resizeErr
+	}, newOptions())
+
+	for _, size := range []image.Point{{3, 2}, {3, 2}, {5, 4}} {
+		c, err := canvas.New(image.Rectangle{Max: size})
+		if err != nil {
+			t.Fatalf("canvas.New => unexpected error: %v", err)
+		}
+		if err := vt.Draw(c, &widgetapi.Meta{}); err != nil {
+			t.Fatalf("Draw => unexpected error: %v", err)
+		}
+	}
+	want := []image.Point{{3, 2}, {5, 4}}
+	if diff := pretty.Compare(want, sizes); diff != "" {
+		t.Errorf("setSize => unexpected diff (-want, +got):\n%s", diff)
+	}
+
+	resizeErr = errors.New("resize failed")
+	c, err := canvas.New(image.Rect(0, 0, 2, 2))
+	if err != nil {
+		t.Fatalf("canvas.New => unexpected error: %v", err)
+	}
+	if err := vt.Draw(c, &widgetapi.Meta{}); err == nil {
+		t.Errorf("Draw => got nil error, want an error when the resize fails")
+	}
+}
+
+func TestReadLoop(t *testing.T) {
+	var gotExit []error
+	exitErr := errors.New("exit status 2")
+	f := &fakeIO{r: strings.NewReader("a\x1b[6n")}
+	vt := newVTerm(f, func(image.Point) error { return nil }, &options{
+		onExit: func(err error) {
+			gotExit = append(gotExit, err)
+		},
+	})
+	vt.readLoop(func() error { return exitErr })
+
+	if got, want := f.w.String(), "\x1b[1;2R"; got != want {
+		t.Errorf("written responses => %q, want %q", got, want)
+	}
+	exited, err := vt.Exited()
+	if !exited || err != exitErr {
+		t.Errorf("Exited => %v, %v, want true, %v", exited, err, exitErr)
+	}
+	if diff := pretty.Compare([]error{exitErr}, gotExit); diff != "" {
+		t.Errorf("OnExit => unexpected diff (-want, +got):\n%s", diff)
+	}
+}
+
+func TestKeyboard(t *testing.T) {
+	tests := []struct {
+		desc    string
+		output  string
+		exitErr error
+		keys    []keyboard.Key
+		want    string
+	}{
+		{
+			desc: "runes",
+			keys: []keyboard.Key{'a', '世', keyboard.KeySpace},
+			want: "a世 ",
+		},
+		{
+			desc: "control keys",
+			keys: []keyboard.Key{keyboard.KeyCtrlC, keyboard.KeyEnter, keyboard.KeyTab, keyboard.KeyEsc, keyboard.KeyCtrlTilde, keyboard.KeyBackspace2},
+			want: "\x03\r\t\x1b\x00\x7f",
+		},
+		{
+			desc: "cursor keys",
+			keys: []keyboard.Key{keyboard.KeyArrowUp, keyboard.KeyArrowLeft, keyboard.KeyHome},
+			want: "\x1b[A\x1b[D\x1b[H",
+		},
+		{
+			desc:   "cursor keys in application mode",
+			output: "\x1b[?1h",
+			keys:   []keyboard.Key{keyboard.KeyArrowDown, keyboard.KeyArrowRight, keyboard.KeyEnd},
+			want:   "\x1bOB\x1bOC\x1bOF",
+		},
+		{
+			desc: "editing and function keys",
+			keys: []keyboard.Key{keyboard.KeyDelete, keyboard.KeyPgDn, keyboard.KeyF1, keyboard.KeyF12},
+			want: "\x1b[3~\x1b[6~\x1bOP\x1b[24~",
+		},
+		{
+			desc:    "ignores keys after the command exited",
+			exitErr: errors.New("exit status 1"),
+			keys:    []keyboard.Key{'a'},
+			want:    "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			vt, f := newTestVTerm(tc.output)
+			if tc.exitErr != nil {
+				vt.exited, vt.exitErr = true, tc.exitErr
+			}
+			for _, k := range tc.keys {
+				if err := vt.Keyboard(&terminalapi.Keyboard{Key: k}); err != nil {
+					t.Fatalf("Keyboard(%v) => unexpected error: %v", k, err)
+				}
+			}
+			if got := f.w.String(); got != tc.want {
+				t.Errorf("written input => %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMouse(t *testing.T) {
+	vt, _ := newTestVTerm("")
+	if err := vt.Mouse(&terminalapi.Mouse{}); err == nil {
+		t.Errorf("Mouse => got nil error, want an error")
+	}
+}
+
+func TestOptions(t *testing.T) {
+	vt, _ := newTestVTerm("")
+	want := widgetapi.Options{
+		MinimumSize:  image.Point{1, 1},
+		WantKeyboard: widgetapi.KeyScopeFocused,
+		WantMouse:    widgetapi.MouseScopeNone,
+	}
+	if diff := pretty.Compare(want, vt.Options()); diff != "" {
+		t.Errorf("Options => unexpected diff (-want, +got):\n%s", diff)
+	}
+}
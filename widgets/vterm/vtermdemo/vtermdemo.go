@@ -0,0 +1,68 @@
+// Copyright 2019 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Binary vtermdemo shows the functionality of the vterm widget.
+package main
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/terminal/termbox"
+	"github.com/mum4k/termdash/widgets/vterm"
+)
+
+func main() {
+	t, err := termbox.New()
+	if err != nil {
+		panic(err)
+	}
+	defer t.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+	vt, err := vterm.New(
+		exec.Command(shell),
+		vterm.OnExit(func(error) {
+			cancel()
+		}),
+	)
+	if err != nil {
+		panic(err)
+	}
+	defer vt.Close()
+
+	c, err := container.New(
+		t,
+		container.Border(linestyle.Light),
+		container.BorderTitle("EXIT THE SHELL TO QUIT"),
+		container.PlaceWidget(vt),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	if err := termdash.Run(ctx, t, c, termdash.RedrawInterval(50*time.Millisecond)); err != nil {
+		panic(err)
+	}
+}